@@ -51,6 +51,12 @@ server:
     AWS_REGION: {{.Values.argo.aws.region}}
     VAULT_AWSKMS_SEAL_KEY_ID: alias/vault-kms-unseal-{{.Values.argo.clusterName}}
 
+  {{- if .Values.argo.vault.roleArn }}
+  # Access the unseal key through IRSA instead of static IAM user credentials
+  serviceAccount:
+    annotations:
+      eks.amazonaws.com/role-arn: {{.Values.argo.vault.roleArn}}
+  {{- else }}
   # extraSecretEnvironmentVars is a list of extra environment variables to set with the stateful set.
   # These variables take value from existing Secret objects.
   extraSecretEnvironmentVars:
@@ -60,6 +66,7 @@ server:
     - envName: AWS_SECRET_ACCESS_KEY
       secretName: vault-kms-unseal
       secretKey: AWS_SECRET_ACCESS_KEY
+  {{- end }}
 
   # https://jira.devtools.intel.com/browse/NEXENPL-1126
   # enable liveness probe such that pod is restarted when auto-unseal failed
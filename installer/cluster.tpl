@@ -65,6 +65,9 @@ argo:
   traefik:
     tlsOption: ""
 
+  vault:
+    roleArn: "${VAULT_ROLE_ARN}"
+
 orchestratorDeployment:
   targetCluster: cloud
 
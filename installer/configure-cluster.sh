@@ -98,6 +98,9 @@ if [ -z $ARGOCD_TG_ARN ]; then
 fi
 
 
+# IRSA role of Vault to access the unseal key, Vault falls back to the vault-kms-unseal secret without it
+export VAULT_ROLE_ARN=$(aws iam get-role --role-name vault-${CLUSTER_NAME} --query Role.Arn --output text 2>/dev/null || true)
+
 if [ -n "$SRE_BASIC_AUTH_USERNAME" ] || [ -n "$SRE_BASIC_AUTH_PASSWORD" ] || [ -n "$SRE_DESTINATION_SECRET_URL" ] || [ -n "$SRE_DESTINATION_CA_SECRET" ]; then
     export SRE_PROFILE="- orch-configs/profiles/enable-sre.yaml"
 else
@@ -1821,6 +1821,11 @@ func (g Gen) DumpReleaseImageManifest() error {
 	return g.dumpReleaseImageManifest()
 }
 
+// Regenerate the third-party image manifest of the installer, new-installer/asset/images.yaml.
+func (g Gen) InstallerImageManifest() error {
+	return g.installerImageManifest(filepath.Join("new-installer", "asset", "images.yaml"))
+}
+
 // Create a Release image manifest with local charts
 func (g Gen) LocalReleaseImageManifest(manifestFilename string) error {
 	return g.localReleaseImageManifest(manifestFilename)
@@ -1683,3 +1683,118 @@ Add to your system trust store so your browser or client trusts the TLS certific
 	)
 	return nil
 }
+
+// installerImageManifestHeader marks the installer image manifest as generated by mage gen:installerImageManifest.
+const installerImageManifestHeader = `# SPDX-FileCopyrightText: 2025 Intel Corporation
+# SPDX-License-Identifier: Apache-2.0
+
+# Code generated by mage gen:installerImageManifest. DO NOT EDIT.
+# Third-party images pulled by the orchestrator deployment, from the templated charts.
+# Used to derive the upstream registries for the container registry cache.
+`
+
+// imageRepository strips the tag and the digest of an image reference and adds the
+// docker.io registry and library namespace when the reference omits them.
+func imageRepository(image string) string {
+	image, _, _ = strings.Cut(image, "@")
+	if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		image = image[:i]
+	}
+	first, rest, found := strings.Cut(image, "/")
+	if !found {
+		return "docker.io/library/" + image
+	}
+	if !strings.ContainsAny(first, ".:") && first != "localhost" {
+		return "docker.io/" + image
+	}
+	if first == "index.docker.io" || first == "registry-1.docker.io" {
+		return "docker.io/" + rest
+	}
+	return image
+}
+
+// getInstallerImageManifest templates the charts of every component, third-party hosted
+// charts included, and returns the repositories of the third-party images they pull.
+func getInstallerImageManifest() ([]string, error) {
+	removeIntelFromNoProxy()
+
+	manifest, err := getManifest()
+	if err != nil {
+		return nil, fmt.Errorf("error creating manifest: %w", err)
+	}
+
+	tempDir, err := os.MkdirTemp(".", "_appimg_*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("error creating temp folder: %w", err)
+	}
+	fmt.Println("Extracting helmfiles to: ", tempDir)
+	defer os.RemoveAll(tempDir)
+
+	clusterValues, err := loadClusterConfig("bkc")
+	if err != nil {
+		return nil, fmt.Errorf("error loading cluster configuration: %w", err)
+	}
+	if err := saveValuesFile(filepath.Join(tempDir, "values.yaml"), clusterValues); err != nil {
+		return nil, fmt.Errorf("error saving cluster values: %w", err)
+	}
+
+	argoValues, err := loadArgoAppValues(tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("error loading argo valueObjects: %w", err)
+	}
+
+	for _, component := range manifest.Components {
+		fmt.Println(hrEqual)
+		fmt.Println(component.AppName)
+		fmt.Println(hrEqual)
+		var cmd *exec.Cmd
+		if strings.HasPrefix(component.Repo, "oci://") {
+			cmd = exec.Command("helm", "pull", strings.Join([]string{component.Repo, component.Chart}, "/"),
+				"--version", component.Version)
+		} else {
+			cmd = exec.Command("helm", "pull", component.Chart, "--repo", component.Repo, "--version", component.Version)
+		}
+		cmd.Dir = tempDir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return nil, fmt.Errorf("error pulling helm chart for %s: %w: %s", component.AppName, err, string(output))
+		}
+		chartLocalPath := filepath.Join(tempDir, filepath.Base(component.Chart)+"-"+component.Version+".tgz")
+		err = helmTemplate(component.AppName, component.ReleaseName, "./"+chartLocalPath,
+			argoValues, filepath.Join(tempDir, component.ReleaseName))
+		if err != nil {
+			return nil, fmt.Errorf("error templating helm chart for %s: %w", component.AppName, err)
+		}
+	}
+
+	imageList, err := parseTemplatedChartsForImageValues(tempDir)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing templated charts: %w", err)
+	}
+
+	repositories := []string{}
+	for _, image := range imageList {
+		repository := imageRepository(image)
+		// The orchestrator images are not third-party
+		if strings.HasPrefix(repository, PublicRegistryRepoURL+"/") {
+			continue
+		}
+		repositories = append(repositories, repository)
+	}
+	return uniqueSortedValues(repositories), nil
+}
+
+func (Gen) installerImageManifest(manifestFilename string) error {
+	repositories, err := getInstallerImageManifest()
+	if err != nil {
+		return fmt.Errorf("error getting installer image manifest: %w", err)
+	}
+
+	content := installerImageManifestHeader + "images:\n"
+	for _, repository := range repositories {
+		content += "  - " + repository + "\n"
+	}
+	if err := os.WriteFile(manifestFilename, []byte(content), 0o644); err != nil {
+		return fmt.Errorf("unable to write installer image manifest: %w", err)
+	}
+	return nil
+}
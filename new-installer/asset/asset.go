@@ -8,3 +8,6 @@ import "embed"
 
 //go:embed packages.yaml
 var EmbedPackage embed.FS
+
+//go:embed images.yaml
+var EmbedImageManifest embed.FS
@@ -0,0 +1,98 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	steps_onprem "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func newBundleCommand(logOptions *internal.LogOptions) *cobra.Command {
+	bundleCmd := &cobra.Command{
+		Use:   "bundle",
+		Short: "Manage air-gapped artifact bundles",
+		Long:  "Create the signed bundle of artifacts that an on-prem install on a disconnected site uses instead of the release service",
+	}
+
+	var output, signingKey, signaturePublicKey, imageManifest, version, registry string
+	createCmd := &cobra.Command{
+		Use:   "create",
+		Short: "Create an artifact bundle",
+		Long: "Pull the installers, RKE2 files, charts and the images of the release image manifest into an OCI image layout tarball, " +
+			"and sign it with an Ed25519 key. The signature is written to <output>" + artifacts.SignatureSuffix + ". " +
+			"The installers and archives are verified with the cosign public key first, and charts against their repository index.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			cfg := config.OrchInstallerConfig{}
+			cfg.Artifacts.Version = version
+			cfg.Artifacts.Registry = registry
+			createBundle(cfg, output, signingKey, signaturePublicKey, imageManifest)
+		},
+	}
+	createCmd.Flags().StringVar(&output, "output", "orch-bundle.tar", "Path of the bundle")
+	createCmd.Flags().StringVar(&signingKey, "signing-key", "", "Ed25519 private key in PEM to sign the bundle with")
+	createCmd.Flags().StringVar(&signaturePublicKey, "signature-public-key", "", "cosign public key in PEM the installers and archives must be signed with")
+	createCmd.Flags().StringVar(&imageManifest, "image-manifest", "", "Release image manifest listing the images to bundle")
+	createCmd.Flags().StringVar(&version, "version", "", "Release to bundle, read from the VERSION file by default")
+	createCmd.Flags().StringVar(&registry, "registry", steps_onprem.DefaultRegistry, "Release service registry the installers and archives are pulled from")
+	_ = createCmd.MarkFlagRequired("signing-key")
+	_ = createCmd.MarkFlagRequired("signature-public-key")
+
+	bundleCmd.AddCommand(createCmd)
+	return bundleCmd
+}
+
+func createBundle(cfg config.OrchInstallerConfig, output, signingKey, signaturePublicKey, imageManifest string) {
+	logger := zap.S()
+	currentDir, err := os.Getwd()
+	if err != nil {
+		logger.Fatalf("error getting current directory: %s", err)
+	}
+	locations, err := steps_onprem.ResolveArtifactLocations(currentDir, cfg)
+	if err != nil {
+		logger.Fatalf("error resolving the artifacts: %s", err)
+	}
+	key, err := artifacts.ReadPrivateKey(signingKey)
+	if err != nil {
+		logger.Fatalf("error reading signing key: %s", err)
+	}
+	publicKey, err := artifacts.ReadSignaturePublicKey(signaturePublicKey)
+	if err != nil {
+		logger.Fatalf("error reading signature public key: %s", err)
+	}
+
+	bundleArtifacts := steps_onprem.BundleArtifacts(locations)
+	if imageManifest != "" {
+		images, err := artifacts.ReadImageManifest(imageManifest)
+		if err != nil {
+			logger.Fatalf("error reading image manifest: %s", err)
+		}
+		for _, image := range images {
+			artifact, err := artifacts.ImageArtifact(image, locations.Registry)
+			if err != nil {
+				logger.Fatalf("error in image manifest: %s", err)
+			}
+			bundleArtifacts = append(bundleArtifacts, artifact)
+		}
+	}
+
+	origin := &artifacts.ReleaseService{
+		Registry: locations.Registry,
+		Verifier: &artifacts.CosignVerifier{PublicKey: publicKey},
+	}
+	if err := artifacts.CreateBundle(context.Background(), origin, bundleArtifacts, output, key); err != nil {
+		logger.Fatalf("error creating bundle: %s", err)
+	}
+	logger.Infof("Bundle of %d artifacts of release %s written to %s", len(bundleArtifacts), locations.OrchVersion, output)
+}
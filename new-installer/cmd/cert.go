@@ -0,0 +1,139 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const DefaultCertExpiryWarningDays = 30
+
+func newCertCommand(configFile, runtimeStateFile *string, logOptions *internal.LogOptions) *cobra.Command {
+	certCmd := &cobra.Command{
+		Use:   "cert",
+		Short: "Manage orchestrator TLS certificates",
+		Long:  "Rotate the orchestrator TLS certificate and check certificate expiry (AWS only)",
+	}
+
+	var certFile, keyFile, caFile string
+	rotateCmd := &cobra.Command{
+		Use:   "rotate",
+		Short: "Re-import a new TLS certificate into ACM",
+		Long:  "Validate the new TLS certificate, key and CA and re-import them into the existing ACM certificate. Listeners keep using the same ACM ARN.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			rotateCert(*configFile, *runtimeStateFile, certFile, keyFile, caFile)
+		},
+	}
+	rotateCmd.Flags().StringVar(&certFile, "cert", "", "Path to the PEM encoded TLS certificate")
+	rotateCmd.Flags().StringVar(&keyFile, "key", "", "Path to the PEM encoded TLS private key")
+	rotateCmd.Flags().StringVar(&caFile, "ca", "", "Path to the PEM encoded CA certificate chain, the current one is kept when omitted")
+	_ = rotateCmd.MarkFlagRequired("cert")
+	_ = rotateCmd.MarkFlagRequired("key")
+
+	var warningDays int
+	expiryCmd := &cobra.Command{
+		Use:   "expiry",
+		Short: "Report days remaining for the orchestrator certificates",
+		Long:  "Report days remaining for the ACM certificate, the in-cluster Traefik TLS secret and the RDS CA certificate. Exits with an error if any expires within the warning period.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			checkCertExpiry(*configFile, *runtimeStateFile, warningDays)
+		},
+	}
+	expiryCmd.Flags().IntVar(&warningDays, "warning-days", DefaultCertExpiryWarningDays, "Fail if a certificate expires within this number of days")
+
+	certCmd.AddCommand(rotateCmd, expiryCmd)
+	return certCmd
+}
+
+func readAWSConfigAndRuntimeState(configFile, runtimeStateFile string) (*config.FileBaseOrchConfigReaderWriter, config.OrchInstallerConfig, config.OrchInstallerRuntimeState) {
+	logger := zap.S()
+	orchConfigReaderWriter := &config.FileBaseOrchConfigReaderWriter{
+		OrchConfigFilePath:   configFile,
+		RuntimeStateFilePath: runtimeStateFile,
+	}
+	orchConfig, err := orchConfigReaderWriter.ReadOrchConfig()
+	if err != nil {
+		logger.Fatalf("error reading config file %s: %s", configFile, err)
+	}
+	runtimeState, err := orchConfigReaderWriter.ReadRuntimeState()
+	if err != nil {
+		logger.Fatalf("error reading runtime state file: %s", err)
+	}
+	internal.RedactSecrets(config.Secrets(orchConfig, runtimeState)...)
+	if orchConfig.Provider != "aws" {
+		logger.Fatalf("error: this command is not supported for provider %s", orchConfig.Provider)
+	}
+	return orchConfigReaderWriter, orchConfig, runtimeState
+}
+
+func rotateCert(configFile, runtimeStateFile, certFile, keyFile, caFile string) {
+	logger := zap.S()
+	orchConfigReaderWriter, orchConfig, runtimeState := readAWSConfigAndRuntimeState(configFile, runtimeStateFile)
+
+	readPEM := func(path string) string {
+		if path == "" {
+			return ""
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Fatalf("error reading %s: %s", path, err)
+		}
+		return string(data)
+	}
+	certPEM, keyPEM, caPEM := readPEM(certFile), readPEM(keyFile), readPEM(caFile)
+
+	runtimeState, rotateErr := steps_aws.RotateACMCertificate(orchConfig, runtimeState, steps_aws.CreateAWSUtility(), certPEM, keyPEM, caPEM)
+	if rotateErr != nil {
+		logger.Errorf("error rotating certificate: %s", rotateErr)
+		showActionsForError(rotateErr)
+		os.Exit(1)
+	}
+	if err := orchConfigReaderWriter.WriteRuntimeState(runtimeState); err != nil {
+		logger.Fatalf("error writing runtime state file: %s", err)
+	}
+	logger.Infof("Certificate %s rotated successfully", runtimeState.AWS.ACMCertArn)
+}
+
+func checkCertExpiry(configFile, runtimeStateFile string, warningDays int) {
+	logger := zap.S()
+	_, orchConfig, runtimeState := readAWSConfigAndRuntimeState(configFile, runtimeStateFile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	expiries := steps_aws.CheckCertExpiry(ctx, orchConfig, runtimeState, steps_aws.CreateAWSUtility(), steps.CreateShellUtility())
+
+	failed := false
+	for _, expiry := range expiries {
+		switch {
+		case expiry.Error != nil:
+			failed = true
+			fmt.Printf("%-20s unknown (%v)\n", expiry.Name, expiry.Error)
+		case expiry.DaysRemaining < warningDays:
+			failed = true
+			fmt.Printf("%-20s %d days remaining, expires at %s (WARNING)\n", expiry.Name, expiry.DaysRemaining, expiry.NotAfter.Format("2006-01-02"))
+		default:
+			fmt.Printf("%-20s %d days remaining, expires at %s\n", expiry.Name, expiry.DaysRemaining, expiry.NotAfter.Format("2006-01-02"))
+		}
+	}
+	if failed {
+		logger.Errorf("one or more certificates cannot be checked or expire within %d days", warningDays)
+		os.Exit(1)
+	}
+}
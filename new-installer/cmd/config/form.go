@@ -62,6 +62,7 @@ func configureProvider() *huh.Group {
 			Options(
 				huh.NewOption("AWS", "aws"),
 				huh.NewOption("On-Premises", "onprem"),
+				huh.NewOption("Existing Kubernetes Cluster", "existing-cluster"),
 			),
 	).Title("Step 2: Infrastructure Type\n")
 }
@@ -102,10 +103,21 @@ func configureAwsExpert() *huh.Group {
 			Value(&input.AWS.CustomerTag),
 		huh.NewInput().
 			Title("Container Registry Cache").
-			Description("(Optional) Pull OCI artifact from this cache registry").
+			Description("(Optional) Pull OCI artifact from this cache registry. Set to 'ecr' to create an ECR pull-through cache").
 			Placeholder("").
 			Validate(validateCacheRegistry).
 			Value(&input.AWS.CacheRegistry),
+		huh.NewInput().
+			Title("Docker Username").
+			Description("(Optional) Docker Hub username. Required by the ECR pull-through cache").
+			Placeholder("").
+			Value(&input.AWS.DockerUsername),
+		huh.NewInput().
+			Title("Docker Token").
+			Description("(Optional) Docker Hub token. Required by the ECR pull-through cache").
+			Placeholder("").
+			EchoMode(huh.EchoModePassword).
+			Value(&input.AWS.DockerToken),
 		huh.NewInput().
 			Title("Jump Host Whitelist").
 			Description("(Optional) Comma-separated CIDR. Traffic from these CIDRs will be allowed to access the jump host").
@@ -124,6 +136,12 @@ func configureAwsExpert() *huh.Group {
 			Placeholder("id_rsa").
 			Validate(validateJumpHostPrivKeyPath).
 			Value(&input.AWS.JumpHostPrivKeyPath),
+		huh.NewInput().
+			Title("Load Balancer Whitelist").
+			Description("(Optional) Comma-separated CIDR. Traffic from these CIDRs will be allowed to access the orchestrator load balancers. Default to 0.0.0.0/0").
+			Placeholder("10.0.0.0/8, 192.168.0.0/16").
+			Validate(validateAwsLBWhitelist).
+			Value(&tmpLBWhitelist),
 		huh.NewInput().
 			Title("VPC ID").
 			Description("(Optional) Enter VPC ID if you prefer to reuse existing VPC instead of letting us create one").
@@ -148,6 +166,20 @@ func configureAwsExpert() *huh.Group {
 			Placeholder("").
 			Validate(validateAwsEKSIAMRoles).
 			Value(&tmpEKSIAMRoles),
+		huh.NewInput().
+			Title("KMS Key ARN").
+			Description("(Optional) ARN of an existing customer-managed KMS key used to encrypt the state bucket, observability buckets, EFS, RDS and Vault. A new key is created if empty").
+			Placeholder("").
+			Validate(validateAwsKMSKeyARN).
+			Value(&input.AWS.KMSKeyARN),
+		huh.NewInput().
+			Title("DR Region").
+			Description("(Optional) Region of the warm standby for disaster recovery").
+			Placeholder("").
+			Validate(func(s string) error {
+				return validateAwsSecondaryRegion(input.AWS.Region, s)
+			}).
+			Value(&input.AWS.DRRegion),
 	).WithHideFunc(func() bool {
 		return input.Provider != "aws" || !flags.ConfigureAwsExpert
 	}).Title("Step 3b: (Optional) AWS Expert Configurations\n")
@@ -208,6 +240,24 @@ func configureOnPremExpert() *huh.Group {
 	}).Title("Step 3b: (Optional) On-Prem Expert Configurations\n")
 }
 
+func configureExistingCluster() *huh.Group {
+	return huh.NewGroup(
+		huh.NewInput().
+			Title("Kubeconfig Path").
+			Description("Path to the kubeconfig of the cluster the EMF will be deployed on.").
+			Placeholder("$HOME/.kube/config").
+			Validate(validateKubeConfigPath).
+			Value(&input.ExistingCluster.KubeConfigPath),
+		huh.NewInput().
+			Title("Storage Class").
+			Description("(Optional) Storage class of the volumes, the default storage class of the cluster if empty").
+			Placeholder("").
+			Value(&input.ExistingCluster.StorageClass),
+	).WithHideFunc(func() bool {
+		return input.Provider != "existing-cluster"
+	}).Title("Step 3a: Existing Cluster Configuration\n")
+}
+
 func confirmProxy() *huh.Group {
 	return huh.NewGroup(
 		huh.NewConfirm().
@@ -522,6 +572,7 @@ func orchInstallerForm() *huh.Form {
 		configureOnPremBasic(),
 		confirmOnPremExpert(),
 		configureOnPremExpert(),
+		configureExistingCluster(),
 		confirmProxy(),
 		configureProxy(),
 		confirmCert(),
@@ -115,6 +115,12 @@ func (s *OrchConfigFormTest) testConfigureAwsExpert() {
 	// Enter registry cache
 	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("registry-rs.edgeorchestrator.intel.com")})
 	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	// Enter docker username
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("docker-user")})
+	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	// Enter docker token
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("docker-token")})
+	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
 	// Enter just host whitelist
 	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10.0.0.0/8,192.168.0.0/16")})
 	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
@@ -124,6 +130,9 @@ func (s *OrchConfigFormTest) testConfigureAwsExpert() {
 	// Enter just host SSH private key path
 	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(tmpPrivKey.Name())})
 	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	// Enter load balancer whitelist
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("10.0.0.0/8")})
+	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
 	// Enter VPC ID
 	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("vpc-12345678")})
 	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
@@ -136,6 +145,12 @@ func (s *OrchConfigFormTest) testConfigureAwsExpert() {
 	// Enter EKS IAM role
 	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("developer_eks_role")})
 	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	// Enter KMS key ARN
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab")})
+	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
+	// Enter DR region
+	model.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("us-east-1")})
+	batchUpdate(model.Update(tea.KeyMsg{Type: tea.KeyEnter}))
 
 	view := ansi.Strip(model.View())
 	expected := "Step 4: (Optional) Proxy"
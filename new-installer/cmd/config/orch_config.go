@@ -47,6 +47,7 @@ var (
 	flags                flag
 	orchPackages         map[string]config.OrchPackage
 	tmpJumpHostWhitelist string
+	tmpLBWhitelist       string
 	tmpEKSIAMRoles       string
 	enabledSimple        []string
 	enabledAdvanced      []string
@@ -178,6 +179,7 @@ func saveConfig() {
 func preProcessConfig() {
 	// Convert slice to comma separated string
 	tmpJumpHostWhitelist = config.SliceToCommaSeparated(input.AWS.JumpHostWhitelist)
+	tmpLBWhitelist = config.SliceToCommaSeparated(input.AWS.LBWhitelist)
 	tmpEKSIAMRoles = config.SliceToCommaSeparated(input.AWS.EKSIAMRoles)
 }
 
@@ -198,6 +200,7 @@ func postProcessConfig() {
 
 	// Convert comma separated field into a slice
 	input.AWS.JumpHostWhitelist = config.CommaSeparatedToSlice(tmpJumpHostWhitelist)
+	input.AWS.LBWhitelist = config.CommaSeparatedToSlice(tmpLBWhitelist)
 	input.AWS.EKSIAMRoles = config.CommaSeparatedToSlice(tmpEKSIAMRoles)
 }
 
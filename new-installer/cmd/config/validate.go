@@ -6,7 +6,9 @@ package main
 
 import (
 	"fmt"
+	"net"
 	"os"
+	"path/filepath"
 	"regexp"
 	"slices"
 	"strconv"
@@ -22,9 +24,24 @@ func validateAll() error {
 	if err := validateAWSConfig(); err != nil {
 		return err
 	}
+	if err := validateOnpremConfig(); err != nil {
+		return err
+	}
+	if err := validateExistingClusterConfig(); err != nil {
+		return err
+	}
 	if err := validateProxyConfig(); err != nil {
 		return err
 	}
+	if err := validateArtifactSource(input.Artifacts.Bundle, input.Artifacts.BundlePublicKey, input.Artifacts.MirrorRegistry); err != nil {
+		return fmt.Errorf("invalid artifacts configuration: %w", err)
+	}
+	if err := validateSignaturePublicKey(input.Provider, input.Artifacts.Bundle, input.Artifacts.SignaturePublicKey); err != nil {
+		return fmt.Errorf("invalid artifacts configuration: %w", err)
+	}
+	if err := validateArtifactLocations(input.Artifacts.Registry, input.Artifacts.Username, input.Artifacts.Password, input.Artifacts.WorkDir); err != nil {
+		return fmt.Errorf("invalid artifacts configuration: %w", err)
+	}
 	if err := validateCertConfig(); err != nil {
 		return err
 	}
@@ -66,6 +83,9 @@ func validateAWSConfig() error {
 	if err := validateCacheRegistry(input.AWS.CacheRegistry); err != nil {
 		return fmt.Errorf("invalid cache registry: %w", err)
 	}
+	if err := validateDockerCredential(input.AWS.CacheRegistry, input.AWS.DockerUsername, input.AWS.DockerToken); err != nil {
+		return fmt.Errorf("invalid docker credential: %w", err)
+	}
 	if err := validateAwsJumpHostWhitelist(config.SliceToCommaSeparated(input.AWS.JumpHostWhitelist)); err != nil {
 		return fmt.Errorf("invalid AWS jump host whitelist: %w", err)
 	}
@@ -75,6 +95,9 @@ func validateAWSConfig() error {
 	if err := validateJumpHostPrivKeyPath(input.AWS.JumpHostPrivKeyPath); err != nil {
 		return fmt.Errorf("invalid AWS jump host private key path: %w", err)
 	}
+	if err := validateAwsLBWhitelist(config.SliceToCommaSeparated(input.AWS.LBWhitelist)); err != nil {
+		return fmt.Errorf("invalid AWS load balancer whitelist: %w", err)
+	}
 	if err := validateAwsVpcId(input.AWS.VPCID); err != nil {
 		return fmt.Errorf("invalid AWS VPC ID: %w", err)
 	}
@@ -84,6 +107,38 @@ func validateAWSConfig() error {
 	if err := validateAwsEKSIAMRoles(config.SliceToCommaSeparated(input.AWS.EKSIAMRoles)); err != nil {
 		return fmt.Errorf("invalid AWS EKS IAM roles: %w", err)
 	}
+	if err := validateAwsKMSKeyARN(input.AWS.KMSKeyARN); err != nil {
+		return fmt.Errorf("invalid AWS KMS key ARN: %w", err)
+	}
+	if err := validateO11yBuckets(input.AWS.O11yBuckets); err != nil {
+		return fmt.Errorf("invalid observability bucket lifecycle: %w", err)
+	}
+	if err := validateAwsSecondaryRegion(input.AWS.Region, input.AWS.O11yReplicationRegion); err != nil {
+		return fmt.Errorf("invalid observability replication region: %w", err)
+	}
+	if err := validateAwsSecondaryRegion(input.AWS.Region, input.AWS.DRRegion); err != nil {
+		return fmt.Errorf("invalid AWS DR region: %w", err)
+	}
+	return nil
+}
+
+func validateOnpremConfig() error {
+	if err := validateOnpremNodes(input.Onprem.ServerAddress, input.Onprem.Nodes); err != nil {
+		return fmt.Errorf("invalid on-prem nodes: %w", err)
+	}
+	if err := validateDeploymentProfile(input.Onprem.DeploymentProfile); err != nil {
+		return fmt.Errorf("invalid deployment profile: %w", err)
+	}
+	return nil
+}
+
+func validateExistingClusterConfig() error {
+	if err := validateKubeConfigPath(input.ExistingCluster.KubeConfigPath); err != nil {
+		return fmt.Errorf("invalid existing cluster kubeconfig: %w", err)
+	}
+	if err := validateDeploymentProfile(input.ExistingCluster.DeploymentProfile); err != nil {
+		return fmt.Errorf("invalid deployment profile: %w", err)
+	}
 	return nil
 }
 
@@ -200,6 +255,22 @@ func validateAwsCustomTag(s string) error {
 }
 
 func validateCacheRegistry(s string) error {
+	if strings.Contains(s, "://") {
+		return fmt.Errorf("cache registry must not contain a scheme, e.g., 'registry.example.com' or '%s'", config.ECRCacheRegistry)
+	}
+	if strings.ContainsAny(s, " \t") {
+		return fmt.Errorf("cache registry must not contain whitespace")
+	}
+	return nil
+}
+
+func validateDockerCredential(cacheRegistry, username, token string) error {
+	if (username == "") != (token == "") {
+		return fmt.Errorf("docker username and token must be set together")
+	}
+	if cacheRegistry == config.ECRCacheRegistry && username == "" {
+		return fmt.Errorf("docker username and token are required by the ECR pull-through cache")
+	}
 	return nil
 }
 
@@ -207,6 +278,22 @@ func validateAwsJumpHostWhitelist(s string) error {
 	return nil
 }
 
+func validateAwsLBWhitelist(s string) error {
+	if s == "" {
+		return nil
+	}
+	for _, cidr := range strings.Split(s, ",") {
+		cidr = strings.TrimSpace(cidr)
+		if cidr == "" {
+			continue
+		}
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			return fmt.Errorf("invalid CIDR: %s", cidr)
+		}
+	}
+	return nil
+}
+
 func validateAwsVpcId(s string) error {
 	if s == "" {
 		return nil
@@ -393,6 +480,16 @@ func validateJumpHostPrivKeyPath(s string) error {
 	return nil
 }
 
+func validateKubeConfigPath(s string) error {
+	if s == "" {
+		return nil
+	}
+	if _, err := os.Stat(os.ExpandEnv(s)); err != nil {
+		return fmt.Errorf("kubeconfig file does not exist: %w", err)
+	}
+	return nil
+}
+
 func validateAwsEKSIAMRoles(s string) error {
 	if s == "" {
 		return nil
@@ -409,3 +506,165 @@ func validateAwsEKSIAMRoles(s string) error {
 	}
 	return nil
 }
+
+func validateO11yBuckets(buckets map[string]config.ObservabilityBucketConfig) error {
+	for name, bucket := range buckets {
+		if !slices.Contains(config.ObservabilityBucketNames, name) {
+			return fmt.Errorf("unknown bucket %s, must be one of %s", name, strings.Join(config.ObservabilityBucketNames, ", "))
+		}
+		if bucket.ExpirationDays < 0 || bucket.InfrequentAccessDays < 0 || bucket.GlacierDays < 0 || bucket.ObjectLockDays < 0 {
+			return fmt.Errorf("bucket %s: days must not be negative", name)
+		}
+		// S3 requires objects to be stored for 30 days before moving to Standard-IA
+		if bucket.InfrequentAccessDays > 0 && bucket.InfrequentAccessDays < 30 {
+			return fmt.Errorf("bucket %s: infrequentAccessDays must be at least 30", name)
+		}
+		if bucket.GlacierDays > 0 && bucket.InfrequentAccessDays > 0 && bucket.GlacierDays < bucket.InfrequentAccessDays+30 {
+			return fmt.Errorf("bucket %s: glacierDays must be at least 30 days after infrequentAccessDays", name)
+		}
+		lastTransition := max(bucket.InfrequentAccessDays, bucket.GlacierDays)
+		if bucket.ExpirationDays > 0 && bucket.ExpirationDays <= lastTransition {
+			return fmt.Errorf("bucket %s: expirationDays must be after all transitions", name)
+		}
+		switch bucket.ObjectLockMode {
+		case "":
+			if bucket.ObjectLockDays != 0 {
+				return fmt.Errorf("bucket %s: objectLockDays requires objectLockMode", name)
+			}
+		case "GOVERNANCE", "COMPLIANCE":
+			if bucket.ObjectLockDays == 0 {
+				return fmt.Errorf("bucket %s: objectLockDays is required with objectLockMode", name)
+			}
+			// Expired objects are kept as noncurrent versions deleted after a day, which the lock forbids
+			if bucket.ExpirationDays > 0 {
+				return fmt.Errorf("bucket %s: set either expirationDays or objectLockMode, not both", name)
+			}
+		default:
+			return fmt.Errorf("bucket %s: objectLockMode must be GOVERNANCE or COMPLIANCE", name)
+		}
+	}
+	return nil
+}
+
+func validateAwsSecondaryRegion(region, secondaryRegion string) error {
+	if secondaryRegion == "" {
+		return nil
+	}
+	if err := validateAwsRegion(secondaryRegion); err != nil {
+		return err
+	}
+	if secondaryRegion == region {
+		return fmt.Errorf("must differ from the deployment region %s", region)
+	}
+	return nil
+}
+
+func validateAwsKMSKeyARN(s string) error {
+	if s == "" {
+		return nil
+	}
+	// Aliases are not accepted since the key policy and grants need the key itself
+	if matched := regexp.MustCompile(`^arn:aws[a-z-]*:kms:[a-z0-9-]+:\d{12}:key/[a-zA-Z0-9-]+$`).MatchString(s); !matched {
+		return fmt.Errorf("must be a KMS key ARN, e.g. arn:aws:kms:us-west-2:123456789012:key/<key-id>")
+	}
+	return nil
+}
+
+func validateDeploymentProfile(s string) error {
+	if s == "" {
+		return nil
+	}
+	// The profile names a values file in orch-configs/clusters of the deployment repo
+	if matched := regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`).MatchString(s); !matched {
+		return fmt.Errorf("must be a cluster profile name, e.g. onprem or onprem-1k")
+	}
+	return nil
+}
+
+func validateArtifactSource(bundle, publicKey, mirrorRegistry string) error {
+	if bundle != "" && mirrorRegistry != "" {
+		return fmt.Errorf("bundle and mirror registry cannot both be set")
+	}
+	if bundle == "" {
+		return nil
+	}
+	if _, err := os.Stat(bundle); err != nil {
+		return fmt.Errorf("bundle %s does not exist", bundle)
+	}
+	// The bundle is only used once its signature is verified
+	if publicKey == "" {
+		return fmt.Errorf("bundle public key is required with a bundle")
+	}
+	if _, err := os.Stat(publicKey); err != nil {
+		return fmt.Errorf("bundle public key %s does not exist", publicKey)
+	}
+	return nil
+}
+
+func validateSignaturePublicKey(provider, bundle, publicKey string) error {
+	if publicKey == "" {
+		// Artifacts from a bundle are covered by the signature of the bundle
+		if (provider == "onprem" || provider == "existing-cluster") && bundle == "" {
+			return fmt.Errorf("signature public key is required to verify the artifacts of %s installs", provider)
+		}
+		return nil
+	}
+	if _, err := os.Stat(publicKey); err != nil {
+		return fmt.Errorf("signature public key %s does not exist", publicKey)
+	}
+	return nil
+}
+
+func validateArtifactLocations(registry, username, password, workDir string) error {
+	if strings.Contains(registry, "://") || strings.Contains(registry, "/") {
+		return fmt.Errorf("registry must be a host with an optional port, without scheme or path")
+	}
+	if (username == "") != (password == "") {
+		return fmt.Errorf("registry username and password must be set together")
+	}
+	if workDir != "" && !filepath.IsAbs(workDir) {
+		return fmt.Errorf("work directory must be an absolute path")
+	}
+	return nil
+}
+
+func validateOnpremNodes(serverAddress string, nodes []config.OnpremNode) error {
+	if len(nodes) == 0 {
+		return nil
+	}
+	if err := validateIP(serverAddress); err != nil {
+		return fmt.Errorf("server address is required to join additional nodes: %w", err)
+	}
+	addresses := map[string]bool{serverAddress: true}
+	servers := 1
+	for _, node := range nodes {
+		if err := validateIP(node.Address); err != nil {
+			return fmt.Errorf("node %s: %w", node.Address, err)
+		}
+		if addresses[node.Address] {
+			return fmt.Errorf("node %s: duplicate address", node.Address)
+		}
+		addresses[node.Address] = true
+		switch node.Role {
+		case config.OnpremNodeRoleServer:
+			servers++
+		case config.OnpremNodeRoleAgent:
+		default:
+			return fmt.Errorf("node %s: role must be %s or %s", node.Address, config.OnpremNodeRoleServer, config.OnpremNodeRoleAgent)
+		}
+		if node.SSHUser == "" {
+			return fmt.Errorf("node %s: SSH user cannot be empty", node.Address)
+		}
+		if node.SSHKeyPath == "" {
+			return fmt.Errorf("node %s: SSH key path cannot be empty", node.Address)
+		}
+		if _, err := os.Stat(os.ExpandEnv(node.SSHKeyPath)); err != nil {
+			return fmt.Errorf("node %s: SSH key file does not exist: %w", node.Address, err)
+		}
+	}
+	// etcd loses quorum when half of the servers are down, an even number adds no redundancy
+	if servers%2 == 0 {
+		return fmt.Errorf("the cluster must have an odd number of servers, including this machine, got %d", servers)
+	}
+	return nil
+}
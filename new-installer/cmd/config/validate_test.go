@@ -6,6 +6,7 @@ package main
 
 import (
 	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -42,14 +43,22 @@ func (s *OrchConfigValidationTest) TestValidateAll() {
 			Region                string   `yaml:"region"`
 			CustomerTag           string   `yaml:"customerTag,omitempty"`
 			CacheRegistry         string   `yaml:"cacheRegistry,omitempty"`
+			DockerUsername        string   `yaml:"dockerUsername,omitempty"`
+			DockerToken           string   `yaml:"dockerToken,omitempty"`
 			JumpHostWhitelist     []string `yaml:"jumpHostWhitelist,omitempty"`
 			JumpHostIP            string   `yaml:"jumpHostIP,omitempty"`
 			JumpHostPrivKeyPath   string   `yaml:"jumpHostPrivKeyPath,omitempty"`
+			LBWhitelist           []string `yaml:"lbWhitelist,omitempty"`
 			VPCID                 string   `yaml:"vpcID,omitempty"`
 			ReduceNSTTL           bool     `yaml:"reduceNSTTL,omitempty"` // TODO: do we need this?
 			EKSDNSIP              string   `yaml:"eksDNSIP,omitempty"`    // TODO: do we need this?
 			EKSIAMRoles           []string `yaml:"eksIAMRoles,omitempty"`
+			KMSKeyARN             string   `yaml:"kmsKeyARN,omitempty"`
 			PreviousS3StateBucket string   `yaml:"previousS3StateBucket,omitempty"`
+
+			O11yBuckets           map[string]config.ObservabilityBucketConfig `yaml:"o11yBuckets,omitempty"`
+			O11yReplicationRegion string                                      `yaml:"o11yReplicationRegion,omitempty"`
+			DRRegion              string                                      `yaml:"drRegion,omitempty"`
 		}{
 			Region: "us-west-2",
 		},
@@ -502,6 +511,21 @@ func (s *OrchConfigValidationTest) TestValidateCacheRegistry() {
 			input:   "192.168.1.100:5000",
 			wantErr: false,
 		},
+		{
+			name:    "ECR pull-through cache",
+			input:   "ecr",
+			wantErr: false,
+		},
+		{
+			name:    "registry with scheme",
+			input:   "https://myregistry.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "registry with whitespace",
+			input:   "myregistry example.com",
+			wantErr: true,
+		},
 	}
 
 	for _, tt := range tests {
@@ -516,6 +540,55 @@ func (s *OrchConfigValidationTest) TestValidateCacheRegistry() {
 	}
 }
 
+func (s *OrchConfigValidationTest) TestValidateDockerCredential() {
+	tests := []struct {
+		name          string
+		cacheRegistry string
+		username      string
+		token         string
+		wantErr       bool
+	}{
+		{
+			name:    "no credential",
+			wantErr: false,
+		},
+		{
+			name:     "username and token",
+			username: "user",
+			token:    "token",
+			wantErr:  false,
+		},
+		{
+			name:     "username without token",
+			username: "user",
+			wantErr:  true,
+		},
+		{
+			name:          "ECR cache with credential",
+			cacheRegistry: "ecr",
+			username:      "user",
+			token:         "token",
+			wantErr:       false,
+		},
+		{
+			name:          "ECR cache without credential",
+			cacheRegistry: "ecr",
+			wantErr:       true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateDockerCredential(tt.cacheRegistry, tt.username, tt.token)
+			if tt.wantErr {
+				s.Require().Error(err, "expected an error but got nil")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
 func (s *OrchConfigValidationTest) TestValidateAwsJumpHostWhitelist() {
 	tests := []struct {
 		name    string
@@ -576,6 +649,51 @@ func (s *OrchConfigValidationTest) TestValidateAwsJumpHostWhitelist() {
 	}
 }
 
+func (s *OrchConfigValidationTest) TestValidateAwsLBWhitelist() {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "empty string",
+			input:   "",
+			wantErr: false,
+		},
+		{
+			name:    "single CIDR",
+			input:   "10.0.0.0/8",
+			wantErr: false,
+		},
+		{
+			name:    "multiple CIDRs with spaces",
+			input:   " 10.0.0.0/8 , 192.168.0.0/16 ",
+			wantErr: false,
+		},
+		{
+			name:    "IP without mask",
+			input:   "192.168.1.1",
+			wantErr: true,
+		},
+		{
+			name:    "hostname",
+			input:   "lb.example.com",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateAwsLBWhitelist(tt.input)
+			if tt.wantErr {
+				s.Require().Error(err, "expected an error but got nil")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
 func (s *OrchConfigValidationTest) TestValidateAwsVpcId() {
 	tests := []struct {
 		name    string
@@ -1521,6 +1639,385 @@ func (s *OrchConfigValidationTest) TestValidateAwsEKSIAMRoles() {
 	}
 }
 
+func (s *OrchConfigValidationTest) TestValidateAwsKMSKeyARN() {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "empty string",
+			input:   "",
+			wantErr: false,
+		},
+		{
+			name:    "valid key ARN",
+			input:   "arn:aws:kms:us-west-2:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantErr: false,
+		},
+		{
+			name:    "valid GovCloud key ARN",
+			input:   "arn:aws-us-gov:kms:us-gov-west-1:123456789012:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantErr: false,
+		},
+		{
+			name:    "alias ARN",
+			input:   "arn:aws:kms:us-west-2:123456789012:alias/my-key",
+			wantErr: true,
+		},
+		{
+			name:    "key ID only",
+			input:   "1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantErr: true,
+		},
+		{
+			name:    "invalid account ID",
+			input:   "arn:aws:kms:us-west-2:1234:key/1234abcd-12ab-34cd-56ef-1234567890ab",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateAwsKMSKeyARN(tt.input)
+			if tt.wantErr {
+				s.Error(err, "expected an error but got nil")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateDeploymentProfile() {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{
+			name:    "empty string",
+			input:   "",
+			wantErr: false,
+		},
+		{
+			name:    "valid profile",
+			input:   "onprem",
+			wantErr: false,
+		},
+		{
+			name:    "valid profile with dashes",
+			input:   "onprem-explicit-proxy",
+			wantErr: false,
+		},
+		{
+			name:    "file name",
+			input:   "onprem.yaml",
+			wantErr: true,
+		},
+		{
+			name:    "path",
+			input:   "../onprem",
+			wantErr: true,
+		},
+		{
+			name:    "uppercase",
+			input:   "OnPrem",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateDeploymentProfile(tt.input)
+			if tt.wantErr {
+				s.Error(err, "expected an error but got nil")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateArtifactSource() {
+	dir := s.T().TempDir()
+	bundle := filepath.Join(dir, "orch-bundle.tar")
+	publicKey := filepath.Join(dir, "bundle.pub")
+	s.Require().NoError(os.WriteFile(bundle, []byte{}, 0o600))
+	s.Require().NoError(os.WriteFile(publicKey, []byte{}, 0o600))
+
+	tests := []struct {
+		name           string
+		bundle         string
+		publicKey      string
+		mirrorRegistry string
+		wantErr        bool
+	}{
+		{
+			name: "release service",
+		},
+		{
+			name:      "bundle",
+			bundle:    bundle,
+			publicKey: publicKey,
+		},
+		{
+			name:           "mirror registry",
+			mirrorRegistry: "registry.example.com:5000",
+		},
+		{
+			name:           "bundle and mirror registry",
+			bundle:         bundle,
+			publicKey:      publicKey,
+			mirrorRegistry: "registry.example.com:5000",
+			wantErr:        true,
+		},
+		{
+			name:    "bundle without public key",
+			bundle:  bundle,
+			wantErr: true,
+		},
+		{
+			name:      "missing bundle",
+			bundle:    filepath.Join(dir, "missing.tar"),
+			publicKey: publicKey,
+			wantErr:   true,
+		},
+		{
+			name:      "missing public key",
+			bundle:    bundle,
+			publicKey: filepath.Join(dir, "missing.pub"),
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateArtifactSource(tt.bundle, tt.publicKey, tt.mirrorRegistry)
+			if tt.wantErr {
+				s.Error(err, "expected error")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateSignaturePublicKey() {
+	publicKey := filepath.Join(s.T().TempDir(), "cosign.pub")
+	s.Require().NoError(os.WriteFile(publicKey, []byte{}, 0o600))
+
+	tests := []struct {
+		name      string
+		provider  string
+		bundle    string
+		publicKey string
+		wantErr   bool
+	}{
+		{
+			name:     "aws",
+			provider: "aws",
+		},
+		{
+			name:      "onprem",
+			provider:  "onprem",
+			publicKey: publicKey,
+		},
+		{
+			name:     "onprem with bundle",
+			provider: "onprem",
+			bundle:   "orch-bundle.tar",
+		},
+		{
+			name:     "onprem without public key",
+			provider: "onprem",
+			wantErr:  true,
+		},
+		{
+			name:      "missing public key",
+			provider:  "onprem",
+			publicKey: publicKey + ".missing",
+			wantErr:   true,
+		},
+		{
+			name:     "existing cluster without public key",
+			provider: "existing-cluster",
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateSignaturePublicKey(tt.provider, tt.bundle, tt.publicKey)
+			if tt.wantErr {
+				s.Error(err, "expected error")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateKubeConfigPath() {
+	kubeConfig := filepath.Join(s.T().TempDir(), "kubeconfig")
+	s.Require().NoError(os.WriteFile(kubeConfig, []byte("apiVersion: v1\nkind: Config\n"), 0o600))
+
+	s.NoError(validateKubeConfigPath(""))
+	s.NoError(validateKubeConfigPath(kubeConfig))
+	s.T().Setenv("TEST_KUBECONFIG", kubeConfig)
+	s.NoError(validateKubeConfigPath("$TEST_KUBECONFIG"))
+	s.ErrorContains(validateKubeConfigPath(kubeConfig+".missing"), "kubeconfig file does not exist")
+}
+
+func (s *OrchConfigValidationTest) TestValidateArtifactLocations() {
+	tests := []struct {
+		name     string
+		registry string
+		username string
+		password string
+		workDir  string
+		wantErr  bool
+	}{
+		{
+			name: "defaults",
+		},
+		{
+			name:     "registry with credentials",
+			registry: "registry.example.com:5000",
+			username: "orch",
+			password: "secret",
+			workDir:  "/var/lib/orch",
+		},
+		{
+			name:     "registry with scheme",
+			registry: "https://registry.example.com",
+			wantErr:  true,
+		},
+		{
+			name:     "registry with path",
+			registry: "registry.example.com/edge-orch",
+			wantErr:  true,
+		},
+		{
+			name:     "username without password",
+			username: "orch",
+			wantErr:  true,
+		},
+		{
+			name:    "relative work directory",
+			workDir: "orch",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateArtifactLocations(tt.registry, tt.username, tt.password, tt.workDir)
+			if tt.wantErr {
+				s.Error(err, "expected error")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateO11yBuckets() {
+	tests := []struct {
+		name    string
+		input   map[string]config.ObservabilityBucketConfig
+		wantErr bool
+	}{
+		{
+			name:    "empty",
+			input:   nil,
+			wantErr: false,
+		},
+		{
+			name: "valid tiering and retention",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-chunks": {InfrequentAccessDays: 30, GlacierDays: 90, ExpirationDays: 365},
+				"tempo-traces":     {ExpirationDays: 14},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid object lock",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-admin": {ObjectLockMode: "COMPLIANCE", ObjectLockDays: 365},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown bucket",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-unknown": {ExpirationDays: 30},
+			},
+			wantErr: true,
+		},
+		{
+			name: "infrequent access too early",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-chunks": {InfrequentAccessDays: 7},
+			},
+			wantErr: true,
+		},
+		{
+			name: "glacier too close to infrequent access",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-chunks": {InfrequentAccessDays: 30, GlacierDays: 45},
+			},
+			wantErr: true,
+		},
+		{
+			name: "expiration before transition",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-chunks": {GlacierDays: 90, ExpirationDays: 60},
+			},
+			wantErr: true,
+		},
+		{
+			name: "invalid object lock mode",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-admin": {ObjectLockMode: "LEGAL", ObjectLockDays: 30},
+			},
+			wantErr: true,
+		},
+		{
+			name: "object lock with expiration",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-admin": {ObjectLockMode: "GOVERNANCE", ObjectLockDays: 30, ExpirationDays: 60},
+			},
+			wantErr: true,
+		},
+		{
+			name: "object lock days without mode",
+			input: map[string]config.ObservabilityBucketConfig{
+				"orch-loki-admin": {ObjectLockDays: 30},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		s.Run(tt.name, func() {
+			err := validateO11yBuckets(tt.input)
+			if tt.wantErr {
+				s.Error(err, "expected an error but got nil")
+			} else {
+				s.NoError(err, "expected no error")
+			}
+		})
+	}
+}
+
+func (s *OrchConfigValidationTest) TestValidateAwsSecondaryRegion() {
+	s.NoError(validateAwsSecondaryRegion("us-west-2", ""))
+	s.NoError(validateAwsSecondaryRegion("us-west-2", "us-east-1"))
+	s.Error(validateAwsSecondaryRegion("us-west-2", "us-west-2"))
+	s.Error(validateAwsSecondaryRegion("us-west-2", "useast1"))
+}
+
 func (s *OrchConfigValidationTest) TestValidateJumpHostPrivKeyPath() {
 	tmpFile, err := os.CreateTemp("", "privkey")
 	s.Require().NoError(err, "Failed to create temp file")
@@ -1755,3 +2252,81 @@ func TestValidateJumpHostPrivKeyPath(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateOnpremNodes(t *testing.T) {
+	keyFile, err := os.CreateTemp(t.TempDir(), "sshkey")
+	if err != nil {
+		t.Fatalf("failed to create temp file: %v", err)
+	}
+	node := func(address, role string) config.OnpremNode {
+		return config.OnpremNode{Address: address, Role: role, SSHUser: "ubuntu", SSHKeyPath: keyFile.Name()}
+	}
+
+	tests := []struct {
+		name          string
+		serverAddress string
+		nodes         []config.OnpremNode
+		errMsg        string
+	}{
+		{
+			name: "single node",
+		},
+		{
+			name:          "three servers and an agent",
+			serverAddress: "192.168.1.10",
+			nodes: []config.OnpremNode{
+				node("192.168.1.11", config.OnpremNodeRoleServer),
+				node("192.168.1.12", config.OnpremNodeRoleServer),
+				node("192.168.1.13", config.OnpremNodeRoleAgent),
+			},
+		},
+		{
+			name:   "missing server address",
+			nodes:  []config.OnpremNode{node("192.168.1.11", config.OnpremNodeRoleAgent)},
+			errMsg: "server address is required",
+		},
+		{
+			name:          "even number of servers",
+			serverAddress: "192.168.1.10",
+			nodes:         []config.OnpremNode{node("192.168.1.11", config.OnpremNodeRoleServer)},
+			errMsg:        "odd number of servers",
+		},
+		{
+			name:          "duplicate address",
+			serverAddress: "192.168.1.10",
+			nodes:         []config.OnpremNode{node("192.168.1.10", config.OnpremNodeRoleAgent)},
+			errMsg:        "duplicate address",
+		},
+		{
+			name:          "invalid role",
+			serverAddress: "192.168.1.10",
+			nodes:         []config.OnpremNode{node("192.168.1.11", "worker")},
+			errMsg:        "role must be server or agent",
+		},
+		{
+			name:          "missing SSH user",
+			serverAddress: "192.168.1.10",
+			nodes:         []config.OnpremNode{{Address: "192.168.1.11", Role: config.OnpremNodeRoleAgent, SSHKeyPath: keyFile.Name()}},
+			errMsg:        "SSH user cannot be empty",
+		},
+		{
+			name:          "missing SSH key",
+			serverAddress: "192.168.1.10",
+			nodes:         []config.OnpremNode{{Address: "192.168.1.11", Role: config.OnpremNodeRoleAgent, SSHUser: "ubuntu", SSHKeyPath: "/tmp/this_file_should_not_exist_123456789"}},
+			errMsg:        "SSH key file does not exist",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := validateOnpremNodes(tt.serverAddress, tt.nodes)
+			if tt.errMsg == "" {
+				if err != nil {
+					t.Errorf("expected no error, got %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.errMsg) {
+				t.Errorf("expected error message to contain %q, got %v", tt.errMsg, err)
+			}
+		})
+	}
+}
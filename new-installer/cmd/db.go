@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func newDBCommand(configFile, runtimeStateFile *string, logOptions *internal.LogOptions, keepGeneratedFiles *bool) *cobra.Command {
+	dbCmd := &cobra.Command{
+		Use:   "db",
+		Short: "Manage the orchestrator database",
+		Long:  "Manage the orchestrator database (AWS only)",
+	}
+
+	var snapshot, restoreTime string
+	restoreCmd := &cobra.Command{
+		Use:   "restore",
+		Short: "Restore the RDS cluster from a snapshot or a point in time",
+		Long: "Restore the RDS cluster from a named cluster snapshot, e.g. the one taken before the last upgrade, " +
+			"or from a point in time within the backup retention period. The cluster is replaced and keeps its endpoints.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			restoreDB(*configFile, *runtimeStateFile, logOptions.Dir, *keepGeneratedFiles, snapshot, restoreTime)
+		},
+	}
+	restoreCmd.Flags().StringVar(&snapshot, "snapshot", "", "Identifier of the cluster snapshot to restore from")
+	restoreCmd.Flags().StringVar(&restoreTime, "time", "", "Point in time to restore to, in RFC3339 format, e.g. 2025-01-02T15:04:05Z")
+	restoreCmd.MarkFlagsMutuallyExclusive("snapshot", "time")
+	restoreCmd.MarkFlagsOneRequired("snapshot", "time")
+
+	dbCmd.AddCommand(restoreCmd)
+	return dbCmd
+}
+
+func restoreDB(configFile, runtimeStateFile, logDir string, keepGeneratedFiles bool, snapshot, restoreTimeStr string) {
+	logger := zap.S()
+	orchConfigReaderWriter, orchConfig, runtimeState := readAWSConfigAndRuntimeState(configFile, runtimeStateFile)
+
+	var restoreTime time.Time
+	if restoreTimeStr != "" {
+		var err error
+		restoreTime, err = time.Parse(time.RFC3339, restoreTimeStr)
+		if err != nil {
+			logger.Fatalf("error parsing restore time %s: %s", restoreTimeStr, err)
+		}
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		logger.Fatalf("error getting current directory: %s", err)
+	}
+	tfUtil, err := steps.CreateTerraformUtility(currentDir)
+	if err != nil {
+		logger.Fatalf("error creating Terraform utility: %s", err)
+	}
+	rdsStep := steps_aws.CreateRDSStep(currentDir, keepGeneratedFiles, tfUtil, steps_aws.CreateAWSUtility())
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	runtimeState.LogDir = logDir
+	runtimeState, restoreErr := rdsStep.RestoreRDS(ctx, orchConfig, runtimeState, snapshot, restoreTime)
+	if rsWriteErr := orchConfigReaderWriter.WriteRuntimeState(runtimeState); rsWriteErr != nil {
+		logger.Errorf("error writing runtime state file: %s", rsWriteErr)
+	}
+	if restoreErr != nil {
+		logger.Errorf("error restoring database: %s", restoreErr)
+		showActionsForError(restoreErr)
+		os.Exit(1)
+	}
+	logger.Infof("Database restored from snapshot %s", runtimeState.Database.RestoreSnapshotIdentifier)
+}
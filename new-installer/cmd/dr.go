@@ -0,0 +1,95 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func newDRCommand(configFile, runtimeStateFile *string, logOptions *internal.LogOptions) *cobra.Command {
+	drCmd := &cobra.Command{
+		Use:   "dr",
+		Short: "Manage the disaster-recovery standby",
+		Long:  "Check and promote the warm standby in the DR region (AWS only)",
+	}
+
+	statusCmd := &cobra.Command{
+		Use:   "status",
+		Short: "Report the state of the DR standby",
+		Long:  "Report the members of the RDS global cluster and the replica of the state bucket, as seen from the DR region",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			drStatus(*configFile, *runtimeStateFile)
+		},
+	}
+
+	var allowDataLoss bool
+	failoverCmd := &cobra.Command{
+		Use:   "failover",
+		Short: "Promote the DR standby",
+		Long: "Promote the standby RDS cluster to the writer of the global cluster and point the runtime state to it. " +
+			"A switchover without data loss is performed unless --allow-data-loss is set, which is required when the primary region is unavailable.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			failoverDR(*configFile, *runtimeStateFile, allowDataLoss)
+		},
+	}
+	failoverCmd.Flags().BoolVar(&allowDataLoss, "allow-data-loss", false, "Fail over even if the standby has not caught up with the primary")
+
+	drCmd.AddCommand(statusCmd, failoverCmd)
+	return drCmd
+}
+
+func drStatus(configFile, runtimeStateFile string) {
+	logger := zap.S()
+	_, _, runtimeState := readAWSConfigAndRuntimeState(configFile, runtimeStateFile)
+
+	status, statusErr := steps_aws.GetDRStatus(runtimeState, steps_aws.CreateAWSUtility())
+	fmt.Printf("%-16s %s\n", "DR region", status.Region)
+	fmt.Printf("%-16s %s\n", "State bucket", status.StateBucket)
+	fmt.Printf("%-16s %s (%s)\n", "Global cluster", status.GlobalClusterID, status.GlobalClusterStatus)
+	for _, member := range status.Members {
+		role := "reader"
+		if member.IsWriter {
+			role = "writer"
+		}
+		fmt.Printf("%-16s %s %s %s\n", "", member.ClusterArn, role, member.SynchronizationStatus)
+	}
+	fmt.Printf("%-16s %t\n", "Failed over", status.FailedOver)
+	if statusErr != nil {
+		logger.Errorf("error getting DR status: %s", statusErr)
+		showActionsForError(statusErr)
+		os.Exit(1)
+	}
+}
+
+func failoverDR(configFile, runtimeStateFile string, allowDataLoss bool) {
+	logger := zap.S()
+	orchConfigReaderWriter, _, runtimeState := readAWSConfigAndRuntimeState(configFile, runtimeStateFile)
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultTimeout)
+	defer cancel()
+	runtimeState, failoverErr := steps_aws.FailoverDR(ctx, runtimeState, steps_aws.CreateAWSUtility(), allowDataLoss)
+	if failoverErr != nil {
+		logger.Errorf("error failing over to DR region: %s", failoverErr)
+		showActionsForError(failoverErr)
+		os.Exit(1)
+	}
+	if err := orchConfigReaderWriter.WriteRuntimeState(runtimeState); err != nil {
+		logger.Fatalf("error writing runtime state file: %s", err)
+	}
+	logger.Infof("Failed over to %s, database endpoint is now %s", runtimeState.AWS.DR.Region, runtimeState.Database.Host)
+}
@@ -0,0 +1,107 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+func newListTargetsCommand(configFile, targetExpressions *string, logOptions *internal.LogOptions) *cobra.Command {
+	var provider string
+	listTargetsCmd := &cobra.Command{
+		Use:   "list-targets",
+		Short: "List the stages and steps --target can select",
+		Long: "List the stages and steps of the provider of the config, or of --provider, with their labels. " +
+			"With --target, the stages and steps it selects are marked.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			listTargets(*configFile, provider, *targetExpressions)
+		},
+	}
+	listTargetsCmd.Flags().StringVar(&provider, "provider", "", "List the default stages of this provider instead of the stages of the config")
+	return listTargetsCmd
+}
+
+func listTargets(configFile, provider, targetExpressions string) {
+	logger := zap.S()
+	sel, err := selector.Parse(config.CommaSeparatedToSlice(targetExpressions))
+	if err != nil {
+		logger.Fatalf("error parsing --target: %s", err)
+	}
+
+	orchConfigReaderWriter := &config.FileBaseOrchConfigReaderWriter{OrchConfigFilePath: configFile}
+	orchConfig := config.OrchInstallerConfig{}
+	if provider == "" {
+		orchConfig, err = orchConfigReaderWriter.ReadOrchConfig()
+		if err != nil {
+			logger.Fatalf("error reading config file %s: %s", configFile, err)
+		}
+	} else {
+		if _, statErr := os.Stat(configFile); !errors.Is(statErr, fs.ErrNotExist) {
+			logger.Warnf("ignoring the stages of config file %s, listing the default stages of provider %s", configFile, provider)
+		}
+		orchConfig.Provider = provider
+	}
+
+	currentDir, err := os.Getwd()
+	if err != nil {
+		logger.Fatalf("error getting current directory: %s", err)
+	}
+	stages, err := targets.CreateStages(orchConfig, targets.Environment{
+		RootPath:               currentDir,
+		OrchConfigReaderWriter: orchConfigReaderWriter,
+	})
+	if err != nil {
+		logger.Fatalf("error creating stages for provider %s: %s", orchConfig.Provider, err)
+	}
+
+	// Marks what --target selects, when it is given
+	mark := func(selected bool) string {
+		if sel.Empty() || !selected {
+			return " "
+		}
+		return "*"
+	}
+	fmt.Printf("Provider %s\n", orchConfig.Provider)
+	for _, stage := range stages {
+		stageSelected := len(internal.FilterStages([]internal.OrchInstallerStage{stage}, sel)) > 0
+		fmt.Printf("%s %-30s %s\n", mark(stageSelected), stage.Name(), strings.Join(stage.Labels(), ", "))
+		withSteps, ok := stage.(internal.StageWithSteps)
+		if !ok {
+			continue
+		}
+		for _, step := range withSteps.StepTargets() {
+			fmt.Printf("%s   %-28s %s\n", mark(sel.Match(internal.StageTarget(stage), step)), step.Name, strings.Join(step.Labels, ", "))
+		}
+	}
+	if !sel.Empty() {
+		fmt.Printf("\n* selected by --target %s\n", sel)
+	}
+}
+
+// checkTargets validates the --target expressions and warns when they select nothing.
+func checkTargets(stages []internal.OrchInstallerStage, targetLabels []string) {
+	logger := zap.S()
+	sel, err := selector.Parse(targetLabels)
+	if err != nil {
+		logger.Fatalf("error parsing --target: %s", err)
+	}
+	if !sel.Empty() && len(internal.FilterStages(stages, sel)) == 0 {
+		logger.Warnf("--target %s selects no stage or step, see list-targets", sel)
+	}
+}
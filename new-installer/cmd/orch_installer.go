@@ -8,13 +8,17 @@ import (
 	"context"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
-	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws"
-	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/onprem"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/existingcluster"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/onprem"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 )
@@ -32,14 +36,24 @@ func main() {
 	}
 
 	// These flags are common to all commands
-	var configFile, runtimeStateFile, logLevel, logDir, targets string
-	var keepGeneratedFiles bool
+	var configFile, runtimeStateFile, targets string
+	var logOptions internal.LogOptions
+	var traceOptions tracing.Options
+	var keepGeneratedFiles, dryRun bool
 	rootCmd.PersistentFlags().StringVarP(&configFile, "config", "c", "config.yaml", "Path to the configuration file")
 	rootCmd.PersistentFlags().StringVarP(&runtimeStateFile, "runtime-state", "r", "config.yaml", "Path to the runtime state file")
-	rootCmd.PersistentFlags().StringVarP(&logLevel, "log-level", "l", "info", "Log level (debug, info, warn, error)")
-	rootCmd.PersistentFlags().StringVarP(&logDir, "log-dir", "o", ".logs", "Path to the log dir")
+	rootCmd.PersistentFlags().StringVarP(&logOptions.Level, "log-level", "l", "info", "Log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVarP(&logOptions.Dir, "log-dir", "o", ".logs", "Path to the log dir")
+	rootCmd.PersistentFlags().StringVar(&logOptions.Format, "log-format", internal.LogFormatConsole, "Log format (console, json)")
+	rootCmd.PersistentFlags().IntVar(&logOptions.MaxSizeMB, "log-max-size", 100, "Size in MB log files are rotated at")
+	rootCmd.PersistentFlags().IntVar(&logOptions.MaxBackups, "log-max-backups", 5, "Rotated log files to keep")
 	rootCmd.PersistentFlags().BoolVarP(&keepGeneratedFiles, "keep-generated-files", "k", false, "Keep generated files, such as Terraform backend config and variables files.")
-	rootCmd.PersistentFlags().StringVarP(&targets, "target", "t", "", "Only execute targets with this label")
+	rootCmd.PersistentFlags().StringVar(&traceOptions.Endpoint, "trace-endpoint", "", "OTLP/HTTP endpoint of the collector the traces are sent to, e.g. localhost:4318")
+	rootCmd.PersistentFlags().BoolVar(&traceOptions.Insecure, "trace-insecure", false, "Send the traces to the collector over plain HTTP")
+	rootCmd.PersistentFlags().StringVar(&traceOptions.File, "trace-file", "", "Path to a file the traces are written to, as JSON")
+	rootCmd.PersistentFlags().StringVarP(&targets, "target", "t", "",
+		"Only execute the stages and steps with these names or labels, comma separated: \"infra,!rds\" runs infra but RDS, \"infra+aws\" the steps with both labels, see list-targets")
+	rootCmd.PersistentFlags().BoolVar(&dryRun, "dry-run", false, "Report the changes without making them, supported by OSConfigStep and ArgoStep only, the other steps must be left out with --target")
 
 	commands := []struct {
 		use   string
@@ -57,22 +71,28 @@ func main() {
 			Short: cmd.short,
 			Long:  cmd.long,
 			Run: func(cmd *cobra.Command, args []string) {
-				err := internal.InitLogger(logLevel, logDir)
+				err := internal.InitLogger(logOptions)
 				if err != nil {
 					zap.S().Fatalf("error initializing logger: %s", err)
 				}
-				execute(cmd.Name(), configFile, runtimeStateFile, logDir, keepGeneratedFiles, targets)
+				execute(cmd.Name(), configFile, runtimeStateFile, logOptions.Dir, keepGeneratedFiles, dryRun, targets, traceOptions)
 			},
 		}
 		rootCmd.AddCommand(c)
 	}
+	rootCmd.AddCommand(newCertCommand(&configFile, &runtimeStateFile, &logOptions))
+	rootCmd.AddCommand(newDBCommand(&configFile, &runtimeStateFile, &logOptions, &keepGeneratedFiles))
+	rootCmd.AddCommand(newDRCommand(&configFile, &runtimeStateFile, &logOptions))
+	rootCmd.AddCommand(newBundleCommand(&logOptions))
+	rootCmd.AddCommand(newSupportBundleCommand(&configFile, &runtimeStateFile, &logOptions))
+	rootCmd.AddCommand(newListTargetsCommand(&configFile, &targets, &logOptions))
 	err := rootCmd.Execute()
 	if err != nil {
 		zap.S().Fatalf("error executing command: %s", err)
 	}
 }
 
-func execute(action string, orchConfigFile string, runtimeStateFile string, logDir string, keepGeneratedFiles bool, targets string) {
+func execute(action string, orchConfigFile string, runtimeStateFile string, logDir string, keepGeneratedFiles bool, dryRun bool, targetLabels string, traceOptions tracing.Options) {
 	logger := zap.S()
 	currentDir, err := os.Getwd()
 	if err != nil {
@@ -105,6 +125,7 @@ func execute(action string, orchConfigFile string, runtimeStateFile string, logD
 	if err != nil {
 		logger.Fatalf("error reading runtime state file: %s", err)
 	}
+	internal.RedactSecrets(config.Secrets(orchConfig, runtimeState)...)
 
 	if orchConfig.Version != config.UserConfigVersion {
 		logger.Fatalf("error: orchestrator config version %s does not match installer version %s", orchConfig.Version, config.UserConfigVersion)
@@ -113,7 +134,8 @@ func execute(action string, orchConfigFile string, runtimeStateFile string, logD
 
 	runtimeState.Action = action
 	runtimeState.LogDir = logDir
-	runtimeState.TargetLabels = config.CommaSeparatedToSlice(targets)
+	runtimeState.DryRun = dryRun
+	runtimeState.TargetLabels = config.CommaSeparatedToSlice(targetLabels)
 
 	logger.Infof("Action: %s", action)
 	logger.Infof("Target environment: %s", orchConfig.Provider)
@@ -124,23 +146,36 @@ func execute(action string, orchConfigFile string, runtimeStateFile string, logD
 		logger.Fatalf("error: orchestrator config version %s does not match installer version %d", orchConfig.Version, config.UserConfigVersion)
 	}
 
-	var stages []internal.OrchInstallerStage
-	switch orchConfig.Provider {
-	case "aws":
-		stages, err = aws.CreateAWSStages(currentDir, keepGeneratedFiles, &orchConfigReaderWriter)
-	case "onprem":
-		stages, err = onprem.CreateOnPremStages(currentDir, keepGeneratedFiles, &orchConfigReaderWriter)
-	default:
-		logger.Fatalf("error: target environment %s not supported", orchConfig.Provider)
-	}
+	stages, err := targets.CreateStages(orchConfig, targets.Environment{
+		RootPath:               currentDir,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		OrchConfigReaderWriter: &orchConfigReaderWriter,
+	})
 	if err != nil {
 		logger.Fatalf("error creating stages for provider %s: %s", orchConfig.Provider, err)
 	}
+	checkTargets(stages, runtimeState.TargetLabels)
 	orchInstaller, err := internal.CreateOrchInstaller(stages)
 	if err != nil {
 		logger.Fatalf("error creating orch installer: %s", err)
 	}
 
+	traceOptions.Version = strings.TrimSpace(string(installerVersion))
+	traceOptions.DeploymentID = runtimeState.DeploymentID
+	traceOptions.Provider = orchConfig.Provider
+	shutdownTracing, err := tracing.Init(context.Background(), traceOptions)
+	if err != nil {
+		logger.Fatalf("error initializing tracing: %s", err)
+	}
+	defer func() {
+		// The spans are flushed even if the installation timed out
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := shutdownTracing(shutdownCtx); err != nil {
+			logger.Warnf("error flushing traces: %s", err)
+		}
+	}()
+
 	ctx, cancelFunc := context.WithTimeout(context.Background(), DefaultTimeout)
 	signal.Ignore(syscall.SIGINT, syscall.SIGTERM)
 	defer signal.Reset(syscall.SIGINT, syscall.SIGTERM)
@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package main
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/readiness"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/support"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+func newSupportBundleCommand(configFile, runtimeStateFile *string, logOptions *internal.LogOptions) *cobra.Command {
+	var outputDir string
+	var namespaces []string
+	var podLogLines int64
+	supportBundleCmd := &cobra.Command{
+		Use:   "support-bundle",
+		Short: "Collect diagnostics for support",
+		Long: "Collect the installer logs, the config and runtime state with their secrets redacted, the Terraform outputs, " +
+			"the nodes, pods, events, volume claims and ArgoCD applications of the cluster and the logs of selected pods " +
+			"into orch-support-<time>.tar.gz, with a manifest of its content.",
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := internal.InitLogger(*logOptions); err != nil {
+				zap.S().Fatalf("error initializing logger: %s", err)
+			}
+			collectSupportBundle(*configFile, *runtimeStateFile, logOptions.Dir, outputDir, support.Options{
+				PodLogNamespaces: namespaces,
+				PodLogLines:      podLogLines,
+			})
+		},
+	}
+	supportBundleCmd.Flags().StringVar(&outputDir, "output-dir", ".", "Directory the support bundle is written to")
+	supportBundleCmd.Flags().StringSliceVar(&namespaces, "namespace", support.DefaultPodLogNamespaces,
+		"Namespaces all pod logs are collected from, elsewhere only pods that are not running or restarted are")
+	supportBundleCmd.Flags().Int64Var(&podLogLines, "pod-log-lines", support.DefaultPodLogLines, "Lines at the end of each container log to collect")
+	return supportBundleCmd
+}
+
+// collectSupportBundle collects what it can: a broken config or unreachable cluster is exactly
+// when support needs the rest.
+func collectSupportBundle(configFile, runtimeStateFile, logDir, outputDir string, options support.Options) {
+	logger := zap.S()
+	options.LogDir = logDir
+	if version, err := os.ReadFile("VERSION"); err == nil {
+		options.Version = strings.TrimSpace(string(version))
+	}
+
+	orchConfigReaderWriter := config.FileBaseOrchConfigReaderWriter{
+		OrchConfigFilePath:   configFile,
+		RuntimeStateFilePath: runtimeStateFile,
+	}
+	orchConfig, configErr := orchConfigReaderWriter.ReadOrchConfig()
+	runtimeState, runtimeStateErr := orchConfigReaderWriter.ReadRuntimeState()
+	switch {
+	case configErr != nil:
+		logger.Warnf("Collecting without the config, error reading config file %s: %s", configFile, configErr)
+	case runtimeStateErr != nil:
+		logger.Warnf("Collecting without the config, error reading runtime state file: %s", runtimeStateErr)
+	default:
+		internal.RedactSecrets(config.Secrets(orchConfig, runtimeState)...)
+		options.Config = &orchConfig
+		options.RuntimeState = &runtimeState
+	}
+
+	if options.Config != nil {
+		kubeConfig := common.ClusterKubeConfig(orchConfig, runtimeState)
+		if kubeConfig == "" {
+			logger.Warn("Collecting without the cluster resources, the kubeconfig is not set in the runtime state")
+		} else if err := createSupportClients(kubeConfig, &options); err != nil {
+			logger.Warnf("Collecting without the cluster resources: %s", err)
+		}
+	}
+
+	output, err := support.Collect(context.Background(), outputDir, options)
+	if err != nil {
+		logger.Fatalf("error collecting support bundle: %s", err)
+	}
+	logger.Infof("Support bundle written to %s", output)
+}
+
+func createSupportClients(kubeConfig string, options *support.Options) error {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig))
+	if err != nil {
+		return err
+	}
+	if options.Client, err = kubernetes.NewForConfig(restConfig); err != nil {
+		return err
+	}
+	options.DynamicClient, err = readiness.CreateDynamicClient(kubeConfig)
+	return err
+}
@@ -0,0 +1,465 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package artifacts gets the installers, RKE2 files, charts and images of an orchestrator
+// release from the release service, a mirror registry or a local bundle.
+package artifacts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	pathpkg "path"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/retry"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/oci"
+	"oras.land/oras-go/v2/errdef"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	remoteretry "oras.land/oras-go/v2/registry/remote/retry"
+)
+
+type Kind string
+
+const (
+	// Debian packages published in the release service
+	KindInstaller Kind = "installer"
+	// Tarballs published in the release service, such as the deployment repo
+	KindArchive Kind = "archive"
+	// Files published over HTTP, such as the RKE2 tarballs and third party charts
+	KindFile Kind = "file"
+	// Container images of the release image manifest
+	KindImage Kind = "image"
+
+	// Files are stored as OCI artifacts of this type in bundles and mirrors
+	fileArtifactType = "application/vnd.intel.orch.file"
+	fileMediaType    = "application/octet-stream"
+)
+
+// Artifact is a part of a release. In bundles and mirror registries every artifact is stored
+// under its Ref.
+type Artifact struct {
+	Kind Kind
+	// File name of files, the image reference of images
+	Name string
+	// Repository of the artifact, relative to the registry
+	Repository string
+	Tag        string
+	// Where files are downloaded from when they are not in a bundle or mirror
+	URL string
+	// Helm repository of a chart, the downloaded chart is verified against the digest in its
+	// index. The chart name is the base of the Repository and the version is the Tag.
+	ChartRepository string
+	// Name of the checksum file of the release listing the file, fetched to the same directory
+	ChecksumFile string
+	// The file is a checksum file of a release, trusted as published by the release
+	Checksums bool
+	// Pinned SHA256 of a file, in hex
+	SHA256 string
+	// The image is signed like the installers and archives, as an image of the release registry
+	Signed bool
+}
+
+// Verification is how the content of an artifact is verified.
+type Verification string
+
+const (
+	// Cosign signature, checked with the signature public key of the config
+	VerificationSignature Verification = "signature"
+	// Digest of the chart in the index of its Helm repository
+	VerificationChartIndex Verification = "chart-index"
+	// Listed in the checksum file of its release
+	VerificationChecksumFile Verification = "checksum-file"
+	// Checksum file of a release, the root of the VerificationChecksumFile verification
+	VerificationChecksums Verification = "checksums"
+	// Digest pinned in the artifact or in the image reference
+	VerificationDigest Verification = "digest"
+)
+
+// Verification returns how the artifact is verified when it is fetched or bundled, an empty
+// string when it is not.
+func (a Artifact) Verification() Verification {
+	switch {
+	case a.Kind == KindInstaller || a.Kind == KindArchive:
+		return VerificationSignature
+	case a.Kind == KindImage && a.Signed:
+		return VerificationSignature
+	case a.Kind == KindImage && strings.Contains(a.Name, "@sha256:"):
+		return VerificationDigest
+	case a.Kind == KindFile && a.SHA256 != "":
+		return VerificationDigest
+	case a.Kind == KindFile && a.ChartRepository != "":
+		return VerificationChartIndex
+	case a.Kind == KindFile && a.ChecksumFile != "":
+		return VerificationChecksumFile
+	case a.Kind == KindFile && a.Checksums:
+		return VerificationChecksums
+	default:
+		return ""
+	}
+}
+
+// Ref returns the reference the artifact is stored under in bundles and mirrors.
+func (a Artifact) Ref() string {
+	return a.Repository + ":" + a.Tag
+}
+
+// Source provides the artifacts at install time.
+type Source interface {
+	// Fetch writes the files of the artifact to dir
+	Fetch(ctx context.Context, artifact Artifact, dir string) error
+}
+
+// Origin provides the artifacts a bundle is created from.
+type Origin interface {
+	// Copy stores the artifact in the target under the reference
+	Copy(ctx context.Context, artifact Artifact, target oras.Target, ref string) error
+}
+
+// ReleaseService is the default source, the installers and archives are pulled from the
+// release service registry and the files downloaded from where they are published.
+type ReleaseService struct {
+	Registry   string
+	Username   string
+	Password   string
+	HTTPClient *http.Client
+	// Verifies the installers and archives, which are pulled unverified when nil
+	Verifier Verifier
+	// Downloads the files, with the HTTPClient and no cache when nil. Its retry options
+	// apply to the registry too.
+	Downloader *Downloader
+	// The installers and archives are pulled into this cache first, when set
+	Cache *oci.Store
+}
+
+func (s *ReleaseService) Fetch(ctx context.Context, artifact Artifact, dir string) error {
+	switch artifact.Kind {
+	case KindInstaller, KindArchive:
+		return fetchFromRegistry(ctx, s.Registry, false, artifact.Repository, artifact.Tag, dir, copyOptions{
+			verifier:   s.Verifier,
+			credential: credential(s.Username, s.Password),
+			cache:      s.Cache,
+			retry:      s.downloader().Retry,
+		})
+	case KindFile:
+		return s.download(ctx, artifact, filepath.Join(dir, artifact.Name))
+	default:
+		return fmt.Errorf("%s artifacts are pulled by the cluster, not fetched from the release service", artifact.Kind)
+	}
+}
+
+func (s *ReleaseService) Copy(ctx context.Context, artifact Artifact, target oras.Target, ref string) error {
+	switch artifact.Kind {
+	case KindInstaller, KindArchive:
+		return copyFromRegistry(ctx, s.Registry, false, artifact.Repository, artifact.Tag, target, ref, copyOptions{
+			verifier:      s.Verifier,
+			credential:    credential(s.Username, s.Password),
+			cache:         s.Cache,
+			retry:         s.downloader().Retry,
+			withSignature: true,
+		})
+	case KindImage:
+		repository, reference, err := imageLocation(artifact.Name)
+		if err != nil {
+			return err
+		}
+		host, path, _ := strings.Cut(repository, "/")
+		opts := copyOptions{
+			platform: linuxAMD64,
+			retry:    s.downloader().Retry,
+		}
+		if err := imageVerifier(artifact, s.Verifier, &opts); err != nil {
+			return err
+		}
+		return copyFromRegistry(ctx, host, false, path, reference, target, ref, opts)
+	case KindFile:
+		dir, err := os.MkdirTemp("", "orch-artifact-*")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, artifact.Name)
+		if err := s.download(ctx, artifact, path); err != nil {
+			return err
+		}
+		return pushFile(ctx, target, ref, path)
+	default:
+		return fmt.Errorf("unknown artifact kind %q", artifact.Kind)
+	}
+}
+
+func (s *ReleaseService) downloader() *Downloader {
+	if s.Downloader != nil {
+		return s.Downloader
+	}
+	return &Downloader{Client: s.HTTPClient}
+}
+
+func (s *ReleaseService) download(ctx context.Context, artifact Artifact, path string) error {
+	if err := s.downloader().Download(ctx, artifact.URL, artifact.SHA256, path); err != nil {
+		return err
+	}
+	if artifact.SHA256 != "" {
+		return verifyDigest(path, artifact.SHA256)
+	}
+	if artifact.ChartRepository != "" {
+		client := s.HTTPClient
+		if client == nil {
+			client = http.DefaultClient
+		}
+		return verifyChart(ctx, client, artifact.ChartRepository, pathpkg.Base(artifact.Repository), artifact.Tag, path)
+	}
+	return nil
+}
+
+// Registry is a mirror holding a copy of a bundle, every artifact is stored under its Ref.
+type Registry struct {
+	Host      string
+	PlainHTTP bool
+	Username  string
+	Password  string
+	// Verifies the installers and archives, with the signatures copied from the bundle
+	Verifier Verifier
+	// The artifacts are pulled into this cache first, when set
+	Cache *oci.Store
+	Retry retry.Options
+}
+
+func (r *Registry) Fetch(ctx context.Context, artifact Artifact, dir string) error {
+	if artifact.Kind == KindImage {
+		return fmt.Errorf("images are pulled from the mirror by the cluster")
+	}
+	return fetchFromRegistry(ctx, r.Host, r.PlainHTTP, artifact.Repository, artifact.Tag, dir, copyOptions{
+		verifier:   r.Verifier,
+		credential: credential(r.Username, r.Password),
+		cache:      r.Cache,
+		retry:      r.Retry,
+	})
+}
+
+func (r *Registry) Copy(ctx context.Context, artifact Artifact, target oras.Target, ref string) error {
+	opts := copyOptions{credential: credential(r.Username, r.Password), retry: r.Retry, withSignature: true}
+	if artifact.Kind != KindImage {
+		opts.verifier = r.Verifier
+	} else if err := imageVerifier(artifact, r.Verifier, &opts); err != nil {
+		return err
+	}
+	return copyFromRegistry(ctx, r.Host, r.PlainHTTP, artifact.Repository, artifact.Tag, target, ref, opts)
+}
+
+// imageVerifier sets the verifier of a signed image. An image pinned by digest needs none, as
+// its content is checked against the digest when it is copied, and other images are refused.
+func imageVerifier(artifact Artifact, verifier Verifier, opts *copyOptions) error {
+	switch artifact.Verification() {
+	case VerificationSignature:
+		if verifier == nil {
+			return fmt.Errorf("image %s is signed but no verifier is set", artifact.Name)
+		}
+		opts.verifier = verifier
+		return nil
+	case VerificationDigest:
+		return nil
+	default:
+		return fmt.Errorf("image %s is neither signed nor pinned by digest, refusing to bundle it", artifact.Name)
+	}
+}
+
+var linuxAMD64 = &ocispec.Platform{OS: "linux", Architecture: "amd64"}
+
+type copyOptions struct {
+	platform   *ocispec.Platform
+	verifier   Verifier
+	credential auth.Credential
+	// Blobs already in the cache are not pulled again
+	cache *oci.Store
+	retry retry.Options
+	// Copy the signature along with the artifact, so that it can be verified again when
+	// it is copied out of the target
+	withSignature bool
+}
+
+// copyFromRegistry copies an artifact to the target under ref, retrying failed pulls. With a
+// verifier, the artifact is only copied once its signature is verified, and then by digest so
+// that the tag cannot move in between.
+func copyFromRegistry(ctx context.Context, host string, plainHTTP bool, repository, tag string, target oras.Target, ref string, opts copyOptions) error {
+	return pullFromRegistry(ctx, host, plainHTTP, repository, tag, opts, func(repo *remote.Repository) error {
+		return copyArtifact(ctx, repo, tag, target, ref, opts)
+	})
+}
+
+// pullFromRegistry calls pull with the repository until it succeeds, fails permanently or the
+// attempts are exhausted.
+func pullFromRegistry(ctx context.Context, host string, plainHTTP bool, repository, tag string, opts copyOptions, pull func(repo *remote.Repository) error) error {
+	repo, err := remote.NewRepository(host + "/" + repository)
+	if err != nil {
+		return fmt.Errorf("failed to create repository for %s: %w", repository, err)
+	}
+	repo.PlainHTTP = plainHTTP
+	if opts.credential != auth.EmptyCredential {
+		repo.Client = &auth.Client{
+			Client:     remoteretry.DefaultClient,
+			Cache:      auth.NewCache(),
+			Credential: auth.StaticCredential(host, opts.credential),
+		}
+	}
+	location := fmt.Sprintf("%s/%s:%s", host, repository, tag)
+	return retry.Do(ctx, opts.retry, func(int) error {
+		err := pull(repo)
+		if errors.Is(err, errdef.ErrNotFound) || errors.Is(err, errVerification) {
+			err = retry.Permanent(err)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to copy %s: %w", location, err)
+		}
+		return nil
+	})
+}
+
+// errVerification marks verification failures, which are not retried.
+var errVerification = errors.New("verification failed")
+
+func copyArtifact(ctx context.Context, repo *remote.Repository, tag string, target oras.Target, ref string, opts copyOptions) error {
+	copyOpts := oras.DefaultCopyOptions
+	if opts.platform != nil {
+		copyOpts.WithTargetPlatform(opts.platform)
+	}
+	if opts.verifier == nil && opts.cache == nil {
+		_, err := oras.Copy(ctx, repo, tag, target, ref, copyOpts)
+		return err
+	}
+
+	manifest, err := repo.Resolve(ctx, tag)
+	if err != nil {
+		return err
+	}
+	if opts.verifier != nil {
+		if err := opts.verifier.Verify(ctx, repo, manifest); err != nil {
+			return fmt.Errorf("%w: %w", errVerification, err)
+		}
+		if opts.withSignature {
+			// Signatures live next to the artifact, under the repository of ref
+			signatureRef := signatureTag(manifest)
+			if i := strings.LastIndex(ref, ":"); i > 0 {
+				signatureRef = ref[:i+1] + signatureRef
+			}
+			if _, err := oras.Copy(ctx, repo, signatureTag(manifest), target, signatureRef, oras.DefaultCopyOptions); err != nil {
+				return fmt.Errorf("failed to copy the signature: %w", err)
+			}
+		}
+	}
+	var source oras.ReadOnlyTarget = repo
+	if opts.cache != nil {
+		if err := oras.CopyGraph(ctx, repo, opts.cache, manifest, oras.DefaultCopyGraphOptions); err != nil {
+			return err
+		}
+		source = opts.cache
+	}
+	_, err = oras.Copy(ctx, source, manifest.Digest.String(), target, ref, copyOpts)
+	return err
+}
+
+// fetchFromRegistry writes the layers of an artifact to dir, named after their title.
+func fetchFromRegistry(ctx context.Context, host string, plainHTTP bool, repository, tag, dir string, opts copyOptions) error {
+	opts.withSignature = false
+	return pullFromRegistry(ctx, host, plainHTTP, repository, tag, opts, func(repo *remote.Repository) error {
+		// A new store for each attempt, so that the files of a failed one are written again
+		fileStore, err := file.New(dir)
+		if err != nil {
+			return retry.Permanent(fmt.Errorf("failed to create file store: %w", err))
+		}
+		defer fileStore.Close()
+		return copyArtifact(ctx, repo, tag, fileStore, tag, opts)
+	})
+}
+
+func credential(username, password string) auth.Credential {
+	if username == "" && password == "" {
+		return auth.EmptyCredential
+	}
+	return auth.Credential{Username: username, Password: password}
+}
+
+// pushFile stores a file as a single layer artifact, like the release service does.
+func pushFile(ctx context.Context, target oras.Target, ref, path string) error {
+	input, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	digester := digest.Canonical.Digester()
+	size, err := io.Copy(digester.Hash(), input)
+	if err != nil {
+		return err
+	}
+	if _, err := input.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	layer := ocispec.Descriptor{
+		MediaType:   fileMediaType,
+		Digest:      digester.Digest(),
+		Size:        size,
+		Annotations: map[string]string{ocispec.AnnotationTitle: filepath.Base(path)},
+	}
+	if err := target.Push(ctx, layer, input); err != nil {
+		return fmt.Errorf("failed to store %s: %w", path, err)
+	}
+	manifest, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, fileArtifactType, oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layer},
+	})
+	if err != nil {
+		return err
+	}
+	return target.Tag(ctx, manifest, ref)
+}
+
+// imageLocation splits an image reference into its repository, including the registry, and
+// its tag or digest.
+func imageLocation(image string) (string, string, error) {
+	repository, reference := image, "latest"
+	if name, dgst, ok := strings.Cut(image, "@"); ok {
+		repository, reference = name, dgst
+	} else if i := strings.LastIndex(image, ":"); i > strings.LastIndex(image, "/") {
+		repository, reference = image[:i], image[i+1:]
+	}
+	host, path, ok := strings.Cut(repository, "/")
+	if !ok || (!strings.ContainsAny(host, ".:") && host != "localhost") {
+		host, path = "docker.io", repository
+	}
+	if host == "docker.io" && !strings.Contains(path, "/") {
+		path = "library/" + path
+	}
+	if path == "" {
+		return "", "", fmt.Errorf("invalid image reference %q", image)
+	}
+	if host == "docker.io" {
+		host = "registry-1.docker.io"
+	}
+	return host + "/" + path, reference, nil
+}
+
+// ImageArtifact returns the artifact of a container image, stored under images/ in bundles.
+// The images of the release registry are signed like the other artifacts of the release.
+func ImageArtifact(image, releaseRegistry string) (Artifact, error) {
+	repository, reference, err := imageLocation(image)
+	if err != nil {
+		return Artifact{}, err
+	}
+	return Artifact{
+		Kind:       KindImage,
+		Name:       image,
+		Repository: "images/" + strings.Replace(repository, "registry-1.docker.io/", "docker.io/", 1),
+		// Digests are not valid tags
+		Tag:    strings.Replace(reference, ":", "-", 1),
+		Signed: releaseRegistry != "" && strings.HasPrefix(repository, releaseRegistry+"/"),
+	}, nil
+}
@@ -0,0 +1,328 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"archive/tar"
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"gopkg.in/yaml.v3"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content/file"
+	"oras.land/oras-go/v2/content/oci"
+)
+
+const (
+	// SignatureSuffix is appended to the bundle path to get its detached signature
+	SignatureSuffix = ".sig"
+
+	imagesPrefix = "images/"
+	// containerd imports an image archive under this name
+	containerdImageName = "io.containerd.image.name"
+)
+
+// CreateBundle stores the artifacts in an OCI image layout, written as a tarball to output and
+// signed with the key. The signature is written next to it.
+func CreateBundle(ctx context.Context, origin Origin, artifacts []Artifact, output string, key ed25519.PrivateKey) error {
+	layoutDir, err := os.MkdirTemp("", "orch-bundle-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(layoutDir)
+	layout, err := oci.NewWithContext(ctx, layoutDir)
+	if err != nil {
+		return fmt.Errorf("failed to create the OCI layout: %w", err)
+	}
+	// The bundle signature only vouches for what was verified when it was created
+	for _, artifact := range artifacts {
+		if artifact.Verification() == "" {
+			return fmt.Errorf("%s %s has no verification, refusing to bundle it", artifact.Kind, artifact.Name)
+		}
+	}
+	for i, artifact := range artifacts {
+		internal.Logger().Infof("[%d/%d] Adding %s %s", i+1, len(artifacts), artifact.Kind, artifact.Ref())
+		if err := origin.Copy(ctx, artifact, layout, artifact.Ref()); err != nil {
+			return err
+		}
+	}
+	if err := tarDir(layoutDir, output); err != nil {
+		return fmt.Errorf("failed to write %s: %w", output, err)
+	}
+	signature, err := signFile(output, key)
+	if err != nil {
+		return fmt.Errorf("failed to sign %s: %w", output, err)
+	}
+	return os.WriteFile(output+SignatureSuffix, []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), 0o644)
+}
+
+// Bundle is a source reading the artifacts from a bundle created by CreateBundle.
+type Bundle struct {
+	store *oci.ReadOnlyStore
+}
+
+// OpenBundle verifies the signature of the bundle with the public key, then opens it.
+func OpenBundle(ctx context.Context, path string, publicKey ed25519.PublicKey) (*Bundle, error) {
+	encoded, err := os.ReadFile(path + SignatureSuffix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read the signature of the bundle: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature %s%s: %w", path, SignatureSuffix, err)
+	}
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+	if !ed25519.Verify(publicKey, digest, signature) {
+		return nil, fmt.Errorf("signature of bundle %s does not match the public key, refusing to use it", path)
+	}
+	store, err := oci.NewFromTar(ctx, path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bundle %s: %w", path, err)
+	}
+	return &Bundle{store: store}, nil
+}
+
+func (b *Bundle) Fetch(ctx context.Context, artifact Artifact, dir string) error {
+	if artifact.Kind == KindImage {
+		return fmt.Errorf("images are exported from the bundle with ExportImages")
+	}
+	fileStore, err := file.New(dir)
+	if err != nil {
+		return fmt.Errorf("failed to create file store: %w", err)
+	}
+	defer fileStore.Close()
+	if _, err := oras.Copy(ctx, b.store, artifact.Ref(), fileStore, artifact.Tag, oras.DefaultCopyOptions); err != nil {
+		return fmt.Errorf("failed to copy %s from the bundle: %w", artifact.Ref(), err)
+	}
+	return nil
+}
+
+func (b *Bundle) Copy(ctx context.Context, artifact Artifact, target oras.Target, ref string) error {
+	if _, err := oras.Copy(ctx, b.store, artifact.Ref(), target, ref, oras.DefaultCopyOptions); err != nil {
+		return fmt.Errorf("failed to copy %s from the bundle: %w", artifact.Ref(), err)
+	}
+	return nil
+}
+
+// ExportImages writes every image of the bundle to dir as an OCI archive that containerd,
+// and so RKE2, imports. It returns the names of the images.
+func (b *Bundle) ExportImages(ctx context.Context, dir string) ([]string, error) {
+	refs := []string{}
+	if err := b.store.Tags(ctx, "", func(tags []string) error {
+		for _, tag := range tags {
+			if strings.HasPrefix(tag, imagesPrefix) {
+				refs = append(refs, tag)
+			}
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	images := []string{}
+	for _, ref := range refs {
+		image := imageName(ref)
+		archive := filepath.Join(dir, strings.NewReplacer("/", "_", ":", "_", "@", "_").Replace(image)+".tar")
+		if err := b.exportImage(ctx, ref, image, archive); err != nil {
+			return nil, fmt.Errorf("failed to export image %s: %w", image, err)
+		}
+		images = append(images, image)
+	}
+	return images, nil
+}
+
+func (b *Bundle) exportImage(ctx context.Context, ref, image, archive string) error {
+	layoutDir, err := os.MkdirTemp("", "orch-image-*")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(layoutDir)
+	layout, err := oci.NewWithContext(ctx, layoutDir)
+	if err != nil {
+		return err
+	}
+	if _, err := oras.Copy(ctx, b.store, ref, layout, image, oras.DefaultCopyOptions); err != nil {
+		return err
+	}
+
+	// Name the image for containerd, which otherwise only takes the tag from the layout
+	indexPath := filepath.Join(layoutDir, "index.json")
+	data, err := os.ReadFile(indexPath)
+	if err != nil {
+		return err
+	}
+	index := map[string]any{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return err
+	}
+	manifests, _ := index["manifests"].([]any)
+	for _, manifest := range manifests {
+		if descriptor, ok := manifest.(map[string]any); ok {
+			annotations, _ := descriptor["annotations"].(map[string]any)
+			if annotations == nil {
+				annotations = map[string]any{}
+				descriptor["annotations"] = annotations
+			}
+			annotations[containerdImageName] = image
+		}
+	}
+	if data, err = json.Marshal(index); err != nil {
+		return err
+	}
+	if err := os.WriteFile(indexPath, data, 0o644); err != nil {
+		return err
+	}
+	return tarDir(layoutDir, archive)
+}
+
+// imageName returns the image reference of a bundle reference under images/.
+func imageName(ref string) string {
+	name := strings.TrimPrefix(ref, imagesPrefix)
+	if i := strings.LastIndex(name, ":"); i > 0 {
+		repository, tag := name[:i], name[i+1:]
+		if algorithm, hex, ok := strings.Cut(tag, "-"); ok && algorithm == "sha256" {
+			return repository + "@sha256:" + hex
+		}
+		return repository + ":" + tag
+	}
+	return name
+}
+
+// ReadImageManifest returns the images of a release image manifest, as generated by
+// mage gen:releaseImageManifest.
+func ReadImageManifest(path string) ([]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	manifest := struct {
+		Images []string `yaml:"images"`
+	}{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse image manifest %s: %w", path, err)
+	}
+	return manifest.Images, nil
+}
+
+// ReadPrivateKey reads an Ed25519 private key in PKCS #8 PEM, as generated by
+// openssl genpkey -algorithm ed25519.
+func ReadPrivateKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse private key %s: %w", path, err)
+	}
+	privateKey, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("private key %s is not an Ed25519 key", path)
+	}
+	return privateKey, nil
+}
+
+// ReadPublicKey reads an Ed25519 public key in PKIX PEM, as generated by openssl pkey -pubout.
+func ReadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is not an Ed25519 key", path)
+	}
+	return publicKey, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not PEM encoded", path)
+	}
+	return block, nil
+}
+
+func signFile(path string, key ed25519.PrivateKey) ([]byte, error) {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return nil, err
+	}
+	return ed25519.Sign(key, digest), nil
+}
+
+func fileDigest(path string) ([]byte, error) {
+	input, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer input.Close()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, input); err != nil {
+		return nil, err
+	}
+	return hash.Sum(nil), nil
+}
+
+// tarDir writes the files of dir to a tarball, with paths relative to dir.
+func tarDir(dir, output string) error {
+	archive, err := os.Create(output)
+	if err != nil {
+		return err
+	}
+	tw := tar.NewWriter(archive)
+	walkErr := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil || path == dir {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		if header.Name, err = filepath.Rel(dir, path); err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(header.Name)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		input, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer input.Close()
+		_, err = io.Copy(tw, input)
+		return err
+	})
+	return errors.Join(walkErr, tw.Close(), archive.Close())
+}
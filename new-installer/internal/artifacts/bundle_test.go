@@ -0,0 +1,243 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package artifacts_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+)
+
+// testOrigin serves the files from a test server and makes up the images.
+type testOrigin struct {
+	files *artifacts.ReleaseService
+}
+
+func (o *testOrigin) Copy(ctx context.Context, artifact artifacts.Artifact, target oras.Target, ref string) error {
+	if artifact.Kind != artifacts.KindImage {
+		return o.files.Copy(ctx, artifact, target, ref)
+	}
+	config := []byte(`{"architecture":"amd64","os":"linux"}`)
+	configDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageConfig, config)
+	layer := []byte(artifact.Name)
+	layerDesc := content.NewDescriptorFromBytes(ocispec.MediaTypeImageLayer, layer)
+	if err := target.Push(ctx, configDesc, bytes.NewReader(config)); err != nil {
+		return err
+	}
+	if err := target.Push(ctx, layerDesc, bytes.NewReader(layer)); err != nil {
+		return err
+	}
+	manifest, err := oras.PackManifest(ctx, target, oras.PackManifestVersion1_1, "", oras.PackManifestOptions{
+		ConfigDescriptor: &configDesc,
+		Layers:           []ocispec.Descriptor{layerDesc},
+	})
+	if err != nil {
+		return err
+	}
+	return target.Tag(ctx, manifest, ref)
+}
+
+type BundleTest struct {
+	suite.Suite
+	dir        string
+	server     *httptest.Server
+	publicKey  ed25519.PublicKey
+	privateKey ed25519.PrivateKey
+	bundle     string
+	artifacts  []artifacts.Artifact
+}
+
+func TestBundle(t *testing.T) {
+	suite.Run(t, new(BundleTest))
+}
+
+func (s *BundleTest) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rke2/install.sh" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("#!/bin/sh\n"))
+	}))
+	s.T().Cleanup(s.server.Close)
+
+	var err error
+	s.publicKey, s.privateKey, err = ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	s.bundle = filepath.Join(s.dir, "orch-bundle.tar")
+
+	image, err := artifacts.ImageArtifact("registry.example.com/edge-orch/app:1.0.0", "registry.example.com")
+	s.Require().NoError(err)
+	s.artifacts = []artifacts.Artifact{
+		{
+			Kind: artifacts.KindFile, Name: "install.sh", Repository: "rke2/install.sh", Tag: "v1.30.10-rke2r1", URL: s.server.URL + "/rke2/install.sh",
+			SHA256: sha256Hex([]byte("#!/bin/sh\n")),
+		},
+		image,
+	}
+}
+
+func (s *BundleTest) createBundle() {
+	origin := &testOrigin{files: &artifacts.ReleaseService{HTTPClient: s.server.Client()}}
+	s.Require().NoError(artifacts.CreateBundle(context.Background(), origin, s.artifacts, s.bundle, s.privateKey))
+}
+
+func (s *BundleTest) TestFetch() {
+	s.createBundle()
+	bundle, err := artifacts.OpenBundle(context.Background(), s.bundle, s.publicKey)
+	s.Require().NoError(err)
+
+	dir := s.T().TempDir()
+	s.Require().NoError(bundle.Fetch(context.Background(), s.artifacts[0], dir))
+	data, err := os.ReadFile(filepath.Join(dir, "install.sh"))
+	s.Require().NoError(err)
+	s.Equal("#!/bin/sh\n", string(data))
+
+	s.Error(bundle.Fetch(context.Background(), artifacts.Artifact{Repository: "rke2/missing", Tag: "v1"}, dir))
+}
+
+func (s *BundleTest) TestExportImages() {
+	s.createBundle()
+	bundle, err := artifacts.OpenBundle(context.Background(), s.bundle, s.publicKey)
+	s.Require().NoError(err)
+
+	dir := s.T().TempDir()
+	images, err := bundle.ExportImages(context.Background(), dir)
+	s.Require().NoError(err)
+	s.Equal([]string{"registry.example.com/edge-orch/app:1.0.0"}, images)
+
+	archive, err := os.Open(filepath.Join(dir, "registry.example.com_edge-orch_app_1.0.0.tar"))
+	s.Require().NoError(err)
+	defer archive.Close()
+	reader := tar.NewReader(archive)
+	for {
+		header, err := reader.Next()
+		s.Require().NoError(err, "index.json not found in the image archive")
+		if header.Name == "index.json" {
+			index, err := io.ReadAll(reader)
+			s.Require().NoError(err)
+			s.Contains(string(index), `"io.containerd.image.name":"registry.example.com/edge-orch/app:1.0.0"`)
+			return
+		}
+	}
+}
+
+func (s *BundleTest) TestUnverifiedArtifact() {
+	s.artifacts[0].SHA256 = ""
+	origin := &testOrigin{files: &artifacts.ReleaseService{HTTPClient: s.server.Client()}}
+	err := artifacts.CreateBundle(context.Background(), origin, s.artifacts, s.bundle, s.privateKey)
+	s.ErrorContains(err, "install.sh has no verification")
+	s.NoFileExists(s.bundle)
+}
+
+func (s *BundleTest) TestPinnedDigestMismatch() {
+	s.artifacts[0].SHA256 = sha256Hex([]byte("another script"))
+	origin := &testOrigin{files: &artifacts.ReleaseService{HTTPClient: s.server.Client()}}
+	err := artifacts.CreateBundle(context.Background(), origin, s.artifacts, s.bundle, s.privateKey)
+	s.ErrorContains(err, "does not match the pinned digest")
+}
+
+func (s *BundleTest) TestUnsignedImage() {
+	image, err := artifacts.ImageArtifact("quay.io/jetstack/cert-manager:v1.16.2", "registry.example.com")
+	s.Require().NoError(err)
+	s.Empty(image.Verification())
+	err = (&artifacts.ReleaseService{}).Copy(context.Background(), image, nil, image.Ref())
+	s.ErrorContains(err, "neither signed nor pinned by digest")
+}
+
+func (s *BundleTest) TestTamperedBundle() {
+	s.createBundle()
+	file, err := os.OpenFile(s.bundle, os.O_APPEND|os.O_WRONLY, 0)
+	s.Require().NoError(err)
+	_, err = file.Write([]byte{0})
+	s.Require().NoError(err)
+	s.Require().NoError(file.Close())
+
+	_, err = artifacts.OpenBundle(context.Background(), s.bundle, s.publicKey)
+	s.ErrorContains(err, "does not match the public key")
+}
+
+func (s *BundleTest) TestOtherKey() {
+	s.createBundle()
+	otherKey, _, err := ed25519.GenerateKey(rand.Reader)
+	s.Require().NoError(err)
+	_, err = artifacts.OpenBundle(context.Background(), s.bundle, otherKey)
+	s.ErrorContains(err, "does not match the public key")
+}
+
+func (s *BundleTest) TestMissingSignature() {
+	s.createBundle()
+	s.Require().NoError(os.Remove(s.bundle + artifacts.SignatureSuffix))
+	_, err := artifacts.OpenBundle(context.Background(), s.bundle, s.publicKey)
+	s.ErrorContains(err, "signature")
+}
+
+func (s *BundleTest) TestReadKeys() {
+	privateDER, err := x509.MarshalPKCS8PrivateKey(s.privateKey)
+	s.Require().NoError(err)
+	publicDER, err := x509.MarshalPKIXPublicKey(s.publicKey)
+	s.Require().NoError(err)
+	privatePath := filepath.Join(s.dir, "bundle.key")
+	publicPath := filepath.Join(s.dir, "bundle.pub")
+	s.Require().NoError(os.WriteFile(privatePath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privateDER}), 0o600))
+	s.Require().NoError(os.WriteFile(publicPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: publicDER}), 0o600))
+
+	privateKey, err := artifacts.ReadPrivateKey(privatePath)
+	s.Require().NoError(err)
+	s.Equal(s.privateKey, privateKey)
+	publicKey, err := artifacts.ReadPublicKey(publicPath)
+	s.Require().NoError(err)
+	s.Equal(s.publicKey, publicKey)
+
+	_, err = artifacts.ReadPublicKey(privatePath)
+	s.Error(err)
+}
+
+func (s *BundleTest) TestImageArtifact() {
+	tests := []struct {
+		image        string
+		repository   string
+		tag          string
+		verification artifacts.Verification
+	}{
+		{"busybox:1.36", "images/docker.io/library/busybox", "1.36", ""},
+		{"bitnami/postgresql", "images/docker.io/bitnami/postgresql", "latest", ""},
+		{"localhost:5000/app:v1", "images/localhost:5000/app", "v1", artifacts.VerificationSignature},
+		{"quay.io/jetstack/cert-manager@sha256:0123", "images/quay.io/jetstack/cert-manager", "sha256-0123", artifacts.VerificationDigest},
+	}
+	for _, tt := range tests {
+		s.Run(tt.image, func() {
+			artifact, err := artifacts.ImageArtifact(tt.image, "localhost:5000")
+			s.Require().NoError(err)
+			s.Equal(tt.repository, artifact.Repository)
+			s.Equal(tt.tag, artifact.Tag)
+			s.Equal(tt.verification, artifact.Verification())
+		})
+	}
+}
+
+func (s *BundleTest) TestReadImageManifest() {
+	path := filepath.Join(s.dir, "image-manifest.yaml")
+	s.Require().NoError(os.WriteFile(path, []byte("\nimages:\n  - busybox:1.36\n  - quay.io/jetstack/cert-manager:v1.16.2\nbinaries: []\n"), 0o600))
+	images, err := artifacts.ReadImageManifest(path)
+	s.Require().NoError(err)
+	s.Equal([]string{"busybox:1.36", "quay.io/jetstack/cert-manager:v1.16.2"}, images)
+}
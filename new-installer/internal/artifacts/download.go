@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/retry"
+)
+
+const (
+	// Partial downloads are kept with this suffix until they complete
+	partialSuffix = ".part"
+
+	defaultProgressInterval = 10 * time.Second
+)
+
+// Progress reports the state of a download.
+type Progress struct {
+	URL  string
+	Name string
+	// Bytes written so far, including the ones of a resumed partial download
+	Done int64
+	// Size of the file, -1 when the server does not tell
+	Total     int64
+	Completed bool
+	// The file was served from the cache
+	Cached bool
+}
+
+// Downloader fetches files over HTTP. An interrupted download resumes from its partial file
+// with a range request, a failed one is retried with backoff, and completed files are kept in
+// a cache keyed by their digest. Only files with a pinned digest are served from the cache,
+// the content of a URL can change.
+type Downloader struct {
+	Client *http.Client
+	// Completed downloads are cached here, nothing is cached when empty
+	CacheDir string
+	Retry    retry.Options
+	// Progress of each download is reported at this interval and when it completes
+	ProgressInterval time.Duration
+	OnProgress       func(Progress)
+}
+
+// Download writes the file at url to path. The file is served from the cache when its SHA256,
+// in hex, is given and a file with this digest is cached.
+func (d *Downloader) Download(ctx context.Context, url, sha256Hex, path string) error {
+	if d.CacheDir != "" && sha256Hex != "" {
+		if blob, ok := d.cachedBlob(sha256Hex); ok {
+			if err := linkOrCopy(blob, path); err != nil {
+				return err
+			}
+			if info, err := os.Stat(path); err == nil {
+				d.report(Progress{URL: url, Name: filepath.Base(path), Done: info.Size(), Total: info.Size(), Completed: true, Cached: true})
+			}
+			return nil
+		}
+	}
+
+	partial := path + partialSuffix
+	if d.CacheDir != "" {
+		if err := os.MkdirAll(filepath.Join(d.CacheDir, "partial"), 0o755); err != nil {
+			return err
+		}
+		partial = filepath.Join(d.CacheDir, "partial", urlKey(url)+partialSuffix)
+	}
+	options := d.Retry
+	onRetry := options.OnRetry
+	options.OnRetry = func(attempt int, err error, delay time.Duration) {
+		if onRetry != nil {
+			onRetry(attempt, fmt.Errorf("download of %s failed: %w", url, err), delay)
+		}
+	}
+	if err := retry.Do(ctx, options, func(int) error {
+		return d.fetch(ctx, url, partial, filepath.Base(path))
+	}); err != nil {
+		return err
+	}
+
+	if d.CacheDir == "" {
+		return os.Rename(partial, path)
+	}
+	blob, err := d.store(partial)
+	if err != nil {
+		return err
+	}
+	return linkOrCopy(blob, path)
+}
+
+// fetch appends the rest of the file to the partial download.
+func (d *Downloader) fetch(ctx context.Context, url, partial, name string) error {
+	var offset int64
+	if info, err := os.Stat(partial); err == nil {
+		offset = info.Size()
+	}
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return retry.Permanent(err)
+	}
+	if offset > 0 {
+		request.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	client := d.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := int64(-1)
+	switch {
+	case response.StatusCode == http.StatusPartialContent:
+		flags |= os.O_APPEND
+		if response.ContentLength >= 0 {
+			total = offset + response.ContentLength
+		}
+	case response.StatusCode == http.StatusOK:
+		// The server ignored the range, start over
+		flags |= os.O_TRUNC
+		offset = 0
+		total = response.ContentLength
+	case response.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file does not belong to the current file anymore
+		if err := os.Remove(partial); err != nil {
+			return retry.Permanent(err)
+		}
+		return fmt.Errorf("%s changed during the download", url)
+	case response.StatusCode >= 400 && response.StatusCode < 500 && response.StatusCode != http.StatusTooManyRequests:
+		return retry.Permanent(fmt.Errorf("failed to download %s: %s", url, response.Status))
+	default:
+		return fmt.Errorf("failed to download %s: %s", url, response.Status)
+	}
+
+	output, err := os.OpenFile(partial, flags, 0o644)
+	if err != nil {
+		return retry.Permanent(err)
+	}
+	progress := &progressWriter{
+		downloader: d,
+		progress:   Progress{URL: url, Name: name, Done: offset, Total: total},
+		last:       time.Now(),
+	}
+	_, copyErr := io.Copy(output, io.TeeReader(response.Body, progress))
+	if err := errors.Join(copyErr, output.Close()); err != nil {
+		return fmt.Errorf("download of %s interrupted: %w", url, err)
+	}
+	progress.progress.Completed = true
+	d.report(progress.progress)
+	return nil
+}
+
+// store moves a completed download into the cache.
+func (d *Downloader) store(partial string) (string, error) {
+	digest, err := fileDigest(partial)
+	if err != nil {
+		return "", err
+	}
+	blob := d.blobPath(hex.EncodeToString(digest))
+	if err := os.MkdirAll(filepath.Dir(blob), 0o755); err != nil {
+		return "", err
+	}
+	return blob, os.Rename(partial, blob)
+}
+
+// cachedBlob returns the cached file with the digest.
+func (d *Downloader) cachedBlob(sha256Hex string) (string, bool) {
+	blob := d.blobPath(strings.ToLower(sha256Hex))
+	if _, err := os.Stat(blob); err != nil {
+		return "", false
+	}
+	return blob, true
+}
+
+func (d *Downloader) blobPath(hexDigest string) string {
+	return filepath.Join(d.CacheDir, "blobs", "sha256", hexDigest)
+}
+
+func (d *Downloader) report(progress Progress) {
+	if d.OnProgress != nil {
+		d.OnProgress(progress)
+	}
+}
+
+func urlKey(url string) string {
+	digest := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(digest[:])
+}
+
+// linkOrCopy places the cached file at path, as a hard link when both are on the same file
+// system.
+func linkOrCopy(source, path string) error {
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.Link(source, path); err == nil {
+		return nil
+	}
+	input, err := os.Open(source)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	output, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(output, input)
+	return errors.Join(err, output.Close())
+}
+
+// progressWriter counts the bytes of a download and reports them at the progress interval.
+type progressWriter struct {
+	downloader *Downloader
+	progress   Progress
+	last       time.Time
+}
+
+func (w *progressWriter) Write(data []byte) (int, error) {
+	w.progress.Done += int64(len(data))
+	interval := w.downloader.ProgressInterval
+	if interval <= 0 {
+		interval = defaultProgressInterval
+	}
+	if now := time.Now(); now.Sub(w.last) >= interval {
+		w.last = now
+		w.downloader.report(w.progress)
+	}
+	return len(data), nil
+}
@@ -0,0 +1,176 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package artifacts_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/retry"
+	"github.com/stretchr/testify/suite"
+)
+
+var fileContent = []byte(strings.Repeat("rke2 images ", 1024))
+
+type DownloadTest struct {
+	suite.Suite
+	dir        string
+	downloader *artifacts.Downloader
+	progress   []artifacts.Progress
+}
+
+func TestDownload(t *testing.T) {
+	suite.Run(t, new(DownloadTest))
+}
+
+func (s *DownloadTest) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.progress = nil
+	s.downloader = &artifacts.Downloader{
+		CacheDir: filepath.Join(s.dir, "cache"),
+		Retry:    retry.Options{Attempts: 3, InitialDelay: time.Millisecond},
+		OnProgress: func(progress artifacts.Progress) {
+			s.progress = append(s.progress, progress)
+		},
+	}
+}
+
+// rangeHandler serves fileContent, honoring the range of the request.
+func rangeHandler(w http.ResponseWriter, r *http.Request) {
+	offset := 0
+	if value, ok := strings.CutPrefix(r.Header.Get("Range"), "bytes="); ok {
+		offset, _ = strconv.Atoi(strings.TrimSuffix(value, "-"))
+		w.Header().Set("Content-Range", fmt.Sprintf("bytes %d-%d/%d", offset, len(fileContent)-1, len(fileContent)))
+		w.Header().Set("Content-Length", strconv.Itoa(len(fileContent)-offset))
+		w.WriteHeader(http.StatusPartialContent)
+	} else {
+		w.Header().Set("Content-Length", strconv.Itoa(len(fileContent)))
+	}
+	_, _ = w.Write(fileContent[offset:])
+}
+
+func (s *DownloadTest) TestResume() {
+	ranges := []string{}
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ranges = append(ranges, r.Header.Get("Range"))
+		if requests.Add(1) == 1 {
+			// Drop the connection halfway through the file
+			w.Header().Set("Content-Length", strconv.Itoa(len(fileContent)))
+			_, _ = w.Write(fileContent[:len(fileContent)/2])
+			w.(http.Flusher).Flush()
+			conn, _, err := w.(http.Hijacker).Hijack()
+			s.Require().NoError(err)
+			conn.Close()
+			return
+		}
+		rangeHandler(w, r)
+	}))
+	defer server.Close()
+	s.downloader.Client = server.Client()
+
+	path := filepath.Join(s.dir, "rke2-images.linux-amd64.tar.zst")
+	s.Require().NoError(s.downloader.Download(context.Background(), server.URL+"/rke2-images.linux-amd64.tar.zst", "", path))
+	data, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Equal(fileContent, data)
+	s.Equal([]string{"", fmt.Sprintf("bytes=%d-", len(fileContent)/2)}, ranges)
+
+	last := s.progress[len(s.progress)-1]
+	s.True(last.Completed)
+	s.Equal(int64(len(fileContent)), last.Done)
+	s.Equal(int64(len(fileContent)), last.Total)
+}
+
+func (s *DownloadTest) TestRetryServerError() {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) < 3 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		rangeHandler(w, r)
+	}))
+	defer server.Close()
+	s.downloader.Client = server.Client()
+
+	path := filepath.Join(s.dir, "install.sh")
+	s.Require().NoError(s.downloader.Download(context.Background(), server.URL+"/install.sh", "", path))
+	s.FileExists(path)
+	s.Equal(int32(3), requests.Load())
+}
+
+func (s *DownloadTest) TestCache() {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		rangeHandler(w, r)
+	}))
+	defer server.Close()
+	s.downloader.Client = server.Client()
+
+	url := server.URL + "/gitea-10.6.0.tgz"
+	first := filepath.Join(s.dir, "first", "gitea-10.6.0.tgz")
+	second := filepath.Join(s.dir, "second", "gitea-10.6.0.tgz")
+	s.Require().NoError(os.MkdirAll(filepath.Dir(first), 0o755))
+	s.Require().NoError(os.MkdirAll(filepath.Dir(second), 0o755))
+	digest := sha256.Sum256(fileContent)
+	s.Require().NoError(s.downloader.Download(context.Background(), url, hex.EncodeToString(digest[:]), first))
+	s.Require().NoError(s.downloader.Download(context.Background(), url, hex.EncodeToString(digest[:]), second))
+
+	s.Equal(int32(1), requests.Load())
+	data, err := os.ReadFile(second)
+	s.Require().NoError(err)
+	s.Equal(fileContent, data)
+	s.True(s.progress[len(s.progress)-1].Cached)
+	blobs, err := os.ReadDir(filepath.Join(s.dir, "cache", "blobs", "sha256"))
+	s.Require().NoError(err)
+	s.Len(blobs, 1)
+}
+
+func (s *DownloadTest) TestNoCacheWithoutDigest() {
+	content := []byte("v1")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(content)
+	}))
+	defer server.Close()
+	s.downloader.Client = server.Client()
+
+	// The file behind a URL without a pinned digest can change, it is fetched again
+	url := server.URL + "/install.sh"
+	path := filepath.Join(s.dir, "install.sh")
+	s.Require().NoError(s.downloader.Download(context.Background(), url, "", path))
+	content = []byte("v2")
+	s.Require().NoError(s.downloader.Download(context.Background(), url, "", path))
+	data, err := os.ReadFile(path)
+	s.Require().NoError(err)
+	s.Equal("v2", string(data))
+	s.False(s.progress[len(s.progress)-1].Cached)
+}
+
+func (s *DownloadTest) TestNotFound() {
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.NotFound(w, r)
+	}))
+	defer server.Close()
+	s.downloader.Client = server.Client()
+
+	err := s.downloader.Download(context.Background(), server.URL+"/missing", "", filepath.Join(s.dir, "missing"))
+	s.ErrorContains(err, "404 Not Found")
+	s.Equal(int32(1), requests.Load())
+}
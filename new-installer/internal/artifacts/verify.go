@@ -0,0 +1,216 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package artifacts
+
+import (
+	"bufio"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/repo"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	sigsyaml "sigs.k8s.io/yaml"
+)
+
+const (
+	// cosign stores the payload it signs as a layer of this type, with the signature in an
+	// annotation
+	cosignPayloadMediaType     = "application/vnd.dev.cosign.simplesigning.v1+json"
+	cosignSignatureAnnotation  = "dev.cosignproject.cosign/signature"
+	cosignSignatureTagSuffix   = ".sig"
+	cosignPayloadSignatureType = "cosign container image signature"
+)
+
+// Verifier checks the signature of an artifact before it is copied out of a registry.
+type Verifier interface {
+	// Verify returns an error unless the manifest is signed in the repository
+	Verify(ctx context.Context, repository oras.ReadOnlyTarget, manifest ocispec.Descriptor) error
+}
+
+// CosignVerifier verifies the signatures `cosign sign --key` stores next to an artifact
+// with a pinned public key.
+type CosignVerifier struct {
+	PublicKey crypto.PublicKey
+}
+
+func (v *CosignVerifier) Verify(ctx context.Context, repository oras.ReadOnlyTarget, manifest ocispec.Descriptor) error {
+	tag := signatureTag(manifest)
+	_, data, err := oras.FetchBytes(ctx, repository, tag, oras.DefaultFetchBytesOptions)
+	if err != nil {
+		return fmt.Errorf("no signature found for %s: %w", manifest.Digest, err)
+	}
+	signatures := ocispec.Manifest{}
+	if err := json.Unmarshal(data, &signatures); err != nil {
+		return fmt.Errorf("invalid signature manifest %s: %w", tag, err)
+	}
+	for _, layer := range signatures.Layers {
+		if layer.MediaType != cosignPayloadMediaType {
+			continue
+		}
+		signature, err := base64.StdEncoding.DecodeString(layer.Annotations[cosignSignatureAnnotation])
+		if err != nil {
+			continue
+		}
+		payload, err := content.FetchAll(ctx, repository, layer)
+		if err != nil {
+			return fmt.Errorf("failed to fetch the signature payload of %s: %w", manifest.Digest, err)
+		}
+		if !verifySignature(v.PublicKey, payload, signature) {
+			continue
+		}
+		signed := struct {
+			Critical struct {
+				Image struct {
+					DockerManifestDigest string `json:"docker-manifest-digest"`
+				} `json:"image"`
+				Type string `json:"type"`
+			} `json:"critical"`
+		}{}
+		if err := json.Unmarshal(payload, &signed); err != nil {
+			continue
+		}
+		if signed.Critical.Type == cosignPayloadSignatureType && signed.Critical.Image.DockerManifestDigest == manifest.Digest.String() {
+			return nil
+		}
+	}
+	return fmt.Errorf("no signature of %s matches the public key", manifest.Digest)
+}
+
+// signatureTag returns the tag cosign stores the signatures of a manifest under.
+func signatureTag(manifest ocispec.Descriptor) string {
+	return strings.Replace(manifest.Digest.String(), ":", "-", 1) + cosignSignatureTagSuffix
+}
+
+func verifySignature(publicKey crypto.PublicKey, payload, signature []byte) bool {
+	switch key := publicKey.(type) {
+	case *ecdsa.PublicKey:
+		digest := sha256.Sum256(payload)
+		return ecdsa.VerifyASN1(key, digest[:], signature)
+	case ed25519.PublicKey:
+		return ed25519.Verify(key, payload, signature)
+	default:
+		return false
+	}
+}
+
+// ReadSignaturePublicKey reads a cosign public key in PKIX PEM, as generated by
+// cosign generate-key-pair. ECDSA and Ed25519 keys are supported.
+func ReadSignaturePublicKey(path string) (crypto.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse public key %s: %w", path, err)
+	}
+	switch key.(type) {
+	case *ecdsa.PublicKey, ed25519.PublicKey:
+		return key, nil
+	default:
+		return nil, fmt.Errorf("public key %s is neither an ECDSA nor an Ed25519 key", path)
+	}
+}
+
+// VerifyChecksums checks the files against a checksum file in the format of sha256sum, as
+// published with RKE2 releases. Every file must be listed.
+func VerifyChecksums(checksumFile string, files ...string) error {
+	input, err := os.Open(checksumFile)
+	if err != nil {
+		return err
+	}
+	defer input.Close()
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(input)
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 2 {
+			checksums[strings.TrimPrefix(fields[1], "*")] = strings.ToLower(fields[0])
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("failed to read %s: %w", checksumFile, err)
+	}
+	for _, file := range files {
+		expected, ok := checksums[filepath.Base(file)]
+		if !ok {
+			return fmt.Errorf("%s is not listed in %s", filepath.Base(file), checksumFile)
+		}
+		digest, err := fileDigest(file)
+		if err != nil {
+			return err
+		}
+		if hex.EncodeToString(digest) != expected {
+			return fmt.Errorf("checksum of %s does not match %s", file, checksumFile)
+		}
+	}
+	return nil
+}
+
+// verifyDigest checks a file against its pinned SHA256.
+func verifyDigest(path, expected string) error {
+	digest, err := fileDigest(path)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(digest) != strings.ToLower(expected) {
+		return fmt.Errorf("digest of %s does not match the pinned digest", filepath.Base(path))
+	}
+	return nil
+}
+
+// verifyChart checks a chart archive against the digest in the index of its Helm repository.
+func verifyChart(ctx context.Context, client *http.Client, repository, name, version, archive string) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimSuffix(repository, "/")+"/index.yaml", nil)
+	if err != nil {
+		return err
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		return fmt.Errorf("failed to get the index of %s: %w", repository, err)
+	}
+	defer response.Body.Close()
+	if response.StatusCode != http.StatusOK {
+		return fmt.Errorf("failed to get the index of %s: %s", repository, response.Status)
+	}
+	data, err := io.ReadAll(response.Body)
+	if err != nil {
+		return err
+	}
+	index := &repo.IndexFile{}
+	if err := sigsyaml.Unmarshal(data, index); err != nil {
+		return fmt.Errorf("failed to parse the index of %s: %w", repository, err)
+	}
+	chartVersion, err := index.Get(name, version)
+	if err != nil {
+		return fmt.Errorf("chart %s %s not found in %s", name, version, repository)
+	}
+	if chartVersion.Digest == "" {
+		return fmt.Errorf("the index of %s has no digest for chart %s %s", repository, name, version)
+	}
+	digest, err := fileDigest(archive)
+	if err != nil {
+		return err
+	}
+	if hex.EncodeToString(digest) != chartVersion.Digest {
+		return fmt.Errorf("digest of %s does not match the index of %s", filepath.Base(archive), repository)
+	}
+	return nil
+}
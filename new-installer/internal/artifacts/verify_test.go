@@ -0,0 +1,160 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package artifacts_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/stretchr/testify/suite"
+	"oras.land/oras-go/v2"
+	"oras.land/oras-go/v2/content"
+	"oras.land/oras-go/v2/content/memory"
+)
+
+type VerifyTest struct {
+	suite.Suite
+	dir string
+	key *ecdsa.PrivateKey
+}
+
+func TestVerify(t *testing.T) {
+	suite.Run(t, new(VerifyTest))
+}
+
+func (s *VerifyTest) SetupTest() {
+	s.dir = s.T().TempDir()
+	var err error
+	s.key, err = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+}
+
+func sha256Hex(data []byte) string {
+	digest := sha256.Sum256(data)
+	return hex.EncodeToString(digest[:])
+}
+
+func (s *VerifyTest) TestVerifyChecksums() {
+	binary := filepath.Join(s.dir, "rke2.linux-amd64.tar.gz")
+	images := filepath.Join(s.dir, "rke2-images.linux-amd64.tar.zst")
+	s.Require().NoError(os.WriteFile(binary, []byte("binary"), 0o600))
+	s.Require().NoError(os.WriteFile(images, []byte("images"), 0o600))
+	checksums := filepath.Join(s.dir, "sha256sum-amd64.txt")
+	s.Require().NoError(os.WriteFile(checksums, []byte(fmt.Sprintf("%s  rke2.linux-amd64.tar.gz\n%s  rke2-images.linux-amd64.tar.zst\n",
+		sha256Hex([]byte("binary")), sha256Hex([]byte("images")))), 0o600))
+
+	s.NoError(artifacts.VerifyChecksums(checksums, binary, images))
+
+	s.Require().NoError(os.WriteFile(images, []byte("tampered"), 0o600))
+	s.ErrorContains(artifacts.VerifyChecksums(checksums, binary, images), "does not match")
+
+	unlisted := filepath.Join(s.dir, "install.sh")
+	s.Require().NoError(os.WriteFile(unlisted, []byte("#!/bin/sh\n"), 0o600))
+	s.ErrorContains(artifacts.VerifyChecksums(checksums, unlisted), "not listed")
+}
+
+// pushSigned stores an artifact in the store, signed by cosign with the key.
+func (s *VerifyTest) pushSigned(ctx context.Context, store oras.Target, key *ecdsa.PrivateKey) ocispec.Descriptor {
+	manifest, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.test", oras.PackManifestOptions{})
+	s.Require().NoError(err)
+	if key == nil {
+		return manifest
+	}
+
+	payload := []byte(fmt.Sprintf(`{"critical":{"identity":{"docker-reference":"registry.example.com/installer"},`+
+		`"image":{"docker-manifest-digest":"%s"},"type":"cosign container image signature"},"optional":null}`, manifest.Digest))
+	digest := sha256.Sum256(payload)
+	signature, err := ecdsa.SignASN1(rand.Reader, key, digest[:])
+	s.Require().NoError(err)
+	layer := content.NewDescriptorFromBytes("application/vnd.dev.cosign.simplesigning.v1+json", payload)
+	layer.Annotations = map[string]string{"dev.cosignproject.cosign/signature": base64.StdEncoding.EncodeToString(signature)}
+	s.Require().NoError(store.Push(ctx, layer, bytes.NewReader(payload)))
+	signatures, err := oras.PackManifest(ctx, store, oras.PackManifestVersion1_1, "application/vnd.test.signature", oras.PackManifestOptions{
+		Layers: []ocispec.Descriptor{layer},
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(store.Tag(ctx, signatures, strings.Replace(manifest.Digest.String(), ":", "-", 1)+".sig"))
+	return manifest
+}
+
+func (s *VerifyTest) TestCosignVerifier() {
+	ctx := context.Background()
+	store := memory.New()
+	manifest := s.pushSigned(ctx, store, s.key)
+
+	verifier := &artifacts.CosignVerifier{PublicKey: &s.key.PublicKey}
+	s.NoError(verifier.Verify(ctx, store, manifest))
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	s.Require().NoError(err)
+	otherVerifier := &artifacts.CosignVerifier{PublicKey: &otherKey.PublicKey}
+	s.ErrorContains(otherVerifier.Verify(ctx, store, manifest), "matches the public key")
+}
+
+func (s *VerifyTest) TestCosignVerifierUnsigned() {
+	ctx := context.Background()
+	store := memory.New()
+	manifest := s.pushSigned(ctx, store, nil)
+
+	verifier := &artifacts.CosignVerifier{PublicKey: &s.key.PublicKey}
+	s.ErrorContains(verifier.Verify(ctx, store, manifest), "no signature found")
+}
+
+func (s *VerifyTest) TestReadSignaturePublicKey() {
+	der, err := x509.MarshalPKIXPublicKey(&s.key.PublicKey)
+	s.Require().NoError(err)
+	path := filepath.Join(s.dir, "cosign.pub")
+	s.Require().NoError(os.WriteFile(path, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), 0o600))
+
+	publicKey, err := artifacts.ReadSignaturePublicKey(path)
+	s.Require().NoError(err)
+	s.True(s.key.PublicKey.Equal(publicKey))
+}
+
+func (s *VerifyTest) TestChartDigest() {
+	chart := []byte("chart archive")
+	digest := sha256Hex(chart)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/charts/index.yaml":
+			fmt.Fprintf(w, "apiVersion: v1\nentries:\n  gitea:\n  - name: gitea\n    version: 10.6.0\n    digest: %s\n    urls: [gitea-10.6.0.tgz]\n", digest)
+		case "/charts/gitea-10.6.0.tgz":
+			_, _ = w.Write(chart)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	artifact := artifacts.Artifact{
+		Kind:            artifacts.KindFile,
+		Name:            "gitea-10.6.0.tgz",
+		Repository:      "charts/gitea",
+		Tag:             "10.6.0",
+		URL:             server.URL + "/charts/gitea-10.6.0.tgz",
+		ChartRepository: server.URL + "/charts/",
+	}
+	source := &artifacts.ReleaseService{HTTPClient: server.Client()}
+	s.NoError(source.Fetch(context.Background(), artifact, s.dir))
+
+	chart = []byte("tampered chart archive")
+	s.ErrorContains(source.Fetch(context.Background(), artifact, s.dir), "does not match the index")
+}
@@ -9,7 +9,7 @@ import "os"
 // Current version
 // Should bump this every time we make backward-compatible config schema changes
 const (
-	UserConfigVersion   = 4
+	UserConfigVersion   = 18
 	RuntimeStateVersion = 2
 )
 
@@ -21,6 +21,75 @@ const (
 	MinRuntimeStateVersion = 1
 )
 
+// When AWS.CacheRegistry is set to this value, the installer creates ECR pull-through
+// cache rules instead of using an existing cache registry.
+const ECRCacheRegistry = "ecr"
+
+// Observability buckets created by the installer, used as keys of AWS.O11yBuckets.
+var ObservabilityBucketNames = []string{
+	"orch-loki-admin",
+	"orch-loki-chunks",
+	"orch-loki-ruler",
+	"orch-mimir-ruler",
+	"orch-mimir-tsdb",
+	"fm-loki-admin",
+	"fm-loki-chunks",
+	"fm-loki-ruler",
+	"fm-mimir-ruler",
+	"fm-mimir-tsdb",
+	"tempo-traces",
+}
+
+// Lifecycle and retention of an observability bucket. Zero values keep the default,
+// i.e. objects are moved to Intelligent-Tiering and never expire.
+type ObservabilityBucketConfig struct {
+	ExpirationDays       int    `yaml:"expirationDays,omitempty"`
+	InfrequentAccessDays int    `yaml:"infrequentAccessDays,omitempty"`
+	GlacierDays          int    `yaml:"glacierDays,omitempty"`
+	ObjectLockMode       string `yaml:"objectLockMode,omitempty"` // GOVERNANCE or COMPLIANCE, object lock is disabled if empty
+	ObjectLockDays       int    `yaml:"objectLockDays,omitempty"`
+}
+
+// Where the artifacts of an on-prem release are pulled from and stored, resolved from the
+// VERSION file and the artifacts section of the config.
+type ArtifactLocations struct {
+	OrchVersion string `yaml:"orchVersion"`
+	RKE2Version string `yaml:"rke2Version"`
+	// Release service registry and the repository prefixes of the installers and archives
+	Registry       string `yaml:"registry"`
+	InstallersPath string `yaml:"installersPath"`
+	ArchivesPath   string `yaml:"archivesPath"`
+	// Directories under the working directory the artifacts are fetched to
+	InstallersDir string `yaml:"installersDir"`
+	ArchivesDir   string `yaml:"archivesDir"`
+	ChartsDir     string `yaml:"chartsDir"`
+	ImagesDir     string `yaml:"imagesDir"`
+	// Downloaded artifacts are cached here, keyed by their digest, across runs
+	CacheDir string `yaml:"cacheDir"`
+}
+
+// A stage of a provider and its steps, in the order they run on install. Labels select the stage
+// with --target.
+type StageLayout struct {
+	Name   string   `yaml:"name"`
+	Labels []string `yaml:"labels,omitempty"`
+	Steps  []string `yaml:"steps"`
+}
+
+// Roles of the additional on-prem nodes
+const (
+	OnpremNodeRoleServer = "server"
+	OnpremNodeRoleAgent  = "agent"
+)
+
+// An additional RKE2 node joined to the on-prem cluster over SSH.
+type OnpremNode struct {
+	Address    string `yaml:"address"`
+	Role       string `yaml:"role"` // server or agent
+	SSHUser    string `yaml:"sshUser"`
+	SSHKeyPath string `yaml:"sshKeyPath"`
+}
+
 type OrchInstallerRuntimeState struct {
 	Version int `yaml:"version"`
 	// The Action that will be performed
@@ -54,7 +123,43 @@ type OrchInstallerRuntimeState struct {
 		JumpHostSSHKeyPrivateKey string   `yaml:"jumpHostSSHPrivateKey"`
 		EFSFileSystemID          string   `yaml:"efsFileSystemID"`
 		EKSOIDCIssuer            string   `yaml:"eksOIDCIssuer"`
+		EKSNodeSecurityGroupID   string   `yaml:"eksNodeSecurityGroupID"`
+		EKSNodeRoleName          string   `yaml:"eksNodeRoleName"`
 		ACMCertArn               string   `yaml:"acmCertArn"`
+		// Customer-managed key used by the state bucket, observability buckets, EFS and RDS.
+		// KMSKeyExisting is set when it is the existing key of the config rather than one the
+		// installer created.
+		KMSKeyARN      string `yaml:"kmsKeyARN"`
+		KMSKeyExisting bool   `yaml:"kmsKeyExisting"`
+		// Vault auto-unseal key and the IRSA role Vault uses to access it
+		VaultKMSKeyARN        string `yaml:"vaultKMSKeyARN"`
+		VaultRoleARN          string `yaml:"vaultRoleARN"`
+		KMSKeyRotationEnabled bool   `yaml:"kmsKeyRotationEnabled"`
+		// Target groups to be bound to the orchestrator services
+		TraefikTargetGroupARN     string `yaml:"traefikTargetGroupARN"`
+		TraefikGRPCTargetGroupARN string `yaml:"traefikGRPCTargetGroupARN"`
+		Traefik2TargetGroupARN    string `yaml:"traefik2TargetGroupARN"`
+		ArgoCDTargetGroupARN      string `yaml:"argoCDTargetGroupARN"`
+		GiteaTargetGroupARN       string `yaml:"giteaTargetGroupARN"`
+		// Orchestrator endpoints, e.g. argocd.<orchName>.<parentDomain>. The other hostnames of
+		// the orchestrator are CNAMEs of TraefikHostname.
+		OrchDomain       string `yaml:"orchDomain"`
+		TraefikHostname  string `yaml:"traefikHostname"`
+		ArgoCDHostname   string `yaml:"argoCDHostname"`
+		GiteaHostname    string `yaml:"giteaHostname"`
+		Traefik2Hostname string `yaml:"traefik2Hostname"`
+		// Warm standby in the disaster-recovery region
+		DR struct {
+			Region             string `yaml:"region"`
+			StateBucket        string `yaml:"stateBucket"`
+			KMSKeyARN          string `yaml:"kmsKeyARN"`
+			GlobalClusterID    string `yaml:"globalClusterID"`
+			ClusterARN         string `yaml:"clusterARN"`
+			DatabaseHost       string `yaml:"databaseHost"`
+			DatabaseReaderHost string `yaml:"databaseReaderHost"`
+			// Set once the standby has been promoted, the primary region is no longer in use
+			FailedOver bool `yaml:"failedOver"`
+		} `yaml:"dr"`
 	} `yaml:"aws,omitempty"`
 
 	// Database connection information. Used for both cloud and on-prem deployments.
@@ -64,6 +169,10 @@ type OrchInstallerRuntimeState struct {
 		Port       int    `yaml:"port"`
 		Username   string `yaml:"username"`
 		Password   string `yaml:"password"`
+		// Manual cluster snapshot taken before the last upgrade
+		PreUpgradeSnapshotARN string `yaml:"preUpgradeSnapshotARN"`
+		// The snapshot the cluster was restored from, must be kept to avoid replacing the cluster again
+		RestoreSnapshotIdentifier string `yaml:"restoreSnapshotIdentifier"`
 	}
 	Cert struct {
 		TLSCert string `yaml:"tlsCert"`
@@ -72,7 +181,31 @@ type OrchInstallerRuntimeState struct {
 	} `yaml:"cert,omitempty"`
 	Onprem struct {
 		KubeConfig string `yaml:"kubeConfig"`
-	} `yaml:"onprem,omitempty"`
+		// RKE2 version running on the cluster, updated after each upgrade hop
+		RKE2Version string `yaml:"rke2Version"`
+		// Host changes made by the OS configuration, reverted on uninstall
+		OSConfig struct {
+			ModulesConfigured bool `yaml:"modulesConfigured"`
+			SysctlConfigured  bool `yaml:"sysctlConfigured"`
+			// The drop-in files found on the host were backed up before being replaced
+			ModulesBackedUp    bool `yaml:"modulesBackedUp"`
+			SysctlBackedUp     bool `yaml:"sysctlBackedUp"`
+			HostpathDirCreated bool `yaml:"hostpathDirCreated"`
+			YqInstalled        bool `yaml:"yqInstalled"`
+			HelmInstalled      bool `yaml:"helmInstalled"`
+		} `yaml:"osConfig"`
+		GiteaInstalled bool `yaml:"giteaInstalled"`
+		// Commit of the deployment repo pushed to Gitea and the profile root-app was installed with
+		DeploymentRepoCommit string `yaml:"deploymentRepoCommit"`
+		RootAppProfile       string `yaml:"rootAppProfile"`
+		// Resolved by the ArtifactDownloader, shared with the RKE2 and Orchestrator steps
+		Artifacts ArtifactLocations `yaml:"artifacts"`
+	} `yaml:"onprem"`
+	ExistingCluster struct {
+		// Read from existingCluster.kubeConfigPath on every run
+		KubeConfig     string `yaml:"kubeConfig"`
+		RootAppProfile string `yaml:"rootAppProfile"`
+	} `yaml:"existingCluster,omitempty"`
 }
 
 type OrchInstallerConfig struct {
@@ -93,15 +226,26 @@ type OrchInstallerConfig struct {
 	AWS struct {
 		Region                string   `yaml:"region"`
 		CustomerTag           string   `yaml:"customerTag,omitempty"`
-		CacheRegistry         string   `yaml:"cacheRegistry,omitempty"`
+		CacheRegistry         string   `yaml:"cacheRegistry,omitempty"`  // Set to "ecr" to create ECR pull-through cache rules
+		DockerUsername        string   `yaml:"dockerUsername,omitempty"` // Docker Hub credential for the ECR pull-through cache
+		DockerToken           string   `yaml:"dockerToken,omitempty"`
 		JumpHostWhitelist     []string `yaml:"jumpHostWhitelist,omitempty"`
 		JumpHostIP            string   `yaml:"jumpHostIP,omitempty"`
 		JumpHostPrivKeyPath   string   `yaml:"jumpHostPrivKeyPath,omitempty"`
+		LBWhitelist           []string `yaml:"lbWhitelist,omitempty"`
 		VPCID                 string   `yaml:"vpcID,omitempty"`
 		ReduceNSTTL           bool     `yaml:"reduceNSTTL,omitempty"` // TODO: do we need this?
 		EKSDNSIP              string   `yaml:"eksDNSIP,omitempty"`    // TODO: do we need this?
 		EKSIAMRoles           []string `yaml:"eksIAMRoles,omitempty"`
+		KMSKeyARN             string   `yaml:"kmsKeyARN,omitempty"`
 		PreviousS3StateBucket string   `yaml:"previousS3StateBucket,omitempty"` // The S3 bucket where the previous state is stored, will be deprecated in version 3.2.
+
+		// Per-bucket lifecycle of the observability buckets, keyed by bucket name
+		O11yBuckets map[string]ObservabilityBucketConfig `yaml:"o11yBuckets,omitempty"`
+		// Replicate the observability buckets to this region for disaster recovery
+		O11yReplicationRegion string `yaml:"o11yReplicationRegion,omitempty"`
+		// Stand up a warm standby of the state bucket, RDS and KMS key in this region
+		DRRegion string `yaml:"drRegion,omitempty"`
 	} `yaml:"aws,omitempty"`
 	Onprem struct {
 		ArgoIP         string `yaml:"argoIP"`
@@ -109,7 +253,28 @@ type OrchInstallerConfig struct {
 		NginxIP        string `yaml:"nginxIP"`
 		DockerUsername string `yaml:"dockerUsername,omitempty"`
 		DockerToken    string `yaml:"dockerToken,omitempty"`
+
+		// Address of this machine, the first RKE2 server, that the additional nodes join
+		ServerAddress string `yaml:"serverAddress,omitempty"`
+		// Additional RKE2 server and agent nodes
+		Nodes []OnpremNode `yaml:"nodes,omitempty"`
+		// Pre-flight checks reported as warnings instead of blocking the install
+		IgnorePreflightChecks []string `yaml:"ignorePreflightChecks,omitempty"`
+		// Remove the OpenEBS hostpath directory, and the data of the local volumes, on uninstall
+		RemoveHostpathData bool `yaml:"removeHostpathData,omitempty"`
+		// Cluster profile in orch-configs/clusters that root-app is installed with, existing-cluster by default
+		DeploymentProfile string `yaml:"deploymentProfile,omitempty"`
 	} `yaml:"onprem,omitempty"`
+	// A conformant cluster the orchestrator is installed on, instead of creating one
+	ExistingCluster struct {
+		KubeConfigPath string `yaml:"kubeConfigPath"`
+		// Storage class of the Gitea volumes, the default storage class of the cluster when empty
+		StorageClass string `yaml:"storageClass,omitempty"`
+		// Cluster checks reported as warnings instead of blocking the install
+		IgnoreChecks []string `yaml:"ignoreChecks,omitempty"`
+		// Cluster profile in orch-configs/clusters that root-app is installed with, existing-cluster by default
+		DeploymentProfile string `yaml:"deploymentProfile,omitempty"`
+	} `yaml:"existingCluster,omitempty"`
 	Orch struct {
 		Enabled []string `yaml:"enabled"`
 	} `yaml:"orch"`
@@ -143,6 +308,33 @@ type OrchInstallerConfig struct {
 		ENSOCKSProxy string `yaml:"enSocksProxy,omitempty"`
 		ENNoProxy    string `yaml:"enNoProxy,omitempty"`
 	} `yaml:"proxy,omitempty"`
+	// Where the on-prem installer gets its artifacts from, the release service by default
+	Artifacts struct {
+		// Release to install, read from the VERSION file of the installer by default. Set it to
+		// install release candidates and dev builds, e.g. 3.1.0-dev-eca1939.
+		Version string `yaml:"version,omitempty"`
+		// Release service registry and repository prefixes of the installers and archives
+		Registry       string `yaml:"registry,omitempty"`
+		InstallersPath string `yaml:"installersPath,omitempty"`
+		ArchivesPath   string `yaml:"archivesPath,omitempty"`
+		// Credentials of the release service or mirror registry
+		Username string `yaml:"username,omitempty"`
+		Password string `yaml:"password,omitempty"`
+		// Directory the artifacts are fetched to, /tmp by default
+		WorkDir string `yaml:"workDir,omitempty"`
+		// Bundle created with `orch-installer bundle create`, for disconnected sites
+		Bundle string `yaml:"bundle,omitempty"`
+		// Ed25519 public key, in PEM, the signature of the bundle is verified with
+		BundlePublicKey string `yaml:"bundlePublicKey,omitempty"`
+		// Registry holding a copy of a bundle
+		MirrorRegistry  string `yaml:"mirrorRegistry,omitempty"`
+		MirrorPlainHTTP bool   `yaml:"mirrorPlainHTTP,omitempty"`
+		// cosign public key, in PEM, the installers and archives pulled from the release
+		// service or the mirror registry must be signed with
+		SignaturePublicKey string `yaml:"signaturePublicKey,omitempty"`
+	} `yaml:"artifacts,omitempty"`
+	// Stages of the provider and the steps they run, replacing the default layout of the provider
+	Stages []StageLayout `yaml:"stages,omitempty"`
 }
 
 type OrchApp struct {
@@ -53,3 +53,50 @@ func SliceToCommaSeparated(input []string) string {
 	}
 	return strings.Join(input, ", ")
 }
+
+// RedactedValue replaces the secrets in logs and support bundles.
+const RedactedValue = "[REDACTED]"
+
+// secretFields returns the passwords, tokens and keys of the config and runtime state, that
+// must not end up in logs or support bundles.
+func secretFields(cfg *OrchInstallerConfig, runtimeState *OrchInstallerRuntimeState) []*string {
+	return []*string{
+		&cfg.Global.AdminPassword,
+		&cfg.Advanced.AzureADRefreshToken,
+		&cfg.AWS.DockerToken,
+		&cfg.Onprem.DockerToken,
+		&cfg.Cert.TLSKey,
+		&cfg.SRE.Password,
+		&cfg.SRE.CASecret,
+		&cfg.SMTP.Password,
+		&cfg.Artifacts.Password,
+		&runtimeState.Database.Password,
+		&runtimeState.Cert.TLSKey,
+		&runtimeState.AWS.JumpHostSSHKeyPrivateKey,
+		// Kubeconfigs carry the client key of the cluster admin
+		&runtimeState.AWS.KubeConfig,
+		&runtimeState.Onprem.KubeConfig,
+		&runtimeState.ExistingCluster.KubeConfig,
+	}
+}
+
+// Secrets returns the values of the secrets that are set.
+func Secrets(cfg OrchInstallerConfig, runtimeState OrchInstallerRuntimeState) []string {
+	secrets := []string{}
+	for _, field := range secretFields(&cfg, &runtimeState) {
+		if *field != "" {
+			secrets = append(secrets, *field)
+		}
+	}
+	return secrets
+}
+
+// Redact returns copies of the config and runtime state with the secrets replaced.
+func Redact(cfg OrchInstallerConfig, runtimeState OrchInstallerRuntimeState) (OrchInstallerConfig, OrchInstallerRuntimeState) {
+	for _, field := range secretFields(&cfg, &runtimeState) {
+		if *field != "" {
+			*field = RedactedValue
+		}
+	}
+	return cfg, runtimeState
+}
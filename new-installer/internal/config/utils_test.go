@@ -83,3 +83,13 @@ global:
 	s.Equal(obj.Provider, obj2.Provider)
 	s.Equal(obj.Global.OrchName, obj2.Global.OrchName)
 }
+
+func (s *UtilsTestSuite) TestSecrets() {
+	cfg := config.OrchInstallerConfig{}
+	cfg.Global.AdminPassword = "admin-password"
+	cfg.Artifacts.Password = "registry-token"
+	runtimeState := config.OrchInstallerRuntimeState{}
+	runtimeState.Database.Password = "db-password"
+
+	s.ElementsMatch([]string{"admin-password", "registry-token", "db-password"}, config.Secrets(cfg, runtimeState))
+}
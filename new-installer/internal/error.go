@@ -4,6 +4,11 @@
 
 package internal
 
+import (
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
+)
+
 type OrchInstallerErrorCode int
 
 const (
@@ -22,3 +27,14 @@ type OrchInstallerError struct {
 func (e *OrchInstallerError) Error() string {
 	return e.ErrorMsg
 }
+
+// EndSpan ends a span of a stage or step with its error, if any. A nil *OrchInstallerError is not
+// a nil error, so it cannot be given to tracing.End as is.
+func EndSpan(span trace.Span, err *OrchInstallerError) {
+	if err == nil {
+		tracing.End(span, nil)
+		return
+	}
+	span.SetAttributes(tracing.ErrorCodeKey.Int(int(err.ErrorCode)))
+	tracing.End(span, err)
+}
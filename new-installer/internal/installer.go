@@ -7,12 +7,15 @@ package internal
 import (
 	"context"
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/knadh/koanf/parsers/yaml"
 	"github.com/knadh/koanf/providers/structs"
 	"github.com/knadh/koanf/v2"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
 )
 
 type OrchInstaller struct {
@@ -73,9 +76,11 @@ func CreateOrchInstaller(stages []OrchInstallerStage) (*OrchInstaller, error) {
 	}, nil
 }
 
-func (o *OrchInstaller) Run(ctx context.Context, config config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) *OrchInstallerError {
+func (o *OrchInstaller) Run(ctx context.Context, config config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) (err *OrchInstallerError) {
 	logger := Logger()
 	action := runtimeState.Action
+	ctx, span := tracing.Start(ctx, "OrchInstaller.Run", tracing.ActionKey.String(action))
+	defer func() { EndSpan(span, err) }()
 	if action == "" {
 		return &OrchInstallerError{
 			ErrorCode: OrchInstallerErrorCodeInvalidArgument,
@@ -92,36 +97,58 @@ func (o *OrchInstaller) Run(ctx context.Context, config config.OrchInstallerConf
 	if action == "uninstall" {
 		o.Stages = ReverseStages(o.Stages)
 	}
-	o.Stages = FilterStages(o.Stages, runtimeState.TargetLabels)
+	sel, parseErr := selector.Parse(runtimeState.TargetLabels)
+	if parseErr != nil {
+		return &OrchInstallerError{
+			ErrorCode: OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  parseErr.Error(),
+		}
+	}
+	o.Stages = FilterStages(o.Stages, sel)
 	// Nothing to do if no stages are found
 	if len(o.Stages) == 0 {
 		return nil
 	}
+	// Fail before anything runs rather than make the changes of the steps that would ignore it
+	if runtimeState.DryRun {
+		if unsupported := UnsupportedDryRun(o.Stages, sel); len(unsupported) > 0 {
+			return &OrchInstallerError{
+				ErrorCode: OrchInstallerErrorCodeInvalidArgument,
+				ErrorMsg: fmt.Sprintf("--dry-run is not supported by %s, select the steps that support it with --target",
+					strings.Join(unsupported, ", ")),
+			}
+		}
+	}
 	for _, stage := range o.Stages {
-		var err *OrchInstallerError
 		if o.Cancelled() {
 			logger.Info("Installation cancelled")
 			break
 		}
 		name := stage.Name()
 		logger.Infof("Running stage: %s", name)
-		err = stage.PreStage(ctx, &config, runtimeState)
-
-		// We will skip to run the stage if the previous stage failed
-		if err == nil {
-			err = stage.RunStage(ctx, &config, runtimeState)
-		}
-
-		// But we will always run the post stage, the post stage should
-		// handle the error and rollback if needed.
-		err = stage.PostStage(ctx, &config, runtimeState, err)
-		if err != nil {
+		if err := o.runStage(ctx, stage, &config, runtimeState); err != nil {
 			return err
 		}
 	}
 	return nil
 }
 
+func (o *OrchInstaller) runStage(ctx context.Context, stage OrchInstallerStage, config *config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) (err *OrchInstallerError) {
+	ctx, span := tracing.Start(ctx, "stage "+stage.Name(), tracing.StageKey.String(stage.Name()))
+	defer func() { EndSpan(span, err) }()
+
+	err = stage.PreStage(ctx, config, runtimeState)
+
+	// We will skip to run the stage if the previous stage failed
+	if err == nil {
+		err = stage.RunStage(ctx, config, runtimeState)
+	}
+
+	// But we will always run the post stage, the post stage should
+	// handle the error and rollback if needed.
+	return stage.PostStage(ctx, config, runtimeState, err)
+}
+
 func (o *OrchInstaller) CancelInstallation() {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
@@ -10,6 +10,7 @@ import (
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
 )
@@ -194,6 +195,29 @@ func (s *OrchInstallerTest) TestOrchInstallerInstallSpecificStagesWithTwoLabels(
 	}
 }
 
+// Installer will skip the stages excluded in the config, and run the stages matching all the
+// labels of an expression
+func (s *OrchInstallerTest) TestOrchInstallerInstallExcludedStages() {
+	ctx := context.Background()
+	orchConfig := config.OrchInstallerConfig{}
+	runtimeState := config.OrchInstallerRuntimeState{
+		Action: "install",
+	}
+	runtimeState.TargetLabels = []string{"!label3"}
+	stage1 := createMockStage("MockStage1", true, []string{"label1", "label2"})
+	stage2 := createMockStage("MockStage2", false, []string{"label3", "label4"})
+	installer, err := internal.CreateOrchInstaller([]internal.OrchInstallerStage{stage1, stage2})
+	s.Require().NoError(err)
+	s.Nil(installer.Run(ctx, orchConfig, &runtimeState))
+
+	runtimeState.TargetLabels = []string{"label1+label4", "MockStage2+label4"}
+	stage1 = createMockStage("MockStage1", false, []string{"label1", "label2"})
+	stage2 = createMockStage("MockStage2", true, []string{"label3", "label4"})
+	installer, err = internal.CreateOrchInstaller([]internal.OrchInstallerStage{stage1, stage2})
+	s.Require().NoError(err)
+	s.Nil(installer.Run(ctx, orchConfig, &runtimeState))
+}
+
 func (s *OrchInstallerTest) TestOrchInstallerInvalidArgument() {
 	ctx := context.Background()
 	orchConfig := config.OrchInstallerConfig{}
@@ -217,6 +241,53 @@ func (s *OrchInstallerTest) TestOrchInstallerInvalidArgument() {
 		ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
 		ErrorMsg:  "unsupported action: invalid",
 	}, installerErr)
+
+	runtimeState = config.OrchInstallerRuntimeState{
+		Action: "install",
+	}
+	runtimeState.TargetLabels = []string{"infra+"}
+	installerErr = installer.Run(ctx, orchConfig, &runtimeState)
+	s.Require().NotNil(installerErr)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, installerErr.ErrorCode)
+}
+
+// dryRunStageMock is a stage of which only some steps honor --dry-run
+type dryRunStageMock struct {
+	*OrchInstallerStageMock
+}
+
+func (m *dryRunStageMock) StepTargets() []selector.Target {
+	return []selector.Target{{Name: "DryRunStep", Labels: []string{"dry"}}, {Name: "OtherStep", Labels: []string{"other"}}}
+}
+
+func (m *dryRunStageMock) DryRunSteps() []string {
+	return []string{"DryRunStep"}
+}
+
+func (s *OrchInstallerTest) TestOrchInstallerDryRun() {
+	ctx := context.Background()
+	orchConfig := config.OrchInstallerConfig{}
+	runtimeState := config.OrchInstallerRuntimeState{
+		Action: "install",
+		DryRun: true,
+	}
+	stage1 := &dryRunStageMock{createMockStage("MockStage1", false, []string{"label1"})}
+	stage2 := createMockStage("MockStage2", false, []string{"label2"})
+	installer, err := internal.CreateOrchInstaller([]internal.OrchInstallerStage{stage1, stage2})
+	s.Require().NoError(err)
+	installerErr := installer.Run(ctx, orchConfig, &runtimeState)
+	s.Equal(&internal.OrchInstallerError{
+		ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+		ErrorMsg:  "--dry-run is not supported by OtherStep, MockStage2, select the steps that support it with --target",
+	}, installerErr)
+
+	stage1 = &dryRunStageMock{createMockStage("MockStage1", true, []string{"label1"})}
+	stage2 = createMockStage("MockStage2", false, []string{"label2"})
+	runtimeState.TargetLabels = []string{"dry"}
+	installer, err = internal.CreateOrchInstaller([]internal.OrchInstallerStage{stage1, stage2})
+	s.Require().NoError(err)
+	s.Nil(installer.Run(ctx, orchConfig, &runtimeState))
+	stage1.AssertCalled(s.T(), "RunStage", mock.Anything, mock.Anything)
 }
 
 func (s *OrchInstallerTest) TestUpdateRuntimeState() {
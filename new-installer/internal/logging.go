@@ -5,14 +5,62 @@
 package internal
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
 
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 )
 
+const (
+	LogFormatConsole = "console"
+	LogFormatJSON    = "json"
+
+	// The installer log in the log dir, with the entries of every step
+	InstallerLogFile = "orch-installer.log"
+	// Each step also logs to <stage>-<step>.log in this directory of the log dir
+	StepLogDir = "steps"
+
+	defaultMaxLogSizeMB  = 100
+	defaultMaxLogBackups = 5
+	// Shorter secrets are not redacted, they would mangle every entry
+	minSecretLength = 4
+)
+
+// LogOptions tune InitLogger, the zero value logs at info level in the console format.
+type LogOptions struct {
+	// debug, info, warn or error
+	Level string
+	Dir   string
+	// console or json, for both the terminal and the log files
+	Format string
+	// Log files are rotated at this size, keeping MaxBackups of the old ones
+	MaxSizeMB  int
+	MaxBackups int
+}
+
+// logging holds what the step logs are built from.
+var logging struct {
+	sync.Mutex
+	options LogOptions
+	level   zap.AtomicLevel
+	encoder zapcore.Encoder
+	// Writes to the terminal and the installer log, without redaction
+	core    zapcore.Core
+	root    *zap.Logger
+	secrets []string
+}
+
 func parseLogLevel(level string) zapcore.Level {
 	switch level {
 	case "debug":
@@ -28,31 +76,268 @@ func parseLogLevel(level string) zapcore.Level {
 	}
 }
 
-func InitLogger(logLevel string, logDir string) error {
-	if _, err := os.Stat(logDir); os.IsNotExist(err) {
-		err := os.MkdirAll(logDir, os.ModePerm)
-		if err != nil {
-			return err
-		}
+func InitLogger(options LogOptions) error {
+	if options.Dir == "" {
+		return fmt.Errorf("the log dir is not set")
+	}
+	if options.MaxSizeMB <= 0 {
+		options.MaxSizeMB = defaultMaxLogSizeMB
 	}
-	logFile := filepath.Join(logDir, "orch-installer.log")
-	loggerConfig := zap.NewDevelopmentConfig()
-	loggerConfig.Level.SetLevel(parseLogLevel(logLevel))
-	loggerConfig.OutputPaths = []string{"stdout", logFile}
-	loggerRoot, err := loggerConfig.Build()
+	if options.MaxBackups <= 0 {
+		options.MaxBackups = defaultMaxLogBackups
+	}
+	if err := os.MkdirAll(options.Dir, os.ModePerm); err != nil {
+		return err
+	}
+
+	var encoder zapcore.Encoder
+	switch options.Format {
+	case "", LogFormatConsole:
+		encoder = zapcore.NewConsoleEncoder(zap.NewDevelopmentEncoderConfig())
+	case LogFormatJSON:
+		encoder = zapcore.NewJSONEncoder(zap.NewProductionEncoderConfig())
+	default:
+		return fmt.Errorf("unsupported log format %s", options.Format)
+	}
+	logFile, err := openRotatingFile(filepath.Join(options.Dir, InstallerLogFile), options)
 	if err != nil {
 		return err
 	}
-	zap.ReplaceGlobals(loggerRoot)
-	zap.S().Infof("Log level set to %s", logLevel)
+	level := zap.NewAtomicLevelAt(parseLogLevel(options.Level))
+	core := zapcore.NewTee(
+		zapcore.NewCore(encoder, zapcore.Lock(os.Stdout), level),
+		zapcore.NewCore(encoder, logFile, level),
+	)
+
+	logging.Lock()
+	logging.options = options
+	logging.level = level
+	logging.encoder = encoder
+	logging.core = core
+	logging.root = newLogger(core)
+	logging.Unlock()
+	zap.ReplaceGlobals(logging.root)
+	zap.S().Infof("Log level set to %s", level.Level())
 
 	return nil
 }
 
+func newLogger(core zapcore.Core) *zap.Logger {
+	return zap.New(&redactingCore{core}, zap.AddCaller(), zap.AddStacktrace(zap.ErrorLevel))
+}
+
 func Logger() *zap.SugaredLogger {
 	return zap.S()
 }
 
+// RedactSecrets replaces the values in every log entry written afterwards, e.g. the passwords
+// and tokens of the config.
+func RedactSecrets(values ...string) {
+	logging.Lock()
+	defer logging.Unlock()
+	for _, value := range values {
+		if len(value) >= minSecretLength {
+			logging.secrets = append(logging.secrets, value)
+		}
+	}
+}
+
+func redact(message string) string {
+	logging.Lock()
+	secrets := logging.secrets
+	logging.Unlock()
+	for _, secret := range secrets {
+		message = strings.ReplaceAll(message, secret, config.RedactedValue)
+	}
+	return message
+}
+
+// redactingCore redacts the secrets from the message and string fields of the entries.
+type redactingCore struct {
+	zapcore.Core
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = redact(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, 0, len(fields))
+	for _, field := range fields {
+		switch field.Type {
+		case zapcore.StringType:
+			field.String = redact(field.String)
+		case zapcore.ErrorType:
+			if err, ok := field.Interface.(error); ok {
+				field = zap.String(field.Key, redact(err.Error()))
+			}
+		}
+		redacted = append(redacted, field)
+	}
+	return redacted
+}
+
+// StepLog sends the entries logged during a step to the log file of the step too, tagged with
+// the stage, step and phase.
+type StepLog struct {
+	logger *zap.Logger
+	file   *rotatingFile
+	stage  string
+	step   string
+}
+
+var unsafeFileName = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// StartStepLog replaces the global logger until Close. Steps run one at a time, so the entries
+// of a step are the ones logged between the two.
+func StartStepLog(stage, step string) (*StepLog, error) {
+	logging.Lock()
+	options, core, encoder, level := logging.options, logging.core, logging.encoder, logging.level
+	logging.Unlock()
+
+	stepLog := &StepLog{stage: stage, step: step}
+	if core == nil {
+		// The logger is not initialized, e.g. in tests
+		stepLog.logger = zap.L()
+	} else {
+		name := unsafeFileName.ReplaceAllString(stage+"-"+step, "_") + ".log"
+		if err := os.MkdirAll(filepath.Join(options.Dir, StepLogDir), os.ModePerm); err != nil {
+			return nil, err
+		}
+		file, err := openRotatingFile(filepath.Join(options.Dir, StepLogDir, name), options)
+		if err != nil {
+			return nil, err
+		}
+		stepLog.file = file
+		stepLog.logger = newLogger(zapcore.NewTee(core, zapcore.NewCore(encoder, file, level)))
+	}
+	stepLog.logger = stepLog.logger.With(zap.String("stage", stage), zap.String("step", step))
+	zap.ReplaceGlobals(stepLog.logger)
+	return stepLog, nil
+}
+
+// Phase tags the entries logged from now on with the phase of the step, e.g. RunStep.
+func (l *StepLog) Phase(phase string) {
+	zap.ReplaceGlobals(l.logger.With(zap.String("phase", phase)))
+}
+
+// StartPhase tags the entries logged from now on with the phase, and starts the span of the
+// phase. The span is the current one until the next phase, for the calls not given its context.
+func (l *StepLog) StartPhase(ctx context.Context, phase string) (context.Context, trace.Span) {
+	l.Phase(phase)
+	ctx, span := tracing.Start(ctx, phase+" "+l.step,
+		tracing.StageKey.String(l.stage), tracing.StepKey.String(l.step), tracing.PhaseKey.String(phase))
+	tracing.SetCurrent(ctx)
+	return ctx, span
+}
+
+// Close restores the global logger.
+func (l *StepLog) Close() error {
+	logging.Lock()
+	root := logging.root
+	logging.Unlock()
+	if root != nil {
+		zap.ReplaceGlobals(root)
+	}
+	tracing.SetCurrent(context.Background())
+	if l.file == nil {
+		return nil
+	}
+	return l.file.Close()
+}
+
 func FileLogWriter(logFile string) (io.Writer, error) {
 	return os.OpenFile(logFile, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
 }
+
+// rotatingFile is a log file that is moved to <path>.1 once it grows past its maximum size,
+// shifting the older backups and dropping the last one.
+type rotatingFile struct {
+	mutex   sync.Mutex
+	path    string
+	maxSize int64
+	backups int
+	file    *os.File
+	size    int64
+}
+
+func openRotatingFile(path string, options LogOptions) (*rotatingFile, error) {
+	f := &rotatingFile{
+		path:    path,
+		maxSize: int64(options.MaxSizeMB) * 1024 * 1024,
+		backups: options.MaxBackups,
+	}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+func (f *rotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	info, err := file.Stat()
+	if err != nil {
+		return errors.Join(err, file.Close())
+	}
+	f.file = file
+	f.size = info.Size()
+	return nil
+}
+
+func (f *rotatingFile) Write(data []byte) (int, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.size > 0 && f.size+int64(len(data)) > f.maxSize {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := f.file.Write(data)
+	f.size += int64(n)
+	return n, err
+}
+
+func (f *rotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return err
+	}
+	for i := f.backups - 1; i >= 1; i-- {
+		backup := fmt.Sprintf("%s.%d", f.path, i)
+		if _, err := os.Stat(backup); err == nil {
+			if err := os.Rename(backup, fmt.Sprintf("%s.%d", f.path, i+1)); err != nil {
+				return err
+			}
+		}
+	}
+	if err := os.Rename(f.path, f.path+".1"); err != nil {
+		return err
+	}
+	return f.open()
+}
+
+func (f *rotatingFile) Sync() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.file.Sync()
+}
+
+func (f *rotatingFile) Close() error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.file.Close()
+}
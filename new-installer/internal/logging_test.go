@@ -0,0 +1,102 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package internal_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+)
+
+type LoggingTest struct {
+	suite.Suite
+	dir string
+}
+
+func TestLogging(t *testing.T) {
+	suite.Run(t, new(LoggingTest))
+}
+
+func (s *LoggingTest) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.Require().NoError(internal.InitLogger(internal.LogOptions{Dir: s.dir, Format: internal.LogFormatJSON}))
+}
+
+func (s *LoggingTest) TearDownTest() {
+	zap.ReplaceGlobals(zap.NewNop())
+}
+
+// readEntries returns the JSON entries of a log file.
+func (s *LoggingTest) readEntries(path string) []map[string]any {
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+	entries := []map[string]any{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		entry := map[string]any{}
+		s.Require().NoError(json.Unmarshal(scanner.Bytes(), &entry))
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func (s *LoggingTest) TestStepLog() {
+	stepLog, err := internal.StartStepLog("Infra", "Rke2Step")
+	s.Require().NoError(err)
+	stepLog.Phase("RunStep")
+	internal.Logger().Info("Installing RKE2...")
+	s.Require().NoError(stepLog.Close())
+	internal.Logger().Info("Running stage: Orchestrator")
+
+	stepEntries := s.readEntries(filepath.Join(s.dir, internal.StepLogDir, "Infra-Rke2Step.log"))
+	s.Require().Len(stepEntries, 1)
+	s.Equal("Installing RKE2...", stepEntries[0]["msg"])
+	s.Equal("Infra", stepEntries[0]["stage"])
+	s.Equal("Rke2Step", stepEntries[0]["step"])
+	s.Equal("RunStep", stepEntries[0]["phase"])
+
+	// The installer log has the entries of every step, and the ones in between
+	messages := []string{}
+	for _, entry := range s.readEntries(filepath.Join(s.dir, internal.InstallerLogFile)) {
+		messages = append(messages, entry["msg"].(string))
+	}
+	s.Contains(messages, "Installing RKE2...")
+	s.Contains(messages, "Running stage: Orchestrator")
+}
+
+func (s *LoggingTest) TestRedactSecrets() {
+	internal.RedactSecrets("s3cr3t-token", "ab")
+	internal.Logger().Infof("Logging in with s3cr3t-token as ab")
+	internal.Logger().Infow("Pulling", "password", "s3cr3t-token")
+
+	entries := s.readEntries(filepath.Join(s.dir, internal.InstallerLogFile))
+	last := entries[len(entries)-2:]
+	// Values too short to be secrets are left alone
+	s.Equal("Logging in with [REDACTED] as ab", last[0]["msg"])
+	s.Equal("[REDACTED]", last[1]["password"])
+}
+
+func (s *LoggingTest) TestRotation() {
+	s.Require().NoError(internal.InitLogger(internal.LogOptions{Dir: s.dir, Format: internal.LogFormatJSON, MaxSizeMB: 1, MaxBackups: 2}))
+	line := strings.Repeat("x", 64*1024)
+	for range 40 {
+		internal.Logger().Info(line)
+	}
+
+	s.FileExists(filepath.Join(s.dir, internal.InstallerLogFile+".1"))
+	s.FileExists(filepath.Join(s.dir, internal.InstallerLogFile+".2"))
+	s.NoFileExists(filepath.Join(s.dir, internal.InstallerLogFile+".3"))
+	info, err := os.Stat(filepath.Join(s.dir, internal.InstallerLogFile))
+	s.Require().NoError(err)
+	s.LessOrEqual(info.Size(), int64(1024*1024))
+}
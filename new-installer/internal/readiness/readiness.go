@@ -0,0 +1,336 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package readiness waits for the ArgoCD Applications of an orchestrator deployment to be
+// synced and healthy, with a deadline per application.
+package readiness
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/watch"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DiagnosticsFile is written to the diagnostics directory when an application misses its
+// deadline.
+const DiagnosticsFile = "argocd-apps-diagnostics.json"
+
+// DefaultAppTimeout is the time an application has to become synced and healthy unless it
+// has an entry in DefaultAppTimeouts.
+const DefaultAppTimeout = 60 * time.Minute
+
+// DefaultAppTimeouts are the deadlines of the applications known to take longer, or to be
+// stuck when they take longer, than DefaultAppTimeout. The deadline of the context given to
+// Waiter.Wait comes first, the applications still pending then are reported as late.
+var DefaultAppTimeouts = map[string]time.Duration{
+	"istiod":                     30 * time.Minute,
+	"kiali":                      35 * time.Minute,
+	"kube-prometheus-stack":      25 * time.Minute,
+	"kyverno":                    15 * time.Minute,
+	"mimir":                      20 * time.Minute,
+	"orchestrator-observability": 40 * time.Minute,
+	"postgresql":                 30 * time.Minute,
+	"edgenode-observability":     40 * time.Minute,
+	"root-app":                   120 * time.Minute, // Represents the full Orchestrator deployment
+	"secrets-config":             90 * time.Minute,
+	"sre-exporter":               20 * time.Minute,
+	"traefik":                    20 * time.Minute,
+}
+
+var (
+	// ErrAppTimeout is returned when an application is not ready by its deadline.
+	ErrAppTimeout = errors.New("application deployment exceeded its deadline")
+
+	// ApplicationResource is the ArgoCD application resource.
+	ApplicationResource = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+)
+
+// AppStatus is the state of an application at the time of an event.
+type AppStatus struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Sync      string `json:"sync"`
+	Health    string `json:"health"`
+	// Start of the current deployment attempt, the deadline counts from there
+	Since    time.Time     `json:"since"`
+	Deadline time.Duration `json:"deadline"`
+}
+
+// Ready reports whether the application is synced and healthy.
+func (a AppStatus) Ready() bool {
+	return a.Sync == "Synced" && a.Health == "Healthy"
+}
+
+// Event reports the progress of the deployment.
+type Event struct {
+	Time    time.Time
+	Total   int
+	Ready   int
+	Pending []AppStatus
+}
+
+// Options tune a Waiter, the zero value uses the defaults.
+type Options struct {
+	// Deadlines per application name, DefaultAppTimeouts when nil
+	AppTimeouts    map[string]time.Duration
+	DefaultTimeout time.Duration
+	// Progress is reported at this interval and after each change of an application
+	ProgressInterval time.Duration
+	OnProgress       func(Event)
+	// The diagnostics of stuck applications are written here, skipped when empty
+	DiagnosticsDir string
+	// Clock of the deadlines, time.Now when nil
+	Now func() time.Time
+}
+
+// Waiter follows the ArgoCD Applications of a cluster.
+type Waiter struct {
+	client  dynamic.Interface
+	options Options
+}
+
+// NewWaiter returns a Waiter for the applications of all namespaces of the client's cluster.
+func NewWaiter(client dynamic.Interface, options Options) *Waiter {
+	if options.AppTimeouts == nil {
+		options.AppTimeouts = DefaultAppTimeouts
+	}
+	if options.DefaultTimeout <= 0 {
+		options.DefaultTimeout = DefaultAppTimeout
+	}
+	if options.ProgressInterval <= 0 {
+		options.ProgressInterval = 10 * time.Second
+	}
+	if options.OnProgress == nil {
+		options.OnProgress = func(Event) {}
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	return &Waiter{client: client, options: options}
+}
+
+// CreateDynamicClient returns a client for the cluster of a kubeconfig.
+func CreateDynamicClient(kubeConfig string) (dynamic.Interface, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	client, err := dynamic.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	return client, nil
+}
+
+// Wait blocks until all applications are synced and healthy. It returns an error wrapping
+// ErrAppTimeout when an application misses its deadline, after writing the diagnostics.
+func (w *Waiter) Wait(ctx context.Context) error {
+	apps := map[string]*unstructured.Unstructured{}
+	for {
+		resourceVersion, err := w.list(ctx, apps)
+		if err != nil {
+			return err
+		}
+		if done, err := w.check(apps); done || err != nil {
+			return err
+		}
+		watcher, err := w.client.Resource(ApplicationResource).Namespace(metav1.NamespaceAll).Watch(ctx, metav1.ListOptions{
+			ResourceVersion: resourceVersion,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to watch ArgoCD applications: %w", err)
+		}
+		done, err := w.watch(ctx, watcher, apps)
+		watcher.Stop()
+		if done || err != nil {
+			return err
+		}
+		// The watch expired, list again to catch up
+	}
+}
+
+func (w *Waiter) list(ctx context.Context, apps map[string]*unstructured.Unstructured) (string, error) {
+	list, err := w.client.Resource(ApplicationResource).Namespace(metav1.NamespaceAll).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to list ArgoCD applications: %w", err)
+	}
+	clear(apps)
+	for i := range list.Items {
+		apps[key(&list.Items[i])] = &list.Items[i]
+	}
+	return list.GetResourceVersion(), nil
+}
+
+// watch applies the events to apps until all are ready, one misses its deadline or the watch
+// ends.
+func (w *Waiter) watch(ctx context.Context, watcher watch.Interface, apps map[string]*unstructured.Unstructured) (bool, error) {
+	ticker := time.NewTicker(w.options.ProgressInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return true, w.expired(ctx, apps)
+		case <-ticker.C:
+		case event, ok := <-watcher.ResultChan():
+			if !ok {
+				return false, nil
+			}
+			app, isApp := event.Object.(*unstructured.Unstructured)
+			switch {
+			case event.Type == watch.Error:
+				return false, nil
+			case !isApp:
+				continue
+			case event.Type == watch.Deleted:
+				delete(apps, key(app))
+			default:
+				apps[key(app)] = app
+			}
+		}
+		if done, err := w.check(apps); done || err != nil {
+			return true, err
+		}
+	}
+}
+
+// check reports progress and returns true once all applications are ready.
+func (w *Waiter) check(apps map[string]*unstructured.Unstructured) (bool, error) {
+	now := w.options.Now()
+	event := Event{Time: now, Total: len(apps)}
+	var late []AppStatus
+	for _, app := range apps {
+		status := w.status(app)
+		if status.Ready() {
+			event.Ready++
+			continue
+		}
+		event.Pending = append(event.Pending, status)
+		if !status.Since.IsZero() && now.Sub(status.Since) > status.Deadline {
+			late = append(late, status)
+		}
+	}
+	sort.Slice(event.Pending, func(i, j int) bool { return event.Pending[i].Name < event.Pending[j].Name })
+	w.options.OnProgress(event)
+
+	if len(late) > 0 {
+		sort.Slice(late, func(i, j int) bool { return late[i].Name < late[j].Name })
+		if err := w.writeDiagnostics(apps, event.Pending); err != nil {
+			return true, fmt.Errorf("application %s exceeded its %s deadline: %w, and the diagnostics could not be written: %w",
+				late[0].Name, late[0].Deadline, ErrAppTimeout, err)
+		}
+		return true, fmt.Errorf("application %s exceeded its %s deadline: %w", late[0].Name, late[0].Deadline, ErrAppTimeout)
+	}
+	// root-app creates the other applications, an empty list means it is not there yet
+	return event.Total > 0 && len(event.Pending) == 0, nil
+}
+
+// expired returns the error of a context that ended before the applications were ready. When
+// the context deadline passed, the pending applications missed it like their own deadline.
+func (w *Waiter) expired(ctx context.Context, apps map[string]*unstructured.Unstructured) error {
+	if !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return ctx.Err()
+	}
+	var pending []AppStatus
+	for _, app := range apps {
+		if status := w.status(app); !status.Ready() {
+			pending = append(pending, status)
+		}
+	}
+	if len(pending) == 0 {
+		return ctx.Err()
+	}
+	sort.Slice(pending, func(i, j int) bool { return pending[i].Name < pending[j].Name })
+	if err := w.writeDiagnostics(apps, pending); err != nil {
+		return fmt.Errorf("application %s is not ready by the installer deadline: %w: %w, and the diagnostics could not be written: %w",
+			pending[0].Name, ErrAppTimeout, ctx.Err(), err)
+	}
+	return fmt.Errorf("application %s is not ready by the installer deadline: %w: %w", pending[0].Name, ErrAppTimeout, ctx.Err())
+}
+
+func (w *Waiter) status(app *unstructured.Unstructured) AppStatus {
+	status := AppStatus{
+		Namespace: app.GetNamespace(),
+		Name:      app.GetName(),
+		Since:     app.GetCreationTimestamp().Time,
+		Deadline:  w.options.DefaultTimeout,
+	}
+	status.Sync, _, _ = unstructured.NestedString(app.Object, "status", "sync", "status")
+	status.Health, _, _ = unstructured.NestedString(app.Object, "status", "health", "status")
+	if deadline, ok := w.options.AppTimeouts[status.Name]; ok {
+		status.Deadline = deadline
+	}
+	// A degraded application keeps counting from its creation, as it is not making progress
+	if reconciledAt, _, _ := unstructured.NestedString(app.Object, "status", "reconciledAt"); reconciledAt != "" && status.Health != "Degraded" {
+		if t, err := time.Parse(time.RFC3339, reconciledAt); err == nil {
+			status.Since = t
+		}
+	}
+	return status
+}
+
+// appDiagnostics is what is known about why an application is not ready.
+type appDiagnostics struct {
+	AppStatus
+	Conditions       []any  `json:"conditions,omitempty"`
+	OperationPhase   string `json:"operationPhase,omitempty"`
+	OperationMessage string `json:"operationMessage,omitempty"`
+	// Resources of the application that are out of sync or not healthy
+	Resources []any `json:"resources,omitempty"`
+}
+
+func (w *Waiter) writeDiagnostics(apps map[string]*unstructured.Unstructured, pending []AppStatus) error {
+	if w.options.DiagnosticsDir == "" {
+		return nil
+	}
+	diagnostics := []appDiagnostics{}
+	for _, status := range pending {
+		app := apps[status.Namespace+"/"+status.Name]
+		d := appDiagnostics{AppStatus: status}
+		d.Conditions, _, _ = unstructured.NestedSlice(app.Object, "status", "conditions")
+		d.OperationPhase, _, _ = unstructured.NestedString(app.Object, "status", "operationState", "phase")
+		d.OperationMessage, _, _ = unstructured.NestedString(app.Object, "status", "operationState", "message")
+		resources, _, _ := unstructured.NestedSlice(app.Object, "status", "resources")
+		for _, resource := range resources {
+			r, ok := resource.(map[string]any)
+			if !ok {
+				continue
+			}
+			health, _, _ := unstructured.NestedString(r, "health", "status")
+			sync, _, _ := unstructured.NestedString(r, "status")
+			if (health != "" && health != "Healthy") || (sync != "" && sync != "Synced") {
+				d.Resources = append(d.Resources, r)
+			}
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	data, err := json.MarshalIndent(diagnostics, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(w.options.DiagnosticsDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(w.options.DiagnosticsDir, DiagnosticsFile), data, 0o600)
+}
+
+func key(app runtime.Object) string {
+	object, ok := app.(metav1.Object)
+	if !ok {
+		return ""
+	}
+	return object.GetNamespace() + "/" + object.GetName()
+}
@@ -0,0 +1,161 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package readiness_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/readiness"
+	"github.com/stretchr/testify/suite"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/client-go/dynamic/fake"
+)
+
+var applications = schema.GroupVersionResource{Group: "argoproj.io", Version: "v1alpha1", Resource: "applications"}
+
+func application(name, sync, health string, created time.Time) *unstructured.Unstructured {
+	app := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata": map[string]any{
+			"name":              name,
+			"namespace":         "onprem",
+			"creationTimestamp": created.UTC().Format(time.RFC3339),
+		},
+		"status": map[string]any{
+			"sync":   map[string]any{"status": sync},
+			"health": map[string]any{"status": health},
+		},
+	}}
+	return app
+}
+
+type ReadinessTest struct {
+	suite.Suite
+	now    time.Time
+	events []readiness.Event
+}
+
+func TestReadiness(t *testing.T) {
+	suite.Run(t, new(ReadinessTest))
+}
+
+func (s *ReadinessTest) SetupTest() {
+	s.now = time.Now().Truncate(time.Second)
+	s.events = nil
+}
+
+func (s *ReadinessTest) client(apps ...runtime.Object) *fake.FakeDynamicClient {
+	return fake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{applications: "ApplicationList"}, apps...)
+}
+
+func (s *ReadinessTest) waiter(client *fake.FakeDynamicClient, diagnosticsDir string) *readiness.Waiter {
+	return readiness.NewWaiter(client, readiness.Options{
+		ProgressInterval: 10 * time.Millisecond,
+		OnProgress:       func(event readiness.Event) { s.events = append(s.events, event) },
+		DiagnosticsDir:   diagnosticsDir,
+		Now:              func() time.Time { return s.now },
+	})
+}
+
+func (s *ReadinessTest) TestAllReady() {
+	client := s.client(
+		application("root-app", "Synced", "Healthy", s.now.Add(-time.Hour)),
+		application("traefik", "Synced", "Healthy", s.now.Add(-time.Hour)),
+	)
+	s.NoError(s.waiter(client, "").Wait(context.Background()))
+	s.Require().Len(s.events, 1)
+	s.Equal(2, s.events[0].Ready)
+	s.Equal(2, s.events[0].Total)
+}
+
+func (s *ReadinessTest) TestBecomesReady() {
+	client := s.client(
+		application("root-app", "Synced", "Healthy", s.now.Add(-time.Hour)),
+		application("traefik", "OutOfSync", "Progressing", s.now.Add(-time.Minute)),
+	)
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		_, _ = client.Resource(applications).Namespace("onprem").Update(context.Background(),
+			application("traefik", "Synced", "Healthy", s.now.Add(-time.Minute)), metav1.UpdateOptions{})
+	}()
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	s.NoError(s.waiter(client, "").Wait(ctx))
+	s.Require().NotEmpty(s.events)
+	s.Equal("traefik", s.events[0].Pending[0].Name)
+	s.Equal(20*time.Minute, s.events[0].Pending[0].Deadline)
+	s.Empty(s.events[len(s.events)-1].Pending)
+}
+
+func (s *ReadinessTest) TestNoApplicationsYet() {
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := s.waiter(s.client(), "").Wait(ctx)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.NotEmpty(s.events)
+}
+
+// The installer deadline ends the wait before the deadline of root-app
+func (s *ReadinessTest) TestContextDeadlineExceeded() {
+	client := s.client(application("root-app", "OutOfSync", "Progressing", s.now.Add(-time.Minute)))
+	dir := s.T().TempDir()
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	err := s.waiter(client, dir).Wait(ctx)
+	s.ErrorIs(err, readiness.ErrAppTimeout)
+	s.ErrorIs(err, context.DeadlineExceeded)
+	s.Contains(err.Error(), "root-app")
+	s.FileExists(filepath.Join(dir, readiness.DiagnosticsFile))
+}
+
+func (s *ReadinessTest) TestDeadlineExceeded() {
+	stuck := application("traefik", "Synced", "Degraded", s.now.Add(-21*time.Minute))
+	// Degraded apps count from their creation even if they were reconciled since
+	s.Require().NoError(unstructured.SetNestedField(stuck.Object, s.now.Add(-time.Minute).UTC().Format(time.RFC3339), "status", "reconciledAt"))
+	s.Require().NoError(unstructured.SetNestedSlice(stuck.Object, []any{
+		map[string]any{"kind": "Deployment", "name": "traefik", "status": "Synced", "health": map[string]any{"status": "Degraded", "message": "crash loop"}},
+		map[string]any{"kind": "Service", "name": "traefik", "status": "Synced", "health": map[string]any{"status": "Healthy"}},
+	}, "status", "resources"))
+	client := s.client(
+		stuck,
+		// Reconciled recently, its deadline has not passed
+		application("kyverno", "OutOfSync", "Progressing", s.now.Add(-3*time.Hour)),
+	)
+	kyverno, err := client.Resource(applications).Namespace("onprem").Get(context.Background(), "kyverno", metav1.GetOptions{})
+	s.Require().NoError(err)
+	s.Require().NoError(unstructured.SetNestedField(kyverno.Object, s.now.Add(-time.Minute).UTC().Format(time.RFC3339), "status", "reconciledAt"))
+	_, err = client.Resource(applications).Namespace("onprem").Update(context.Background(), kyverno, metav1.UpdateOptions{})
+	s.Require().NoError(err)
+
+	dir := s.T().TempDir()
+	err = s.waiter(client, dir).Wait(context.Background())
+	s.Require().Error(err)
+	s.True(errors.Is(err, readiness.ErrAppTimeout))
+	s.Contains(err.Error(), "traefik")
+	s.NotContains(err.Error(), "kyverno")
+
+	data, readErr := os.ReadFile(filepath.Join(dir, readiness.DiagnosticsFile))
+	s.Require().NoError(readErr)
+	diagnostics := []struct {
+		Name      string           `json:"name"`
+		Resources []map[string]any `json:"resources"`
+	}{}
+	s.Require().NoError(json.Unmarshal(data, &diagnostics))
+	s.Require().Len(diagnostics, 2)
+	s.Equal("kyverno", diagnostics[0].Name)
+	s.Equal("traefik", diagnostics[1].Name)
+	s.Require().Len(diagnostics[1].Resources, 1)
+	s.Equal("Deployment", diagnostics[1].Resources[0]["kind"])
+}
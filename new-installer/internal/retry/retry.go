@@ -0,0 +1,84 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package retry runs an operation again after a failure, waiting longer after each attempt.
+package retry
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	DefaultAttempts     = 5
+	DefaultInitialDelay = 2 * time.Second
+	DefaultMaxDelay     = time.Minute
+)
+
+// Options tune Do, the zero value uses the defaults.
+type Options struct {
+	// Attempts in total, including the first one
+	Attempts int
+	// The delay after the first failure, doubled after each one up to MaxDelay
+	InitialDelay time.Duration
+	MaxDelay     time.Duration
+	// OnRetry is called before waiting for the next attempt
+	OnRetry func(attempt int, err error, delay time.Duration)
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks an error that another attempt cannot fix, Do returns it right away.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// Do calls op until it succeeds, returns a permanent error, the attempts are exhausted or
+// the context is done. It returns the last error of op, unwrapped from Permanent.
+func Do(ctx context.Context, options Options, op func(attempt int) error) error {
+	if options.Attempts <= 0 {
+		options.Attempts = DefaultAttempts
+	}
+	if options.InitialDelay <= 0 {
+		options.InitialDelay = DefaultInitialDelay
+	}
+	if options.MaxDelay <= 0 {
+		options.MaxDelay = DefaultMaxDelay
+	}
+
+	delay := options.InitialDelay
+	for attempt := 1; ; attempt++ {
+		err := op(attempt)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) {
+			return permanent.err
+		}
+		if attempt >= options.Attempts {
+			return err
+		}
+		if options.OnRetry != nil {
+			options.OnRetry(attempt, err, delay)
+		}
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return errors.Join(err, ctx.Err())
+		case <-timer.C:
+		}
+		delay = min(2*delay, options.MaxDelay)
+	}
+}
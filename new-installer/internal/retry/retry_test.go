@@ -0,0 +1,63 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package retry_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/retry"
+	"github.com/stretchr/testify/assert"
+)
+
+var fastOptions = retry.Options{Attempts: 4, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+func TestSucceedsAfterFailures(t *testing.T) {
+	delays := []time.Duration{}
+	options := fastOptions
+	options.OnRetry = func(attempt int, err error, delay time.Duration) {
+		delays = append(delays, delay)
+	}
+	err := retry.Do(context.Background(), options, func(attempt int) error {
+		if attempt < 3 {
+			return errors.New("connection reset")
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, []time.Duration{time.Millisecond, 2 * time.Millisecond}, delays)
+}
+
+func TestAttemptsExhausted(t *testing.T) {
+	attempts := 0
+	err := retry.Do(context.Background(), fastOptions, func(attempt int) error {
+		attempts = attempt
+		return errors.New("connection reset")
+	})
+	assert.EqualError(t, err, "connection reset")
+	assert.Equal(t, 4, attempts)
+}
+
+func TestPermanent(t *testing.T) {
+	notFound := errors.New("404 Not Found")
+	attempts := 0
+	err := retry.Do(context.Background(), fastOptions, func(attempt int) error {
+		attempts = attempt
+		return retry.Permanent(notFound)
+	})
+	assert.ErrorIs(t, err, notFound)
+	assert.Equal(t, 1, attempts)
+}
+
+func TestContextCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	options := retry.Options{InitialDelay: time.Hour}
+	options.OnRetry = func(int, error, time.Duration) { cancel() }
+	err := retry.Do(ctx, options, func(attempt int) error {
+		return errors.New("connection reset")
+	})
+	assert.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,117 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package selector picks the stages and steps to run from the --target expressions.
+//
+// Every expression is a term of names or labels joined with "+", all of which must match, and a
+// name prefixed with "!" must not match. A target is selected when it matches any of the terms,
+// except that a term made only of "!" names excludes the targets it does not match, whatever the
+// other terms select. For example "infra,!rds" selects the infra targets but RDS, "!rds" selects
+// everything but RDS and "infra+aws" selects the targets labeled both infra and aws.
+package selector
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Target is something that can be selected, such as a stage or a step.
+type Target struct {
+	Name   string
+	Labels []string
+}
+
+type atom struct {
+	value   string
+	negated bool
+}
+
+type term []atom
+
+// Selector matches targets against parsed expressions, the zero value selects everything.
+type Selector struct {
+	includes []term
+	excludes []term
+	source   []string
+}
+
+// Parse parses the expressions given to --target, which have already been split on commas. Blank
+// expressions are ignored.
+func Parse(expressions []string) (*Selector, error) {
+	sel := &Selector{}
+	for _, expression := range expressions {
+		expression = strings.TrimSpace(expression)
+		if expression == "" {
+			continue
+		}
+		t := term{}
+		allNegated := true
+		for _, value := range strings.Split(expression, "+") {
+			value = strings.TrimSpace(value)
+			negated := strings.HasPrefix(value, "!")
+			value = strings.TrimSpace(strings.TrimPrefix(value, "!"))
+			if value == "" {
+				return nil, fmt.Errorf("invalid target %q: empty name or label", expression)
+			}
+			if strings.ContainsAny(value, "!+") {
+				return nil, fmt.Errorf("invalid target %q: unexpected character in %q", expression, value)
+			}
+			allNegated = allNegated && negated
+			t = append(t, atom{value: value, negated: negated})
+		}
+		if allNegated {
+			sel.excludes = append(sel.excludes, t)
+		} else {
+			sel.includes = append(sel.includes, t)
+		}
+		sel.source = append(sel.source, expression)
+	}
+	return sel, nil
+}
+
+// Empty returns true if the selector selects everything.
+func (s *Selector) Empty() bool {
+	return s == nil || len(s.includes) == 0 && len(s.excludes) == 0
+}
+
+// Match returns true if the targets are selected. The names and labels of all the targets are
+// matched together, so a step can be selected by the labels of its stage.
+func (s *Selector) Match(targets ...Target) bool {
+	if s.Empty() {
+		return true
+	}
+	values := []string{}
+	for _, target := range targets {
+		values = append(values, target.Name)
+		values = append(values, target.Labels...)
+	}
+	for _, t := range s.excludes {
+		if !t.match(values) {
+			return false
+		}
+	}
+	if len(s.includes) == 0 {
+		return true
+	}
+	return slices.ContainsFunc(s.includes, func(t term) bool { return t.match(values) })
+}
+
+// String returns the expressions, as they would be given to --target.
+func (s *Selector) String() string {
+	if s == nil {
+		return ""
+	}
+	return strings.Join(s.source, ",")
+}
+
+func (t term) match(values []string) bool {
+	for _, a := range t {
+		found := slices.ContainsFunc(values, func(value string) bool { return strings.EqualFold(value, a.value) })
+		if found == a.negated {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,61 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package selector_test
+
+import (
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	infra = selector.Target{Name: "Infra", Labels: []string{"infra"}}
+	rds   = selector.Target{Name: "RDSStep", Labels: []string{"aws", "rds"}}
+	eks   = selector.Target{Name: "EKSStep", Labels: []string{"aws", "eks"}}
+	gitea = selector.Target{Name: "GiteaStep", Labels: []string{"gitea", "onprem"}}
+)
+
+func TestMatch(t *testing.T) {
+	for _, tc := range []struct {
+		expressions []string
+		selected    map[string]bool
+	}{
+		{nil, map[string]bool{"RDSStep": true, "EKSStep": true, "GiteaStep": true}},
+		{[]string{"infra"}, map[string]bool{"RDSStep": true, "EKSStep": true, "GiteaStep": false}},
+		{[]string{"infra", "!rds"}, map[string]bool{"RDSStep": false, "EKSStep": true, "GiteaStep": false}},
+		{[]string{"!rds"}, map[string]bool{"RDSStep": false, "EKSStep": true, "GiteaStep": true}},
+		{[]string{"!rds", "!eks"}, map[string]bool{"RDSStep": false, "EKSStep": false, "GiteaStep": true}},
+		{[]string{"infra+aws"}, map[string]bool{"RDSStep": true, "EKSStep": true, "GiteaStep": false}},
+		{[]string{"infra+!eks", "gitea"}, map[string]bool{"RDSStep": true, "EKSStep": false, "GiteaStep": true}},
+		{[]string{"rds", "onprem"}, map[string]bool{"RDSStep": true, "EKSStep": false, "GiteaStep": true}},
+		// Step names, in any case
+		{[]string{"eksstep"}, map[string]bool{"RDSStep": false, "EKSStep": true, "GiteaStep": false}},
+		{[]string{"something-else"}, map[string]bool{"RDSStep": false, "EKSStep": false, "GiteaStep": false}},
+	} {
+		sel, err := selector.Parse(tc.expressions)
+		require.NoError(t, err)
+		// The steps are matched together with their stage, Gitea is not in the infra stage
+		assert.Equal(t, tc.selected["RDSStep"], sel.Match(infra, rds), "%v RDSStep", tc.expressions)
+		assert.Equal(t, tc.selected["EKSStep"], sel.Match(infra, eks), "%v EKSStep", tc.expressions)
+		assert.Equal(t, tc.selected["GiteaStep"], sel.Match(gitea), "%v GiteaStep", tc.expressions)
+	}
+}
+
+func TestParse(t *testing.T) {
+	sel, err := selector.Parse([]string{" infra + aws ", "", "! rds"})
+	require.NoError(t, err)
+	assert.False(t, sel.Empty())
+	assert.Equal(t, "infra + aws,! rds", sel.String())
+
+	sel, err = selector.Parse([]string{"", " "})
+	require.NoError(t, err)
+	assert.True(t, sel.Empty())
+
+	for _, expression := range []string{"!", "infra+", "+aws", "!!rds", "infra+!"} {
+		_, err := selector.Parse([]string{expression})
+		assert.Error(t, err, expression)
+	}
+}
@@ -6,13 +6,15 @@ package internal
 
 import (
 	"context"
+	"slices"
 
 	config "github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
 )
 
 type OrchInstallerStage interface {
 	Name() string
-	// Labels for the stage, We can selectively run a subset of stages by specifying labels.
+	// Labels for the stage, We can selectively run a subset of stages by specifying labels or the name of the stage.
 	Labels() []string
 	// PreStage: initialize the stage, such as creating directories, downloading files, etc.
 	// It also process the output/runtime-state from previous stage.
@@ -35,26 +37,68 @@ func ReverseStages(stages []OrchInstallerStage) []OrchInstallerStage {
 	return reversed
 }
 
-func matchAnyLabel(stageLabels []string, filterLabels []string) bool {
-	for _, label := range stageLabels {
-		for _, filterLabel := range filterLabels {
-			if label == filterLabel {
-				return true
+// StageWithSteps is implemented by the stages that can tell the names and labels of their steps.
+type StageWithSteps interface {
+	StepTargets() []selector.Target
+}
+
+// StageWithDryRun is implemented by the stages that can tell which of their steps honor --dry-run.
+type StageWithDryRun interface {
+	StageWithSteps
+	// DryRunSteps returns the names of the steps that report their changes instead of making them.
+	DryRunSteps() []string
+}
+
+// UnsupportedDryRun returns the names of the selected stages and steps that do not honor --dry-run.
+// A stage that does not implement StageWithDryRun is not expected to honor it.
+func UnsupportedDryRun(stages []OrchInstallerStage, sel *selector.Selector) []string {
+	unsupported := []string{}
+	for _, stage := range stages {
+		withDryRun, ok := stage.(StageWithDryRun)
+		if !ok {
+			unsupported = append(unsupported, stage.Name())
+			continue
+		}
+		stageTarget := StageTarget(stage)
+		for _, step := range withDryRun.StepTargets() {
+			if sel.Match(stageTarget, step) && !slices.Contains(withDryRun.DryRunSteps(), step.Name) {
+				unsupported = append(unsupported, step.Name)
 			}
 		}
 	}
-	return false
+	return unsupported
 }
 
-func FilterStages(stages []OrchInstallerStage, labels []string) []OrchInstallerStage {
-	if len(labels) == 0 {
+// StageTarget returns the name and labels of the stage, to be matched by a selector.
+func StageTarget(stage OrchInstallerStage) selector.Target {
+	return selector.Target{Name: stage.Name(), Labels: stage.Labels()}
+}
+
+// FilterStages returns the stages selected by the selector. A stage that implements StageWithSteps
+// is selected if any of its steps is, the steps being matched together with their stage.
+func FilterStages(stages []OrchInstallerStage, sel *selector.Selector) []OrchInstallerStage {
+	if sel.Empty() {
 		return stages
 	}
 	filtered := []OrchInstallerStage{}
 	for _, stage := range stages {
-		if matchAnyLabel(stage.Labels(), labels) {
+		if stageSelected(stage, sel) {
 			filtered = append(filtered, stage)
 		}
 	}
 	return filtered
 }
+
+func stageSelected(stage OrchInstallerStage, sel *selector.Selector) bool {
+	stageTarget := StageTarget(stage)
+	withSteps, ok := stage.(StageWithSteps)
+	if !ok {
+		return sel.Match(stageTarget)
+	}
+	for _, step := range withSteps.StepTargets() {
+		if sel.Match(stageTarget, step) {
+			return true
+		}
+	}
+	return false
+}
@@ -52,7 +52,7 @@ func (s *ACMImportTest) SetupTest() {
 
 	s.randomText = strings.ToLower(rand.Text()[0:8])
 	s.logDir = filepath.Join(rootPath, ".logs")
-	err = internal.InitLogger("debug", s.logDir)
+	err = internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir})
 	if err != nil {
 		s.NoError(err)
 		return
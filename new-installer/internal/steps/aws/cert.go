@@ -0,0 +1,187 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	TraefikTLSSecretNamespace = "orch-gateway"
+	TraefikTLSSecretName      = "tls-orch"
+)
+
+type CertExpiry struct {
+	Name          string
+	NotAfter      time.Time
+	DaysRemaining int
+	Error         error
+}
+
+func newCertExpiry(name string, notAfter time.Time, err error, now time.Time) CertExpiry {
+	expiry := CertExpiry{
+		Name:     name,
+		NotAfter: notAfter,
+		Error:    err,
+	}
+	if err == nil {
+		expiry.DaysRemaining = int(math.Floor(notAfter.Sub(now).Hours() / 24))
+	}
+	return expiry
+}
+
+// ParseCertificatePEM returns the first certificate in the given PEM data.
+func ParseCertificatePEM(certPEM string) (*x509.Certificate, error) {
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("failed to decode PEM certificate")
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// ValidateTLSCertificate checks that the certificate matches the private key, is currently valid
+// and, if a CA is given, is signed by it.
+func ValidateTLSCertificate(certPEM, keyPEM, caPEM string, now time.Time) error {
+	if _, err := tls.X509KeyPair([]byte(certPEM), []byte(keyPEM)); err != nil {
+		return fmt.Errorf("certificate does not match private key: %w", err)
+	}
+	cert, err := ParseCertificatePEM(certPEM)
+	if err != nil {
+		return err
+	}
+	if now.Before(cert.NotBefore) {
+		return fmt.Errorf("certificate is not valid before %s", cert.NotBefore.Format(time.RFC3339))
+	}
+	if now.After(cert.NotAfter) {
+		return fmt.Errorf("certificate expired at %s", cert.NotAfter.Format(time.RFC3339))
+	}
+	if caPEM == "" {
+		return nil
+	}
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM([]byte(caPEM)) {
+		return fmt.Errorf("failed to decode PEM CA certificate")
+	}
+	if _, err := cert.Verify(x509.VerifyOptions{
+		Roots:       roots,
+		CurrentTime: now,
+		KeyUsages:   []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+	}); err != nil {
+		return fmt.Errorf("certificate is not signed by the given CA: %w", err)
+	}
+	return nil
+}
+
+// RotateACMCertificate validates the new TLS material and re-imports it into the ACM certificate
+// created by ImportCertificateToACMStep. The runtime state is updated so that later Terraform runs
+// of the ACM module keep the new material.
+func RotateACMCertificate(cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, awsUtility AWSUtility, certPEM, keyPEM, caPEM string) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.AWS.ACMCertArn == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  "ACMCertArn should not be empty in runtime state, is the orchestrator installed?",
+		}
+	}
+	// The chain of the current certificate is kept when no CA is given. ImportCertificateToACMStep
+	// would replace a certificate without a chain with a self-signed one on the next run.
+	if caPEM == "" {
+		caPEM = runtimeState.Cert.TLSCA
+	}
+	if caPEM == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "the runtime state has no CA certificate chain to keep, it must be given",
+		}
+	}
+	if err := ValidateTLSCertificate(certPEM, keyPEM, caPEM, time.Now()); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("invalid TLS certificate: %v", err),
+		}
+	}
+	err := awsUtility.ReimportACMCertificate(cfg.AWS.Region, runtimeState.AWS.ACMCertArn, certPEM, keyPEM, caPEM)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	runtimeState.Cert.TLSCert = certPEM
+	runtimeState.Cert.TLSKey = keyPEM
+	runtimeState.Cert.TLSCA = caPEM
+	return runtimeState, nil
+}
+
+// GetTraefikTLSCert reads the certificate of the Traefik TLS secret from the cluster.
+func GetTraefikTLSCert(ctx context.Context, shellUtility steps.ShellUtility, kubeConfig string) (string, error) {
+	if kubeConfig == "" {
+		return "", fmt.Errorf("kubeconfig is empty")
+	}
+	kubeConfigFile, err := os.CreateTemp("", "kubeconfig-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubeconfig file: %w", err)
+	}
+	defer os.Remove(kubeConfigFile.Name())
+	if _, err := kubeConfigFile.WriteString(kubeConfig); err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+	if err := kubeConfigFile.Close(); err != nil {
+		return "", fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+
+	output, shellErr := shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: []string{
+			"kubectl", "--kubeconfig", kubeConfigFile.Name(),
+			"get", "secret", "-n", TraefikTLSSecretNamespace, TraefikTLSSecretName,
+			"-o", `jsonpath={.data.tls\.crt}`,
+		},
+	})
+	if shellErr != nil {
+		return "", fmt.Errorf("failed to get secret %s/%s: %s %s", TraefikTLSSecretNamespace, TraefikTLSSecretName, shellErr.ErrorMsg, output.Stderr.String())
+	}
+	certPEM, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output.Stdout.String()))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode secret %s/%s: %w", TraefikTLSSecretNamespace, TraefikTLSSecretName, err)
+	}
+	return string(certPEM), nil
+}
+
+// CheckCertExpiry reports the expiry of the ACM certificate, the in-cluster Traefik TLS secret
+// and the RDS CA certificate. Failures are reported per certificate so that one unreachable
+// source does not hide the others.
+func CheckCertExpiry(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, awsUtility AWSUtility, shellUtility steps.ShellUtility) []CertExpiry {
+	now := time.Now()
+	var expiries []CertExpiry
+
+	acmNotAfter, err := awsUtility.GetACMCertificateExpiry(cfg.AWS.Region, runtimeState.AWS.ACMCertArn)
+	expiries = append(expiries, newCertExpiry("ACM certificate", acmNotAfter, err, now))
+
+	var traefikNotAfter time.Time
+	certPEM, err := GetTraefikTLSCert(ctx, shellUtility, runtimeState.AWS.KubeConfig)
+	if err == nil {
+		var cert *x509.Certificate
+		if cert, err = ParseCertificatePEM(certPEM); err == nil {
+			traefikNotAfter = cert.NotAfter
+		}
+	}
+	expiries = append(expiries, newCertExpiry("Traefik TLS secret", traefikNotAfter, err, now))
+
+	rdsNotAfter, err := awsUtility.GetRDSCACertificateExpiry(cfg.AWS.Region, cfg.Global.OrchName)
+	expiries = append(expiries, newCertExpiry("RDS CA certificate", rdsNotAfter, err, now))
+	return expiries
+}
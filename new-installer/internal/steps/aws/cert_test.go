@@ -0,0 +1,127 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type CertTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	awsUtility   *MockAWSUtility
+	tlsCert      string
+	tlsCA        string
+	tlsKey       string
+}
+
+func TestCert(t *testing.T) {
+	suite.Run(t, new(CertTest))
+}
+
+func (s *CertTest) SetupTest() {
+	var err error
+	s.tlsCert, s.tlsCA, s.tlsKey, err = steps_aws.GenerateSelfSignedTLSCert("test")
+	s.Require().NoError(err)
+	s.config.AWS.Region = "us-west-2"
+	s.config.Global.OrchName = "test"
+	s.runtimeState.AWS.ACMCertArn = "arn:aws:acm:us-west-2:123456789012:certificate/test"
+	s.awsUtility = &MockAWSUtility{}
+}
+
+func (s *CertTest) TestValidateTLSCertificate() {
+	s.NoError(steps_aws.ValidateTLSCertificate(s.tlsCert, s.tlsKey, s.tlsCA, time.Now()))
+	s.NoError(steps_aws.ValidateTLSCertificate(s.tlsCert, s.tlsKey, "", time.Now()))
+
+	_, otherCA, otherKey, err := steps_aws.GenerateSelfSignedTLSCert("other")
+	s.Require().NoError(err)
+	s.Error(steps_aws.ValidateTLSCertificate(s.tlsCert, otherKey, s.tlsCA, time.Now()))
+	s.Error(steps_aws.ValidateTLSCertificate(s.tlsCert, s.tlsKey, otherCA, time.Now()))
+	s.Error(steps_aws.ValidateTLSCertificate(s.tlsCert, s.tlsKey, s.tlsCA, time.Now().Add(20*365*24*time.Hour)))
+}
+
+func (s *CertTest) TestRotateACMCertificate() {
+	s.awsUtility.On("ReimportACMCertificate", "us-west-2", s.runtimeState.AWS.ACMCertArn, s.tlsCert, s.tlsKey, s.tlsCA).Return(nil).Once()
+	rs, err := steps_aws.RotateACMCertificate(s.config, s.runtimeState, s.awsUtility, s.tlsCert, s.tlsKey, s.tlsCA)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(s.runtimeState.AWS.ACMCertArn, rs.AWS.ACMCertArn)
+	s.Equal(s.tlsCert, rs.Cert.TLSCert)
+	s.Equal(s.tlsKey, rs.Cert.TLSKey)
+	s.Equal(s.tlsCA, rs.Cert.TLSCA)
+	s.awsUtility.AssertExpectations(s.T())
+}
+
+func (s *CertTest) TestRotateKeepsCA() {
+	s.runtimeState.Cert.TLSCA = s.tlsCA
+	s.awsUtility.On("ReimportACMCertificate", "us-west-2", s.runtimeState.AWS.ACMCertArn, s.tlsCert, s.tlsKey, s.tlsCA).Return(nil).Once()
+	rs, err := steps_aws.RotateACMCertificate(s.config, s.runtimeState, s.awsUtility, s.tlsCert, s.tlsKey, "")
+	s.Require().Nil(err)
+	s.Equal(s.tlsCA, rs.Cert.TLSCA)
+	s.awsUtility.AssertExpectations(s.T())
+
+	// The next run of the installer imports the rotated certificate, not a generated one
+	step := &steps_aws.ImportCertificateToACMStep{}
+	s.config.Cert.TLSCert = "initial-cert"
+	rs.Action = "upgrade"
+	rs, err = step.ConfigStep(context.Background(), s.config, rs)
+	s.Require().Nil(err)
+	s.Equal(s.tlsCert, rs.Cert.TLSCert)
+	s.Equal(s.tlsKey, rs.Cert.TLSKey)
+	s.Equal(s.tlsCA, rs.Cert.TLSCA)
+}
+
+func (s *CertTest) TestRotateWithoutCA() {
+	_, err := steps_aws.RotateACMCertificate(s.config, s.runtimeState, s.awsUtility, s.tlsCert, s.tlsKey, "")
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.awsUtility.AssertNotCalled(s.T(), "ReimportACMCertificate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *CertTest) TestRotateInvalidCertificate() {
+	_, _, otherKey, genErr := steps_aws.GenerateSelfSignedTLSCert("other")
+	s.Require().NoError(genErr)
+	_, err := steps_aws.RotateACMCertificate(s.config, s.runtimeState, s.awsUtility, s.tlsCert, otherKey, s.tlsCA)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.awsUtility.AssertNotCalled(s.T(), "ReimportACMCertificate", mock.Anything, mock.Anything, mock.Anything, mock.Anything, mock.Anything)
+}
+
+func (s *CertTest) TestRotateWithoutACMCertificate() {
+	s.runtimeState.AWS.ACMCertArn = ""
+	_, err := steps_aws.RotateACMCertificate(s.config, s.runtimeState, s.awsUtility, s.tlsCert, s.tlsKey, s.tlsCA)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *CertTest) TestCheckCertExpiry() {
+	now := time.Now()
+	s.awsUtility.On("GetACMCertificateExpiry", "us-west-2", s.runtimeState.AWS.ACMCertArn).Return(now.Add(90*24*time.Hour+time.Hour), nil).Once()
+	s.awsUtility.On("GetRDSCACertificateExpiry", "us-west-2", "test").Return(now.Add(10*24*time.Hour+time.Hour), nil).Once()
+
+	// No kubeconfig in the runtime state, the Traefik secret cannot be read.
+	expiries := steps_aws.CheckCertExpiry(context.Background(), s.config, s.runtimeState, s.awsUtility, steps.CreateShellUtility())
+	s.Require().Len(expiries, 3)
+	s.Equal("ACM certificate", expiries[0].Name)
+	s.NoError(expiries[0].Error)
+	s.Equal(90, expiries[0].DaysRemaining)
+	s.Equal("Traefik TLS secret", expiries[1].Name)
+	s.Error(expiries[1].Error)
+	s.Equal("RDS CA certificate", expiries[2].Name)
+	s.NoError(expiries[2].Error)
+	s.Equal(10, expiries[2].DaysRemaining)
+	s.awsUtility.AssertExpectations(s.T())
+}
@@ -0,0 +1,309 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	DRModulePath       = "new-installer/targets/aws/iac/dr"
+	DRBackendBucketKey = "dr.tfstate"
+
+	drFailoverPollInterval = 30 * time.Second
+)
+
+var drStepLabels = []string{
+	"aws",
+	"dr",
+}
+
+type DRVariables struct {
+	Region               string `json:"region" yaml:"region"`
+	DRRegion             string `json:"dr_region" yaml:"dr_region"`
+	ClusterName          string `json:"cluster_name" yaml:"cluster_name"`
+	CustomerTag          string `json:"customer_tag" yaml:"customer_tag"`
+	StateBucket          string `json:"state_bucket" yaml:"state_bucket"`
+	KMSKeyARN            string `json:"kms_key_arn" yaml:"kms_key_arn"`
+	RDSClusterIdentifier string `json:"rds_cluster_identifier" yaml:"rds_cluster_identifier"`
+}
+
+func NewDefaultDRVariables() DRVariables {
+	return DRVariables{
+		Region:               "",
+		DRRegion:             "",
+		ClusterName:          "",
+		CustomerTag:          "",
+		StateBucket:          "",
+		KMSKeyARN:            "",
+		RDSClusterIdentifier: "",
+	}
+}
+
+// DRStep stands up a warm standby in AWS.DRRegion: a replica of the state bucket, a secondary
+// cluster of an RDS global database and a KMS key. The standby recorded in the runtime state is
+// torn down once the config no longer sets a DR region, the step does nothing if there is none.
+type DRStep struct {
+	variables     DRVariables
+	backendConfig steps.TerraformAWSBucketBackendConfig
+	// Region of the standby managed by this run, and whether it is torn down
+	region             string
+	teardown           bool
+	RootPath           string
+	KeepGeneratedFiles bool
+	TerraformUtility   steps.TerraformUtility
+	AWSUtility         AWSUtility
+}
+
+func CreateDRStep(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility AWSUtility) *DRStep {
+	return &DRStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: keepGeneratedFiles,
+		TerraformUtility:   terraformUtility,
+		AWSUtility:         awsUtility,
+	}
+}
+
+func (s *DRStep) Name() string {
+	return "DRStep"
+}
+
+func (s *DRStep) Labels() []string {
+	return drStepLabels
+}
+
+func (s *DRStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	deployed := runtimeState.AWS.DR.Region
+	s.region, s.teardown = cfg.AWS.DRRegion, false
+	switch {
+	case s.region == "" && deployed == "":
+		return runtimeState, nil
+	case s.region == "":
+		s.region, s.teardown = deployed, true
+	case deployed != "" && deployed != s.region:
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("DR region cannot be changed from %s to %s, remove it from the config to tear the standby down first", deployed, s.region),
+		}
+	case deployed == "" && runtimeState.Action == "upgrade":
+		// The RDS module ignores changes of the global cluster of the database, which would not be replicated
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "DR can only be set up on install, the existing RDS cluster cannot be added to a global database",
+		}
+	}
+	if runtimeState.AWS.KMSKeyARN == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("KMSKeyARN should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+	s.variables = NewDefaultDRVariables()
+	s.variables.Region = cfg.AWS.Region
+	s.variables.DRRegion = s.region
+	s.variables.ClusterName = cfg.Global.OrchName
+	s.variables.CustomerTag = cfg.AWS.CustomerTag
+	s.variables.StateBucket = cfg.Global.OrchName + "-" + runtimeState.DeploymentID
+	s.variables.KMSKeyARN = runtimeState.AWS.KMSKeyARN
+	s.variables.RDSClusterIdentifier = cfg.Global.OrchName
+	s.backendConfig = steps.TerraformAWSBucketBackendConfig{
+		Bucket: cfg.Global.OrchName + "-" + runtimeState.DeploymentID,
+		Region: cfg.AWS.Region,
+		Key:    DRBackendBucketKey,
+	}
+	return runtimeState, nil
+}
+
+func (s *DRStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if s.region == "" {
+		return runtimeState, nil
+	}
+	if runtimeState.AWS.DR.FailedOver && runtimeState.Action != "uninstall" {
+		// The global cluster topology no longer matches the module, Terraform would try to revert the
+		// failover or, on teardown, destroy the cluster in use.
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("deployment has failed over to %s, redeploy the orchestrator in the DR region instead", runtimeState.AWS.DR.Region),
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *DRStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if s.region == "" {
+		return runtimeState, nil
+	}
+	action := runtimeState.Action
+	if s.teardown {
+		internal.Logger().Infof("DR region is no longer set, tearing down the standby in %s", s.region)
+		action = "uninstall"
+	}
+	terraformStepInput := steps.TerraformUtilityInput{
+		Action:             action,
+		ModulePath:         filepath.Join(s.RootPath, DRModulePath),
+		Variables:          s.variables,
+		BackendConfig:      s.backendConfig,
+		LogFile:            filepath.Join(runtimeState.LogDir, "aws_dr.log"),
+		KeepGeneratedFiles: s.KeepGeneratedFiles,
+	}
+	output, err := s.TerraformUtility.Run(ctx, terraformStepInput)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("failed to run terraform: %v", err),
+		}
+	}
+	if action == "uninstall" {
+		runtimeState.AWS.DR.Region = ""
+		runtimeState.AWS.DR.StateBucket = ""
+		runtimeState.AWS.DR.KMSKeyARN = ""
+		runtimeState.AWS.DR.GlobalClusterID = ""
+		runtimeState.AWS.DR.ClusterARN = ""
+		runtimeState.AWS.DR.DatabaseHost = ""
+		runtimeState.AWS.DR.DatabaseReaderHost = ""
+		runtimeState.AWS.DR.FailedOver = false
+		return runtimeState, nil
+	}
+	if output.Output == nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  "cannot find any output from DR module",
+		}
+	}
+	outputs := map[string]*string{
+		"state_bucket":         &runtimeState.AWS.DR.StateBucket,
+		"kms_key_arn":          &runtimeState.AWS.DR.KMSKeyARN,
+		"global_cluster_id":    &runtimeState.AWS.DR.GlobalClusterID,
+		"cluster_arn":          &runtimeState.AWS.DR.ClusterARN,
+		"database_host":        &runtimeState.AWS.DR.DatabaseHost,
+		"database_reader_host": &runtimeState.AWS.DR.DatabaseReaderHost,
+	}
+	for key, value := range outputs {
+		meta, ok := output.Output[key]
+		if !ok {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+				ErrorMsg:  fmt.Sprintf("cannot find %s in DR module output", key),
+			}
+		}
+		*value = strings.Trim(string(meta.Value), "\"")
+	}
+	runtimeState.AWS.DR.Region = s.region
+	return runtimeState, nil
+}
+
+func (s *DRStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+type DRStatus struct {
+	Region              string
+	StateBucket         string
+	GlobalClusterID     string
+	GlobalClusterStatus string
+	Members             []RDSGlobalClusterMember
+	FailedOver          bool
+}
+
+func checkDRRuntimeState(runtimeState config.OrchInstallerRuntimeState) *internal.OrchInstallerError {
+	if runtimeState.AWS.DR.Region == "" || runtimeState.AWS.DR.GlobalClusterID == "" {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  "DR is not set up in runtime state, is AWS.DRRegion set in the config?",
+		}
+	}
+	return nil
+}
+
+// GetDRStatus reports the state of the global cluster as seen from the DR region, so that it
+// keeps working when the primary region is unavailable.
+func GetDRStatus(runtimeState config.OrchInstallerRuntimeState, awsUtility AWSUtility) (DRStatus, *internal.OrchInstallerError) {
+	if err := checkDRRuntimeState(runtimeState); err != nil {
+		return DRStatus{}, err
+	}
+	status := DRStatus{
+		Region:          runtimeState.AWS.DR.Region,
+		StateBucket:     runtimeState.AWS.DR.StateBucket,
+		GlobalClusterID: runtimeState.AWS.DR.GlobalClusterID,
+		FailedOver:      runtimeState.AWS.DR.FailedOver,
+	}
+	globalCluster, err := awsUtility.GetRDSGlobalClusterStatus(runtimeState.AWS.DR.Region, runtimeState.AWS.DR.GlobalClusterID)
+	if err != nil {
+		return status, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	status.GlobalClusterStatus = globalCluster.Status
+	status.Members = globalCluster.Members
+	return status, nil
+}
+
+// FailoverDR promotes the standby RDS cluster to the writer of the global cluster and points the
+// runtime state database endpoints to it. Without allowDataLoss a switchover is performed, which
+// needs the primary region to be reachable.
+func FailoverDR(ctx context.Context, runtimeState config.OrchInstallerRuntimeState, awsUtility AWSUtility, allowDataLoss bool) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if err := checkDRRuntimeState(runtimeState); err != nil {
+		return runtimeState, err
+	}
+	if runtimeState.AWS.DR.FailedOver {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("deployment has already failed over to %s", runtimeState.AWS.DR.Region),
+		}
+	}
+	region := runtimeState.AWS.DR.Region
+	globalClusterID := runtimeState.AWS.DR.GlobalClusterID
+	targetClusterARN := runtimeState.AWS.DR.ClusterARN
+	if err := awsUtility.FailoverRDSGlobalCluster(region, globalClusterID, targetClusterARN, allowDataLoss); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
+		}
+	}
+
+	for {
+		globalCluster, err := awsUtility.GetRDSGlobalClusterStatus(region, globalClusterID)
+		if err != nil {
+			internal.Logger().Warnf("Unable to get status of global cluster %s: %v", globalClusterID, err)
+		} else if isGlobalClusterWriter(globalCluster, targetClusterARN) {
+			break
+		}
+		internal.Logger().Infof("Waiting for %s to become the writer of global cluster %s", targetClusterARN, globalClusterID)
+		select {
+		case <-ctx.Done():
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("timed out waiting for %s to become the writer: %v", targetClusterARN, ctx.Err()),
+			}
+		case <-time.After(drFailoverPollInterval):
+		}
+	}
+
+	runtimeState.Database.Host = runtimeState.AWS.DR.DatabaseHost
+	runtimeState.Database.ReaderHost = runtimeState.AWS.DR.DatabaseReaderHost
+	runtimeState.AWS.DR.FailedOver = true
+	return runtimeState, nil
+}
+
+func isGlobalClusterWriter(globalCluster RDSGlobalClusterStatus, clusterARN string) bool {
+	if globalCluster.Status != "available" {
+		return false
+	}
+	for _, member := range globalCluster.Members {
+		if member.ClusterArn == clusterARN {
+			return member.IsWriter
+		}
+	}
+	return false
+}
@@ -0,0 +1,246 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const (
+	testDRClusterARN      = "arn:aws:rds:us-east-1:123456789012:cluster:test-dr"
+	testPrimaryClusterARN = "arn:aws:rds:us-west-2:123456789012:cluster:test"
+	testDRDatabaseHost    = "test-dr.cluster-abc.us-east-1.rds.amazonaws.com"
+)
+
+type DRStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *steps_aws.DRStep
+	logDir       string
+	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
+}
+
+func TestDRStep(t *testing.T) {
+	suite.Run(t, new(DRStepTest))
+}
+
+func (s *DRStepTest) SetupTest() {
+	rootPath, err := filepath.Abs("../../../../")
+	s.Require().NoError(err, "Failed to get absolute path")
+	s.logDir = filepath.Join(rootPath, ".logs")
+	err = internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir})
+	s.Require().NoError(err, "Failed to initialize logger")
+	s.config = config.OrchInstallerConfig{}
+	s.config.AWS.Region = "us-west-2"
+	s.config.AWS.DRRegion = "us-east-1"
+	s.config.AWS.CustomerTag = "test"
+	s.config.Global.OrchName = "test"
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.DeploymentID = "test-deployment-id"
+	s.runtimeState.LogDir = s.logDir
+	s.runtimeState.AWS.KMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/test"
+	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
+	s.step = &steps_aws.DRStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: true,
+		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
+	}
+}
+
+func (s *DRStepTest) TestInstallAndUninstallDR() {
+	s.runtimeState.Action = "install"
+	s.expectTFUtiliyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("us-east-1", rs.AWS.DR.Region)
+	s.Equal("test-test-deployment-id-dr", rs.AWS.DR.StateBucket)
+	s.Equal("test-global", rs.AWS.DR.GlobalClusterID)
+	s.Equal(testDRClusterARN, rs.AWS.DR.ClusterARN)
+	s.Equal(testDRDatabaseHost, rs.AWS.DR.DatabaseHost)
+
+	s.runtimeState = rs
+	s.runtimeState.Action = "uninstall"
+	s.expectTFUtiliyCall("uninstall")
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.DR.Region)
+	s.Empty(rs.AWS.DR.GlobalClusterID)
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *DRStepTest) TestNoDRRegion() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.DRRegion = ""
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.DR.Region)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *DRStepTest) TestTeardownWhenDRRegionRemoved() {
+	s.runtimeState.Action = "upgrade"
+	s.config.AWS.DRRegion = ""
+	s.setDRRuntimeState()
+	s.expectTFUtiliyCall("uninstall")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Empty(rs.AWS.DR.Region)
+	s.Empty(rs.AWS.DR.GlobalClusterID)
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *DRStepTest) TestAddDRToExistingDeployment() {
+	s.runtimeState.Action = "upgrade"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *DRStepTest) TestChangeDRRegion() {
+	s.runtimeState.Action = "upgrade"
+	s.setDRRuntimeState()
+	s.config.AWS.DRRegion = "eu-west-1"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *DRStepTest) TestUpgradeAfterFailover() {
+	s.runtimeState.Action = "upgrade"
+	s.runtimeState.AWS.DR.Region = "us-east-1"
+	s.runtimeState.AWS.DR.FailedOver = true
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *DRStepTest) TestFailover() {
+	s.setDRRuntimeState()
+	s.awsUtility.On("FailoverRDSGlobalCluster", "us-east-1", "test-global", testDRClusterARN, false).Return(nil).Once()
+	s.awsUtility.On("GetRDSGlobalClusterStatus", "us-east-1", "test-global").Return(steps_aws.RDSGlobalClusterStatus{
+		Status: "available",
+		Members: []steps_aws.RDSGlobalClusterMember{
+			{ClusterArn: testPrimaryClusterARN, IsWriter: false},
+			{ClusterArn: testDRClusterARN, IsWriter: true},
+		},
+	}, nil).Once()
+
+	rs, err := steps_aws.FailoverDR(context.Background(), s.runtimeState, s.awsUtility, false)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.True(rs.AWS.DR.FailedOver)
+	s.Equal(testDRDatabaseHost, rs.Database.Host)
+	s.awsUtility.AssertExpectations(s.T())
+
+	_, err = steps_aws.FailoverDR(context.Background(), rs, s.awsUtility, false)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *DRStepTest) TestFailoverTimeout() {
+	s.setDRRuntimeState()
+	s.awsUtility.On("FailoverRDSGlobalCluster", "us-east-1", "test-global", testDRClusterARN, true).Return(nil).Once()
+	s.awsUtility.On("GetRDSGlobalClusterStatus", "us-east-1", "test-global").Return(steps_aws.RDSGlobalClusterStatus{
+		Status: "failing-over",
+	}, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	rs, err := steps_aws.FailoverDR(ctx, s.runtimeState, s.awsUtility, true)
+	s.NotNil(err)
+	s.False(rs.AWS.DR.FailedOver)
+	s.Empty(rs.Database.Host)
+}
+
+func (s *DRStepTest) TestStatusWithoutDR() {
+	_, err := steps_aws.GetDRStatus(s.runtimeState, s.awsUtility)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *DRStepTest) setDRRuntimeState() {
+	s.runtimeState.AWS.DR.Region = "us-east-1"
+	s.runtimeState.AWS.DR.GlobalClusterID = "test-global"
+	s.runtimeState.AWS.DR.ClusterARN = testDRClusterARN
+	s.runtimeState.AWS.DR.DatabaseHost = testDRDatabaseHost
+}
+
+func (s *DRStepTest) expectTFUtiliyCall(action string) {
+	// The standby of the runtime state is torn down when the config no longer sets one
+	drRegion := s.config.AWS.DRRegion
+	if drRegion == "" {
+		drRegion = s.runtimeState.AWS.DR.Region
+	}
+	input := steps.TerraformUtilityInput{
+		Action:             action,
+		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.DRModulePath),
+		LogFile:            filepath.Join(s.logDir, "aws_dr.log"),
+		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
+		Variables: steps_aws.DRVariables{
+			Region:               s.config.AWS.Region,
+			DRRegion:             drRegion,
+			ClusterName:          s.config.Global.OrchName,
+			CustomerTag:          s.config.AWS.CustomerTag,
+			StateBucket:          fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			KMSKeyARN:            s.runtimeState.AWS.KMSKeyARN,
+			RDSClusterIdentifier: s.config.Global.OrchName,
+		},
+		BackendConfig: steps.TerraformAWSBucketBackendConfig{
+			Region: s.config.AWS.Region,
+			Bucket: fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			Key:    steps_aws.DRBackendBucketKey,
+		},
+		TerraformState: "",
+	}
+	output := map[string]tfexec.OutputMeta{}
+	if action != "uninstall" {
+		for key, value := range map[string]string{
+			"state_bucket":         fmt.Sprintf("%s-%s-dr", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			"kms_key_arn":          "arn:aws:kms:us-east-1:123456789012:key/dr",
+			"global_cluster_id":    "test-global",
+			"cluster_arn":          testDRClusterARN,
+			"database_host":        testDRDatabaseHost,
+			"database_reader_host": "test-dr.cluster-ro-abc.us-east-1.rds.amazonaws.com",
+		} {
+			output[key] = tfexec.OutputMeta{
+				Type:  json.RawMessage(`"string"`),
+				Value: json.RawMessage(`"` + value + `"`),
+			}
+		}
+	}
+	s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+		TerraformState: "",
+		Output:         output,
+	}, nil).Once()
+}
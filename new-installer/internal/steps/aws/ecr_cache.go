@@ -0,0 +1,302 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/asset"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	ECRCacheModulePath       = "new-installer/targets/aws/iac/ecr_cache"
+	ECRCacheBackendBucketKey = "ecr_cache.tfstate"
+	DockerHubRegistry        = "docker.io"
+)
+
+var ecrCacheStepLabels = []string{
+	"aws",
+	"ecr",
+	"cache",
+}
+
+type ecrCacheUpstream struct {
+	prefix              string
+	upstreamRegistryURL string
+	useDockerCredential bool
+}
+
+// Upstream registries supported by ECR pull-through cache, keyed by the registry
+// host used in image references. Images from other registries are pulled directly.
+var ecrCacheUpstreams = map[string]ecrCacheUpstream{
+	DockerHubRegistry: {prefix: "dockercache", upstreamRegistryURL: "registry-1.docker.io", useDockerCredential: true},
+	"quay.io":         {prefix: "quaycache", upstreamRegistryURL: "quay.io"},
+	"registry.k8s.io": {prefix: "k8scache", upstreamRegistryURL: "registry.k8s.io"},
+	"public.ecr.aws":  {prefix: "ecrpubliccache", upstreamRegistryURL: "public.ecr.aws"},
+}
+
+type ECRCacheRule struct {
+	UpstreamRegistryURL string `json:"upstream_registry_url" yaml:"upstream_registry_url"`
+	UseDockerCredential bool   `json:"use_docker_credential" yaml:"use_docker_credential"`
+}
+
+type ECRCacheVariables struct {
+	Region         string                  `json:"region" yaml:"region"`
+	ClusterName    string                  `json:"cluster_name" yaml:"cluster_name"`
+	CustomerTag    string                  `json:"customer_tag" yaml:"customer_tag"`
+	NodeRoleName   string                  `json:"node_role_name" yaml:"node_role_name"`
+	CacheRules     map[string]ECRCacheRule `json:"cache_rules" yaml:"cache_rules"`
+	DockerUsername string                  `json:"docker_username" yaml:"docker_username"`
+	DockerToken    string                  `json:"docker_token" yaml:"docker_token"`
+}
+
+// NewDefaultECRCacheVariables creates a new ECRCacheVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultECRCacheVariables() ECRCacheVariables {
+	return ECRCacheVariables{
+		Region:         "",
+		ClusterName:    "",
+		CustomerTag:    "",
+		NodeRoleName:   "",
+		CacheRules:     map[string]ECRCacheRule{},
+		DockerUsername: "",
+		DockerToken:    "",
+	}
+}
+
+// UpstreamRegistry returns the registry host of an image reference,
+// images without a registry host are pulled from Docker Hub.
+func UpstreamRegistry(image string) string {
+	parts := strings.SplitN(image, "/", 2)
+	if len(parts) == 1 {
+		return DockerHubRegistry
+	}
+	if strings.ContainsAny(parts[0], ".:") || parts[0] == "localhost" {
+		return parts[0]
+	}
+	return DockerHubRegistry
+}
+
+// ECRCacheMirror is an upstream registry cached by the ECR pull-through cache, as the nodes
+// mirror it.
+type ECRCacheMirror struct {
+	// The upstream registry API, used when the image cannot be pulled through the cache
+	Server string `json:"server" yaml:"server"`
+	// The repository prefix of the cache in the ECR registry of the account
+	RepositoryPrefix string `json:"repository_prefix" yaml:"repository_prefix"`
+}
+
+// ecrCachedUpstreams returns the upstream registries of the image manifest that the ECR
+// pull-through cache supports, keyed by registry host.
+func ecrCachedUpstreams() (map[string]ecrCacheUpstream, error) {
+	registries, err := ImageManifestRegistries()
+	if err != nil {
+		return nil, err
+	}
+	upstreams := map[string]ecrCacheUpstream{}
+	for _, registry := range registries {
+		upstream, ok := ecrCacheUpstreams[registry]
+		if !ok {
+			internal.Logger().Debugf("ECR pull-through cache does not support %s, images will be pulled directly", registry)
+			continue
+		}
+		upstreams[registry] = upstream
+	}
+	return upstreams, nil
+}
+
+// ecrCacheRepositoryPrefix scopes the repository prefix of an upstream registry with the
+// orchestrator name, since ECR prefixes are account wide.
+func ecrCacheRepositoryPrefix(orchName string, upstream ecrCacheUpstream) string {
+	return orchName + "/" + upstream.prefix
+}
+
+// ECRCacheMirrors returns the upstream registries the nodes of the orchestrator mirror to the
+// ECR pull-through cache, keyed by registry host.
+func ECRCacheMirrors(orchName string) (map[string]ECRCacheMirror, error) {
+	upstreams, err := ecrCachedUpstreams()
+	if err != nil {
+		return nil, err
+	}
+	mirrors := map[string]ECRCacheMirror{}
+	for registry, upstream := range upstreams {
+		mirrors[registry] = ECRCacheMirror{
+			Server:           "https://" + upstream.upstreamRegistryURL,
+			RepositoryPrefix: ecrCacheRepositoryPrefix(orchName, upstream),
+		}
+	}
+	return mirrors, nil
+}
+
+// ImageManifestRegistries returns the sorted upstream registries of the images
+// listed in the embedded release image manifest.
+func ImageManifestRegistries() ([]string, error) {
+	data, err := asset.EmbedImageManifest.ReadFile("images.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read embedded images.yaml: %w", err)
+	}
+	manifest := struct {
+		Images []string `yaml:"images"`
+	}{}
+	if err := yaml.Unmarshal(data, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode image manifest: %w", err)
+	}
+	registrySet := map[string]struct{}{}
+	for _, image := range manifest.Images {
+		registrySet[UpstreamRegistry(image)] = struct{}{}
+	}
+	registries := make([]string, 0, len(registrySet))
+	for registry := range registrySet {
+		registries = append(registries, registry)
+	}
+	sort.Strings(registries)
+	return registries, nil
+}
+
+// ECRCacheStep creates ECR pull-through cache rules for the upstream registries used by
+// the orchestrator when AWS.CacheRegistry is set to "ecr", the EKS nodes mirror these
+// registries to the cache. Any other cache registry is passed through to the runtime state
+// as is.
+type ECRCacheStep struct {
+	variables          ECRCacheVariables
+	backendConfig      TerraformAWSBucketBackendConfig
+	enabled            bool
+	RootPath           string
+	KeepGeneratedFiles bool
+	TerraformUtility   steps.TerraformUtility
+	AWSUtility         AWSUtility
+}
+
+func CreateECRCacheStep(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility AWSUtility) *ECRCacheStep {
+	return &ECRCacheStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: keepGeneratedFiles,
+		TerraformUtility:   terraformUtility,
+		AWSUtility:         awsUtility,
+	}
+}
+
+func (s *ECRCacheStep) Name() string {
+	return "ECRCacheStep"
+}
+
+func (s *ECRCacheStep) Labels() []string {
+	return ecrCacheStepLabels
+}
+
+func (s *ECRCacheStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	s.enabled = cfg.AWS.CacheRegistry == config.ECRCacheRegistry
+	if !s.enabled {
+		runtimeState.AWS.CacheRegistry = cfg.AWS.CacheRegistry
+		return runtimeState, nil
+	}
+	if runtimeState.AWS.EKSNodeRoleName == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("EKSNodeRoleName should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+	upstreams, err := ecrCachedUpstreams()
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
+		}
+	}
+
+	s.variables = NewDefaultECRCacheVariables()
+	s.variables.Region = cfg.AWS.Region
+	s.variables.ClusterName = cfg.Global.OrchName
+	s.variables.CustomerTag = cfg.AWS.CustomerTag
+	s.variables.NodeRoleName = runtimeState.AWS.EKSNodeRoleName
+	for _, upstream := range upstreams {
+		if upstream.useDockerCredential {
+			if cfg.AWS.DockerUsername == "" || cfg.AWS.DockerToken == "" {
+				return runtimeState, &internal.OrchInstallerError{
+					ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+					ErrorMsg:  "DockerUsername and DockerToken are required to cache Docker Hub images in ECR",
+				}
+			}
+			s.variables.DockerUsername = cfg.AWS.DockerUsername
+			s.variables.DockerToken = cfg.AWS.DockerToken
+		}
+		s.variables.CacheRules[ecrCacheRepositoryPrefix(cfg.Global.OrchName, upstream)] = ECRCacheRule{
+			UpstreamRegistryURL: upstream.upstreamRegistryURL,
+			UseDockerCredential: upstream.useDockerCredential,
+		}
+	}
+	if len(s.variables.CacheRules) == 0 {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "none of the upstream registries in the image manifest is supported by ECR pull-through cache",
+		}
+	}
+
+	s.backendConfig = TerraformAWSBucketBackendConfig{
+		Region: cfg.AWS.Region,
+		Bucket: cfg.Global.OrchName + "-" + runtimeState.DeploymentID,
+		Key:    ECRCacheBackendBucketKey,
+	}
+	return runtimeState, nil
+}
+
+func (s *ECRCacheStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	// No state to migrate, the previous installer did not manage the cache rules.
+	return runtimeState, nil
+}
+
+func (s *ECRCacheStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if !s.enabled {
+		return runtimeState, nil
+	}
+	terraformStepInput := steps.TerraformUtilityInput{
+		Action:             runtimeState.Action,
+		ModulePath:         filepath.Join(s.RootPath, ECRCacheModulePath),
+		Variables:          s.variables,
+		BackendConfig:      s.backendConfig,
+		LogFile:            filepath.Join(runtimeState.LogDir, "aws_ecr_cache.log"),
+		KeepGeneratedFiles: s.KeepGeneratedFiles,
+	}
+	terraformStepOutput, err := s.TerraformUtility.Run(ctx, terraformStepInput)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("failed to run terraform: %v", err),
+		}
+	}
+
+	if runtimeState.Action == "uninstall" {
+		runtimeState.AWS.CacheRegistry = ""
+		return runtimeState, nil
+	}
+
+	if terraformStepOutput.Output == nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find any output from %s module", s.Name()),
+		}
+	}
+	registry, ok := terraformStepOutput.Output["cache_registry"]
+	if !ok {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find cache_registry in %s module output", s.Name()),
+		}
+	}
+	runtimeState.AWS.CacheRegistry = strings.Trim(string(registry.Value), "\"")
+	return runtimeState, nil
+}
+
+func (s *ECRCacheStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
@@ -0,0 +1,197 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws/iac/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testECRRegistry = "123456789012.dkr.ecr.us-west-2.amazonaws.com"
+
+type ECRCacheStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *steps_aws.ECRCacheStep
+	logDir       string
+	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
+}
+
+func TestECRCacheStep(t *testing.T) {
+	suite.Run(t, new(ECRCacheStepTest))
+}
+
+func (s *ECRCacheStepTest) SetupTest() {
+	rootPath, err := filepath.Abs("../../../../")
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.logDir = filepath.Join(rootPath, ".logs")
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
+		s.NoError(err)
+		return
+	}
+
+	s.config.AWS.Region = "us-west-2"
+	s.config.Global.OrchName = "test"
+	s.config.AWS.CustomerTag = utils.DefaultTestCustomerTag
+	s.config.AWS.CacheRegistry = config.ECRCacheRegistry
+	s.config.AWS.DockerUsername = "user"
+	s.config.AWS.DockerToken = "token"
+	s.runtimeState.AWS.EKSNodeRoleName = "eks-node-test"
+	s.runtimeState.LogDir = s.logDir
+
+	if _, err := os.Stat(s.logDir); os.IsNotExist(err) {
+		err := os.MkdirAll(s.logDir, os.ModePerm)
+		if err != nil {
+			s.NoError(err)
+			return
+		}
+	}
+	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
+	s.step = &steps_aws.ECRCacheStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: true,
+		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
+	}
+}
+
+func (s *ECRCacheStepTest) TestInstallAndUninstallECRCache() {
+	s.runtimeState.Action = "install"
+	s.expectTFUtiliyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(testECRRegistry, rs.AWS.CacheRegistry)
+
+	s.runtimeState = rs
+	s.runtimeState.Action = "uninstall"
+	s.expectTFUtiliyCall("uninstall")
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.CacheRegistry)
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *ECRCacheStepTest) TestExternalCacheRegistry() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.CacheRegistry = "cache.example.com"
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("cache.example.com", rs.AWS.CacheRegistry)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *ECRCacheStepTest) TestMissingDockerCredential() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.DockerToken = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+}
+
+func (s *ECRCacheStepTest) TestMissingNodeRole() {
+	s.runtimeState.Action = "install"
+	s.runtimeState.AWS.EKSNodeRoleName = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *ECRCacheStepTest) TestECRCacheMirrors() {
+	mirrors, err := steps_aws.ECRCacheMirrors("test")
+	s.Require().NoError(err)
+	s.Equal(steps_aws.ECRCacheMirror{Server: "https://registry-1.docker.io", RepositoryPrefix: "test/dockercache"}, mirrors["docker.io"])
+	s.Equal(steps_aws.ECRCacheMirror{Server: "https://quay.io", RepositoryPrefix: "test/quaycache"}, mirrors["quay.io"])
+	// Not supported by the cache, pulled directly
+	s.NotContains(mirrors, "ghcr.io")
+}
+
+func (s *ECRCacheStepTest) TestUpstreamRegistry() {
+	s.Equal("docker.io", steps_aws.UpstreamRegistry("alpine"))
+	s.Equal("docker.io", steps_aws.UpstreamRegistry("bitnami/postgresql:16"))
+	s.Equal("docker.io", steps_aws.UpstreamRegistry("docker.io/library/alpine"))
+	s.Equal("quay.io", steps_aws.UpstreamRegistry("quay.io/keycloak/keycloak"))
+	s.Equal("localhost:5000", steps_aws.UpstreamRegistry("localhost:5000/test"))
+}
+
+func (s *ECRCacheStepTest) expectTFUtiliyCall(action string) {
+	input := steps.TerraformUtilityInput{
+		Action:             action,
+		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.ECRCacheModulePath),
+		LogFile:            filepath.Join(s.logDir, "aws_ecr_cache.log"),
+		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
+		Variables: steps_aws.ECRCacheVariables{
+			Region:       s.config.AWS.Region,
+			ClusterName:  s.config.Global.OrchName,
+			CustomerTag:  s.config.AWS.CustomerTag,
+			NodeRoleName: s.runtimeState.AWS.EKSNodeRoleName,
+			CacheRules: map[string]steps_aws.ECRCacheRule{
+				"test/dockercache": {
+					UpstreamRegistryURL: "registry-1.docker.io",
+					UseDockerCredential: true,
+				},
+				"test/quaycache": {
+					UpstreamRegistryURL: "quay.io",
+				},
+				"test/k8scache": {
+					UpstreamRegistryURL: "registry.k8s.io",
+				},
+				"test/ecrpubliccache": {
+					UpstreamRegistryURL: "public.ecr.aws",
+				},
+			},
+			DockerUsername: s.config.AWS.DockerUsername,
+			DockerToken:    s.config.AWS.DockerToken,
+		},
+		BackendConfig: steps_aws.TerraformAWSBucketBackendConfig{
+			Region: s.config.AWS.Region,
+			Bucket: fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			Key:    "ecr_cache.tfstate",
+		},
+		TerraformState: "",
+	}
+	if action == "install" || action == "upgrade" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output: map[string]tfexec.OutputMeta{
+				"cache_registry": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"` + testECRRegistry + `"`),
+				},
+			},
+		}, nil).Once()
+	}
+	if action == "uninstall" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output:         map[string]tfexec.OutputMeta{},
+		}, nil).Once()
+	}
+}
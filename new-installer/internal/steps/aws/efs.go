@@ -28,6 +28,7 @@ type EFSVariables struct {
 	PrivateSubnetIDs []string `json:"private_subnet_ids" yaml:"private_subnet_ids"`
 	VPCID            string   `json:"vpc_id" yaml:"vpc_id"`
 	EKSOIDCIssuer    string   `json:"eks_oidc_issuer" yaml:"eks_oidc_issuer"`
+	KMSKeyARN        string   `json:"kms_key_arn,omitempty" yaml:"kms_key_arn,omitempty"`
 }
 
 // NewDefaultEFSVariables creates a new EFSVariables with default values
@@ -74,6 +75,7 @@ func (s *EFSStep) ConfigStep(ctx context.Context, config config.OrchInstallerCon
 	s.variables.ClusterName = config.Global.OrchName
 	s.variables.Region = config.AWS.Region
 	s.variables.CustomerTag = config.AWS.CustomerTag
+	s.variables.KMSKeyARN = runtimeState.AWS.KMSKeyARN
 
 	if runtimeState.AWS.EKSOIDCIssuer == "" {
 		return runtimeState, &internal.OrchInstallerError{
@@ -41,7 +41,7 @@ func (s *EFSStepTest) SetupTest() {
 		return
 	}
 	s.logDir = filepath.Join(rootPath, ".logs")
-	if err := internal.InitLogger("debug", s.logDir); err != nil {
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
 		s.NoError(err)
 		return
 	}
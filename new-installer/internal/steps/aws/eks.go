@@ -0,0 +1,432 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	EKSModulePath        = "new-installer/targets/aws/iac/eks"
+	EKSBackendBucketKey  = "eks.tfstate"
+	DefaultEKSVersion    = "1.32"
+	DefaultEKSVolumeType = "gp3"
+
+	ObservabilityNodeGroupName = "observability"
+	ObservabilityNodeLabel     = "node.kubernetes.io/custom-rule"
+)
+
+var eksStepLabels = []string{
+	"aws",
+	"eks",
+}
+
+type EKSNodeGroupTaint struct {
+	Value  string `json:"value" yaml:"value"`
+	Effect string `json:"effect" yaml:"effect"`
+}
+
+type EKSNodeGroup struct {
+	DesiredSize  int                          `json:"desired_size" yaml:"desired_size"`
+	MinSize      int                          `json:"min_size" yaml:"min_size"`
+	MaxSize      int                          `json:"max_size" yaml:"max_size"`
+	Taints       map[string]EKSNodeGroupTaint `json:"taints" yaml:"taints"`
+	Labels       map[string]string            `json:"labels" yaml:"labels"`
+	InstanceType string                       `json:"instance_type" yaml:"instance_type"`
+	VolumeSize   int                          `json:"volume_size" yaml:"volume_size"`
+	VolumeType   string                       `json:"volume_type" yaml:"volume_type"`
+}
+
+type EKSVariables struct {
+	Name                 string                    `json:"name" yaml:"name"`
+	Region               string                    `json:"region" yaml:"region"`
+	VPCID                string                    `json:"vpc_id" yaml:"vpc_id"`
+	CustomerTag          string                    `json:"customer_tag" yaml:"customer_tag"`
+	SubnetIDs            []string                  `json:"subnet_ids" yaml:"subnet_ids"`
+	EKSVersion           string                    `json:"eks_version" yaml:"eks_version"`
+	VolumeSize           int                       `json:"volume_size" yaml:"volume_size"`
+	VolumeType           string                    `json:"volume_type" yaml:"volume_type"`
+	NodeInstanceType     string                    `json:"node_instance_type" yaml:"node_instance_type"`
+	DesiredSize          int                       `json:"desired_size" yaml:"desired_size"`
+	MinSize              int                       `json:"min_size" yaml:"min_size"`
+	MaxSize              int                       `json:"max_size" yaml:"max_size"`
+	MaxPods              int                       `json:"max_pods" yaml:"max_pods"`
+	AdditionalNodeGroups map[string]EKSNodeGroup   `json:"additional_node_groups" yaml:"additional_node_groups"`
+	EnableCacheRegistry  string                    `json:"enable_cache_registry" yaml:"enable_cache_registry"`
+	CacheRegistry        string                    `json:"cache_registry" yaml:"cache_registry"`
+	ECRCacheMirrors      map[string]ECRCacheMirror `json:"ecr_cache_mirrors" yaml:"ecr_cache_mirrors"`
+	HTTPProxy            string                    `json:"http_proxy" yaml:"http_proxy"`
+	HTTPSProxy           string                    `json:"https_proxy" yaml:"https_proxy"`
+	NoProxy              string                    `json:"no_proxy" yaml:"no_proxy"`
+	ClusterDNSIP         string                    `json:"cluster_dns_ip" yaml:"cluster_dns_ip"`
+	IAMRoles             []string                  `json:"iam_roles" yaml:"iam_roles"`
+}
+
+// NewDefaultEKSVariables creates a new EKSVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultEKSVariables() EKSVariables {
+	return EKSVariables{
+		Name:                 "",
+		Region:               "",
+		VPCID:                "",
+		CustomerTag:          "",
+		SubnetIDs:            []string{},
+		EKSVersion:           DefaultEKSVersion,
+		VolumeSize:           20,
+		VolumeType:           DefaultEKSVolumeType,
+		NodeInstanceType:     "t3.2xlarge",
+		DesiredSize:          1,
+		MinSize:              1,
+		MaxSize:              1,
+		MaxPods:              58,
+		AdditionalNodeGroups: map[string]EKSNodeGroup{},
+		EnableCacheRegistry:  "false",
+		CacheRegistry:        "",
+		ECRCacheMirrors:      map[string]ECRCacheMirror{},
+		HTTPProxy:            "",
+		HTTPSProxy:           "",
+		NoProxy:              "",
+		ClusterDNSIP:         "",
+		IAMRoles:             []string{},
+	}
+}
+
+// eksScaleProfile describes the default and observability node groups for a given scale.
+// The values are the same as the profiles used by the legacy pod-configs provisioning.
+type eksScaleProfile struct {
+	volumeSize                int
+	nodeInstanceType          string
+	nodeCount                 int
+	maxPods                   int
+	observabilityInstanceType string
+	observabilityNodeCount    int
+	observabilityVolumeSize   int
+}
+
+var eksScaleProfiles = map[config.Scale]eksScaleProfile{
+	config.Scale50: {
+		volumeSize:                20,
+		nodeInstanceType:          "t3.2xlarge",
+		nodeCount:                 3,
+		maxPods:                   58,
+		observabilityInstanceType: "t3.2xlarge",
+		observabilityNodeCount:    1,
+		observabilityVolumeSize:   20,
+	},
+	config.Scale100: {
+		volumeSize:                128,
+		nodeInstanceType:          "t3.2xlarge",
+		nodeCount:                 3,
+		maxPods:                   58,
+		observabilityInstanceType: "r5.2xlarge",
+		observabilityNodeCount:    1,
+		observabilityVolumeSize:   128,
+	},
+	config.Scale500: {
+		volumeSize:                128,
+		nodeInstanceType:          "t3.2xlarge",
+		nodeCount:                 3,
+		maxPods:                   58,
+		observabilityInstanceType: "r5.4xlarge",
+		observabilityNodeCount:    2,
+		observabilityVolumeSize:   128,
+	},
+	config.Scale1000: {
+		volumeSize:                128,
+		nodeInstanceType:          "m4.4xlarge",
+		nodeCount:                 3,
+		maxPods:                   234,
+		observabilityInstanceType: "r5.4xlarge",
+		observabilityNodeCount:    3,
+		observabilityVolumeSize:   128,
+	},
+}
+
+type EKSStep struct {
+	variables          EKSVariables
+	backendConfig      TerraformAWSBucketBackendConfig
+	RootPath           string
+	KeepGeneratedFiles bool
+	TerraformUtility   steps.TerraformUtility
+	AWSUtility         AWSUtility
+}
+
+func CreateEKSStep(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility AWSUtility) *EKSStep {
+	return &EKSStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: keepGeneratedFiles,
+		TerraformUtility:   terraformUtility,
+		AWSUtility:         awsUtility,
+	}
+}
+
+func (s *EKSStep) Name() string {
+	return "EKSStep"
+}
+
+func (s *EKSStep) Labels() []string {
+	return eksStepLabels
+}
+
+func (s *EKSStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	s.variables = NewDefaultEKSVariables()
+	s.variables.Name = cfg.Global.OrchName
+	s.variables.Region = cfg.AWS.Region
+	s.variables.CustomerTag = cfg.AWS.CustomerTag
+
+	if runtimeState.AWS.VPCID == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("VPCID should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+	s.variables.VPCID = runtimeState.AWS.VPCID
+
+	if len(runtimeState.AWS.PrivateSubnetIDs) == 0 {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("PrivateSubnetIDs should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+	s.variables.SubnetIDs = runtimeState.AWS.PrivateSubnetIDs
+
+	profile, ok := eksScaleProfiles[cfg.Global.Scale]
+	if !ok {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("unsupported scale %d for step %s", cfg.Global.Scale, s.Name()),
+		}
+	}
+	s.variables.VolumeSize = profile.volumeSize
+	s.variables.NodeInstanceType = profile.nodeInstanceType
+	s.variables.DesiredSize = profile.nodeCount
+	s.variables.MinSize = profile.nodeCount
+	s.variables.MaxSize = profile.nodeCount
+	s.variables.MaxPods = profile.maxPods
+	s.variables.AdditionalNodeGroups[ObservabilityNodeGroupName] = EKSNodeGroup{
+		DesiredSize: profile.observabilityNodeCount,
+		MinSize:     profile.observabilityNodeCount,
+		MaxSize:     profile.observabilityNodeCount,
+		Labels: map[string]string{
+			ObservabilityNodeLabel: ObservabilityNodeGroupName,
+		},
+		Taints: map[string]EKSNodeGroupTaint{
+			ObservabilityNodeLabel: {
+				Value:  ObservabilityNodeGroupName,
+				Effect: "NO_SCHEDULE",
+			},
+		},
+		InstanceType: profile.observabilityInstanceType,
+		VolumeSize:   profile.observabilityVolumeSize,
+		VolumeType:   DefaultEKSVolumeType,
+	}
+
+	// The nodes mirror the upstream registries to the ECR pull-through cache created by
+	// ECRCacheStep, and fall back to the upstream registries until the cache rules exist.
+	if cfg.AWS.CacheRegistry == config.ECRCacheRegistry {
+		mirrors, err := ECRCacheMirrors(cfg.Global.OrchName)
+		if err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  err.Error(),
+			}
+		}
+		s.variables.ECRCacheMirrors = mirrors
+	} else if cfg.AWS.CacheRegistry != "" {
+		s.variables.EnableCacheRegistry = "true"
+		s.variables.CacheRegistry = cfg.AWS.CacheRegistry
+	}
+	s.variables.HTTPProxy = cfg.Proxy.HTTPProxy
+	s.variables.HTTPSProxy = cfg.Proxy.HTTPSProxy
+	s.variables.NoProxy = cfg.Proxy.NoProxy
+	s.variables.ClusterDNSIP = cfg.AWS.EKSDNSIP
+	if len(cfg.AWS.EKSIAMRoles) > 0 {
+		s.variables.IAMRoles = cfg.AWS.EKSIAMRoles
+	}
+
+	s.backendConfig = TerraformAWSBucketBackendConfig{
+		Region: cfg.AWS.Region,
+		Bucket: cfg.Global.OrchName + "-" + runtimeState.DeploymentID,
+		Key:    EKSBackendBucketKey,
+	}
+	return runtimeState, nil
+}
+
+func (s *EKSStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if cfg.AWS.PreviousS3StateBucket == "" {
+		// No need to migrate state, since there is no previous state bucket
+		return runtimeState, nil
+	}
+
+	// Need to move Terraform state from old bucket to new bucket:
+	oldEKSBucketKey := fmt.Sprintf("%s/cluster/%s", cfg.AWS.Region, cfg.Global.OrchName)
+	err := s.AWSUtility.S3CopyToS3(cfg.AWS.Region,
+		cfg.AWS.PreviousS3StateBucket,
+		oldEKSBucketKey,
+		cfg.AWS.Region,
+		s.backendConfig.Bucket,
+		s.backendConfig.Key)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to move Terraform state from old bucket to new bucket: %v", err),
+		}
+	}
+
+	modulePath := filepath.Join(s.RootPath, EKSModulePath)
+	mvErr := s.TerraformUtility.MoveStates(ctx, steps.TerraformUtilityMoveStatesInput{
+		ModulePath: modulePath,
+		States: map[string]string{
+			"module.eks.aws_eks_addon.addons":                                                "aws_eks_addon.addons",
+			"module.eks.aws_eks_cluster.eks_cluster":                                         "aws_eks_cluster.eks_cluster",
+			"module.eks.aws_eks_node_group.additional_node_group":                            "aws_eks_node_group.additional_node_group",
+			"module.eks.aws_eks_node_group.nodegroup":                                        "aws_eks_node_group.nodegroup",
+			"module.eks.aws_iam_openid_connect_provider.cluster":                             "aws_iam_openid_connect_provider.cluster",
+			"module.eks.aws_iam_policy.aws_load_balancer":                                    "aws_iam_policy.aws_load_balancer",
+			"module.eks.aws_iam_policy.cas_controller":                                       "aws_iam_policy.cas_controller",
+			"module.eks.aws_iam_policy.certmgr_acm_sync":                                     "aws_iam_policy.certmgr_acm_sync",
+			"module.eks.aws_iam_policy.certmgr_write_route53":                                "aws_iam_policy.certmgr_write_route53",
+			"module.eks.aws_iam_role.cas_controller":                                         "aws_iam_role.cas_controller",
+			"module.eks.aws_iam_role.certmgr":                                                "aws_iam_role.certmgr",
+			"module.eks.aws_iam_role.eks_nodes":                                              "aws_iam_role.eks_nodes",
+			"module.eks.aws_iam_role.iam_role_eks_cluster":                                   "aws_iam_role.iam_role_eks_cluster",
+			"module.eks.aws_iam_role_policy_attachment.AmazonEBSCSIDriverPolicy":             "aws_iam_role_policy_attachment.AmazonEBSCSIDriverPolicy",
+			"module.eks.aws_iam_role_policy_attachment.AmazonEC2ContainerRegistryReadOnly":   "aws_iam_role_policy_attachment.AmazonEC2ContainerRegistryReadOnly",
+			"module.eks.aws_iam_role_policy_attachment.AmazonEFSCSIDriverPolicy":             "aws_iam_role_policy_attachment.AmazonEFSCSIDriverPolicy",
+			"module.eks.aws_iam_role_policy_attachment.AmazonEKSWorkerNodePolicy":            "aws_iam_role_policy_attachment.AmazonEKSWorkerNodePolicy",
+			"module.eks.aws_iam_role_policy_attachment.AmazonEKS_CNI_Policy":                 "aws_iam_role_policy_attachment.AmazonEKS_CNI_Policy",
+			"module.eks.aws_iam_role_policy_attachment.AmazonSSMManagedInstanceCore":         "aws_iam_role_policy_attachment.AmazonSSMManagedInstanceCore",
+			"module.eks.aws_iam_role_policy_attachment.ELB_Controller":                       "aws_iam_role_policy_attachment.ELB_Controller",
+			"module.eks.aws_iam_role_policy_attachment.cas_controller":                       "aws_iam_role_policy_attachment.cas_controller",
+			"module.eks.aws_iam_role_policy_attachment.certmgr_AmazonSSMManagedInstanceCore": "aws_iam_role_policy_attachment.certmgr_AmazonSSMManagedInstanceCore",
+			"module.eks.aws_iam_role_policy_attachment.certmgr_acm_sync_certmgr":             "aws_iam_role_policy_attachment.certmgr_acm_sync_certmgr",
+			"module.eks.aws_iam_role_policy_attachment.certmgr_acm_sync_eks_node":            "aws_iam_role_policy_attachment.certmgr_acm_sync_eks_node",
+			"module.eks.aws_iam_role_policy_attachment.certmgr_write_route53":                "aws_iam_role_policy_attachment.certmgr_write_route53",
+			"module.eks.aws_iam_role_policy_attachment.eks_cluster_AmazonEKSClusterPolicy":   "aws_iam_role_policy_attachment.eks_cluster_AmazonEKSClusterPolicy",
+			"module.eks.aws_iam_role_policy_attachment.eks_cluster_AmazonEKSServicePolicy":   "aws_iam_role_policy_attachment.eks_cluster_AmazonEKSServicePolicy",
+			"module.eks.aws_launch_template.additional_node_group_launch_template":           "aws_launch_template.additional_node_group_launch_template",
+			"module.eks.aws_launch_template.eks_launch_template":                             "aws_launch_template.eks_launch_template",
+			"module.eks.aws_security_group.eks_cluster":                                      "aws_security_group.eks_cluster",
+		},
+	})
+	if mvErr != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to move Terraform states: %v", mvErr),
+		}
+	}
+
+	rmErr := s.TerraformUtility.RemoveStates(ctx, steps.TerraformUtilityRemoveStatesInput{
+		ModulePath: modulePath,
+		States: []string{
+			"module.eks",
+			"module.s3",
+			"module.efs",
+			"module.aurora",
+			"module.aurora_database",
+			"module.aurora_import",
+			"module.kms",
+			"module.orch_init",
+			"module.eks_auth",
+			"module.ec2log",
+			"module.aws_lb_controller",
+			"module.gitea",
+		},
+	})
+	if rmErr != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to remove Terraform states: %v", rmErr),
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *EKSStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	terraformStepInput := steps.TerraformUtilityInput{
+		Action:             runtimeState.Action,
+		ModulePath:         filepath.Join(s.RootPath, EKSModulePath),
+		Variables:          s.variables,
+		BackendConfig:      s.backendConfig,
+		LogFile:            filepath.Join(runtimeState.LogDir, "aws_eks.log"),
+		KeepGeneratedFiles: s.KeepGeneratedFiles,
+	}
+	terraformStepOutput, err := s.TerraformUtility.Run(ctx, terraformStepInput)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("failed to run terraform: %v", err),
+		}
+	}
+
+	if runtimeState.Action == "uninstall" {
+		runtimeState.AWS.KubeConfig = ""
+		runtimeState.AWS.EKSOIDCIssuer = ""
+		runtimeState.AWS.EKSNodeSecurityGroupID = ""
+		runtimeState.AWS.EKSNodeRoleName = ""
+		return runtimeState, nil
+	}
+
+	if terraformStepOutput.Output == nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find any output from %s module", s.Name()),
+		}
+	}
+	if oidcIssuer, ok := terraformStepOutput.Output["eks_oidc_issuer"]; ok {
+		runtimeState.AWS.EKSOIDCIssuer = strings.Trim(string(oidcIssuer.Value), "\"")
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find eks_oidc_issuer in %s module output", s.Name()),
+		}
+	}
+	if sgID, ok := terraformStepOutput.Output["eks_security_group_id"]; ok {
+		runtimeState.AWS.EKSNodeSecurityGroupID = strings.Trim(string(sgID.Value), "\"")
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find eks_security_group_id in %s module output", s.Name()),
+		}
+	}
+	if roleName, ok := terraformStepOutput.Output["eks_nodegroup_role_name"]; ok {
+		runtimeState.AWS.EKSNodeRoleName = strings.Trim(string(roleName.Value), "\"")
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find eks_nodegroup_role_name in %s module output", s.Name()),
+		}
+	}
+	if kubeConfig, ok := terraformStepOutput.Output["kubeconfig"]; ok {
+		// The kubeconfig is a YAML document encoded as a JSON string, so we need to
+		// unquote it instead of just trimming the quotes.
+		var kubeConfigStr string
+		if unmarshalErr := json.Unmarshal(kubeConfig.Value, &kubeConfigStr); unmarshalErr != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+				ErrorMsg:  fmt.Sprintf("failed to parse kubeconfig from %s module output: %v", s.Name(), unmarshalErr),
+			}
+		}
+		runtimeState.AWS.KubeConfig = kubeConfigStr
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find kubeconfig in %s module output", s.Name()),
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *EKSStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
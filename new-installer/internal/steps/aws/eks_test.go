@@ -0,0 +1,239 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws/iac/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+const testKubeConfig = "apiVersion: v1\nkind: Config\n"
+
+type EKSStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *steps_aws.EKSStep
+	logDir       string
+	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
+}
+
+func TestEKSStep(t *testing.T) {
+	suite.Run(t, new(EKSStepTest))
+}
+
+func (s *EKSStepTest) SetupTest() {
+	rootPath, err := filepath.Abs("../../../../")
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.logDir = filepath.Join(rootPath, ".logs")
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
+		s.NoError(err)
+		return
+	}
+
+	s.config.AWS.Region = "us-west-2"
+	s.config.Global.OrchName = "test"
+	s.config.Global.Scale = config.Scale500
+	s.config.AWS.CustomerTag = utils.DefaultTestCustomerTag
+	s.config.AWS.CacheRegistry = "cache.example.com"
+	s.config.AWS.EKSIAMRoles = []string{"admin-role"}
+	s.runtimeState.AWS.PrivateSubnetIDs = []string{"subnet-12345678", "subnet-87654321"}
+	s.runtimeState.AWS.VPCID = "vpc-12345678"
+	s.runtimeState.LogDir = s.logDir
+
+	if _, err := os.Stat(s.logDir); os.IsNotExist(err) {
+		err := os.MkdirAll(s.logDir, os.ModePerm)
+		if err != nil {
+			s.NoError(err)
+			return
+		}
+	}
+	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
+	s.step = &steps_aws.EKSStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: true,
+		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
+	}
+}
+
+func (s *EKSStepTest) TestInstallAndUninstallEKS() {
+	s.runtimeState.Action = "install"
+	s.expectTFUtiliyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("https://oidc.eks.us-west-2.amazonaws.com/id/test", rs.AWS.EKSOIDCIssuer)
+	s.Equal(testKubeConfig, rs.AWS.KubeConfig)
+	s.Equal("sg-12345678", rs.AWS.EKSNodeSecurityGroupID)
+	s.Equal("eks-node-test", rs.AWS.EKSNodeRoleName)
+
+	s.runtimeState.Action = "uninstall"
+	s.expectTFUtiliyCall("uninstall")
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.KubeConfig)
+}
+
+// The nodes mirror the registries cached by the ECR pull-through cache
+func (s *EKSStepTest) TestInstallWithECRCache() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.CacheRegistry = config.ECRCacheRegistry
+	s.expectTFUtiliyCall("install")
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *EKSStepTest) TestUpgradeEKS() {
+	s.runtimeState.Action = "upgrade"
+	s.config.AWS.PreviousS3StateBucket = "old-bucket-name"
+	s.expectTFUtiliyCall("upgrade")
+	s.awsUtility.On("S3CopyToS3",
+		s.config.AWS.Region,
+		s.config.AWS.PreviousS3StateBucket,
+		fmt.Sprintf("%s/cluster/%s", s.config.AWS.Region, s.config.Global.OrchName),
+		s.config.AWS.Region,
+		s.config.Global.OrchName+"-"+s.runtimeState.DeploymentID,
+		"eks.tfstate",
+	).Return(nil).Once()
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.EKSModulePath) &&
+			input.States["module.eks.aws_eks_cluster.eks_cluster"] == "aws_eks_cluster.eks_cluster"
+	})).Return(nil).Once()
+	s.tfUtility.On("RemoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityRemoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.EKSModulePath)
+	})).Return(nil).Once()
+
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.NotEmpty(rs.AWS.EKSOIDCIssuer)
+	s.NotEmpty(rs.AWS.KubeConfig)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *EKSStepTest) TestUnsupportedScale() {
+	s.runtimeState.Action = "install"
+	s.config.Global.Scale = 42
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+}
+
+func (s *EKSStepTest) expectTFUtiliyCall(action string) {
+	variables := steps_aws.NewDefaultEKSVariables()
+	variables.Name = s.config.Global.OrchName
+	variables.Region = s.config.AWS.Region
+	variables.CustomerTag = s.config.AWS.CustomerTag
+	variables.VPCID = s.runtimeState.AWS.VPCID
+	variables.SubnetIDs = s.runtimeState.AWS.PrivateSubnetIDs
+	variables.VolumeSize = 128
+	variables.NodeInstanceType = "t3.2xlarge"
+	variables.DesiredSize = 3
+	variables.MinSize = 3
+	variables.MaxSize = 3
+	variables.MaxPods = 58
+	variables.AdditionalNodeGroups = map[string]steps_aws.EKSNodeGroup{
+		steps_aws.ObservabilityNodeGroupName: {
+			DesiredSize: 2,
+			MinSize:     2,
+			MaxSize:     2,
+			Labels: map[string]string{
+				steps_aws.ObservabilityNodeLabel: steps_aws.ObservabilityNodeGroupName,
+			},
+			Taints: map[string]steps_aws.EKSNodeGroupTaint{
+				steps_aws.ObservabilityNodeLabel: {
+					Value:  steps_aws.ObservabilityNodeGroupName,
+					Effect: "NO_SCHEDULE",
+				},
+			},
+			InstanceType: "r5.4xlarge",
+			VolumeSize:   128,
+			VolumeType:   "gp3",
+		},
+	}
+	if s.config.AWS.CacheRegistry == config.ECRCacheRegistry {
+		variables.ECRCacheMirrors = map[string]steps_aws.ECRCacheMirror{
+			"docker.io":       {Server: "https://registry-1.docker.io", RepositoryPrefix: "test/dockercache"},
+			"quay.io":         {Server: "https://quay.io", RepositoryPrefix: "test/quaycache"},
+			"registry.k8s.io": {Server: "https://registry.k8s.io", RepositoryPrefix: "test/k8scache"},
+			"public.ecr.aws":  {Server: "https://public.ecr.aws", RepositoryPrefix: "test/ecrpubliccache"},
+		}
+	} else {
+		variables.EnableCacheRegistry = "true"
+		variables.CacheRegistry = s.config.AWS.CacheRegistry
+	}
+	variables.IAMRoles = s.config.AWS.EKSIAMRoles
+
+	input := steps.TerraformUtilityInput{
+		Action:             action,
+		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.EKSModulePath),
+		LogFile:            filepath.Join(s.logDir, "aws_eks.log"),
+		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
+		Variables:          variables,
+		BackendConfig: steps_aws.TerraformAWSBucketBackendConfig{
+			Region: s.config.AWS.Region,
+			Bucket: fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			Key:    "eks.tfstate",
+		},
+		TerraformState: "",
+	}
+	kubeConfigValue, _ := json.Marshal(testKubeConfig)
+	if action == "install" || action == "upgrade" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output: map[string]tfexec.OutputMeta{
+				"eks_oidc_issuer": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"https://oidc.eks.us-west-2.amazonaws.com/id/test"`),
+				},
+				"eks_security_group_id": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"sg-12345678"`),
+				},
+				"eks_nodegroup_role_name": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"eks-node-test"`),
+				},
+				"kubeconfig": {
+					Type:      json.RawMessage(`"string"`),
+					Value:     json.RawMessage(kubeConfigValue),
+					Sensitive: true,
+				},
+			},
+		}, nil).Once()
+	}
+	if action == "uninstall" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output:         map[string]tfexec.OutputMeta{},
+		}, nil).Once()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
@@ -25,16 +26,20 @@ var kmsStepLabels = []string{
 }
 
 type KMSVariables struct {
-	Region      string `json:"region" yaml:"region"`
-	CustomerTag string `json:"customer_tag" yaml:"customer_tag"`
-	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Region        string `json:"region" yaml:"region"`
+	CustomerTag   string `json:"customer_tag" yaml:"customer_tag"`
+	ClusterName   string `json:"cluster_name" yaml:"cluster_name"`
+	EKSOIDCIssuer string `json:"eks_oidc_issuer" yaml:"eks_oidc_issuer"`
+	KMSKeyARN     string `json:"kms_key_arn,omitempty" yaml:"kms_key_arn,omitempty"`
 }
 
 func NewKMSVariables() KMSVariables {
 	return KMSVariables{
-		Region:      "",
-		CustomerTag: "",
-		ClusterName: "",
+		Region:        "",
+		CustomerTag:   "",
+		ClusterName:   "",
+		EKSOIDCIssuer: "",
+		KMSKeyARN:     "",
 	}
 }
 
@@ -69,6 +74,14 @@ func (s *KMSStep) ConfigStep(ctx context.Context, config config.OrchInstallerCon
 	s.variables.Region = config.AWS.Region
 	s.variables.CustomerTag = config.AWS.CustomerTag
 	s.variables.ClusterName = config.Global.OrchName
+	s.variables.KMSKeyARN = config.AWS.KMSKeyARN
+	if runtimeState.AWS.EKSOIDCIssuer == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("EKSOIDCIssuer should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+	s.variables.EKSOIDCIssuer = runtimeState.AWS.EKSOIDCIssuer
 	s.backendConfig = steps.TerraformAWSBucketBackendConfig{
 		Bucket: config.Global.OrchName + "-" + runtimeState.DeploymentID,
 		Region: config.AWS.Region,
@@ -99,6 +112,7 @@ func (s *KMSStep) PreStep(ctx context.Context, config config.OrchInstallerConfig
 	}
 
 	modulePath := filepath.Join(s.RootPath, KMSModulePath)
+	// The IAM user of Vault is moved as well so that the next apply deletes it, Vault uses the IRSA role instead
 	states := map[string]string{
 		"module.kms.aws_iam_user.vault":       "aws_iam_user.vault",
 		"module.kms.aws_iam_access_key.vault": "aws_iam_access_key.vault",
@@ -154,16 +168,71 @@ func (s *KMSStep) RunStep(ctx context.Context, config config.OrchInstallerConfig
 		KeepGeneratedFiles: s.KeepGeneratedFiles,
 	}
 	internal.Logger().Debugf("Running Terraform util %s with input: %+v\n", s.TerraformUtility, terraformStepInput)
-	_, err := s.TerraformUtility.Run(ctx, terraformStepInput)
+	output, err := s.TerraformUtility.Run(ctx, terraformStepInput)
 	if err != nil {
 		return runtimeState, &internal.OrchInstallerError{
 			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
 			ErrorMsg:  fmt.Sprintf("failed to run terraform: %v", err),
 		}
 	}
+	if runtimeState.Action == "uninstall" {
+		runtimeState.AWS.VaultKMSKeyARN = ""
+		runtimeState.AWS.VaultRoleARN = ""
+		runtimeState.AWS.KMSKeyRotationEnabled = false
+		return runtimeState, nil
+	}
+	if output.Output == nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  "cannot find any output from KMS module",
+		}
+	}
+	if kmsKeyARN, ok := output.Output["kms_key_arn"]; ok {
+		runtimeState.AWS.VaultKMSKeyARN = strings.Trim(string(kmsKeyARN.Value), "\"")
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  "cannot find kms_key_arn in KMS module output",
+		}
+	}
+	if vaultRoleARN, ok := output.Output["vault_role_arn"]; ok {
+		runtimeState.AWS.VaultRoleARN = strings.Trim(string(vaultRoleARN.Value), "\"")
+	} else {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  "cannot find vault_role_arn in KMS module output",
+		}
+	}
+	runtimeState.AWS.KMSKeyRotationEnabled = s.checkKeyRotation(config.AWS.Region, runtimeState.AWS.VaultKMSKeyARN, runtimeState.AWS.KMSKeyARN)
 	return runtimeState, nil
 }
 
+// checkKeyRotation reports whether automatic rotation is enabled for all given keys. Keys created
+// by the installer always have rotation enabled, existing customer-managed keys may not.
+func (s *KMSStep) checkKeyRotation(region string, keyARNs ...string) bool {
+	enabled := true
+	checked := map[string]bool{}
+	for _, keyARN := range keyARNs {
+		if keyARN == "" || checked[keyARN] {
+			continue
+		}
+		checked[keyARN] = true
+		rotation, err := s.AWSUtility.GetKMSKeyRotationStatus(region, keyARN)
+		if err != nil {
+			internal.Logger().Warnf("Unable to get rotation status of KMS key %s: %v", keyARN, err)
+			enabled = false
+			continue
+		}
+		if !rotation {
+			internal.Logger().Warnf("Automatic rotation is disabled for KMS key %s", keyARN)
+			enabled = false
+			continue
+		}
+		internal.Logger().Infof("Automatic rotation is enabled for KMS key %s", keyARN)
+	}
+	return enabled
+}
+
 func (s *KMSStep) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	return runtimeState, prevStepError
 }
@@ -6,6 +6,7 @@ package steps_aws_test
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"fmt"
 	"path/filepath"
 	"strings"
@@ -20,6 +21,12 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const (
+	testVaultKMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/vault"
+	testSharedKeyARN   = "arn:aws:kms:us-west-2:123456789012:key/shared"
+	testVaultRoleARN   = "arn:aws:iam::123456789012:role/vault-kms-test"
+)
+
 type KMSStepTest struct {
 	suite.Suite
 	config       config.OrchInstallerConfig
@@ -28,6 +35,7 @@ type KMSStepTest struct {
 	randomText   string
 	logDir       string
 	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
 }
 
 func TestKMSStep(t *testing.T) {
@@ -39,49 +47,89 @@ func (s *KMSStepTest) SetupTest() {
 	s.Require().NoError(err, "Failed to get absolute path")
 	s.randomText = strings.ToLower(rand.Text()[0:8])
 	s.logDir = filepath.Join(rootPath, ".logs")
-	err = internal.InitLogger("debug", s.logDir)
+	err = internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir})
 	s.Require().NoError(err, "Failed to initialize logger")
 	s.config.AWS.Region = "us-west-2"
 	s.config.Global.OrchName = "kms-test"
 	s.config.AWS.CustomerTag = "test"
 	s.runtimeState.DeploymentID = "test-deployment-id"
 	s.runtimeState.LogDir = filepath.Join(rootPath, ".logs")
+	s.runtimeState.AWS.EKSOIDCIssuer = "https://oidc.eks.us-west-2.amazonaws.com/id/test"
+	s.runtimeState.AWS.KMSKeyARN = testSharedKeyARN
 	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
 	s.step = &steps_aws.KMSStep{
 		RootPath:           rootPath,
 		KeepGeneratedFiles: true,
 		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
 	}
 }
 
 func (s *KMSStepTest) TestInstallAndUninstallKMS() {
 	s.runtimeState.Action = "install"
-	s.expectTFUtiliyCall("install")
+	s.expectTFUtiliyCall("install", testVaultKMSKeyARN)
+	s.awsUtility.On("GetKMSKeyRotationStatus", "us-west-2", testVaultKMSKeyARN).Return(true, nil).Once()
+	s.awsUtility.On("GetKMSKeyRotationStatus", "us-west-2", testSharedKeyARN).Return(true, nil).Once()
 	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
 	if err != nil {
 		s.NoError(err)
 		return
 	}
-	fmt.Println(rs)
+	s.Equal(testVaultKMSKeyARN, rs.AWS.VaultKMSKeyARN)
+	s.Equal(testVaultRoleARN, rs.AWS.VaultRoleARN)
+	s.True(rs.AWS.KMSKeyRotationEnabled)
 
+	s.runtimeState = rs
 	s.runtimeState.Action = "uninstall"
-	s.expectTFUtiliyCall("uninstall")
-	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.expectTFUtiliyCall("uninstall", "")
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
 	if err != nil {
 		s.NoError(err)
+		return
 	}
+	s.Empty(rs.AWS.VaultKMSKeyARN)
+	s.Empty(rs.AWS.VaultRoleARN)
+	s.False(rs.AWS.KMSKeyRotationEnabled)
+	s.tfUtility.AssertExpectations(s.T())
+	s.awsUtility.AssertExpectations(s.T())
 }
 
-func (s *KMSStepTest) expectTFUtiliyCall(action string) {
+func (s *KMSStepTest) TestCustomerManagedKeyWithoutRotation() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.KMSKeyARN = testSharedKeyARN
+	s.expectTFUtiliyCall("install", testSharedKeyARN)
+	s.awsUtility.On("GetKMSKeyRotationStatus", "us-west-2", testSharedKeyARN).Return(false, nil).Once()
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(testSharedKeyARN, rs.AWS.VaultKMSKeyARN)
+	s.False(rs.AWS.KMSKeyRotationEnabled)
+	s.awsUtility.AssertExpectations(s.T())
+}
+
+func (s *KMSStepTest) TestMissingOIDCIssuer() {
+	s.runtimeState.Action = "install"
+	s.runtimeState.AWS.EKSOIDCIssuer = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *KMSStepTest) expectTFUtiliyCall(action string, kmsKeyARN string) {
 	input := steps.TerraformUtilityInput{
 		Action:             action,
 		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.KMSModulePath),
 		LogFile:            filepath.Join(s.logDir, "aws_kms.log"),
 		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
 		Variables: steps_aws.KMSVariables{
-			Region:      s.config.AWS.Region,
-			CustomerTag: s.config.AWS.CustomerTag,
-			ClusterName: s.config.Global.OrchName,
+			Region:        s.config.AWS.Region,
+			CustomerTag:   s.config.AWS.CustomerTag,
+			ClusterName:   s.config.Global.OrchName,
+			EKSOIDCIssuer: s.runtimeState.AWS.EKSOIDCIssuer,
+			KMSKeyARN:     s.config.AWS.KMSKeyARN,
 		},
 		BackendConfig: steps.TerraformAWSBucketBackendConfig{
 			Region: s.config.AWS.Region,
@@ -90,8 +138,19 @@ func (s *KMSStepTest) expectTFUtiliyCall(action string) {
 		},
 		TerraformState: "",
 	}
+	output := map[string]tfexec.OutputMeta{}
+	if action != "uninstall" {
+		output["kms_key_arn"] = tfexec.OutputMeta{
+			Type:  json.RawMessage(`"string"`),
+			Value: json.RawMessage(`"` + kmsKeyARN + `"`),
+		}
+		output["vault_role_arn"] = tfexec.OutputMeta{
+			Type:  json.RawMessage(`"string"`),
+			Value: json.RawMessage(`"` + testVaultRoleARN + `"`),
+		}
+	}
 	s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
 		TerraformState: "",
-		Output:         map[string]tfexec.OutputMeta{},
+		Output:         output,
 	}, nil).Once()
 }
@@ -0,0 +1,498 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	ALBModulePath        = "new-installer/targets/aws/iac/alb"
+	ALBBackendBucketKey  = "alb.tfstate"
+	NLBModulePath        = "new-installer/targets/aws/iac/nlb"
+	NLBBackendBucketKey  = "nlb.tfstate"
+	WAFModulePath        = "new-installer/targets/aws/iac/waf"
+	WAFBackendBucketKey  = "waf.tfstate"
+	LBSGModulePath       = "new-installer/targets/aws/iac/lb_sg"
+	LBSGBackendBucketKey = "lb_sg.tfstate"
+
+	DefaultLBWhitelist = "0.0.0.0/0"
+)
+
+var loadBalancerStepLabels = []string{
+	"aws",
+	"load_balancer",
+}
+
+// Modules of the legacy orch-load-balancer Terraform state.
+var legacyLoadBalancerModules = []string{
+	"module.acm_import",
+	"module.traefik_load_balancer",
+	"module.traefik2_load_balancer",
+	"module.argocd_load_balancer",
+	"module.traefik_lb_target_group_binding",
+	"module.aws_lb_security_group_roles",
+	"module.wait_until_alb_ready",
+	"module.waf_web_acl_traefik",
+	"module.waf_web_acl_argocd",
+}
+
+type ALBVariables struct {
+	ClusterName              string   `json:"cluster_name" yaml:"cluster_name"`
+	Region                   string   `json:"region" yaml:"region"`
+	CustomerTag              string   `json:"customer_tag" yaml:"customer_tag"`
+	VPCID                    string   `json:"vpc_id" yaml:"vpc_id"`
+	PublicSubnetIDs          []string `json:"public_subnet_ids" yaml:"public_subnet_ids"`
+	IPAllowList              []string `json:"ip_allow_list" yaml:"ip_allow_list"`
+	Internal                 bool     `json:"internal" yaml:"internal"`
+	EnableDeletionProtection bool     `json:"enable_deletion_protection" yaml:"enable_deletion_protection"`
+	TLSCertARN               string   `json:"tls_cert_arn" yaml:"tls_cert_arn"`
+}
+
+// NewDefaultALBVariables creates a new ALBVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultALBVariables() ALBVariables {
+	return ALBVariables{
+		ClusterName:              "",
+		Region:                   "",
+		CustomerTag:              "",
+		VPCID:                    "",
+		PublicSubnetIDs:          []string{},
+		IPAllowList:              []string{},
+		Internal:                 false,
+		EnableDeletionProtection: true,
+		TLSCertARN:               "",
+	}
+}
+
+type NLBVariables struct {
+	ClusterName              string   `json:"cluster_name" yaml:"cluster_name"`
+	Region                   string   `json:"region" yaml:"region"`
+	CustomerTag              string   `json:"customer_tag" yaml:"customer_tag"`
+	VPCID                    string   `json:"vpc_id" yaml:"vpc_id"`
+	PublicSubnetIDs          []string `json:"public_subnet_ids" yaml:"public_subnet_ids"`
+	Subnets                  []string `json:"subnets" yaml:"subnets"`
+	IPAllowList              []string `json:"ip_allow_list" yaml:"ip_allow_list"`
+	Internal                 bool     `json:"internal" yaml:"internal"`
+	EnableDeletionProtection bool     `json:"enable_deletion_protection" yaml:"enable_deletion_protection"`
+}
+
+// NewDefaultNLBVariables creates a new NLBVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultNLBVariables() NLBVariables {
+	return NLBVariables{
+		ClusterName:              "",
+		Region:                   "",
+		CustomerTag:              "",
+		VPCID:                    "",
+		PublicSubnetIDs:          []string{},
+		Subnets:                  []string{},
+		IPAllowList:              []string{},
+		Internal:                 false,
+		EnableDeletionProtection: true,
+	}
+}
+
+type WAFVariables struct {
+	ClusterName            string `json:"cluster_name" yaml:"cluster_name"`
+	Region                 string `json:"region" yaml:"region"`
+	CustomerTag            string `json:"customer_tag" yaml:"customer_tag"`
+	TraefikLoadBalancerARN string `json:"traefik_load_balancer_arn" yaml:"traefik_load_balancer_arn"`
+	ArgoCDLoadBalancerARN  string `json:"argocd_load_balancer_arn" yaml:"argocd_load_balancer_arn"`
+}
+
+// NewDefaultWAFVariables creates a new WAFVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultWAFVariables() WAFVariables {
+	return WAFVariables{
+		ClusterName:            "",
+		Region:                 "",
+		CustomerTag:            "",
+		TraefikLoadBalancerARN: "",
+		ArgoCDLoadBalancerARN:  "",
+	}
+}
+
+type LBSGVariables struct {
+	ClusterName  string `json:"cluster_name" yaml:"cluster_name"`
+	Region       string `json:"region" yaml:"region"`
+	CustomerTag  string `json:"customer_tag" yaml:"customer_tag"`
+	EKSNodeSGID  string `json:"eks_node_sg_id" yaml:"eks_node_sg_id"`
+	TraefikSGID  string `json:"traefik_sg_id" yaml:"traefik_sg_id"`
+	Traefik2SGID string `json:"traefik2_sg_id" yaml:"traefik2_sg_id"`
+	ArgoCDSGID   string `json:"argocd_sg_id" yaml:"argocd_sg_id"`
+}
+
+// NewDefaultLBSGVariables creates a new LBSGVariables with default values
+// based on variable.tf default definitions.
+func NewDefaultLBSGVariables() LBSGVariables {
+	return LBSGVariables{
+		ClusterName:  "",
+		Region:       "",
+		CustomerTag:  "",
+		EKSNodeSGID:  "",
+		TraefikSGID:  "",
+		Traefik2SGID: "",
+		ArgoCDSGID:   "",
+	}
+}
+
+// LoadBalancerStep creates the load balancers in front of the orchestrator.
+// It runs the ALB, NLB, WAF and load balancer security group modules in order,
+// since each of them depends on the outputs of the previous ones.
+type LoadBalancerStep struct {
+	albVariables       ALBVariables
+	nlbVariables       NLBVariables
+	wafVariables       WAFVariables
+	lbSGVariables      LBSGVariables
+	albBackendConfig   TerraformAWSBucketBackendConfig
+	nlbBackendConfig   TerraformAWSBucketBackendConfig
+	wafBackendConfig   TerraformAWSBucketBackendConfig
+	lbSGBackendConfig  TerraformAWSBucketBackendConfig
+	RootPath           string
+	KeepGeneratedFiles bool
+	TerraformUtility   steps.TerraformUtility
+	AWSUtility         AWSUtility
+}
+
+func CreateLoadBalancerStep(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility AWSUtility) *LoadBalancerStep {
+	return &LoadBalancerStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: keepGeneratedFiles,
+		TerraformUtility:   terraformUtility,
+		AWSUtility:         awsUtility,
+	}
+}
+
+func (s *LoadBalancerStep) Name() string {
+	return "LoadBalancerStep"
+}
+
+func (s *LoadBalancerStep) Labels() []string {
+	return loadBalancerStepLabels
+}
+
+func (s *LoadBalancerStep) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action != "uninstall" {
+		if runtimeState.AWS.VPCID == "" {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+				ErrorMsg:  fmt.Sprintf("VPCID should not be empty in runtime state for step %s", s.Name()),
+			}
+		}
+		if len(runtimeState.AWS.PublicSubnetIDs) == 0 {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+				ErrorMsg:  fmt.Sprintf("PublicSubnetIDs should not be empty in runtime state for step %s", s.Name()),
+			}
+		}
+		if runtimeState.AWS.ACMCertArn == "" {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+				ErrorMsg:  fmt.Sprintf("ACMCertArn should not be empty in runtime state for step %s", s.Name()),
+			}
+		}
+		if runtimeState.AWS.EKSNodeSecurityGroupID == "" {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+				ErrorMsg:  fmt.Sprintf("EKSNodeSecurityGroupID should not be empty in runtime state for step %s", s.Name()),
+			}
+		}
+	}
+
+	ipAllowList := config.AWS.LBWhitelist
+	if len(ipAllowList) == 0 {
+		ipAllowList = []string{DefaultLBWhitelist}
+	}
+	bucket := config.Global.OrchName + "-" + runtimeState.DeploymentID
+
+	s.albVariables = NewDefaultALBVariables()
+	s.albVariables.ClusterName = config.Global.OrchName
+	s.albVariables.Region = config.AWS.Region
+	s.albVariables.CustomerTag = config.AWS.CustomerTag
+	s.albVariables.VPCID = runtimeState.AWS.VPCID
+	s.albVariables.PublicSubnetIDs = runtimeState.AWS.PublicSubnetIDs
+	s.albVariables.IPAllowList = ipAllowList
+	s.albVariables.EnableDeletionProtection = !config.Advanced.DevMode
+	s.albVariables.TLSCertARN = runtimeState.AWS.ACMCertArn
+	s.albBackendConfig = TerraformAWSBucketBackendConfig{
+		Region: config.AWS.Region,
+		Bucket: bucket,
+		Key:    ALBBackendBucketKey,
+	}
+
+	s.nlbVariables = NewDefaultNLBVariables()
+	s.nlbVariables.ClusterName = config.Global.OrchName
+	s.nlbVariables.Region = config.AWS.Region
+	s.nlbVariables.CustomerTag = config.AWS.CustomerTag
+	s.nlbVariables.VPCID = runtimeState.AWS.VPCID
+	s.nlbVariables.PublicSubnetIDs = runtimeState.AWS.PublicSubnetIDs
+	s.nlbVariables.Subnets = runtimeState.AWS.PublicSubnetIDs
+	s.nlbVariables.IPAllowList = ipAllowList
+	s.nlbVariables.EnableDeletionProtection = !config.Advanced.DevMode
+	s.nlbBackendConfig = TerraformAWSBucketBackendConfig{
+		Region: config.AWS.Region,
+		Bucket: bucket,
+		Key:    NLBBackendBucketKey,
+	}
+
+	// Load balancer ARNs and security group IDs are filled in RunStep
+	// from the ALB and NLB module outputs.
+	s.wafVariables = NewDefaultWAFVariables()
+	s.wafVariables.ClusterName = config.Global.OrchName
+	s.wafVariables.Region = config.AWS.Region
+	s.wafVariables.CustomerTag = config.AWS.CustomerTag
+	s.wafBackendConfig = TerraformAWSBucketBackendConfig{
+		Region: config.AWS.Region,
+		Bucket: bucket,
+		Key:    WAFBackendBucketKey,
+	}
+
+	s.lbSGVariables = NewDefaultLBSGVariables()
+	s.lbSGVariables.ClusterName = config.Global.OrchName
+	s.lbSGVariables.Region = config.AWS.Region
+	s.lbSGVariables.CustomerTag = config.AWS.CustomerTag
+	s.lbSGVariables.EKSNodeSGID = runtimeState.AWS.EKSNodeSecurityGroupID
+	s.lbSGBackendConfig = TerraformAWSBucketBackendConfig{
+		Region: config.AWS.Region,
+		Bucket: bucket,
+		Key:    LBSGBackendBucketKey,
+	}
+	return runtimeState, nil
+}
+
+func (s *LoadBalancerStep) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if config.AWS.PreviousS3StateBucket == "" {
+		// No need to migrate state, since there is no previous state bucket
+		return runtimeState, nil
+	}
+
+	// All load balancer resources were managed by the orch-load-balancer module in the
+	// previous installer. Copy the state for every new module and only keep the related resources.
+	oldLBBucketKey := fmt.Sprintf("%s/orch-load-balancer/%s", config.AWS.Region, config.Global.OrchName)
+	migrations := []struct {
+		modulePath    string
+		backendConfig TerraformAWSBucketBackendConfig
+		states        map[string]string
+	}{
+		{
+			modulePath:    ALBModulePath,
+			backendConfig: s.albBackendConfig,
+			states: map[string]string{
+				"module.traefik_load_balancer.aws_security_group.common":                      "aws_security_group.traefik",
+				"module.traefik_load_balancer.aws_lb.main":                                    "aws_lb.traefik",
+				"module.traefik_load_balancer.aws_lb_target_group.main[\"default\"]":          "aws_lb_target_group.traefik",
+				"module.traefik_load_balancer.aws_lb_target_group.main[\"grpc\"]":             "aws_lb_target_group.traefik_grpc",
+				"module.traefik_load_balancer.aws_lb_listener.main[\"https\"]":                "aws_lb_listener.traefik",
+				"module.traefik_load_balancer.aws_lb_listener_rule.match_headers[\"grpc\"]":   "aws_lb_listener_rule.traefik_grpc",
+				"module.argocd_load_balancer[0].aws_lb.main":                                  "aws_lb.infra",
+				"module.argocd_load_balancer[0].aws_lb_target_group.main[\"argocd\"]":         "aws_lb_target_group.infra_argocd",
+				"module.argocd_load_balancer[0].aws_lb_target_group.main[\"gitea\"]":          "aws_lb_target_group.infra_gitea",
+				"module.argocd_load_balancer[0].aws_lb_listener.main[\"https\"]":              "aws_lb_listener.infra",
+				"module.argocd_load_balancer[0].aws_lb_listener_rule.match_hosts[\"argocd\"]": "aws_lb_listener_rule.infra_argocd",
+				"module.argocd_load_balancer[0].aws_lb_listener_rule.match_hosts[\"gitea\"]":  "aws_lb_listener_rule.infra_gitea",
+			},
+		},
+		{
+			modulePath:    NLBModulePath,
+			backendConfig: s.nlbBackendConfig,
+			states: map[string]string{
+				"module.traefik2_load_balancer[0].aws_security_group.common":           "aws_security_group.common",
+				"module.traefik2_load_balancer[0].aws_eip.main":                        "aws_eip.main",
+				"module.traefik2_load_balancer[0].aws_lb.main":                         "aws_lb.main",
+				"module.traefik2_load_balancer[0].aws_lb_target_group.main[\"https\"]": "aws_lb_target_group.main",
+				"module.traefik2_load_balancer[0].aws_lb_listener.main[\"https\"]":     "aws_lb_listener.main",
+			},
+		},
+		{
+			modulePath:    WAFModulePath,
+			backendConfig: s.wafBackendConfig,
+			states: map[string]string{
+				"module.waf_web_acl_traefik.aws_wafv2_web_acl.main":                 "aws_wafv2_web_acl.traefik",
+				"module.waf_web_acl_traefik.aws_wafv2_web_acl_association.webacl":   "aws_wafv2_web_acl_association.traefik",
+				"module.waf_web_acl_argocd[0].aws_wafv2_web_acl.main":               "aws_wafv2_web_acl.argocd",
+				"module.waf_web_acl_argocd[0].aws_wafv2_web_acl_association.webacl": "aws_wafv2_web_acl_association.argocd",
+			},
+		},
+		{
+			modulePath:    LBSGModulePath,
+			backendConfig: s.lbSGBackendConfig,
+			states: map[string]string{
+				"module.aws_lb_security_group_roles.aws_security_group_rule.node_sg_rule": "aws_security_group_rule.node_sg_rule",
+			},
+		},
+	}
+	for _, m := range migrations {
+		err := s.AWSUtility.S3CopyToS3(config.AWS.Region,
+			config.AWS.PreviousS3StateBucket,
+			oldLBBucketKey,
+			config.AWS.Region,
+			m.backendConfig.Bucket,
+			m.backendConfig.Key)
+		if err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to move Terraform state from old bucket to new bucket: %v", err),
+			}
+		}
+
+		modulePath := filepath.Join(s.RootPath, m.modulePath)
+		mvErr := s.TerraformUtility.MoveStates(ctx, steps.TerraformUtilityMoveStatesInput{
+			ModulePath: modulePath,
+			States:     m.states,
+		})
+		if mvErr != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to move Terraform states: %v", mvErr),
+			}
+		}
+
+		rmErr := s.TerraformUtility.RemoveStates(ctx, steps.TerraformUtilityRemoveStatesInput{
+			ModulePath: modulePath,
+			States:     legacyLoadBalancerModules,
+		})
+		if rmErr != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to remove Terraform states: %v", rmErr),
+			}
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *LoadBalancerStep) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action == "uninstall" {
+		return s.uninstall(ctx, config, runtimeState)
+	}
+
+	albOutput, err := s.runModule(ctx, runtimeState, ALBModulePath, "aws_alb.log", s.albVariables, s.albBackendConfig)
+	if err != nil {
+		return runtimeState, err
+	}
+	albOutputs := map[string]*string{
+		"traefik_lb_arn":                &s.wafVariables.TraefikLoadBalancerARN,
+		"infra_lb_arn":                  &s.wafVariables.ArgoCDLoadBalancerARN,
+		"traefik_sg_id":                 &s.lbSGVariables.TraefikSGID,
+		"infra_sg_id":                   &s.lbSGVariables.ArgoCDSGID,
+		"traefik_target_group_arn":      &runtimeState.AWS.TraefikTargetGroupARN,
+		"traefik_grpc_target_group_arn": &runtimeState.AWS.TraefikGRPCTargetGroupARN,
+		"infra_argocd_target_group_arn": &runtimeState.AWS.ArgoCDTargetGroupARN,
+		"infra_gitea_target_group_arn":  &runtimeState.AWS.GiteaTargetGroupARN,
+	}
+	if err := s.readOutputs(albOutput, ALBModulePath, albOutputs); err != nil {
+		return runtimeState, err
+	}
+
+	nlbOutput, err := s.runModule(ctx, runtimeState, NLBModulePath, "aws_nlb.log", s.nlbVariables, s.nlbBackendConfig)
+	if err != nil {
+		return runtimeState, err
+	}
+	nlbOutputs := map[string]*string{
+		"lb_sg_id":         &s.lbSGVariables.Traefik2SGID,
+		"target_group_arn": &runtimeState.AWS.Traefik2TargetGroupARN,
+	}
+	if err := s.readOutputs(nlbOutput, NLBModulePath, nlbOutputs); err != nil {
+		return runtimeState, err
+	}
+
+	if _, err := s.runModule(ctx, runtimeState, WAFModulePath, "aws_waf.log", s.wafVariables, s.wafBackendConfig); err != nil {
+		return runtimeState, err
+	}
+	if _, err := s.runModule(ctx, runtimeState, LBSGModulePath, "aws_lb_sg.log", s.lbSGVariables, s.lbSGBackendConfig); err != nil {
+		return runtimeState, err
+	}
+	return runtimeState, nil
+}
+
+func (s *LoadBalancerStep) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+func (s *LoadBalancerStep) uninstall(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	for _, lb := range []string{"traefik", "argocd", "traefik2"} {
+		lbName := LoadBalancerName(config.Global.OrchName, lb)
+		if err := s.AWSUtility.DisableLBDeletionProtection(config.AWS.Region, lbName); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to disable load balancer deletion protection: %v", err),
+			}
+		}
+	}
+
+	// Destroy in the reverse order of creation.
+	if _, err := s.runModule(ctx, runtimeState, LBSGModulePath, "aws_lb_sg.log", s.lbSGVariables, s.lbSGBackendConfig); err != nil {
+		return runtimeState, err
+	}
+	if _, err := s.runModule(ctx, runtimeState, WAFModulePath, "aws_waf.log", s.wafVariables, s.wafBackendConfig); err != nil {
+		return runtimeState, err
+	}
+	if _, err := s.runModule(ctx, runtimeState, NLBModulePath, "aws_nlb.log", s.nlbVariables, s.nlbBackendConfig); err != nil {
+		return runtimeState, err
+	}
+	if _, err := s.runModule(ctx, runtimeState, ALBModulePath, "aws_alb.log", s.albVariables, s.albBackendConfig); err != nil {
+		return runtimeState, err
+	}
+
+	runtimeState.AWS.TraefikTargetGroupARN = ""
+	runtimeState.AWS.TraefikGRPCTargetGroupARN = ""
+	runtimeState.AWS.Traefik2TargetGroupARN = ""
+	runtimeState.AWS.ArgoCDTargetGroupARN = ""
+	runtimeState.AWS.GiteaTargetGroupARN = ""
+	return runtimeState, nil
+}
+
+func (s *LoadBalancerStep) runModule(ctx context.Context, runtimeState config.OrchInstallerRuntimeState, modulePath string, logFile string, variables any, backendConfig TerraformAWSBucketBackendConfig) (steps.TerraformUtilityOutput, *internal.OrchInstallerError) {
+	output, err := s.TerraformUtility.Run(ctx, steps.TerraformUtilityInput{
+		Action:             runtimeState.Action,
+		ModulePath:         filepath.Join(s.RootPath, modulePath),
+		Variables:          variables,
+		BackendConfig:      backendConfig,
+		LogFile:            filepath.Join(runtimeState.LogDir, logFile),
+		KeepGeneratedFiles: s.KeepGeneratedFiles,
+	})
+	if err != nil {
+		return output, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("failed to run terraform for %s: %v", filepath.Base(modulePath), err),
+		}
+	}
+	return output, nil
+}
+
+func (s *LoadBalancerStep) readOutputs(output steps.TerraformUtilityOutput, modulePath string, outputs map[string]*string) *internal.OrchInstallerError {
+	if output.Output == nil {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find any output from %s module", filepath.Base(modulePath)),
+		}
+	}
+	for name, value := range outputs {
+		v, ok := output.Output[name]
+		if !ok {
+			return &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+				ErrorMsg:  fmt.Sprintf("cannot find %s in %s module output", name, filepath.Base(modulePath)),
+			}
+		}
+		*value = strings.Trim(string(v.Value), "\"")
+	}
+	return nil
+}
+
+// LoadBalancerName returns the AWS load balancer name used by the Terraform modules,
+// which is the first 32 characters of the SHA256 of "<orchName>-<lb>".
+func LoadBalancerName(orchName string, lb string) string {
+	return fmt.Sprintf("%x", sha256.Sum256([]byte(orchName+"-"+lb)))[:32]
+}
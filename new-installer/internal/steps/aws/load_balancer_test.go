@@ -0,0 +1,242 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws/iac/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type LoadBalancerStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *steps_aws.LoadBalancerStep
+	logDir       string
+	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
+}
+
+func TestLoadBalancerStep(t *testing.T) {
+	suite.Run(t, new(LoadBalancerStepTest))
+}
+
+func (s *LoadBalancerStepTest) SetupTest() {
+	rootPath, err := filepath.Abs("../../../../")
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.logDir = filepath.Join(rootPath, ".logs")
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
+		s.NoError(err)
+		return
+	}
+
+	s.config.AWS.Region = "us-west-2"
+	s.config.Global.OrchName = "test"
+	s.config.AWS.CustomerTag = utils.DefaultTestCustomerTag
+	s.config.AWS.LBWhitelist = []string{"10.0.0.0/8"}
+	s.runtimeState.AWS.VPCID = "vpc-12345678"
+	s.runtimeState.AWS.PublicSubnetIDs = []string{"subnet-11111111", "subnet-22222222"}
+	s.runtimeState.AWS.ACMCertArn = "arn:aws:acm:us-west-2:123456789012:certificate/test"
+	s.runtimeState.AWS.EKSNodeSecurityGroupID = "sg-eks"
+	s.runtimeState.LogDir = s.logDir
+
+	if _, err := os.Stat(s.logDir); os.IsNotExist(err) {
+		err := os.MkdirAll(s.logDir, os.ModePerm)
+		if err != nil {
+			s.NoError(err)
+			return
+		}
+	}
+	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
+	s.step = &steps_aws.LoadBalancerStep{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: true,
+		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
+	}
+}
+
+func (s *LoadBalancerStepTest) TestInstallAndUninstallLoadBalancer() {
+	s.runtimeState.Action = "install"
+	s.expectTFUtiliyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("arn:traefik-tg", rs.AWS.TraefikTargetGroupARN)
+	s.Equal("arn:traefik-grpc-tg", rs.AWS.TraefikGRPCTargetGroupARN)
+	s.Equal("arn:traefik2-tg", rs.AWS.Traefik2TargetGroupARN)
+	s.Equal("arn:argocd-tg", rs.AWS.ArgoCDTargetGroupARN)
+	s.Equal("arn:gitea-tg", rs.AWS.GiteaTargetGroupARN)
+	s.tfUtility.AssertExpectations(s.T())
+
+	s.runtimeState = rs
+	s.runtimeState.Action = "uninstall"
+	s.expectTFUtiliyCall("uninstall")
+	for _, lb := range []string{"traefik", "argocd", "traefik2"} {
+		s.awsUtility.On("DisableLBDeletionProtection", s.config.AWS.Region, steps_aws.LoadBalancerName(s.config.Global.OrchName, lb)).Return(nil).Once()
+	}
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.TraefikTargetGroupARN)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *LoadBalancerStepTest) TestUpgradeLoadBalancer() {
+	s.runtimeState.Action = "upgrade"
+	s.config.AWS.PreviousS3StateBucket = "old-bucket-name"
+	s.expectTFUtiliyCall("upgrade")
+	for _, key := range []string{"alb.tfstate", "nlb.tfstate", "waf.tfstate", "lb_sg.tfstate"} {
+		s.awsUtility.On("S3CopyToS3",
+			s.config.AWS.Region,
+			s.config.AWS.PreviousS3StateBucket,
+			fmt.Sprintf("%s/orch-load-balancer/%s", s.config.AWS.Region, s.config.Global.OrchName),
+			s.config.AWS.Region,
+			s.config.Global.OrchName+"-"+s.runtimeState.DeploymentID,
+			key,
+		).Return(nil).Once()
+	}
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.ALBModulePath) &&
+			input.States["module.traefik_load_balancer.aws_lb.main"] == "aws_lb.traefik"
+	})).Return(nil).Once()
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.NLBModulePath) &&
+			input.States["module.traefik2_load_balancer[0].aws_lb.main"] == "aws_lb.main"
+	})).Return(nil).Once()
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.WAFModulePath)
+	})).Return(nil).Once()
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.LBSGModulePath)
+	})).Return(nil).Once()
+	s.tfUtility.On("RemoveStates", mock.Anything, mock.Anything).Return(nil).Times(4)
+
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.NotEmpty(rs.AWS.TraefikTargetGroupARN)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *LoadBalancerStepTest) TestMissingACMCert() {
+	s.runtimeState.Action = "install"
+	s.runtimeState.AWS.ACMCertArn = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *LoadBalancerStepTest) expectTFUtiliyCall(action string) {
+	bucket := fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID)
+	albVariables := steps_aws.NewDefaultALBVariables()
+	albVariables.ClusterName = s.config.Global.OrchName
+	albVariables.Region = s.config.AWS.Region
+	albVariables.CustomerTag = s.config.AWS.CustomerTag
+	albVariables.VPCID = s.runtimeState.AWS.VPCID
+	albVariables.PublicSubnetIDs = s.runtimeState.AWS.PublicSubnetIDs
+	albVariables.IPAllowList = s.config.AWS.LBWhitelist
+	albVariables.TLSCertARN = s.runtimeState.AWS.ACMCertArn
+
+	nlbVariables := steps_aws.NewDefaultNLBVariables()
+	nlbVariables.ClusterName = s.config.Global.OrchName
+	nlbVariables.Region = s.config.AWS.Region
+	nlbVariables.CustomerTag = s.config.AWS.CustomerTag
+	nlbVariables.VPCID = s.runtimeState.AWS.VPCID
+	nlbVariables.PublicSubnetIDs = s.runtimeState.AWS.PublicSubnetIDs
+	nlbVariables.Subnets = s.runtimeState.AWS.PublicSubnetIDs
+	nlbVariables.IPAllowList = s.config.AWS.LBWhitelist
+
+	wafVariables := steps_aws.NewDefaultWAFVariables()
+	wafVariables.ClusterName = s.config.Global.OrchName
+	wafVariables.Region = s.config.AWS.Region
+	wafVariables.CustomerTag = s.config.AWS.CustomerTag
+
+	lbSGVariables := steps_aws.NewDefaultLBSGVariables()
+	lbSGVariables.ClusterName = s.config.Global.OrchName
+	lbSGVariables.Region = s.config.AWS.Region
+	lbSGVariables.CustomerTag = s.config.AWS.CustomerTag
+	lbSGVariables.EKSNodeSGID = s.runtimeState.AWS.EKSNodeSecurityGroupID
+
+	if action != "uninstall" {
+		// Filled from the ALB and NLB outputs
+		wafVariables.TraefikLoadBalancerARN = "arn:traefik-lb"
+		wafVariables.ArgoCDLoadBalancerARN = "arn:infra-lb"
+		lbSGVariables.TraefikSGID = "sg-traefik"
+		lbSGVariables.ArgoCDSGID = "sg-infra"
+		lbSGVariables.Traefik2SGID = "sg-traefik2"
+	}
+
+	input := func(modulePath, logFile, key string, variables any) steps.TerraformUtilityInput {
+		return steps.TerraformUtilityInput{
+			Action:             action,
+			ModulePath:         filepath.Join(s.step.RootPath, modulePath),
+			LogFile:            filepath.Join(s.logDir, logFile),
+			KeepGeneratedFiles: s.step.KeepGeneratedFiles,
+			Variables:          variables,
+			BackendConfig: steps_aws.TerraformAWSBucketBackendConfig{
+				Region: s.config.AWS.Region,
+				Bucket: bucket,
+				Key:    key,
+			},
+			TerraformState: "",
+		}
+	}
+	output := func(values map[string]string) steps.TerraformUtilityOutput {
+		out := steps.TerraformUtilityOutput{
+			Output: map[string]tfexec.OutputMeta{},
+		}
+		if action == "uninstall" {
+			return out
+		}
+		for k, v := range values {
+			out.Output[k] = tfexec.OutputMeta{
+				Type:  json.RawMessage(`"string"`),
+				Value: json.RawMessage(fmt.Sprintf("%q", v)),
+			}
+		}
+		return out
+	}
+
+	s.tfUtility.On("Run", mock.Anything, input(steps_aws.ALBModulePath, "aws_alb.log", "alb.tfstate", albVariables)).Return(output(map[string]string{
+		"traefik_lb_arn":                "arn:traefik-lb",
+		"infra_lb_arn":                  "arn:infra-lb",
+		"traefik_sg_id":                 "sg-traefik",
+		"infra_sg_id":                   "sg-infra",
+		"traefik_target_group_arn":      "arn:traefik-tg",
+		"traefik_grpc_target_group_arn": "arn:traefik-grpc-tg",
+		"infra_argocd_target_group_arn": "arn:argocd-tg",
+		"infra_gitea_target_group_arn":  "arn:gitea-tg",
+	}), nil).Once()
+	s.tfUtility.On("Run", mock.Anything, input(steps_aws.NLBModulePath, "aws_nlb.log", "nlb.tfstate", nlbVariables)).Return(output(map[string]string{
+		"lb_sg_id":         "sg-traefik2",
+		"target_group_arn": "arn:traefik2-tg",
+	}), nil).Once()
+	s.tfUtility.On("Run", mock.Anything, input(steps_aws.WAFModulePath, "aws_waf.log", "waf.tfstate", wafVariables)).Return(output(nil), nil).Once()
+	s.tfUtility.On("Run", mock.Anything, input(steps_aws.LBSGModulePath, "aws_lb_sg.log", "lb_sg.tfstate", lbSGVariables)).Return(output(nil), nil).Once()
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"slices"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
@@ -25,12 +26,23 @@ var observabilityBucketsStepLabels = []string{
 	"s3",
 }
 
+type ObservabilityBucketLifecycle struct {
+	ExpirationDays       int    `json:"expiration_days" yaml:"expiration_days"`
+	InfrequentAccessDays int    `json:"infrequent_access_days" yaml:"infrequent_access_days"`
+	GlacierDays          int    `json:"glacier_days" yaml:"glacier_days"`
+	ObjectLockMode       string `json:"object_lock_mode" yaml:"object_lock_mode"`
+	ObjectLockDays       int    `json:"object_lock_days" yaml:"object_lock_days"`
+}
+
 type ObservabilityBucketsVariables struct {
-	Region      string `json:"region" yaml:"region"`
-	CustomerTag string `json:"customer_tag" yaml:"customer_tag"`
-	S3Prefix    string `json:"s3_prefix" yaml:"s3_prefix"`
-	OIDCIssuer  string `json:"oidc_issuer" yaml:"oidc_issuer"`
-	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Region            string                                  `json:"region" yaml:"region"`
+	CustomerTag       string                                  `json:"customer_tag" yaml:"customer_tag"`
+	S3Prefix          string                                  `json:"s3_prefix" yaml:"s3_prefix"`
+	OIDCIssuer        string                                  `json:"oidc_issuer" yaml:"oidc_issuer"`
+	ClusterName       string                                  `json:"cluster_name" yaml:"cluster_name"`
+	KMSKeyARN         string                                  `json:"kms_key_arn,omitempty" yaml:"kms_key_arn,omitempty"`
+	BucketLifecycles  map[string]ObservabilityBucketLifecycle `json:"bucket_lifecycles,omitempty" yaml:"bucket_lifecycles,omitempty"`
+	ReplicationRegion string                                  `json:"replication_region,omitempty" yaml:"replication_region,omitempty"`
 }
 
 func NewObservabilityBucketsVariables() ObservabilityBucketsVariables {
@@ -77,6 +89,19 @@ func (s *ObservabilityBucketsStep) ConfigStep(ctx context.Context, config config
 	s.variables.S3Prefix = runtimeState.DeploymentID
 	s.variables.OIDCIssuer = runtimeState.AWS.EKSOIDCIssuer
 	s.variables.ClusterName = config.Global.OrchName
+	s.variables.KMSKeyARN = runtimeState.AWS.KMSKeyARN
+	s.variables.ReplicationRegion = config.AWS.O11yReplicationRegion
+	if s.variables.ReplicationRegion == "" {
+		s.variables.ReplicationRegion = config.AWS.DRRegion
+	}
+	lifecycles, err := bucketLifecycles(config.AWS.O11yBuckets)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	s.variables.BucketLifecycles = lifecycles
 	s.backendConfig = steps.TerraformAWSBucketBackendConfig{
 		Bucket: config.Global.OrchName + "-" + runtimeState.DeploymentID,
 		Region: config.AWS.Region,
@@ -85,6 +110,32 @@ func (s *ObservabilityBucketsStep) ConfigStep(ctx context.Context, config config
 	return runtimeState, nil
 }
 
+// bucketLifecycles returns the Terraform variables of the configured bucket lifecycles. A bucket
+// is either expired or locked, the noncurrent versions left by an expiration are deleted after a
+// day, which a lock forbids.
+func bucketLifecycles(buckets map[string]config.ObservabilityBucketConfig) (map[string]ObservabilityBucketLifecycle, error) {
+	var lifecycles map[string]ObservabilityBucketLifecycle
+	for name, bucket := range buckets {
+		if !slices.Contains(config.ObservabilityBucketNames, name) {
+			return nil, fmt.Errorf("unknown observability bucket %s", name)
+		}
+		if bucket.ObjectLockMode != "" && bucket.ExpirationDays > 0 {
+			return nil, fmt.Errorf("observability bucket %s sets both an expiration and an object lock", name)
+		}
+		if lifecycles == nil {
+			lifecycles = map[string]ObservabilityBucketLifecycle{}
+		}
+		lifecycles[name] = ObservabilityBucketLifecycle{
+			ExpirationDays:       bucket.ExpirationDays,
+			InfrequentAccessDays: bucket.InfrequentAccessDays,
+			GlacierDays:          bucket.GlacierDays,
+			ObjectLockMode:       bucket.ObjectLockMode,
+			ObjectLockDays:       bucket.ObjectLockDays,
+		}
+	}
+	return lifecycles, nil
+}
+
 func (s *ObservabilityBucketsStep) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	if config.AWS.PreviousS3StateBucket == "" {
 		// No need to migrate state, since there is no previous state bucket
@@ -44,8 +44,9 @@ func (s *ObservabilityBucketsStepTest) SetupTest() {
 	s.Require().NoError(err, "Failed to get absolute path")
 	s.randomText = strings.ToLower(rand.Text()[0:8])
 	s.logDir = filepath.Join(rootPath, ".logs")
-	err = internal.InitLogger("debug", s.logDir)
+	err = internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir})
 	s.Require().NoError(err, "Failed to initialize logger")
+	s.config = config.OrchInstallerConfig{}
 	s.config.AWS.Region = "us-west-2"
 	s.config.Global.OrchName = "observability-buckets-test"
 	s.config.AWS.CustomerTag = "test"
@@ -90,18 +91,71 @@ func (s *ObservabilityBucketsStepTest) TestUpgradeObservabilityBucket() {
 	}
 }
 
+func (s *ObservabilityBucketsStepTest) TestBucketLifecyclesAndReplication() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.O11yReplicationRegion = "us-east-1"
+	s.config.AWS.O11yBuckets = map[string]config.ObservabilityBucketConfig{
+		"orch-loki-chunks": {InfrequentAccessDays: 30, GlacierDays: 90, ExpirationDays: 365},
+		"orch-loki-admin":  {ObjectLockMode: "COMPLIANCE", ObjectLockDays: 365},
+	}
+	s.expectTFUtiliyyCall("install")
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *ObservabilityBucketsStepTest) TestUnknownBucket() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.O11yBuckets = map[string]config.ObservabilityBucketConfig{
+		"orch-unknown": {ExpirationDays: 30},
+	}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *ObservabilityBucketsStepTest) TestExpirationWithObjectLock() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.O11yBuckets = map[string]config.ObservabilityBucketConfig{
+		"orch-loki-admin": {ObjectLockMode: "GOVERNANCE", ObjectLockDays: 30, ExpirationDays: 60},
+	}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
 func (s *ObservabilityBucketsStepTest) expectTFUtiliyyCall(action string) {
+	var lifecycles map[string]steps_aws.ObservabilityBucketLifecycle
+	for name, bucket := range s.config.AWS.O11yBuckets {
+		if lifecycles == nil {
+			lifecycles = map[string]steps_aws.ObservabilityBucketLifecycle{}
+		}
+		lifecycles[name] = steps_aws.ObservabilityBucketLifecycle{
+			ExpirationDays:       bucket.ExpirationDays,
+			InfrequentAccessDays: bucket.InfrequentAccessDays,
+			GlacierDays:          bucket.GlacierDays,
+			ObjectLockMode:       bucket.ObjectLockMode,
+			ObjectLockDays:       bucket.ObjectLockDays,
+		}
+	}
 	input := steps.TerraformUtilityInput{
 		Action:             action,
 		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.ObservabilityBucketsModulePath),
 		LogFile:            filepath.Join(s.logDir, "aws_observability_bucket.log"),
 		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
 		Variables: steps_aws.ObservabilityBucketsVariables{
-			Region:      s.config.AWS.Region,
-			CustomerTag: s.config.AWS.CustomerTag,
-			S3Prefix:    s.runtimeState.DeploymentID,
-			OIDCIssuer:  s.runtimeState.AWS.EKSOIDCIssuer,
-			ClusterName: s.config.Global.OrchName,
+			Region:            s.config.AWS.Region,
+			CustomerTag:       s.config.AWS.CustomerTag,
+			S3Prefix:          s.runtimeState.DeploymentID,
+			OIDCIssuer:        s.runtimeState.AWS.EKSOIDCIssuer,
+			ClusterName:       s.config.Global.OrchName,
+			BucketLifecycles:  lifecycles,
+			ReplicationRegion: s.config.AWS.O11yReplicationRegion,
 		},
 		BackendConfig: steps.TerraformAWSBucketBackendConfig{
 			Region: s.config.AWS.Region,
@@ -10,6 +10,7 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
@@ -39,6 +40,8 @@ type RDSVariables struct {
 	DevMode                   bool     `json:"dev_mode" yaml:"dev_mode"`
 	Username                  string   `json:"username,omitempty" yaml:"username,omitempty"`
 	CACertIdentifier          string   `json:"ca_cert_identifier,omitempty" yaml:"ca_cert_identifier,omitempty"`
+	SnapshotIdentifier        string   `json:"snapshot_identifier,omitempty" yaml:"snapshot_identifier,omitempty"`
+	KMSKeyARN                 string   `json:"kms_key_arn,omitempty" yaml:"kms_key_arn,omitempty"`
 }
 
 // NewDefaultRDSVariables creates a new RDSVariables with default values
@@ -60,6 +63,7 @@ func NewDefaultRDSVariables() RDSVariables {
 		DevMode:                   false,
 		Username:                  "",
 		CACertIdentifier:          "",
+		SnapshotIdentifier:        "",
 	}
 }
 
@@ -94,6 +98,7 @@ func (s *RDSStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig
 	s.variables.ClusterName = cfg.Global.OrchName
 	s.variables.Region = cfg.AWS.Region
 	s.variables.CustomerTag = cfg.AWS.CustomerTag
+	s.variables.KMSKeyARN = runtimeState.AWS.KMSKeyARN
 
 	if len(runtimeState.AWS.PrivateSubnetIDs) == 0 {
 		return runtimeState, &internal.OrchInstallerError{
@@ -137,6 +142,7 @@ func (s *RDSStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig
 	s.variables.AvailabilityZones = zones
 	s.variables.InstanceAvailabilityZones = zones
 	s.variables.DevMode = cfg.Advanced.DevMode
+	s.variables.SnapshotIdentifier = runtimeState.Database.RestoreSnapshotIdentifier
 
 	s.backendConfig = TerraformAWSBucketBackendConfig{
 		Region: cfg.AWS.Region,
@@ -148,6 +154,20 @@ func (s *RDSStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig
 }
 
 func (s *RDSStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action == "upgrade" {
+		// Upgrades may change the Postgres version or the ACU limits, take a snapshot so that
+		// the database can be restored with `db restore` if anything goes wrong.
+		snapshotIdentifier := RDSSnapshotIdentifier(cfg.Global.OrchName, "pre-upgrade", time.Now())
+		snapshotArn, err := s.AWSUtility.CreateRDSClusterSnapshot(cfg.AWS.Region, cfg.Global.OrchName, snapshotIdentifier)
+		if err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to create RDS snapshot before upgrade: %v", err),
+			}
+		}
+		runtimeState.Database.PreUpgradeSnapshotARN = snapshotArn
+	}
+
 	if cfg.AWS.PreviousS3StateBucket == "" {
 		// No need to migrate state, since there is no previous state bucket
 		return runtimeState, nil
@@ -304,6 +324,53 @@ func (s *RDSStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, r
 	return runtimeState, nil
 }
 
+// RDSSnapshotIdentifier returns a snapshot identifier of the cluster, unique per second.
+func RDSSnapshotIdentifier(clusterName, purpose string, t time.Time) string {
+	return fmt.Sprintf("%s-%s-%s", clusterName, purpose, t.UTC().Format("20060102-150405"))
+}
+
+// RestoreRDS restores the cluster managed by RDSStep from a snapshot, or from a point in time if
+// snapshotIdentifier is empty. The cluster is replaced by Terraform and keeps its identifier.
+func (s *RDSStep) RestoreRDS(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, snapshotIdentifier string, restoreTime time.Time) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if snapshotIdentifier == "" {
+		if restoreTime.IsZero() {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+				ErrorMsg:  "either a snapshot or a restore time must be provided",
+			}
+		}
+		snapshotIdentifier = RDSSnapshotIdentifier(cfg.Global.OrchName, "pitr", restoreTime)
+		if _, err := s.AWSUtility.CreateRDSClusterSnapshotFromPointInTime(cfg.AWS.Region, cfg.Global.OrchName, restoreTime, snapshotIdentifier); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				ErrorMsg:  fmt.Sprintf("failed to create RDS snapshot from point in time: %v", err),
+			}
+		}
+	}
+	if snapshotIdentifier == runtimeState.Database.RestoreSnapshotIdentifier {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("the RDS cluster is already restored from snapshot %s", snapshotIdentifier),
+		}
+	}
+
+	runtimeState.Action = "install"
+	runtimeState.Database.RestoreSnapshotIdentifier = snapshotIdentifier
+	runtimeState, err := s.ConfigStep(ctx, cfg, runtimeState)
+	if err != nil {
+		return runtimeState, err
+	}
+	// The existing cluster is destroyed before the restored one is created.
+	if err := s.AWSUtility.DisableRDSDeletionProtection(cfg.AWS.Region, s.variables.ClusterName); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to disable RDS deletion protection: %v", err),
+		}
+	}
+	runtimeState, err = s.RunStep(ctx, cfg, runtimeState)
+	return s.PostStep(ctx, cfg, runtimeState, err)
+}
+
 func (s *RDSStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	return runtimeState, prevStepError
 }
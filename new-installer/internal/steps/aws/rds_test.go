@@ -4,11 +4,14 @@
 package steps_aws_test
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
@@ -43,7 +46,7 @@ func (s *RDSStepTest) SetupTest() {
 		return
 	}
 	s.logDir = filepath.Join(rootPath, ".logs")
-	if err := internal.InitLogger("debug", s.logDir); err != nil {
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
 		s.NoError(err)
 		return
 	}
@@ -115,6 +118,52 @@ func (s *RDSStepTest) TestUpgradeRDS() {
 	s.NotEmptyf(rs.Database.Port, "database port should not be empty after installation")
 	s.NotEmptyf(rs.Database.Username, "database username should not be empty after installation")
 	s.NotEmptyf(rs.Database.Password, "database password should not be empty after installation")
+	s.Equal("arn:aws:rds:us-west-2:123456789012:cluster-snapshot:test-pre-upgrade", rs.Database.PreUpgradeSnapshotARN)
+	s.awsUtility.AssertExpectations(s.T())
+}
+
+func (s *RDSStepTest) TestRestoreRDSFromSnapshot() {
+	s.runtimeState.Database.RestoreSnapshotIdentifier = "test-pre-upgrade-20250102-150405"
+	s.expectTFUtiliyCall("install")
+	s.expectAWSUtiliyCall("install")
+	s.awsUtility.On("DisableRDSDeletionProtection", s.config.AWS.Region, s.config.Global.OrchName).Return(nil).Once()
+
+	s.runtimeState.Database.RestoreSnapshotIdentifier = ""
+	rs, err := s.step.RestoreRDS(context.Background(), s.config, s.runtimeState, "test-pre-upgrade-20250102-150405", time.Time{})
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("test-pre-upgrade-20250102-150405", rs.Database.RestoreSnapshotIdentifier)
+	s.NotEmpty(rs.Database.Host)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *RDSStepTest) TestRestoreRDSFromPointInTime() {
+	restoreTime := time.Date(2025, 1, 2, 15, 4, 5, 0, time.UTC)
+	s.runtimeState.Database.RestoreSnapshotIdentifier = "test-pitr-20250102-150405"
+	s.expectTFUtiliyCall("install")
+	s.expectAWSUtiliyCall("install")
+	s.awsUtility.On("CreateRDSClusterSnapshotFromPointInTime", s.config.AWS.Region, s.config.Global.OrchName, restoreTime, "test-pitr-20250102-150405").
+		Return("arn:aws:rds:us-west-2:123456789012:cluster-snapshot:test-pitr-20250102-150405", nil).Once()
+	s.awsUtility.On("DisableRDSDeletionProtection", s.config.AWS.Region, s.config.Global.OrchName).Return(nil).Once()
+
+	s.runtimeState.Database.RestoreSnapshotIdentifier = ""
+	rs, err := s.step.RestoreRDS(context.Background(), s.config, s.runtimeState, "", restoreTime)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("test-pitr-20250102-150405", rs.Database.RestoreSnapshotIdentifier)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *RDSStepTest) TestRestoreRDSWithoutSource() {
+	_, err := s.step.RestoreRDS(context.Background(), s.config, s.runtimeState, "", time.Time{})
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
 }
 
 func (s *RDSStepTest) expectTFUtiliyCall(action string) {
@@ -139,6 +188,7 @@ func (s *RDSStepTest) expectTFUtiliyCall(action string) {
 			DevMode:                   true,
 			Username:                  "",
 			CACertIdentifier:          "",
+			SnapshotIdentifier:        s.runtimeState.Database.RestoreSnapshotIdentifier,
 		},
 		BackendConfig: steps_aws.TerraformAWSBucketBackendConfig{
 			Region: s.config.AWS.Region,
@@ -255,6 +305,10 @@ func (s *RDSStepTest) expectAWSUtiliyCall(action string) {
 		s.awsUtility.On("GetAvailableZones", s.config.AWS.Region).
 			Return(availabilityZones, nil).
 			Once()
+		s.awsUtility.On("CreateRDSClusterSnapshot",
+			s.config.AWS.Region, s.config.Global.OrchName,
+			mock.MatchedBy(func(id string) bool { return strings.HasPrefix(id, "test-pre-upgrade-") }),
+		).Return("arn:aws:rds:us-west-2:123456789012:cluster-snapshot:test-pre-upgrade", nil).Once()
 	} else if action == "uninstall" {
 		s.awsUtility.On("GetAvailableZones", s.config.AWS.Region).
 			Return(availabilityZones, nil).
@@ -0,0 +1,225 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package steps_aws
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	Route53ModulePath       = "new-installer/targets/aws/iac/route53"
+	Route53BackendBucketKey = "route53.tfstate"
+)
+
+var route53StepLabels = []string{
+	"aws",
+	"route53",
+}
+
+type Route53Variables struct {
+	OrchName    string `json:"orch_name" yaml:"orch_name"`
+	ParentZone  string `json:"parent_zone" yaml:"parent_zone"`
+	CustomerTag string `json:"customer_tag" yaml:"customer_tag"`
+	VPCID       string `json:"vpc_id" yaml:"vpc_id"`
+	VPCRegion   string `json:"vpc_region" yaml:"vpc_region"`
+	ReduceNSTTL bool   `json:"reduce_ns_ttl" yaml:"reduce_ns_ttl"`
+}
+
+// NewDefaultRoute53Variables creates a new Route53Variables with default values
+// based on variable.tf default definitions.
+func NewDefaultRoute53Variables() Route53Variables {
+	return Route53Variables{
+		OrchName:    "",
+		ParentZone:  "",
+		CustomerTag: "",
+		VPCID:       "",
+		VPCRegion:   "",
+		ReduceNSTTL: false,
+	}
+}
+
+type Route53Step struct {
+	variables          Route53Variables
+	backendConfig      TerraformAWSBucketBackendConfig
+	RootPath           string
+	KeepGeneratedFiles bool
+	TerraformUtility   steps.TerraformUtility
+	AWSUtility         AWSUtility
+}
+
+func CreateRoute53Step(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility AWSUtility) *Route53Step {
+	return &Route53Step{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: keepGeneratedFiles,
+		TerraformUtility:   terraformUtility,
+		AWSUtility:         awsUtility,
+	}
+}
+
+func (s *Route53Step) Name() string {
+	return "Route53Step"
+}
+
+func (s *Route53Step) Labels() []string {
+	return route53StepLabels
+}
+
+func (s *Route53Step) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if config.Global.ParentDomain == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "ParentDomain is not set",
+		}
+	}
+	if runtimeState.AWS.VPCID == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  fmt.Sprintf("VPCID should not be empty in runtime state for step %s", s.Name()),
+		}
+	}
+
+	s.variables = NewDefaultRoute53Variables()
+	s.variables.OrchName = config.Global.OrchName
+	s.variables.ParentZone = config.Global.ParentDomain
+	s.variables.CustomerTag = config.AWS.CustomerTag
+	s.variables.VPCID = runtimeState.AWS.VPCID
+	s.variables.VPCRegion = config.AWS.Region
+	s.variables.ReduceNSTTL = config.AWS.ReduceNSTTL
+
+	s.backendConfig = TerraformAWSBucketBackendConfig{
+		Region: config.AWS.Region,
+		Bucket: config.Global.OrchName + "-" + runtimeState.DeploymentID,
+		Key:    Route53BackendBucketKey,
+	}
+	return runtimeState, nil
+}
+
+func (s *Route53Step) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if config.AWS.PreviousS3StateBucket == "" {
+		// No need to migrate state, since there is no previous state bucket
+		return runtimeState, nil
+	}
+
+	// Need to move Terraform state from old bucket to new bucket:
+	oldRoute53BucketKey := fmt.Sprintf("%s/orch-route53/%s", config.AWS.Region, config.Global.OrchName)
+	err := s.AWSUtility.S3CopyToS3(config.AWS.Region,
+		config.AWS.PreviousS3StateBucket,
+		oldRoute53BucketKey,
+		config.AWS.Region,
+		s.backendConfig.Bucket,
+		s.backendConfig.Key)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to move Terraform state from old bucket to new bucket: %v", err),
+		}
+	}
+
+	modulePath := filepath.Join(s.RootPath, Route53ModulePath)
+	mvErr := s.TerraformUtility.MoveStates(ctx, steps.TerraformUtilityMoveStatesInput{
+		ModulePath: modulePath,
+		States: map[string]string{
+			"module.route53_orch.aws_route53_zone.orch_public[0]":              "aws_route53_zone.orch_public",
+			"module.route53_orch.aws_route53_zone.orch_private[0]":             "aws_route53_zone.orch_private",
+			"module.route53_orch.aws_route53_record.orch_public[0]":            "aws_route53_record.orch_public",
+			"module.route53_orch.aws_route53_record.orch_private[0]":           "aws_route53_record.orch_private",
+			"module.route53_orch.aws_route53_record.traetik_public[0]":         "aws_route53_record.traetik_public",
+			"module.route53_orch.aws_route53_record.traetik_private[0]":        "aws_route53_record.traetik_private",
+			"module.route53_orch.aws_route53_record.argocd_public[0]":          "aws_route53_record.argocd_public",
+			"module.route53_orch.aws_route53_record.argocd_private[0]":         "aws_route53_record.argocd_private",
+			"module.route53_orch.aws_route53_record.gitea_public[0]":           "aws_route53_record.gitea_public",
+			"module.route53_orch.aws_route53_record.gitea_private[0]":          "aws_route53_record.gitea_private",
+			"module.route53_orch.aws_route53_record.traefik2_public[0]":        "aws_route53_record.traefik2_public",
+			"module.route53_orch.aws_route53_record.traefik2_private[0]":       "aws_route53_record.traefik2_private",
+			"module.route53_orch.aws_route53_record.public_hostname":           "aws_route53_record.public_hostname",
+			"module.route53_orch.aws_route53_record.private_hostname":          "aws_route53_record.private_hostname",
+			"module.route53_orch.aws_route53_record.public_hostname_traefik2":  "aws_route53_record.public_hostname_traefik2",
+			"module.route53_orch.aws_route53_record.private_hostname_traefik2": "aws_route53_record.private_hostname_traefik2",
+		},
+	})
+	if mvErr != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to move Terraform states: %v", mvErr),
+		}
+	}
+
+	rmErr := s.TerraformUtility.RemoveStates(ctx, steps.TerraformUtilityRemoveStatesInput{
+		ModulePath: modulePath,
+		States: []string{
+			"module.route53_orch",
+		},
+	})
+	if rmErr != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to remove Terraform states: %v", rmErr),
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *Route53Step) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	terraformStepInput := steps.TerraformUtilityInput{
+		Action:             runtimeState.Action,
+		ModulePath:         filepath.Join(s.RootPath, Route53ModulePath),
+		Variables:          s.variables,
+		BackendConfig:      s.backendConfig,
+		LogFile:            filepath.Join(runtimeState.LogDir, "aws_route53.log"),
+		KeepGeneratedFiles: s.KeepGeneratedFiles,
+	}
+	terraformStepOutput, err := s.TerraformUtility.Run(ctx, terraformStepInput)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("failed to run terraform: %v", err),
+		}
+	}
+
+	if runtimeState.Action == "uninstall" {
+		runtimeState.AWS.OrchDomain = ""
+		runtimeState.AWS.TraefikHostname = ""
+		runtimeState.AWS.ArgoCDHostname = ""
+		runtimeState.AWS.GiteaHostname = ""
+		runtimeState.AWS.Traefik2Hostname = ""
+		return runtimeState, nil
+	}
+
+	if terraformStepOutput.Output == nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+			ErrorMsg:  fmt.Sprintf("cannot find any output from %s module", s.Name()),
+		}
+	}
+	outputs := map[string]*string{
+		"orch_domain":       &runtimeState.AWS.OrchDomain,
+		"traefik_hostname":  &runtimeState.AWS.TraefikHostname,
+		"argocd_hostname":   &runtimeState.AWS.ArgoCDHostname,
+		"gitea_hostname":    &runtimeState.AWS.GiteaHostname,
+		"traefik2_hostname": &runtimeState.AWS.Traefik2Hostname,
+	}
+	for name, value := range outputs {
+		v, ok := terraformStepOutput.Output[name]
+		if !ok {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+				ErrorMsg:  fmt.Sprintf("cannot find %s in %s module output", name, s.Name()),
+			}
+		}
+		*value = strings.Trim(string(v.Value), "\"")
+	}
+	return runtimeState, nil
+}
+
+func (s *Route53Step) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
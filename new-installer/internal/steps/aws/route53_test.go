@@ -0,0 +1,194 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package steps_aws_test
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/hashicorp/terraform-exec/tfexec"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws/iac/utils"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/suite"
+)
+
+type Route53StepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *steps_aws.Route53Step
+	logDir       string
+	tfUtility    *MockTerraformUtility
+	awsUtility   *MockAWSUtility
+}
+
+func TestRoute53Step(t *testing.T) {
+	suite.Run(t, new(Route53StepTest))
+}
+
+func (s *Route53StepTest) SetupTest() {
+	rootPath, err := filepath.Abs("../../../../")
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.logDir = filepath.Join(rootPath, ".logs")
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
+		s.NoError(err)
+		return
+	}
+
+	s.config.AWS.Region = "us-west-2"
+	s.config.Global.OrchName = "test"
+	s.config.Global.ParentDomain = "example.com"
+	s.config.AWS.CustomerTag = utils.DefaultTestCustomerTag
+	s.config.AWS.ReduceNSTTL = true
+	s.runtimeState.AWS.VPCID = "vpc-12345678"
+	s.runtimeState.LogDir = s.logDir
+
+	if _, err := os.Stat(s.logDir); os.IsNotExist(err) {
+		err := os.MkdirAll(s.logDir, os.ModePerm)
+		if err != nil {
+			s.NoError(err)
+			return
+		}
+	}
+	s.tfUtility = &MockTerraformUtility{}
+	s.awsUtility = &MockAWSUtility{}
+	s.step = &steps_aws.Route53Step{
+		RootPath:           rootPath,
+		KeepGeneratedFiles: true,
+		TerraformUtility:   s.tfUtility,
+		AWSUtility:         s.awsUtility,
+	}
+}
+
+func (s *Route53StepTest) TestInstallAndUninstallRoute53() {
+	s.runtimeState.Action = "install"
+	s.expectTFUtiliyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("test.example.com", rs.AWS.OrchDomain)
+	s.Equal("test.example.com", rs.AWS.TraefikHostname)
+	s.Equal("argocd.test.example.com", rs.AWS.ArgoCDHostname)
+	s.Equal("gitea.test.example.com", rs.AWS.GiteaHostname)
+	s.Equal("traefik2.test.example.com", rs.AWS.Traefik2Hostname)
+
+	s.runtimeState = rs
+	s.runtimeState.Action = "uninstall"
+	s.expectTFUtiliyCall("uninstall")
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Empty(rs.AWS.OrchDomain)
+	s.Empty(rs.AWS.TraefikHostname)
+}
+
+func (s *Route53StepTest) TestUpgradeRoute53() {
+	s.runtimeState.Action = "upgrade"
+	s.config.AWS.PreviousS3StateBucket = "old-bucket-name"
+	s.expectTFUtiliyCall("upgrade")
+	s.awsUtility.On("S3CopyToS3",
+		s.config.AWS.Region,
+		s.config.AWS.PreviousS3StateBucket,
+		fmt.Sprintf("%s/orch-route53/%s", s.config.AWS.Region, s.config.Global.OrchName),
+		s.config.AWS.Region,
+		s.config.Global.OrchName+"-"+s.runtimeState.DeploymentID,
+		"route53.tfstate",
+	).Return(nil).Once()
+	s.tfUtility.On("MoveStates", mock.Anything, mock.MatchedBy(func(input steps.TerraformUtilityMoveStatesInput) bool {
+		return input.ModulePath == filepath.Join(s.step.RootPath, steps_aws.Route53ModulePath) &&
+			input.States["module.route53_orch.aws_route53_zone.orch_public[0]"] == "aws_route53_zone.orch_public"
+	})).Return(nil).Once()
+	s.tfUtility.On("RemoveStates", mock.Anything, steps.TerraformUtilityRemoveStatesInput{
+		ModulePath: filepath.Join(s.step.RootPath, steps_aws.Route53ModulePath),
+		States:     []string{"module.route53_orch"},
+	}).Return(nil).Once()
+
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal("test.example.com", rs.AWS.OrchDomain)
+	s.Equal("test.example.com", rs.AWS.TraefikHostname)
+	s.awsUtility.AssertExpectations(s.T())
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *Route53StepTest) TestMissingParentDomain() {
+	s.runtimeState.Action = "install"
+	s.config.Global.ParentDomain = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+}
+
+func (s *Route53StepTest) expectTFUtiliyCall(action string) {
+	input := steps.TerraformUtilityInput{
+		Action:             action,
+		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.Route53ModulePath),
+		LogFile:            filepath.Join(s.logDir, "aws_route53.log"),
+		KeepGeneratedFiles: s.step.KeepGeneratedFiles,
+		Variables: steps_aws.Route53Variables{
+			OrchName:    s.config.Global.OrchName,
+			ParentZone:  s.config.Global.ParentDomain,
+			CustomerTag: s.config.AWS.CustomerTag,
+			VPCID:       s.runtimeState.AWS.VPCID,
+			VPCRegion:   s.config.AWS.Region,
+			ReduceNSTTL: true,
+		},
+		BackendConfig: steps_aws.TerraformAWSBucketBackendConfig{
+			Region: s.config.AWS.Region,
+			Bucket: fmt.Sprintf("%s-%s", s.config.Global.OrchName, s.runtimeState.DeploymentID),
+			Key:    "route53.tfstate",
+		},
+		TerraformState: "",
+	}
+	if action == "install" || action == "upgrade" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output: map[string]tfexec.OutputMeta{
+				"orch_domain": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"test.example.com"`),
+				},
+				"traefik_hostname": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"test.example.com"`),
+				},
+				"argocd_hostname": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"argocd.test.example.com"`),
+				},
+				"gitea_hostname": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"gitea.test.example.com"`),
+				},
+				"traefik2_hostname": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"traefik2.test.example.com"`),
+				},
+			},
+		}, nil).Once()
+	}
+	if action == "uninstall" {
+		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
+			TerraformState: "",
+			Output:         map[string]tfexec.OutputMeta{},
+		}, nil).Once()
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
@@ -21,9 +22,10 @@ const (
 var StateBucketStepLabels = []string{"aws", "state_bucket"}
 
 type StateBucketVariables struct {
-	Region   string `json:"region"`
-	OrchName string `json:"orch_name"`
-	Bucket   string `json:"bucket"`
+	Region    string `json:"region"`
+	OrchName  string `json:"orch_name"`
+	Bucket    string `json:"bucket"`
+	KMSKeyARN string `json:"kms_key_arn,omitempty"`
 }
 
 type AWSStateBucketStep struct {
@@ -70,10 +72,25 @@ func (s *AWSStateBucketStep) ConfigStep(ctx context.Context, config config.OrchI
 			ErrorMsg:  "DeploymentId is not set",
 		}
 	}
+	// EFS and RDS keep the key they were created with, a new key would only apply to the buckets
+	if runtimeState.Action != "uninstall" && runtimeState.AWS.KMSKeyARN != "" {
+		deployedKey := ""
+		if runtimeState.AWS.KMSKeyExisting {
+			deployedKey = runtimeState.AWS.KMSKeyARN
+		}
+		if config.AWS.KMSKeyARN != deployedKey {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+				ErrorMsg: fmt.Sprintf("the KMS key of the deployment cannot be changed from %s, "+
+					"EFS and RDS would have to be replaced to use another key", runtimeState.AWS.KMSKeyARN),
+			}
+		}
+	}
 	s.variables = StateBucketVariables{
-		Region:   config.AWS.Region,
-		OrchName: config.Global.OrchName,
-		Bucket:   fmt.Sprintf("%s-%s", config.Global.OrchName, runtimeState.DeploymentID),
+		Region:    config.AWS.Region,
+		OrchName:  config.Global.OrchName,
+		Bucket:    fmt.Sprintf("%s-%s", config.Global.OrchName, runtimeState.DeploymentID),
+		KMSKeyARN: config.AWS.KMSKeyARN,
 	}
 	return runtimeState, nil
 }
@@ -105,6 +122,13 @@ func (s *AWSStateBucketStep) RunStep(ctx context.Context, config config.OrchInst
 	} else {
 		runtimeState.StateBucketState = output.TerraformState
 	}
+	if runtimeState.Action == "uninstall" {
+		runtimeState.AWS.KMSKeyARN = ""
+		runtimeState.AWS.KMSKeyExisting = false
+	} else if kmsKeyARN, ok := output.Output["kms_key_arn"]; ok {
+		runtimeState.AWS.KMSKeyARN = strings.Trim(string(kmsKeyARN.Value), "\"")
+		runtimeState.AWS.KMSKeyExisting = config.AWS.KMSKeyARN != ""
+	}
 	return runtimeState, err
 }
 
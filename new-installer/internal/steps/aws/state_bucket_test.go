@@ -5,10 +5,12 @@ package steps_aws_test
 
 import (
 	"crypto/rand"
+	"encoding/json"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
@@ -17,6 +19,8 @@ import (
 	"github.com/stretchr/testify/suite"
 )
 
+const testStateBucketKMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/state-bucket"
+
 type StateBucketTest struct {
 	suite.Suite
 	config       config.OrchInstallerConfig
@@ -41,6 +45,7 @@ func (s *StateBucketTest) SetupTest() {
 	s.config = config.OrchInstallerConfig{}
 	s.config.AWS.Region = "us-west-2"
 	s.config.Global.OrchName = "test"
+	s.runtimeState = config.OrchInstallerRuntimeState{}
 	s.runtimeState.DeploymentID = s.randomText
 	s.tfUtility = &MockTerraformUtility{}
 
@@ -54,27 +59,77 @@ func (s *StateBucketTest) SetupTest() {
 func (s *StateBucketTest) TestInstallAndUninstall() {
 	s.runtimeState.Action = "install"
 	s.expectTFUtiliyyCall("install")
-	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
 	if err != nil {
 		s.NoError(err)
 		return
 	}
+	s.Equal(testStateBucketKMSKeyARN, rs.AWS.KMSKeyARN)
 
+	s.runtimeState = rs
 	s.runtimeState.Action = "uninstall"
 	s.expectTFUtiliyyCall("uninstall")
-	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	rs, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+	}
+	s.Empty(rs.AWS.KMSKeyARN)
+}
+
+func (s *StateBucketTest) TestCustomerManagedKey() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.KMSKeyARN = testStateBucketKMSKeyARN
+	s.expectTFUtiliyyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
 	if err != nil {
 		s.NoError(err)
+		return
 	}
+	s.Equal(testStateBucketKMSKeyARN, rs.AWS.KMSKeyARN)
+	s.tfUtility.AssertExpectations(s.T())
+}
+
+func (s *StateBucketTest) TestChangeKey() {
+	s.runtimeState.Action = "upgrade"
+	s.runtimeState.AWS.KMSKeyARN = "arn:aws:kms:us-west-2:123456789012:key/created"
+	s.config.AWS.KMSKeyARN = testStateBucketKMSKeyARN
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "cannot be changed")
+
+	// Back to a created key
+	s.runtimeState.AWS.KMSKeyARN = testStateBucketKMSKeyARN
+	s.runtimeState.AWS.KMSKeyExisting = true
+	s.config.AWS.KMSKeyARN = ""
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "cannot be changed")
+	s.tfUtility.AssertNotCalled(s.T(), "Run", mock.Anything, mock.Anything)
+}
+
+func (s *StateBucketTest) TestUpgradeKeepsKey() {
+	s.runtimeState.Action = "install"
+	s.config.AWS.KMSKeyARN = testStateBucketKMSKeyARN
+	s.expectTFUtiliyyCall("install")
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.True(rs.AWS.KMSKeyExisting)
+
+	s.runtimeState = rs
+	s.expectTFUtiliyyCall("install")
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.tfUtility.AssertExpectations(s.T())
 }
 
 func (s *StateBucketTest) expectTFUtiliyyCall(action string) {
 	input := steps.TerraformUtilityInput{
 		Action: action,
 		Variables: steps_aws.StateBucketVariables{
-			Region:   s.config.AWS.Region,
-			OrchName: s.config.Global.OrchName,
-			Bucket:   s.config.Global.OrchName + "-" + s.runtimeState.DeploymentID,
+			Region:    s.config.AWS.Region,
+			OrchName:  s.config.Global.OrchName,
+			Bucket:    s.config.Global.OrchName + "-" + s.runtimeState.DeploymentID,
+			KMSKeyARN: s.config.AWS.KMSKeyARN,
 		},
 		ModulePath:         filepath.Join(s.step.RootPath, steps_aws.StateBucketModulePath),
 		LogFile:            filepath.Join(s.step.RootPath, ".logs", "aws_state_bucket.log"),
@@ -84,6 +139,12 @@ func (s *StateBucketTest) expectTFUtiliyyCall(action string) {
 	if action == "install" {
 		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
 			TerraformState: "some state",
+			Output: map[string]tfexec.OutputMeta{
+				"kms_key_arn": {
+					Type:  json.RawMessage(`"string"`),
+					Value: json.RawMessage(`"` + testStateBucketKMSKeyARN + `"`),
+				},
+			},
 		}, nil).Once()
 	} else {
 		s.tfUtility.On("Run", mock.Anything, input).Return(steps.TerraformUtilityOutput{
@@ -17,10 +17,15 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/acm"
 	"github.com/aws/aws-sdk-go/service/ec2"
+	"github.com/aws/aws-sdk-go/service/elbv2"
+	"github.com/aws/aws-sdk-go/service/kms"
 	"github.com/aws/aws-sdk-go/service/rds"
 	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
 )
 
 const (
@@ -35,6 +40,26 @@ type AWSUtility interface {
 	S3CopyToS3(srcRegion, srcBucket, srcKey, destRegion, destBucket, destKey string) error
 	GetSubnetIDsFromVPC(region, vpcID string) ([]string, []string, error)
 	DisableRDSDeletionProtection(region, dbIdentifier string) error
+	DisableLBDeletionProtection(region, lbName string) error
+	ReimportACMCertificate(region, certArn, certBody, privateKey, certChain string) error
+	GetACMCertificateExpiry(region, certArn string) (time.Time, error)
+	GetRDSCACertificateExpiry(region, dbClusterIdentifier string) (time.Time, error)
+	CreateRDSClusterSnapshot(region, dbClusterIdentifier, snapshotIdentifier string) (string, error)
+	CreateRDSClusterSnapshotFromPointInTime(region, dbClusterIdentifier string, restoreTime time.Time, snapshotIdentifier string) (string, error)
+	GetKMSKeyRotationStatus(region, keyArn string) (bool, error)
+	GetRDSGlobalClusterStatus(region, globalClusterIdentifier string) (RDSGlobalClusterStatus, error)
+	FailoverRDSGlobalCluster(region, globalClusterIdentifier, targetClusterArn string, allowDataLoss bool) error
+}
+
+type RDSGlobalClusterMember struct {
+	ClusterArn            string
+	IsWriter              bool
+	SynchronizationStatus string
+}
+
+type RDSGlobalClusterStatus struct {
+	Status  string
+	Members []RDSGlobalClusterMember
 }
 
 type awsUtilityImpl struct{}
@@ -49,8 +74,18 @@ type TerraformAWSBucketBackendConfig struct {
 	Key    string `json:"key" yaml:"key"`
 }
 
+// newSession creates a session whose calls are traced.
+func newSession(config *aws.Config) (*session.Session, error) {
+	session, err := session.NewSession(config)
+	if err != nil {
+		return nil, err
+	}
+	tracing.InstrumentAWS(&session.Handlers)
+	return session, nil
+}
+
 func (*awsUtilityImpl) GetAvailableZones(region string) ([]string, error) {
-	session, err := session.NewSession(&aws.Config{
+	session, err := newSession(&aws.Config{
 		Region: aws.String(region),
 	})
 	if err != nil {
@@ -91,7 +126,7 @@ func (*awsUtilityImpl) GetAvailableZones(region string) ([]string, error) {
 }
 
 func FindAMIID(region string, amiName string, amiOwner string) (string, error) {
-	session, err := session.NewSession(&aws.Config{
+	session, err := newSession(&aws.Config{
 		Region: aws.String(region),
 	})
 	if err != nil {
@@ -123,7 +158,7 @@ func FindAMIID(region string, amiName string, amiOwner string) (string, error) {
 }
 
 func (*awsUtilityImpl) S3CopyToS3(srcRegion, srcBucket, srcKey, destRegion, destBucket, destKey string) error {
-	session, err := session.NewSession(&aws.Config{
+	session, err := newSession(&aws.Config{
 		Region: aws.String(srcRegion),
 	})
 	if err != nil {
@@ -147,7 +182,7 @@ func (*awsUtilityImpl) S3CopyToS3(srcRegion, srcBucket, srcKey, destRegion, dest
 
 // GetPublicSubnetIDsFromVPC retrieves publicand private subnet IDs from a specified VPC in a given AWS region.
 func (*awsUtilityImpl) GetSubnetIDsFromVPC(region, vpcID string) ([]string, []string, error) {
-	session, err := session.NewSession(&aws.Config{
+	session, err := newSession(&aws.Config{
 		Region: aws.String(region),
 	})
 	if err != nil {
@@ -217,7 +252,7 @@ func (*awsUtilityImpl) GetSubnetIDsFromVPC(region, vpcID string) ([]string, []st
 }
 
 func (*awsUtilityImpl) DisableRDSDeletionProtection(region, dbIdentifier string) error {
-	session, err := session.NewSession(&aws.Config{
+	session, err := newSession(&aws.Config{
 		Region: aws.String(region),
 	})
 	if err != nil {
@@ -235,6 +270,298 @@ func (*awsUtilityImpl) DisableRDSDeletionProtection(region, dbIdentifier string)
 	return nil
 }
 
+// DisableLBDeletionProtection disables the deletion protection of the load balancer with the given name.
+// It does nothing if the load balancer does not exist.
+func (*awsUtilityImpl) DisableLBDeletionProtection(region, lbName string) error {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return err
+	}
+	elbClient := elbv2.New(session)
+
+	resp, err := elbClient.DescribeLoadBalancers(&elbv2.DescribeLoadBalancersInput{
+		Names: []*string{aws.String(lbName)},
+	})
+	if err != nil {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == elbv2.ErrCodeLoadBalancerNotFoundException {
+			return nil
+		}
+		return fmt.Errorf("failed to describe load balancer %s: %w", lbName, err)
+	}
+	for _, lb := range resp.LoadBalancers {
+		_, err = elbClient.ModifyLoadBalancerAttributes(&elbv2.ModifyLoadBalancerAttributesInput{
+			LoadBalancerArn: lb.LoadBalancerArn,
+			Attributes: []*elbv2.LoadBalancerAttribute{
+				{
+					Key:   aws.String("deletion_protection.enabled"),
+					Value: aws.String("false"),
+				},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to disable deletion protection for load balancer %s: %w", lbName, err)
+		}
+	}
+	return nil
+}
+
+// ReimportACMCertificate imports new certificate material into an existing ACM certificate.
+// The ARN is kept, so listeners referencing the certificate are not changed.
+func (*awsUtilityImpl) ReimportACMCertificate(region, certArn, certBody, privateKey, certChain string) error {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return err
+	}
+	acmClient := acm.New(session)
+
+	input := &acm.ImportCertificateInput{
+		CertificateArn: aws.String(certArn),
+		Certificate:    []byte(certBody),
+		PrivateKey:     []byte(privateKey),
+	}
+	if certChain != "" {
+		input.CertificateChain = []byte(certChain)
+	}
+	if _, err = acmClient.ImportCertificate(input); err != nil {
+		return fmt.Errorf("failed to re-import ACM certificate %s: %w", certArn, err)
+	}
+	return nil
+}
+
+func (*awsUtilityImpl) GetACMCertificateExpiry(region, certArn string) (time.Time, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	acmClient := acm.New(session)
+
+	resp, err := acmClient.DescribeCertificate(&acm.DescribeCertificateInput{
+		CertificateArn: aws.String(certArn),
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to describe ACM certificate %s: %w", certArn, err)
+	}
+	if resp.Certificate == nil || resp.Certificate.NotAfter == nil {
+		return time.Time{}, fmt.Errorf("ACM certificate %s has no expiry date", certArn)
+	}
+	return *resp.Certificate.NotAfter, nil
+}
+
+// GetRDSCACertificateExpiry returns the earliest expiry of the CA certificates
+// (CACertIdentifier) used by the instances of the given RDS cluster.
+func (*awsUtilityImpl) GetRDSCACertificateExpiry(region, dbClusterIdentifier string) (time.Time, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return time.Time{}, err
+	}
+	rdsClient := rds.New(session)
+
+	instances, err := rdsClient.DescribeDBInstances(&rds.DescribeDBInstancesInput{
+		Filters: []*rds.Filter{
+			{
+				Name:   aws.String("db-cluster-id"),
+				Values: []*string{aws.String(dbClusterIdentifier)},
+			},
+		},
+	})
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to describe RDS instances of cluster %s: %w", dbClusterIdentifier, err)
+	}
+	var expiry time.Time
+	for _, instance := range instances.DBInstances {
+		if instance.CACertificateIdentifier == nil {
+			continue
+		}
+		certs, err := rdsClient.DescribeCertificates(&rds.DescribeCertificatesInput{
+			CertificateIdentifier: instance.CACertificateIdentifier,
+		})
+		if err != nil {
+			return time.Time{}, fmt.Errorf("failed to describe RDS CA certificate %s: %w", *instance.CACertificateIdentifier, err)
+		}
+		for _, cert := range certs.Certificates {
+			if cert.ValidTill != nil && (expiry.IsZero() || cert.ValidTill.Before(expiry)) {
+				expiry = *cert.ValidTill
+			}
+		}
+	}
+	if expiry.IsZero() {
+		return time.Time{}, fmt.Errorf("cannot find CA certificate for RDS cluster %s", dbClusterIdentifier)
+	}
+	return expiry, nil
+}
+
+// CreateRDSClusterSnapshot creates a manual snapshot of the RDS cluster, waits until it is available
+// and returns its ARN.
+func (*awsUtilityImpl) CreateRDSClusterSnapshot(region, dbClusterIdentifier, snapshotIdentifier string) (string, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return "", err
+	}
+	rdsClient := rds.New(session)
+
+	resp, err := rdsClient.CreateDBClusterSnapshot(&rds.CreateDBClusterSnapshotInput{
+		DBClusterIdentifier:         aws.String(dbClusterIdentifier),
+		DBClusterSnapshotIdentifier: aws.String(snapshotIdentifier),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create snapshot %s of RDS cluster %s: %w", snapshotIdentifier, dbClusterIdentifier, err)
+	}
+	err = rdsClient.WaitUntilDBClusterSnapshotAvailable(&rds.DescribeDBClusterSnapshotsInput{
+		DBClusterSnapshotIdentifier: aws.String(snapshotIdentifier),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for snapshot %s of RDS cluster %s: %w", snapshotIdentifier, dbClusterIdentifier, err)
+	}
+	return aws.StringValue(resp.DBClusterSnapshot.DBClusterSnapshotArn), nil
+}
+
+// CreateRDSClusterSnapshotFromPointInTime restores the RDS cluster at the given time into a temporary
+// cluster, takes a snapshot of it and deletes the temporary cluster. The snapshot can then be restored
+// into the cluster managed by Terraform, which keeps its identifier and endpoints.
+func (u *awsUtilityImpl) CreateRDSClusterSnapshotFromPointInTime(region, dbClusterIdentifier string, restoreTime time.Time, snapshotIdentifier string) (string, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return "", err
+	}
+	rdsClient := rds.New(session)
+
+	clusters, err := rdsClient.DescribeDBClusters(&rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(dbClusterIdentifier),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to describe RDS cluster %s: %w", dbClusterIdentifier, err)
+	}
+	if len(clusters.DBClusters) == 0 {
+		return "", fmt.Errorf("cannot find RDS cluster %s", dbClusterIdentifier)
+	}
+	source := clusters.DBClusters[0]
+	securityGroupIDs := []*string{}
+	for _, sg := range source.VpcSecurityGroups {
+		securityGroupIDs = append(securityGroupIDs, sg.VpcSecurityGroupId)
+	}
+
+	tmpClusterIdentifier := snapshotIdentifier + "-tmp"
+	_, err = rdsClient.RestoreDBClusterToPointInTime(&rds.RestoreDBClusterToPointInTimeInput{
+		DBClusterIdentifier:       aws.String(tmpClusterIdentifier),
+		SourceDBClusterIdentifier: aws.String(dbClusterIdentifier),
+		RestoreToTime:             aws.Time(restoreTime),
+		DBSubnetGroupName:         source.DBSubnetGroup,
+		VpcSecurityGroupIds:       securityGroupIDs,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to restore RDS cluster %s to %s: %w", dbClusterIdentifier, restoreTime.Format(time.RFC3339), err)
+	}
+	err = rdsClient.WaitUntilDBClusterAvailable(&rds.DescribeDBClustersInput{
+		DBClusterIdentifier: aws.String(tmpClusterIdentifier),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to wait for temporary RDS cluster %s: %w", tmpClusterIdentifier, err)
+	}
+
+	snapshotArn, snapshotErr := u.CreateRDSClusterSnapshot(region, tmpClusterIdentifier, snapshotIdentifier)
+
+	_, err = rdsClient.DeleteDBCluster(&rds.DeleteDBClusterInput{
+		DBClusterIdentifier: aws.String(tmpClusterIdentifier),
+		SkipFinalSnapshot:   aws.Bool(true),
+	})
+	if snapshotErr != nil {
+		return "", snapshotErr
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to delete temporary RDS cluster %s: %w", tmpClusterIdentifier, err)
+	}
+	return snapshotArn, nil
+}
+
+func (*awsUtilityImpl) GetKMSKeyRotationStatus(region, keyArn string) (bool, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return false, err
+	}
+	kmsClient := kms.New(session)
+
+	resp, err := kmsClient.GetKeyRotationStatus(&kms.GetKeyRotationStatusInput{
+		KeyId: aws.String(keyArn),
+	})
+	if err != nil {
+		return false, fmt.Errorf("failed to get rotation status of KMS key %s: %w", keyArn, err)
+	}
+	return aws.BoolValue(resp.KeyRotationEnabled), nil
+}
+
+func (*awsUtilityImpl) GetRDSGlobalClusterStatus(region, globalClusterIdentifier string) (RDSGlobalClusterStatus, error) {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return RDSGlobalClusterStatus{}, err
+	}
+	rdsClient := rds.New(session)
+
+	resp, err := rdsClient.DescribeGlobalClusters(&rds.DescribeGlobalClustersInput{
+		GlobalClusterIdentifier: aws.String(globalClusterIdentifier),
+	})
+	if err != nil {
+		return RDSGlobalClusterStatus{}, fmt.Errorf("failed to describe RDS global cluster %s: %w", globalClusterIdentifier, err)
+	}
+	if len(resp.GlobalClusters) == 0 {
+		return RDSGlobalClusterStatus{}, fmt.Errorf("RDS global cluster %s not found", globalClusterIdentifier)
+	}
+	globalCluster := resp.GlobalClusters[0]
+	status := RDSGlobalClusterStatus{
+		Status: aws.StringValue(globalCluster.Status),
+	}
+	for _, member := range globalCluster.GlobalClusterMembers {
+		status.Members = append(status.Members, RDSGlobalClusterMember{
+			ClusterArn:            aws.StringValue(member.DBClusterArn),
+			IsWriter:              aws.BoolValue(member.IsWriter),
+			SynchronizationStatus: aws.StringValue(member.SynchronizationStatus),
+		})
+	}
+	return status, nil
+}
+
+// FailoverRDSGlobalCluster promotes the target cluster to the writer of the global cluster.
+// Without allowDataLoss a switchover is performed, which waits for the secondary to catch up
+// and requires the primary region to be reachable.
+func (*awsUtilityImpl) FailoverRDSGlobalCluster(region, globalClusterIdentifier, targetClusterArn string, allowDataLoss bool) error {
+	session, err := newSession(&aws.Config{
+		Region: aws.String(region),
+	})
+	if err != nil {
+		return err
+	}
+	rdsClient := rds.New(session)
+
+	input := &rds.FailoverGlobalClusterInput{
+		GlobalClusterIdentifier:   aws.String(globalClusterIdentifier),
+		TargetDbClusterIdentifier: aws.String(targetClusterArn),
+	}
+	if allowDataLoss {
+		input.AllowDataLoss = aws.Bool(true)
+	} else {
+		input.Switchover = aws.Bool(true)
+	}
+	if _, err := rdsClient.FailoverGlobalCluster(input); err != nil {
+		return fmt.Errorf("failed to fail over RDS global cluster %s to %s: %w", globalClusterIdentifier, targetClusterArn, err)
+	}
+	return nil
+}
+
 // GenerateSelfSignedTLSCert generates a self-signed TLS certificate, CA certificate, and private key.
 // Returns the leaf certificate, CA certificate, and private key as PEM-encoded strings.
 // This CA is not an external or trusted third-party CA, but a local, self-signed CA created on the fly. The leaf (end-entity) certificate
@@ -5,9 +5,11 @@ package steps_aws_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
 	"github.com/stretchr/testify/mock"
 )
 
@@ -109,3 +111,48 @@ func (m *MockAWSUtility) DisableRDSDeletionProtection(region, dbIdentifier strin
 	args := m.Called(region, dbIdentifier)
 	return args.Error(0)
 }
+
+func (m *MockAWSUtility) DisableLBDeletionProtection(region, lbName string) error {
+	args := m.Called(region, lbName)
+	return args.Error(0)
+}
+
+func (m *MockAWSUtility) ReimportACMCertificate(region, certArn, certBody, privateKey, certChain string) error {
+	args := m.Called(region, certArn, certBody, privateKey, certChain)
+	return args.Error(0)
+}
+
+func (m *MockAWSUtility) GetACMCertificateExpiry(region, certArn string) (time.Time, error) {
+	args := m.Called(region, certArn)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockAWSUtility) GetRDSCACertificateExpiry(region, dbClusterIdentifier string) (time.Time, error) {
+	args := m.Called(region, dbClusterIdentifier)
+	return args.Get(0).(time.Time), args.Error(1)
+}
+
+func (m *MockAWSUtility) CreateRDSClusterSnapshot(region, dbClusterIdentifier, snapshotIdentifier string) (string, error) {
+	args := m.Called(region, dbClusterIdentifier, snapshotIdentifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAWSUtility) CreateRDSClusterSnapshotFromPointInTime(region, dbClusterIdentifier string, restoreTime time.Time, snapshotIdentifier string) (string, error) {
+	args := m.Called(region, dbClusterIdentifier, restoreTime, snapshotIdentifier)
+	return args.String(0), args.Error(1)
+}
+
+func (m *MockAWSUtility) GetKMSKeyRotationStatus(region, keyArn string) (bool, error) {
+	args := m.Called(region, keyArn)
+	return args.Bool(0), args.Error(1)
+}
+
+func (m *MockAWSUtility) GetRDSGlobalClusterStatus(region, globalClusterIdentifier string) (steps_aws.RDSGlobalClusterStatus, error) {
+	args := m.Called(region, globalClusterIdentifier)
+	return args.Get(0).(steps_aws.RDSGlobalClusterStatus), args.Error(1)
+}
+
+func (m *MockAWSUtility) FailoverRDSGlobalCluster(region, globalClusterIdentifier, targetClusterArn string, allowDataLoss bool) error {
+	args := m.Called(region, globalClusterIdentifier, targetClusterArn, allowDataLoss)
+	return args.Error(0)
+}
@@ -45,7 +45,7 @@ func (s *VPCStepTest) SetupTest() {
 	}
 	s.randomText = strings.ToLower(rand.Text()[0:8])
 	s.logDir = filepath.Join(rootPath, ".logs")
-	if err := internal.InitLogger("debug", s.logDir); err != nil {
+	if err := internal.InitLogger(internal.LogOptions{Level: "debug", Dir: s.logDir}); err != nil {
 		s.NoError(err)
 		return
 	}
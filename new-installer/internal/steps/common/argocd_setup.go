@@ -6,29 +6,131 @@ package common
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
+	"time"
 
-	"github.com/bitfield/script"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/getter"
+	"helm.sh/helm/v3/pkg/repo"
+	"helm.sh/helm/v3/pkg/storage/driver"
+	sigsyaml "sigs.k8s.io/yaml"
 )
 
+const (
+	argocdNamespace    = "argocd"
+	argocdReleaseName  = "argocd"
+	ArgoCDChartRepo    = "https://argoproj.github.io/argo-helm"
+	argocdChartName    = "argo-cd"
+	ArgoCDChartVersion = "8.0.0"
+	argocdTimeout      = 10 * time.Minute
+
+	// ArgoCDChartArchive of ArgoCDChartVersion is downloaded from ArgoCDChartURL into bundles
+	ArgoCDChartArchive = argocdChartName + "-" + ArgoCDChartVersion + ".tgz"
+	ArgoCDChartURL     = "https://github.com/argoproj/argo-helm/releases/download/" + argocdChartName + "-" + ArgoCDChartVersion + "/" + ArgoCDChartArchive
+
+	argocdNoProxy   = "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,127.0.0.0/8,localhost,.svc,.local,argocd-repo-server,argocd-application-controller,argocd-metrics,argocd-server,argocd-server-metrics,argocd-redis,argocd-dex-server"
+	nodeCABundle    = "/etc/ssl/certs/ca-certificates.crt"
+	giteaCACert     = "/usr/local/share/ca-certificates/gitea_cert.crt"
+	customCAMap     = "argocd-custom-ca"
+	customCAMountAt = "/etc/ssl/certs/orch-custom-ca.crt"
+)
+
+// nolint
+const argocdBaseValues = `
+server:
+  service:
+    type: LoadBalancer
+configs:
+  params:
+    application.namespaces: "*"
+    server.tls.minversion: "1.2"
+    # // Note that for TLS v1.3, cipher suites are not configurable and will be chosen automatically.
+    server.tls.ciphers: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384:TLS_AES_256_GCM_SHA384"
+  # https://argo-cd.readthedocs.io/en/stable/operator-manual/health/#argocd-app
+  cm:
+    resource.customizations: |
+      argoproj.io/Application:
+        health.lua: |
+          hs = {}
+          hs.status = "Progressing"
+          hs.message = ""
+          if obj.status ~= nil then
+            if obj.status.health ~= nil then
+              hs.status = obj.status.health.status
+              if obj.status.health.message ~= nil then
+                hs.message = obj.status.health.message
+              end
+            end
+          end
+          return hs
+    users.session.duration: "1h"
+
+# Disabled due to vulnerability report and we are not using it
+dex:
+  enabled: false
+`
+
+// argocdResources are the requests and limits of the ArgoCD components at the scales whose
+// needs differ, the smallest applies below 500 edge nodes.
+var argocdResources = map[config.Scale]map[string]string{
+	config.Scale50: {
+		"controller": "{requests: {cpu: 500m, memory: 1Gi}, limits: {cpu: 2, memory: 2Gi}}",
+		"repoServer": "{requests: {cpu: 250m, memory: 256Mi}, limits: {cpu: 1, memory: 1Gi}}",
+		"server":     "{requests: {cpu: 100m, memory: 128Mi}, limits: {cpu: 500m, memory: 512Mi}}",
+	},
+	config.Scale500: {
+		"controller": "{requests: {cpu: 1, memory: 2Gi}, limits: {cpu: 4, memory: 4Gi}}",
+		"repoServer": "{requests: {cpu: 500m, memory: 512Mi}, limits: {cpu: 2, memory: 2Gi}}",
+		"server":     "{requests: {cpu: 250m, memory: 256Mi}, limits: {cpu: 1, memory: 1Gi}}",
+	},
+	config.Scale1000: {
+		"controller": "{requests: {cpu: 2, memory: 4Gi}, limits: {cpu: 8, memory: 8Gi}}",
+		"repoServer": "{requests: {cpu: 1, memory: 1Gi}, limits: {cpu: 4, memory: 4Gi}}",
+		"server":     "{requests: {cpu: 500m, memory: 512Mi}, limits: {cpu: 2, memory: 2Gi}}",
+	},
+}
+
+var argoCDStepLabels = []string{"argocd"}
+
+// ArgoCDStep installs, upgrades and uninstalls ArgoCD with the Helm SDK. The values are
+// rendered from the installer config in memory, nothing is written to disk.
 type ArgoCDStep struct {
 	RootPath               string
 	KeepGeneratedFiles     bool
 	orchConfigReaderWriter config.OrchConfigReaderWriter
 	StepLabels             []string
+	// Chart archive bundled with the installer, the one fetched by the on-prem
+	// ArtifactDownloader when empty. The chart is downloaded from the argo-helm repository
+	// when it is missing.
+	ChartPath string
+	// Replaced in tests
+	HelmConfig func(kubeConfig, namespace string) (*action.Configuration, error)
+	LoadChart  func(ctx context.Context) (*chart.Chart, error)
+
+	chart      *chart.Chart
+	helmConfig *action.Configuration
 }
 
 func CreateArgoStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *ArgoCDStep {
-	return &ArgoCDStep{
+	s := &ArgoCDStep{
 		RootPath:               rootPath,
 		KeepGeneratedFiles:     keepGeneratedFiles,
 		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             argoCDStepLabels,
+		HelmConfig:             CreateHelmConfig,
 	}
+	s.LoadChart = s.loadChart
+	return s
 }
 
 func (s *ArgoCDStep) Name() string {
@@ -39,282 +141,265 @@ func (s *ArgoCDStep) Labels() []string {
 	return s.StepLabels
 }
 
-func (s *ArgoCDStep) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
-	return runtimeState, nil
+// SupportsDryRun tells that the step only prints the changes of the release with --dry-run.
+func (s *ArgoCDStep) SupportsDryRun() bool {
+	return true
 }
 
-func (s *ArgoCDStep) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
-	if runtimeState.Action == "install" {
-		// no-op for now
-		err := argocdValues(config)
-		if err != nil {
-			return runtimeState, &internal.OrchInstallerError{
-				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("Error installing Argocd %v \n", err),
-			}
-		}
+func (s *ArgoCDStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if s.ChartPath == "" && runtimeState.Onprem.Artifacts.ChartsDir != "" {
+		s.ChartPath = filepath.Join(runtimeState.Onprem.Artifacts.ChartsDir, ArgoCDChartArchive)
 	}
 	return runtimeState, nil
 }
 
-func (s *ArgoCDStep) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
-	if runtimeState.Action == "install" {
-		// InstallArgoCD
-		err := InstallArgoCD()
-		if err != nil {
-			return runtimeState, &internal.OrchInstallerError{
-				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("Error installing Argocd %v \n", err),
-			}
+func (s *ArgoCDStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	kubeConfig := ClusterKubeConfig(cfg, runtimeState)
+	if kubeConfig == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot manage ArgoCD",
+		}
+	}
+	helmConfig, err := s.HelmConfig(kubeConfig, argocdNamespace)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
 		}
 	}
-	if runtimeState.Action == "uninstall" {
-		// UninstallArgoCD
-		err := UninstallArgoCD()
+	s.helmConfig = helmConfig
+
+	if runtimeState.Action == "install" || runtimeState.Action == "upgrade" {
+		argocdChart, err := s.LoadChart(ctx)
 		if err != nil {
 			return runtimeState, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("Error uninstalling Argocd %v \n", err),
+				ErrorMsg:  fmt.Sprintf("failed to load the ArgoCD chart: %s", err),
 			}
 		}
+		s.chart = argocdChart
 	}
 	return runtimeState, nil
 }
 
-func (s *ArgoCDStep) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
-	if runtimeState.Action == "install" {
-		// Wait for ArgoCD namespace creation normally taken from an env var
-		argoCDNS := "argocd"
-		if argoCDNS == "" {
-			argoCDNS = "argocd"
-		}
-		err := WaitForNamespaceCreation(argoCDNS)
-		if err != nil {
-			return runtimeState, &internal.OrchInstallerError{
-				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("Error installing Argocd %v \n", err),
-			}
+func (s *ArgoCDStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	var err error
+	switch runtimeState.Action {
+	case "install", "upgrade":
+		err = s.installOrUpgrade(ctx, cfg, runtimeState.DryRun)
+	case "uninstall":
+		err = s.uninstall(runtimeState.DryRun)
+	}
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to %s ArgoCD: %s", runtimeState.Action, err),
 		}
 	}
+	return runtimeState, nil
+}
+
+func (s *ArgoCDStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	return runtimeState, prevStepError
 }
 
-func addArgoHelmRepo() error {
-	argocdHelmVersion := "8.0.0"
-	argocdPath := "/tmp/argo-cd/"
-	if err := os.RemoveAll(filepath.Join(argocdPath, "argo-cd")); err != nil {
-		fmt.Println("Failed to remove file:", err)
+func (s *ArgoCDStep) installOrUpgrade(ctx context.Context, cfg config.OrchInstallerConfig, dryRun bool) error {
+	values, err := argocdValues(cfg)
+	if err != nil {
+		return err
+	}
+	current, err := action.NewGet(s.helmConfig).Run(argocdReleaseName)
+	if errors.Is(err, driver.ErrReleaseNotFound) {
+		if dryRun {
+			internal.Logger().Infof("Would install ArgoCD chart %s", s.chart.Metadata.Version)
+			return nil
+		}
+		internal.Logger().Info(argocdInstallBanner)
+		install := action.NewInstall(s.helmConfig)
+		install.ReleaseName = argocdReleaseName
+		install.Namespace = argocdNamespace
+		install.CreateNamespace = true
+		install.Wait = true
+		install.Timeout = argocdTimeout
+		_, err = install.RunWithContext(ctx, s.chart, values)
 		return err
 	}
-	cmd := exec.Command("helm", "repo", "add", "argo-helm", "https://argoproj.github.io/argo-helm", "--force-update")
-	cmd.Stdout = nil // or os.Stdout to print output
-	cmd.Stderr = nil // or os.Stderr to print errors
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to add argo-helm repo: %w", err)
+	if err != nil {
+		return fmt.Errorf("failed to get the ArgoCD release: %w", err)
 	}
 
-	cmd_str := fmt.Sprintf("helm fetch argo-helm/argo-cd --version %v --untar --untardir %v", argocdHelmVersion, argocdPath)
-	if _, err := script.Exec(cmd_str).Stdout(); err != nil {
-		return fmt.Errorf("failed to fetch argo-cd chart: %w", err)
+	// Render the upgrade first, to report what it changes and skip it when nothing does
+	upgrade := action.NewUpgrade(s.helmConfig)
+	upgrade.Namespace = argocdNamespace
+	upgrade.DryRun = true
+	desired, err := upgrade.RunWithContext(ctx, argocdReleaseName, s.chart, values)
+	if err != nil {
+		return fmt.Errorf("failed to render the ArgoCD upgrade: %w", err)
+	}
+	diff := manifestDiff(current.Manifest, desired.Manifest)
+	if diff == "" {
+		internal.Logger().Infof("ArgoCD chart %s is up to date", current.Chart.Metadata.Version)
+		return nil
+	}
+	internal.Logger().Infof("ArgoCD chart %s -> %s changes:\n%s", current.Chart.Metadata.Version, s.chart.Metadata.Version, diff)
+	if dryRun {
+		return nil
 	}
-	return nil
+	upgrade.DryRun = false
+	upgrade.Wait = true
+	upgrade.Timeout = argocdTimeout
+	_, err = upgrade.RunWithContext(ctx, argocdReleaseName, s.chart, values)
+	return err
 }
 
-func UninstallArgoCD() error {
-	// Print ASCII art
-	fmt.Println(`
-     _                     ____ ____    ____
-    / \   _ __ __ _  ___  / ___|  _ \  |  _ \ ___ _ __ ___   _____   _____
-   / _ \ | '__/ _  |/ _ \| |   | | | | | |_) / _ \  _   _ \ /_  \ \ / / _ \
-  / ___ \| | | (_| | (_) | |___| |_| | |  _ <  __/ | | | | | (_) \ V /  __/
- /_/   \_\_|  \__, |\___/ \____|____/  |_| \_\___|_| |_| |_|\___/ \_/ \___|
-              |___/`)
-
-	cmd := exec.Command("helm", "delete", "argocd", "-n", "argocd")
-	_ = cmd.Run()
-
-	// Remove artifacts
-	err := os.RemoveAll("/tmp/argo-cd")
-
+func (s *ArgoCDStep) uninstall(dryRun bool) error {
+	if dryRun {
+		internal.Logger().Info("Would uninstall ArgoCD")
+		return nil
+	}
+	internal.Logger().Info(argocdUninstallBanner)
+	uninstall := action.NewUninstall(s.helmConfig)
+	uninstall.IgnoreNotFound = true
+	uninstall.Wait = true
+	uninstall.Timeout = argocdTimeout
+	_, err := uninstall.Run(argocdReleaseName)
 	return err
 }
 
-func InstallArgoCD() error {
-	// Print ASCII art
-	fmt.Println(`
-     _                     ____ ____
-    / \   _ __ __ _  ___  / ___|  _ \
-   / _ \ | '__/ _  |/ _ \| |   | | | |
-  / ___ \| | | (_| | (_) | |___| |_| |
- /_/   \_\_|  \__, |\___/ \____|____/
-              |___/`)
-
-	// Run helm template
-	helmTemplateCmd := exec.Command(
-		"helm", "template", "-s", "templates/values.tmpl", "/tmp/argo-cd/argo-cd",
-		"--values", "/tmp/argo-cd/proxy-values.yaml",
-	)
-
-	valuesYaml, err := helmTemplateCmd.Output()
+// loadChart loads the bundled chart, or downloads it from the argo-helm repository into
+// memory.
+func (s *ArgoCDStep) loadChart(ctx context.Context) (*chart.Chart, error) {
+	if _, err := os.Stat(s.ChartPath); s.ChartPath != "" && err == nil {
+		return loader.Load(s.ChartPath)
+	}
+	httpGetter, err := getter.NewHTTPGetter()
 	if err != nil {
-		return fmt.Errorf("failed to run helm template: %w", err)
+		return nil, err
 	}
-
-	if err := os.WriteFile("/tmp/argo-cd/values.yaml", valuesYaml, 0o644); err != nil {
-		return fmt.Errorf("failed to write values.yaml: %w", err)
+	indexData, err := httpGetter.Get(ArgoCDChartRepo + "/index.yaml")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get the index of %s: %w", ArgoCDChartRepo, err)
 	}
-
-	// Remove values.tmpl
-	if err := os.Remove("/tmp/argo-cd/argo-cd/templates/values.tmpl"); err != nil {
-		return fmt.Errorf("failed to remove values.tmpl: %w", err)
+	index := &repo.IndexFile{}
+	if err := sigsyaml.Unmarshal(indexData.Bytes(), index); err != nil {
+		return nil, fmt.Errorf("failed to parse the index of %s: %w", ArgoCDChartRepo, err)
 	}
-
-	// Write mounts.yaml
-	mountsYaml := `
-notifications:
-  extraVolumeMounts:
-  - mountPath: /etc/ssl/certs/ca-certificates.crt
-    name: tls-from-node
-  - mountPath: /etc/ssl/certs/gitea_cert.crt
-    name: gitea-tls
-  extraVolumes:
-  - name: tls-from-node
-    hostPath:
-      path: /etc/ssl/certs/ca-certificates.crt
-  - name: gitea-tls
-    hostPath:
-      path: /usr/local/share/ca-certificates/gitea_cert.crt
-server:
-  volumeMounts:
-  - mountPath: /etc/ssl/certs/ca-certificates.crt
-    name: tls-from-node
-  - mountPath: /etc/ssl/certs/gitea_cert.crt
-    name: gitea-tls
-  volumes:
-  - name: tls-from-node
-    hostPath:
-      path: /etc/ssl/certs/ca-certificates.crt
-  - name: gitea-tls
-    hostPath:
-      path: /usr/local/share/ca-certificates/gitea_cert.crt
-repoServer:
-  volumeMounts:
-  - mountPath: /etc/ssl/certs/ca-certificates.crt
-    name: tls-from-node
-  - mountPath: /etc/ssl/certs/gitea_cert.crt
-    name: gitea-tls
-  volumes:
-  - name: tls-from-node
-    hostPath:
-      path: /etc/ssl/certs/ca-certificates.crt
-  - name: gitea-tls
-    hostPath:
-      path: /usr/local/share/ca-certificates/gitea_cert.crt
-applicationSet:
-  extraVolumeMounts:
-  - mountPath: /etc/ssl/certs/ca-certificates.crt
-    name: tls-from-node
-  - mountPath: /etc/ssl/certs/gitea_cert.crt
-    name: gitea-tls
-  extraVolumes:
-  - name: tls-from-node
-    hostPath:
-      path: /etc/ssl/certs/ca-certificates.crt
-  - name: gitea-tls
-    hostPath:
-      path: /usr/local/share/ca-certificates/gitea_cert.crt
-`
-	if err := os.WriteFile("/tmp/argo-cd/mounts.yaml", []byte(mountsYaml), 0o644); err != nil {
-		return fmt.Errorf("failed to write mounts.yaml: %w", err)
+	version, err := index.Get(argocdChartName, ArgoCDChartVersion)
+	if err != nil || len(version.URLs) == 0 {
+		return nil, fmt.Errorf("chart %s %s not found in %s", argocdChartName, ArgoCDChartVersion, ArgoCDChartRepo)
 	}
-
-	// Run helm install
-	helmInstallCmd := exec.Command(
-		"helm", "install", "argocd", "/tmp/argo-cd/argo-cd",
-		"--values", "/tmp/argo-cd/values.yaml",
-		"-f", "/tmp/argo-cd/mounts.yaml",
-		"-n", "argocd", "--create-namespace",
-	)
-	helmInstallCmd.Stdout = os.Stdout
-	helmInstallCmd.Stderr = os.Stderr
-	if err := helmInstallCmd.Run(); err != nil {
-		return fmt.Errorf("failed to run helm install: %w", err)
+	chartURL, err := repo.ResolveReferenceURL(ArgoCDChartRepo, version.URLs[0])
+	if err != nil {
+		return nil, err
 	}
-
-	return nil
+	archive, err := httpGetter.Get(chartURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download %s: %w", chartURL, err)
+	}
+	if version.Digest != "" {
+		digest := sha256.Sum256(archive.Bytes())
+		if hex.EncodeToString(digest[:]) != version.Digest {
+			return nil, fmt.Errorf("digest of %s does not match the repository index", chartURL)
+		}
+	}
+	return loader.LoadArchive(archive)
 }
 
-func argocdValues(config config.OrchInstallerConfig) error {
-	//nolint
-	valuesFile := `
-server:
-  service:
-    type: LoadBalancer
-configs:
-  params:
-    application.namespaces: "*"
-    server.tls.minversion: "1.2"
-    # // Note that for TLS v1.3, cipher suites are not configurable and will be chosen automatically.
-    server.tls.ciphers: "TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384:TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384:TLS_AES_256_GCM_SHA384"
-  # https://argo-cd.readthedocs.io/en/stable/operator-manual/health/#argocd-app
-  cm:
-    resource.customizations: |
-      argoproj.io/Application:
-        health.lua: |
-          hs = {}
-          hs.status = "Progressing"
-          hs.message = ""
-          if obj.status ~= nil then
-            if obj.status.health ~= nil then
-              hs.status = obj.status.health.status
-              if obj.status.health.message ~= nil then
-                hs.message = obj.status.health.message
-              end
-            end
-          end
-          return hs
-    users.session.duration: "1h"
-{{ if or .Values.http_proxy .Values.https_proxy }}
-global:
-  env:
-    {{ if .Values.http_proxy }}
-    - name: http_proxy
-      value: {{ .Values.http_proxy }}
-    {{ end }}
-    {{ if .Values.https_proxy }}
-    - name: https_proxy
-      value: {{ .Values.https_proxy }}
-    {{ end }}
-    - name: no_proxy
-      value: "10.0.0.0/8,172.16.0.0/12,192.168.0.0/16,127.0.0.0/8,localhost,.svc,.local,argocd-repo-server,argocd-application-controller,argocd-metrics,argocd-server,argocd-server-metrics,argocd-redis,argocd-dex-server,{{ .Values.no_proxy }}"
-{{ end }}
-
-# Disabled due to vulnerability report and we are not using it
-dex:
-  enabled: false
-`
-	// revive:enable:dupword
-
-	path := "/tmp/argo-cd/argo-cd/templates/"
-	if err := os.MkdirAll(path, 0o755); err != nil {
-		return fmt.Errorf("failed to create directory %s: %w", path, err)
+// argocdValues renders the chart values from the installer config.
+func argocdValues(cfg config.OrchInstallerConfig) (map[string]any, error) {
+	values := map[string]any{}
+	if err := yaml.Unmarshal([]byte(argocdBaseValues), &values); err != nil {
+		return nil, fmt.Errorf("failed to parse the ArgoCD values: %w", err)
 	}
 
-	if err := chownToCurrentUserRecursive("/tmp/argo-cd/"); err != nil {
-		return fmt.Errorf("failed to change ownership of /tmp/argo-cd/: %w", err)
+	if cfg.Proxy.HTTPProxy != "" || cfg.Proxy.HTTPSProxy != "" {
+		env := []any{}
+		if cfg.Proxy.HTTPProxy != "" {
+			env = append(env, map[string]any{"name": "http_proxy", "value": cfg.Proxy.HTTPProxy})
+		}
+		if cfg.Proxy.HTTPSProxy != "" {
+			env = append(env, map[string]any{"name": "https_proxy", "value": cfg.Proxy.HTTPSProxy})
+		}
+		noProxy := argocdNoProxy
+		if cfg.Proxy.NoProxy != "" {
+			noProxy += "," + cfg.Proxy.NoProxy
+		}
+		env = append(env, map[string]any{"name": "no_proxy", "value": noProxy})
+		values["global"] = map[string]any{"env": env}
 	}
 
-	if err := addArgoHelmRepo(); err != nil {
-		return fmt.Errorf("failed to add helm template: %w", err)
+	// Trust the CAs of the node, of Gitea on-prem and the custom CA of the orchestrator. On an
+	// existing cluster the Gitea certificate is added to argocd-tls-certs-cm instead, the
+	// installer does not manage the nodes.
+	volumes := []any{hostPathVolume("tls-from-node", nodeCABundle)}
+	mounts := []any{map[string]any{"name": "tls-from-node", "mountPath": nodeCABundle}}
+	if cfg.Provider == "onprem" {
+		volumes = append(volumes, hostPathVolume("gitea-tls", giteaCACert))
+		mounts = append(mounts, map[string]any{"name": "gitea-tls", "mountPath": "/etc/ssl/certs/gitea_cert.crt"})
 	}
-
-	if err := os.WriteFile("/tmp/argo-cd/argo-cd/templates/values.tmpl", []byte(valuesFile), 0o644); err != nil {
-		return fmt.Errorf("failed to copy values.tmpl: %w", err)
+	if cfg.Cert.TLSCA != "" {
+		values["extraObjects"] = []any{map[string]any{
+			"apiVersion": "v1",
+			"kind":       "ConfigMap",
+			"metadata":   map[string]any{"name": customCAMap},
+			"data":       map[string]any{"ca.crt": cfg.Cert.TLSCA},
+		}}
+		volumes = append(volumes, map[string]any{"name": "custom-ca", "configMap": map[string]any{"name": customCAMap}})
+		mounts = append(mounts, map[string]any{"name": "custom-ca", "mountPath": customCAMountAt, "subPath": "ca.crt"})
+	}
+	for component, keys := range map[string][2]string{
+		"notifications":  {"extraVolumes", "extraVolumeMounts"},
+		"server":         {"volumes", "volumeMounts"},
+		"repoServer":     {"volumes", "volumeMounts"},
+		"applicationSet": {"extraVolumes", "extraVolumeMounts"},
+	} {
+		componentValues, _ := values[component].(map[string]any)
+		if componentValues == nil {
+			componentValues = map[string]any{}
+			values[component] = componentValues
+		}
+		componentValues[keys[0]] = volumes
+		componentValues[keys[1]] = mounts
 	}
 
-	proxyYaml := fmt.Sprintf("http_proxy: %s\nhttps_proxy: %s\nno_proxy: %s\n", config.Proxy.HTTPProxy, config.Proxy.HTTPProxy, config.Proxy.NoProxy)
-	if err := os.WriteFile("/tmp/argo-cd/proxy-values.yaml", []byte(proxyYaml), 0o644); err != nil {
-		return fmt.Errorf("failed to write proxy-values.yaml: %w", err)
+	resources := argocdResources[config.Scale50]
+	if cfg.Global.Scale >= config.Scale1000 {
+		resources = argocdResources[config.Scale1000]
+	} else if cfg.Global.Scale >= config.Scale500 {
+		resources = argocdResources[config.Scale500]
+	}
+	for component, componentResources := range resources {
+		componentValues, _ := values[component].(map[string]any)
+		if componentValues == nil {
+			componentValues = map[string]any{}
+			values[component] = componentValues
+		}
+		parsed := map[string]any{}
+		if err := yaml.Unmarshal([]byte(componentResources), &parsed); err != nil {
+			return nil, fmt.Errorf("failed to parse the %s resources: %w", component, err)
+		}
+		componentValues["resources"] = parsed
 	}
-	return nil
+	return values, nil
 }
+
+func hostPathVolume(name, path string) map[string]any {
+	return map[string]any{"name": name, "hostPath": map[string]any{"path": path}}
+}
+
+const argocdInstallBanner = `
+     _                     ____ ____
+    / \   _ __ __ _  ___  / ___|  _ \
+   / _ \ | '__/ _  |/ _ \| |   | | | |
+  / ___ \| | | (_| | (_) | |___| |_| |
+ /_/   \_\_|  \__, |\___/ \____|____/
+              |___/`
+
+const argocdUninstallBanner = `
+     _                     ____ ____    ____
+    / \   _ __ __ _  ___  / ___|  _ \  |  _ \ ___ _ __ ___   _____   _____
+   / _ \ | '__/ _  |/ _ \| |   | | | | | |_) / _ \  _   _ \ /_  \ \ / / _ \
+  / ___ \| | | (_| | (_) | |___| |_| | |  _ <  __/ | | | | | (_) \ V /  __/
+ /_/   \_\_|  \__, |\___/ \____|____/  |_| \_\___|_| |_| |_|\___/ \_/ \___|
+              |___/`
@@ -0,0 +1,209 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package common_test
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"github.com/stretchr/testify/suite"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zaptest/observer"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+	kubefake "helm.sh/helm/v3/pkg/kube/fake"
+	"helm.sh/helm/v3/pkg/release"
+	"helm.sh/helm/v3/pkg/storage"
+	"helm.sh/helm/v3/pkg/storage/driver"
+)
+
+// The values are rendered into the manifest so that a change of values changes the release
+const valuesTemplate = `apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: argocd-values
+data:
+  values: {{ toJson .Values | quote }}
+`
+
+const secretTemplate = `apiVersion: v1
+kind: Secret
+metadata:
+  name: argocd-secret
+stringData:
+  values: {{ toJson .Values | quote }}
+`
+
+type ArgoCDStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *common.ArgoCDStep
+	releases     *storage.Storage
+}
+
+func TestArgoCDStep(t *testing.T) {
+	suite.Run(t, new(ArgoCDStepTest))
+}
+
+func (s *ArgoCDStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{Provider: "onprem"}
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "install"
+	s.runtimeState.Onprem.KubeConfig = "test-kubeconfig"
+
+	memory := driver.NewMemory()
+	memory.SetNamespace("argocd")
+	s.releases = storage.Init(memory)
+	s.step = common.CreateArgoStep("", false, nil)
+	s.step.HelmConfig = func(kubeConfig, namespace string) (*action.Configuration, error) {
+		return &action.Configuration{
+			Releases:     s.releases,
+			KubeClient:   &kubefake.PrintingKubeClient{Out: io.Discard},
+			Capabilities: chartutil.DefaultCapabilities,
+			Log:          func(string, ...any) {},
+		}, nil
+	}
+	s.step.LoadChart = func(ctx context.Context) (*chart.Chart, error) {
+		return &chart.Chart{
+			Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "argo-cd", Version: "8.0.0"},
+			Templates: []*chart.File{{Name: "templates/values.yaml", Data: []byte(valuesTemplate)}},
+		}, nil
+	}
+}
+
+func (s *ArgoCDStepTest) release() *release.Release {
+	rel, err := s.releases.Last("argocd")
+	s.Require().NoError(err)
+	return rel
+}
+
+func (s *ArgoCDStepTest) TestInstall() {
+	s.config.Proxy.HTTPProxy = "http://proxy:911"
+	s.config.Proxy.HTTPSProxy = "http://proxy:912"
+	s.config.Proxy.NoProxy = "example.com"
+	s.config.Cert.TLSCA = "test-ca"
+	s.config.Global.Scale = config.Scale1000
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	rel := s.release()
+	s.Equal(1, rel.Version)
+	s.Equal(release.StatusDeployed, rel.Info.Status)
+	values := rel.Config
+	env := values["global"].(map[string]any)["env"].([]any)
+	s.Equal(map[string]any{"name": "https_proxy", "value": "http://proxy:912"}, env[1])
+	s.Contains(env[2].(map[string]any)["value"], ".svc,.local,")
+	s.Contains(env[2].(map[string]any)["value"], ",example.com")
+
+	s.Equal("test-ca", values["extraObjects"].([]any)[0].(map[string]any)["data"].(map[string]any)["ca.crt"])
+	mounts := values["repoServer"].(map[string]any)["volumeMounts"].([]any)
+	s.Len(mounts, 3)
+	s.Equal("/etc/ssl/certs/orch-custom-ca.crt", mounts[2].(map[string]any)["mountPath"])
+	s.Len(values["notifications"].(map[string]any)["extraVolumes"], 3)
+
+	limits := values["controller"].(map[string]any)["resources"].(map[string]any)["limits"].(map[string]any)
+	s.Equal("8Gi", limits["memory"])
+	s.Equal("LoadBalancer", values["server"].(map[string]any)["service"].(map[string]any)["type"])
+}
+
+func (s *ArgoCDStepTest) TestNoProxy() {
+	s.config.Provider = "aws"
+	s.runtimeState.AWS.KubeConfig = "test-kubeconfig"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	values := s.release().Config
+	s.NotContains(values, "global")
+	s.NotContains(values, "extraObjects")
+	// Gitea only runs on-prem
+	s.Len(values["server"].(map[string]any)["volumes"], 1)
+	limits := values["controller"].(map[string]any)["resources"].(map[string]any)["limits"].(map[string]any)
+	s.Equal("2Gi", limits["memory"])
+}
+
+func (s *ArgoCDStepTest) TestExistingCluster() {
+	s.config.Provider = "existing-cluster"
+	s.runtimeState.Onprem.KubeConfig = ""
+	s.runtimeState.ExistingCluster.KubeConfig = "test-kubeconfig"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	// The Gitea certificate is not on the nodes of an existing cluster
+	s.Len(s.release().Config["server"].(map[string]any)["volumes"], 1)
+}
+
+func (s *ArgoCDStepTest) TestUpgrade() {
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	s.runtimeState.Action = "upgrade"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal(1, s.release().Version, "an upgrade without changes is skipped")
+
+	s.config.Proxy.HTTPSProxy = "http://proxy:912"
+	s.runtimeState.DryRun = true
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal(1, s.release().Version, "a dry run only reports the changes")
+
+	s.runtimeState.DryRun = false
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal(2, s.release().Version)
+	s.Contains(s.release().Manifest, "proxy:912")
+}
+
+func (s *ArgoCDStepTest) TestUpgradeDoesNotLogSecrets() {
+	s.step.LoadChart = func(ctx context.Context) (*chart.Chart, error) {
+		return &chart.Chart{
+			Metadata:  &chart.Metadata{APIVersion: chart.APIVersionV2, Name: "argo-cd", Version: "8.0.0"},
+			Templates: []*chart.File{{Name: "templates/secret.yaml", Data: []byte(secretTemplate)}},
+		}, nil
+	}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	core, logs := observer.New(zap.InfoLevel)
+	defer zap.ReplaceGlobals(zap.L())
+	zap.ReplaceGlobals(zap.New(core))
+	s.config.Proxy.HTTPSProxy = "http://proxy:912"
+	s.runtimeState.Action = "upgrade"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	changes := logs.FilterMessageSnippet("changes:").All()
+	s.Require().Len(changes, 1)
+	s.Contains(changes[0].Message, "~ Secret/argocd-secret")
+	s.NotContains(changes[0].Message, "proxy:912")
+}
+
+func (s *ArgoCDStepTest) TestUninstall() {
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	s.runtimeState.Action = "uninstall"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	_, lastErr := s.releases.Last("argocd")
+	s.ErrorIs(lastErr, driver.ErrReleaseNotFound)
+
+	// Uninstalling again is a no-op
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+}
+
+func (s *ArgoCDStepTest) TestMissingKubeConfig() {
+	s.runtimeState.Onprem.KubeConfig = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
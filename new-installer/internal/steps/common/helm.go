@@ -0,0 +1,133 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/action"
+	"helm.sh/helm/v3/pkg/releaseutil"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/clientcmd"
+	clientcmdapi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// kubeConfigGetter gives Helm access to a cluster from the kubeconfig in the runtime state,
+// without writing it to disk.
+type kubeConfigGetter struct {
+	clientConfig clientcmd.ClientConfig
+}
+
+func (g *kubeConfigGetter) ToRESTConfig() (*rest.Config, error) {
+	return g.clientConfig.ClientConfig()
+}
+
+func (g *kubeConfigGetter) ToDiscoveryClient() (discovery.CachedDiscoveryInterface, error) {
+	restConfig, err := g.ToRESTConfig()
+	if err != nil {
+		return nil, err
+	}
+	client, err := discovery.NewDiscoveryClientForConfig(restConfig)
+	if err != nil {
+		return nil, err
+	}
+	return memory.NewMemCacheClient(client), nil
+}
+
+func (g *kubeConfigGetter) ToRESTMapper() (meta.RESTMapper, error) {
+	client, err := g.ToDiscoveryClient()
+	if err != nil {
+		return nil, err
+	}
+	return restmapper.NewDeferredDiscoveryRESTMapper(client), nil
+}
+
+func (g *kubeConfigGetter) ToRawKubeConfigLoader() clientcmd.ClientConfig {
+	return g.clientConfig
+}
+
+// CreateHelmConfig returns a Helm configuration managing the releases of a namespace, with
+// the release records stored in secrets like the helm CLI does.
+func CreateHelmConfig(kubeConfig, namespace string) (*action.Configuration, error) {
+	rawConfig, err := clientcmd.Load([]byte(kubeConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	getter := &kubeConfigGetter{
+		clientConfig: clientcmd.NewNonInteractiveClientConfig(*rawConfig, rawConfig.CurrentContext, &clientcmd.ConfigOverrides{
+			Context: clientcmdapi.Context{Namespace: namespace},
+		}, nil),
+	}
+	helmConfig := &action.Configuration{}
+	if err := helmConfig.Init(getter, namespace, "secret", func(string, ...any) {}); err != nil {
+		return nil, fmt.Errorf("failed to initialize helm: %w", err)
+	}
+	return helmConfig, nil
+}
+
+// manifestDiff returns the changes between two release manifests, resource by resource, only
+// naming the secrets that change. It is empty when the manifests define the same resources.
+func manifestDiff(current, desired string) string {
+	before, after := manifestResources(current), manifestResources(desired)
+	keys := slices.Sorted(maps.Keys(before))
+	for key := range after {
+		if _, ok := before[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	slices.Sort(keys)
+
+	var diff strings.Builder
+	for _, key := range keys {
+		was, existed := before[key]
+		will, exists := after[key]
+		switch {
+		case !existed:
+			fmt.Fprintf(&diff, "+ %s\n", key)
+		case !exists:
+			fmt.Fprintf(&diff, "- %s\n", key)
+		case was != will:
+			fmt.Fprintf(&diff, "~ %s\n", key)
+			if strings.HasPrefix(key, "Secret/") {
+				// The content of a secret is not logged
+				continue
+			}
+			unified, _ := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+				A:       difflib.SplitLines(was + "\n"),
+				B:       difflib.SplitLines(will + "\n"),
+				Context: 2,
+			})
+			diff.WriteString(unified)
+		}
+	}
+	return diff.String()
+}
+
+// manifestResources splits a manifest into its resources, keyed by kind and name.
+func manifestResources(manifest string) map[string]string {
+	resources := map[string]string{}
+	for _, document := range releaseutil.SplitManifests(manifest) {
+		header := struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name string `yaml:"name"`
+			} `yaml:"metadata"`
+		}{}
+		if err := yaml.Unmarshal([]byte(document), &header); err != nil || header.Kind == "" {
+			continue
+		}
+		resources[header.Kind+"/"+header.Metadata.Name] = strings.TrimSpace(document)
+	}
+	return resources
+}
@@ -5,44 +5,20 @@
 package common
 
 import (
-	"fmt"
-	"os"
-	"os/exec"
-	"os/user"
-	"path/filepath"
-	"strconv"
-	"time"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
 )
 
-func WaitForNamespaceCreation(namespace string) error {
-	for {
-		cmd := exec.Command("kubectl", "get", "ns", namespace, "-o", "jsonpath={.status.phase}")
-		out, err := cmd.Output()
-		if err != nil {
-			return err
-		}
-		if string(out) == "Active" {
-			break
-		}
-		fmt.Printf("%s\n", string(out))
-		fmt.Printf("Waiting for namespace %s to be created...\n", namespace)
-		time.Sleep(5 * time.Second)
-	}
-	return nil
-}
+// ExistingClusterProvider installs the orchestrator on a cluster the installer did not create.
+const ExistingClusterProvider = "existing-cluster"
 
-func chownToCurrentUserRecursive(root string) error {
-	u, err := user.Current()
-	if err != nil {
-		return err
+// ClusterKubeConfig returns the kubeconfig of the cluster created by the provider, or of the
+// existing cluster.
+func ClusterKubeConfig(cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) string {
+	switch cfg.Provider {
+	case "aws":
+		return runtimeState.AWS.KubeConfig
+	case ExistingClusterProvider:
+		return runtimeState.ExistingCluster.KubeConfig
 	}
-	uid, _ := strconv.Atoi(u.Uid)
-	gid, _ := strconv.Atoi(u.Gid)
-
-	return filepath.Walk(root, func(path string, info os.FileInfo, err error) error {
-		if err != nil {
-			return err
-		}
-		return os.Chown(path, uid, gid)
-	})
+	return runtimeState.Onprem.KubeConfig
 }
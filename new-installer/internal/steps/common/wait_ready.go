@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package common
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/readiness"
+	"k8s.io/client-go/dynamic"
+)
+
+var waitForReadyStepLabels = []string{"wait_ready"}
+
+// WaitForReadyStep waits for the orchestrator applications to be synced and healthy, so that
+// an install or upgrade returns once the platform is usable.
+type WaitForReadyStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	CreateClient           func(kubeConfig string) (dynamic.Interface, error)
+	ProgressInterval       time.Duration
+}
+
+func CreateWaitForReadyStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *WaitForReadyStep {
+	return &WaitForReadyStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             waitForReadyStepLabels,
+		CreateClient:           readiness.CreateDynamicClient,
+	}
+}
+
+func (s *WaitForReadyStep) Name() string {
+	return "WaitForReadyStep"
+}
+
+func (s *WaitForReadyStep) Labels() []string {
+	return s.StepLabels
+}
+
+func (s *WaitForReadyStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *WaitForReadyStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *WaitForReadyStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action == "uninstall" {
+		return runtimeState, nil
+	}
+	kubeConfig := ClusterKubeConfig(cfg, runtimeState)
+	if kubeConfig == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot wait for the orchestrator",
+		}
+	}
+	client, err := s.CreateClient(kubeConfig)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
+		}
+	}
+
+	internal.Logger().Info("Waiting for the orchestrator applications to be synced and healthy...")
+	waiter := readiness.NewWaiter(client, readiness.Options{
+		ProgressInterval: s.ProgressInterval,
+		OnProgress:       printProgress,
+		DiagnosticsDir:   runtimeState.LogDir,
+	})
+	if err := waiter.Wait(ctx); err != nil {
+		if errors.Is(err, readiness.ErrAppTimeout) && runtimeState.LogDir != "" {
+			err = fmt.Errorf("%w, see %s/%s", err, runtimeState.LogDir, readiness.DiagnosticsFile)
+		}
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("orchestrator is not ready: %s", err),
+		}
+	}
+	internal.Logger().Info("All applications synced and healthy. Orchestrator is ready")
+	return runtimeState, nil
+}
+
+func (s *WaitForReadyStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+func printProgress(event readiness.Event) {
+	pending := make([]string, 0, len(event.Pending))
+	for _, app := range event.Pending {
+		if app.Since.IsZero() {
+			pending = append(pending, app.Name+" (not started)")
+			continue
+		}
+		pending = append(pending, fmt.Sprintf("%s (%s/%s, %s)", app.Name, app.Sync, app.Health,
+			event.Time.Sub(app.Since).Truncate(time.Second)))
+	}
+	if len(pending) == 0 {
+		internal.Logger().Infof("%d/%d applications ready", event.Ready, event.Total)
+		return
+	}
+	internal.Logger().Infof("%d/%d applications ready, waiting for %s", event.Ready, event.Total, strings.Join(pending, ", "))
+}
@@ -9,21 +9,32 @@ import (
 	"fmt"
 	"net/url"
 	"os"
-	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
-	"oras.land/oras-go/v2"
-	"oras.land/oras-go/v2/content/file"
-	"oras.land/oras-go/v2/registry/remote"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/retry"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"golang.org/x/sync/errgroup"
+	"oras.land/oras-go/v2/content/oci"
 )
 
 const (
-	RS_URL             = "registry-rs.edgeorchestration.intel.com"
-	INSTALLERS_RS_PATH = "edge-orch/common/files"
-	ARCHIVES_RS_PATH   = "edge-orch/common/files/orchestrator"
-	ORCH_VERSION       = "3.1.0-dev-eca1939"
-	INSTALLERS_DIR     = "/tmp/installers"
+	// Defaults of the artifacts section of the config
+	DefaultRegistry       = "registry-rs.edgeorchestration.intel.com"
+	DefaultInstallersPath = "edge-orch/common/files"
+	DefaultArchivesPath   = "edge-orch/common/files/orchestrator"
+	DefaultWorkDir        = "/tmp"
+
+	// Artifacts fetched at the same time
+	defaultDownloadWorkers = 4
+
+	// The release to install, in the root of the installer
+	versionFile = "VERSION"
 
 	rke2Version        = "v1.30.10+rke2r1"
 	rke2Binary         = "rke2.linux-amd64.tar.gz"
@@ -39,11 +50,250 @@ var installerList = []string{
 	"onprem-orch-installer",
 }
 
+var archiveList = []string{
+	deploymentRepoName,
+}
+
+// RKE2 files listed in rke2LibSHAFile
+var rke2VerifiedFiles = []string{rke2Binary, rke2ImagesPkg, rke2CalicoImagePkg}
+
+// onpremArtifact is an artifact and the directory the steps expect it in.
+type onpremArtifact struct {
+	artifacts.Artifact
+	dir string
+}
+
+// ResolveArtifactLocations resolves where the artifacts of the release are pulled from and
+// fetched to. The release is read from the VERSION file in rootPath unless the config sets one.
+func ResolveArtifactLocations(rootPath string, cfg config.OrchInstallerConfig) (config.ArtifactLocations, error) {
+	version := cfg.Artifacts.Version
+	if version == "" {
+		data, err := os.ReadFile(filepath.Join(rootPath, versionFile))
+		if err != nil {
+			return config.ArtifactLocations{}, fmt.Errorf("failed to read the installer version: %w", err)
+		}
+		version = strings.TrimSpace(string(data))
+	}
+	if version == "" {
+		return config.ArtifactLocations{}, fmt.Errorf("the installer version is empty")
+	}
+	workDir := valueOrDefault(cfg.Artifacts.WorkDir, DefaultWorkDir)
+	installersDir := filepath.Join(workDir, "installers")
+	return config.ArtifactLocations{
+		OrchVersion:    version,
+		RKE2Version:    rke2Version,
+		Registry:       valueOrDefault(cfg.Artifacts.Registry, DefaultRegistry),
+		InstallersPath: strings.Trim(valueOrDefault(cfg.Artifacts.InstallersPath, DefaultInstallersPath), "/"),
+		ArchivesPath:   strings.Trim(valueOrDefault(cfg.Artifacts.ArchivesPath, DefaultArchivesPath), "/"),
+		InstallersDir:  installersDir,
+		ArchivesDir:    filepath.Join(workDir, "archives"),
+		ChartsDir:      filepath.Join(workDir, "charts"),
+		// Images exported from a bundle, imported by RKE2 when it starts
+		ImagesDir: filepath.Join(installersDir, "images"),
+		CacheDir:  filepath.Join(workDir, "cache"),
+	}, nil
+}
+
+// artifactLocations returns the locations resolved by the ArtifactDownloader, or resolves them
+// when the step runs without it.
+func artifactLocations(rootPath string, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.ArtifactLocations, *internal.OrchInstallerError) {
+	if runtimeState.Onprem.Artifacts.OrchVersion != "" {
+		return runtimeState.Onprem.Artifacts, nil
+	}
+	locations, err := ResolveArtifactLocations(rootPath, cfg)
+	if err != nil {
+		return locations, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	return locations, nil
+}
+
+func valueOrDefault(value, defaultValue string) string {
+	if value == "" {
+		return defaultValue
+	}
+	return value
+}
+
+func onpremArtifacts(locations config.ArtifactLocations) []onpremArtifact {
+	list := []onpremArtifact{}
+	for _, installer := range installerList {
+		list = append(list, onpremArtifact{artifacts.Artifact{
+			Kind:       artifacts.KindInstaller,
+			Name:       installer,
+			Repository: locations.InstallersPath + "/" + installer,
+			Tag:        locations.OrchVersion,
+		}, locations.InstallersDir})
+	}
+	for _, archive := range archiveList {
+		list = append(list, onpremArtifact{artifacts.Artifact{
+			Kind:       artifacts.KindArchive,
+			Name:       archive,
+			Repository: locations.ArchivesPath + "/" + archive,
+			Tag:        locations.OrchVersion,
+		}, locations.ArchivesDir})
+	}
+	// Registry tags cannot hold the + of RKE2 versions
+	rke2Tag := strings.ReplaceAll(locations.RKE2Version, "+", "-")
+	for _, rke2File := range rke2VerifiedFiles {
+		list = append(list, onpremArtifact{artifacts.Artifact{
+			Kind:         artifacts.KindFile,
+			Name:         rke2File,
+			Repository:   "rke2/" + rke2File,
+			Tag:          rke2Tag,
+			URL:          fmt.Sprintf("%s/%s/%s", rke2ImagesUrl, url.QueryEscape(locations.RKE2Version), rke2File),
+			ChecksumFile: rke2LibSHAFile,
+		}, locations.InstallersDir})
+	}
+	list = append(list,
+		onpremArtifact{artifacts.Artifact{
+			Kind:       artifacts.KindFile,
+			Name:       rke2LibSHAFile,
+			Repository: "rke2/" + rke2LibSHAFile,
+			Tag:        rke2Tag,
+			URL:        fmt.Sprintf("%s/%s/%s", rke2ImagesUrl, url.QueryEscape(locations.RKE2Version), rke2LibSHAFile),
+			Checksums:  true,
+		}, locations.InstallersDir},
+		onpremArtifact{artifacts.Artifact{
+			Kind:            artifacts.KindFile,
+			Name:            common.ArgoCDChartArchive,
+			Repository:      "charts/argo-cd",
+			Tag:             common.ArgoCDChartVersion,
+			URL:             common.ArgoCDChartURL,
+			ChartRepository: common.ArgoCDChartRepo,
+		}, locations.ChartsDir},
+		onpremArtifact{artifacts.Artifact{
+			Kind:            artifacts.KindFile,
+			Name:            giteaChartArchive,
+			Repository:      "charts/gitea",
+			Tag:             giteaChartVersion,
+			URL:             giteaChartRepo + giteaChartArchive,
+			ChartRepository: giteaChartRepo,
+		}, locations.ChartsDir},
+	)
+	return list
+}
+
+// verifyChecksumFiles checks the fetched files listed in a checksum file against it.
+func verifyChecksumFiles(list []onpremArtifact) error {
+	files := map[string][]string{}
+	for _, artifact := range list {
+		if artifact.ChecksumFile != "" {
+			checksumFile := filepath.Join(artifact.dir, artifact.ChecksumFile)
+			files[checksumFile] = append(files[checksumFile], filepath.Join(artifact.dir, artifact.Name))
+		}
+	}
+	for checksumFile, listed := range files {
+		if err := artifacts.VerifyChecksums(checksumFile, listed...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BundleArtifacts returns the artifacts of an on-prem install, that a bundle must contain
+// besides the images.
+func BundleArtifacts(locations config.ArtifactLocations) []artifacts.Artifact {
+	list := []artifacts.Artifact{}
+	for _, artifact := range onpremArtifacts(locations) {
+		list = append(list, artifact.Artifact)
+	}
+	return list
+}
+
+// CreateArtifactSource returns the source selected in the config: a bundle, a mirror
+// registry or the release service. Artifacts from a registry must be signed with the
+// signature public key of the config.
+func CreateArtifactSource(ctx context.Context, cfg config.OrchInstallerConfig, locations config.ArtifactLocations) (artifacts.Source, error) {
+	if cfg.Artifacts.Bundle != "" {
+		publicKey, err := artifacts.ReadPublicKey(cfg.Artifacts.BundlePublicKey)
+		if err != nil {
+			return nil, err
+		}
+		return artifacts.OpenBundle(ctx, cfg.Artifacts.Bundle, publicKey)
+	}
+	if cfg.Artifacts.SignaturePublicKey == "" {
+		return nil, fmt.Errorf("a signature public key is required to verify the artifacts")
+	}
+	publicKey, err := artifacts.ReadSignaturePublicKey(cfg.Artifacts.SignaturePublicKey)
+	if err != nil {
+		return nil, err
+	}
+	verifier := &artifacts.CosignVerifier{PublicKey: publicKey}
+	retryOptions := retry.Options{
+		OnRetry: func(attempt int, err error, delay time.Duration) {
+			internal.Logger().Warnf("Attempt %d failed, retrying in %s: %s", attempt, delay, err)
+		},
+	}
+	cache, err := openCache(locations.CacheDir)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Artifacts.MirrorRegistry != "" {
+		return &artifacts.Registry{
+			Host:      cfg.Artifacts.MirrorRegistry,
+			PlainHTTP: cfg.Artifacts.MirrorPlainHTTP,
+			Username:  cfg.Artifacts.Username,
+			Password:  cfg.Artifacts.Password,
+			Verifier:  verifier,
+			Cache:     cache,
+			Retry:     retryOptions,
+		}, nil
+	}
+	return &artifacts.ReleaseService{
+		Registry: locations.Registry,
+		Username: cfg.Artifacts.Username,
+		Password: cfg.Artifacts.Password,
+		Verifier: verifier,
+		Downloader: &artifacts.Downloader{
+			CacheDir:   filepath.Join(locations.CacheDir, "files"),
+			Retry:      retryOptions,
+			OnProgress: logProgress,
+		},
+		Cache: cache,
+	}, nil
+}
+
+// openCache opens the OCI layout the registry artifacts are cached in.
+func openCache(cacheDir string) (*oci.Store, error) {
+	if cacheDir == "" {
+		return nil, nil
+	}
+	cache, err := oci.New(filepath.Join(cacheDir, "oci"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to open the artifact cache: %w", err)
+	}
+	return cache, nil
+}
+
+func logProgress(progress artifacts.Progress) {
+	logger := internal.Logger()
+	switch {
+	case progress.Cached:
+		logger.Infof("%s found in the cache", progress.Name)
+	case progress.Completed:
+		logger.Infof("%s downloaded (%d bytes)", progress.Name, progress.Done)
+	case progress.Total > 0:
+		logger.Infof("Downloading %s: %d%% (%d/%d bytes)", progress.Name, progress.Done*100/progress.Total, progress.Done, progress.Total)
+	default:
+		logger.Infof("Downloading %s: %d bytes", progress.Name, progress.Done)
+	}
+}
+
+var artifactDownloaderStepLabels = []string{"artifacts"}
+
 type ArtifactDownloader struct {
 	RootPath               string
 	KeepGeneratedFiles     bool
 	orchConfigReaderWriter config.OrchConfigReaderWriter
 	StepLabels             []string
+	// Artifacts fetched at the same time, defaults to 4
+	Workers      int
+	CreateSource func(ctx context.Context, cfg config.OrchInstallerConfig, locations config.ArtifactLocations) (artifacts.Source, error)
+	// Only fetch the deployment repo and the charts, for a cluster the installer does not create
+	OrchestratorOnly bool
 }
 
 func CreateArtifactDownloader(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *ArtifactDownloader {
@@ -51,6 +301,8 @@ func CreateArtifactDownloader(rootPath string, keepGeneratedFiles bool, orchConf
 		RootPath:               rootPath,
 		KeepGeneratedFiles:     keepGeneratedFiles,
 		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             artifactDownloaderStepLabels,
+		CreateSource:           CreateArtifactSource,
 	}
 }
 
@@ -62,7 +314,17 @@ func (s *ArtifactDownloader) Labels() []string {
 	return s.StepLabels
 }
 
-func (s *ArtifactDownloader) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+// ConfigStep resolves the artifact locations for this run, so that a changed config or
+// VERSION file takes effect.
+func (s *ArtifactDownloader) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	locations, err := ResolveArtifactLocations(s.RootPath, cfg)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	runtimeState.Onprem.Artifacts = locations
 	return runtimeState, nil
 }
 
@@ -71,98 +333,81 @@ func (s *ArtifactDownloader) PreStep(ctx context.Context, config config.OrchInst
 	return runtimeState, nil
 }
 
-func (s *ArtifactDownloader) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
-	if runtimeState.Action == "install" {
-		fmt.Println("Running ArtifactDownloader step")
+func (s *ArtifactDownloader) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action != "install" {
+		return runtimeState, nil
+	}
+	internal.Logger().Info("Running ArtifactDownloader step")
+	locations := runtimeState.Onprem.Artifacts
+	internal.Logger().Infof("Fetching the artifacts of release %s", locations.OrchVersion)
+	source, err := s.CreateSource(ctx, cfg, locations)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("failed to open the artifact source: %s", err),
+		}
+	}
 
-		// Create directories for installers and archives
-		if err := os.MkdirAll(INSTALLERS_DIR, 0o755); err != nil {
+	list := onpremArtifacts(locations)
+	if s.OrchestratorOnly {
+		list = slices.DeleteFunc(list, func(artifact onpremArtifact) bool {
+			return artifact.dir != locations.ArchivesDir && artifact.dir != locations.ChartsDir
+		})
+	}
+	for _, artifact := range list {
+		if err := os.MkdirAll(artifact.dir, 0o755); err != nil {
 			return runtimeState, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("failed to create installers directory %s: %s", INSTALLERS_DIR, err),
+				ErrorMsg:  fmt.Sprintf("failed to create directory %s: %s", artifact.dir, err),
 			}
 		}
-
-		fmt.Println("Created directories for installers")
-
-		if err := downloadRKE2Images(ctx, INSTALLERS_DIR); err != nil {
-			return runtimeState, &internal.OrchInstallerError{
-				ErrorMsg:  fmt.Sprintf("failed to download RKE2 artifacts: %s", err),
-				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+	}
+	workers := s.Workers
+	if workers <= 0 {
+		workers = defaultDownloadWorkers
+	}
+	group, groupCtx := errgroup.WithContext(ctx)
+	group.SetLimit(workers)
+	for _, artifact := range list {
+		group.Go(func() error {
+			internal.Logger().Infof("Fetching %s %s", artifact.Kind, artifact.Name)
+			if err := source.Fetch(groupCtx, artifact.Artifact, artifact.dir); err != nil {
+				return fmt.Errorf("failed to fetch %s: %w", artifact.Name, err)
 			}
+			return nil
+		})
+	}
+	if err := group.Wait(); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  err.Error(),
 		}
+	}
+	if s.OrchestratorOnly {
+		internal.Logger().Info("Artifacts fetched successfully")
+		return runtimeState, nil
+	}
+	if err := verifyChecksumFiles(list); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("refusing to install RKE2: %s", err),
+		}
+	}
+	internal.Logger().Info("Artifacts fetched and verified successfully")
 
-		fmt.Println("RKE2 images and install script downloaded successfully")
-
-		if err := downloadArtifacts(ctx, RS_URL, INSTALLERS_RS_PATH, ORCH_VERSION, INSTALLERS_DIR, installerList); err != nil {
+	if bundle, ok := source.(*artifacts.Bundle); ok {
+		images, err := bundle.ExportImages(ctx, locations.ImagesDir)
+		if err != nil {
 			return runtimeState, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeInternal,
-				ErrorMsg:  fmt.Sprintf("failed to download installers: %s", err),
+				ErrorMsg:  fmt.Sprintf("failed to export the images of the bundle: %s", err),
 			}
 		}
-
-		fmt.Println("Downloaded installers successfully")
+		internal.Logger().Infof("Exported %d images from the bundle", len(images))
 	}
-
 	return runtimeState, nil
 }
 
 func (s *ArtifactDownloader) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	return runtimeState, prevStepError
 }
-
-func downloadArtifacts(ctx context.Context, registryUrl, registryPath, orchVersion, artifactDir string, artifactList []string) error {
-	fileStore, err := file.New(artifactDir)
-	if err != nil {
-		return fmt.Errorf("failed to create file store: %w", err)
-	}
-	defer fileStore.Close()
-
-	for _, artifact := range artifactList {
-		fmt.Println("downloading artifact: " + artifact)
-		repo, err := remote.NewRepository(registryUrl + "/" + registryPath + "/" + artifact)
-		if err != nil {
-			return fmt.Errorf("failed to create repository for %s: %w", artifact, err)
-		}
-
-		manifestDescriptor, err := oras.Copy(ctx, repo, orchVersion, fileStore, orchVersion, oras.DefaultCopyOptions)
-		if err != nil {
-			return fmt.Errorf("failed to copy artifact %s: %w", artifact, err)
-		}
-		fmt.Println("manifest descriptor:", manifestDescriptor)
-	}
-	return nil
-}
-
-func downloadRKE2Images(ctx context.Context, artifactDir string) error {
-	fmt.Println("Downloading RKE2 images and install script...")
-	rke2VersionEscaped := url.QueryEscape(rke2Version)
-
-	// Download the RKE2 images and binaries
-	for _, image := range []string{
-		rke2Binary,
-		rke2ImagesPkg,
-		rke2CalicoImagePkg,
-		rke2LibSHAFile,
-	} {
-		rke2DownloadURL := fmt.Sprintf("%s/%s/%s", rke2ImagesUrl, rke2VersionEscaped, image)
-		fmt.Printf("Downloading RKE2 from %s\n", rke2DownloadURL)
-		cmd := exec.CommandContext(ctx, "curl", "-L", rke2DownloadURL, "-o", fmt.Sprintf("%s/%s", artifactDir, image))
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-		if err := cmd.Run(); err != nil {
-			return fmt.Errorf("failed to download RKE2 image %s: %w", image, err)
-		}
-	}
-
-	// Download the RKE2 install script
-	fmt.Printf("Downloading RKE2 install script from %s\n", rke2InstallerUrl)
-	cmd := exec.CommandContext(ctx, "curl", "-sfL", rke2InstallerUrl, "-o", fmt.Sprintf("%s/%s", artifactDir, "install.sh"))
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to download install script: %w", err)
-	}
-
-	return nil
-}
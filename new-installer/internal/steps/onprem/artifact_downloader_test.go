@@ -0,0 +1,166 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/artifacts"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+// fakeSource writes every artifact as a file holding its name, and a checksum file of the
+// RKE2 tarballs.
+type fakeSource struct {
+	mutex     sync.Mutex
+	fetched   map[string]artifacts.Artifact
+	corrupted string
+}
+
+func (f *fakeSource) Fetch(ctx context.Context, artifact artifacts.Artifact, dir string) error {
+	f.mutex.Lock()
+	f.fetched[artifact.Name] = artifact
+	f.mutex.Unlock()
+	data := []byte(artifact.Name)
+	if artifact.Name == "sha256sum-amd64.txt" {
+		data = []byte{}
+		for _, name := range []string{"rke2.linux-amd64.tar.gz", "rke2-images.linux-amd64.tar.zst", "rke2-images-calico.linux-amd64.tar.zst"} {
+			digest := sha256.Sum256([]byte(name))
+			data = append(data, fmt.Sprintf("%s  %s\n", hex.EncodeToString(digest[:]), name)...)
+		}
+	} else if artifact.Name == f.corrupted {
+		data = []byte("corrupted")
+	}
+	return os.WriteFile(filepath.Join(dir, artifact.Name), data, 0o600)
+}
+
+type ArtifactDownloaderTest struct {
+	suite.Suite
+	rootPath     string
+	workDir      string
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	source       *fakeSource
+	step         *onprem.ArtifactDownloader
+}
+
+func TestArtifactDownloader(t *testing.T) {
+	suite.Run(t, new(ArtifactDownloaderTest))
+}
+
+func (s *ArtifactDownloaderTest) SetupTest() {
+	s.rootPath = s.T().TempDir()
+	s.workDir = s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(s.rootPath, "VERSION"), []byte("3.1.0\n"), 0o600))
+	s.config = config.OrchInstallerConfig{}
+	s.config.Artifacts.WorkDir = s.workDir
+	s.runtimeState = config.OrchInstallerRuntimeState{Action: "install"}
+	s.source = &fakeSource{fetched: map[string]artifacts.Artifact{}}
+	s.step = onprem.CreateArtifactDownloader(s.rootPath, false, nil)
+	s.step.CreateSource = func(ctx context.Context, cfg config.OrchInstallerConfig, locations config.ArtifactLocations) (artifacts.Source, error) {
+		return s.source, nil
+	}
+}
+
+func (s *ArtifactDownloaderTest) TestResolveDefaults() {
+	locations, err := onprem.ResolveArtifactLocations(s.rootPath, config.OrchInstallerConfig{})
+	s.Require().NoError(err)
+	s.Equal("3.1.0", locations.OrchVersion)
+	s.Equal(onprem.DefaultRegistry, locations.Registry)
+	s.Equal(onprem.DefaultInstallersPath, locations.InstallersPath)
+	s.Equal(onprem.DefaultArchivesPath, locations.ArchivesPath)
+	s.Equal("/tmp/installers", locations.InstallersDir)
+	s.Equal("/tmp/archives", locations.ArchivesDir)
+	s.Equal("/tmp/charts", locations.ChartsDir)
+	s.Equal("/tmp/installers/images", locations.ImagesDir)
+	s.Equal("/tmp/cache", locations.CacheDir)
+}
+
+func (s *ArtifactDownloaderTest) TestResolveConfig() {
+	cfg := config.OrchInstallerConfig{}
+	cfg.Artifacts.Version = "3.1.0-rc1"
+	cfg.Artifacts.Registry = "registry.example.com:5000"
+	cfg.Artifacts.InstallersPath = "/rc/files/"
+	cfg.Artifacts.WorkDir = "/var/lib/orch"
+	// The VERSION file is not needed when the config sets the version
+	locations, err := onprem.ResolveArtifactLocations(s.T().TempDir(), cfg)
+	s.Require().NoError(err)
+	s.Equal("3.1.0-rc1", locations.OrchVersion)
+	s.Equal("registry.example.com:5000", locations.Registry)
+	s.Equal("rc/files", locations.InstallersPath)
+	s.Equal(onprem.DefaultArchivesPath, locations.ArchivesPath)
+	s.Equal("/var/lib/orch/installers", locations.InstallersDir)
+}
+
+func (s *ArtifactDownloaderTest) TestMissingVersion() {
+	_, err := onprem.ResolveArtifactLocations(s.T().TempDir(), config.OrchInstallerConfig{})
+	s.ErrorContains(err, "installer version")
+}
+
+func (s *ArtifactDownloaderTest) TestDownload() {
+	s.config.Artifacts.InstallersPath = "rc/files"
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	locations := rs.Onprem.Artifacts
+	s.Equal("3.1.0", locations.OrchVersion)
+	s.Equal(filepath.Join(s.workDir, "installers"), locations.InstallersDir)
+	s.FileExists(filepath.Join(s.workDir, "installers", "onprem-ke-installer"))
+	s.FileExists(filepath.Join(s.workDir, "installers", "rke2.linux-amd64.tar.gz"))
+	s.FileExists(filepath.Join(s.workDir, "archives", "edge-manageability-framework"))
+	s.FileExists(filepath.Join(s.workDir, "charts", "gitea-10.6.0.tgz"))
+
+	s.Require().Contains(s.source.fetched, "onprem-ke-installer")
+	s.Equal("rc/files/onprem-ke-installer", s.source.fetched["onprem-ke-installer"].Repository)
+	s.Equal("3.1.0", s.source.fetched["onprem-ke-installer"].Tag)
+}
+
+// Every artifact of an install must be verified, by a signature, a digest or a checksum file
+func (s *ArtifactDownloaderTest) TestArtifactsVerified() {
+	locations, err := onprem.ResolveArtifactLocations(s.rootPath, s.config)
+	s.Require().NoError(err)
+	list := onprem.BundleArtifacts(locations)
+	checksumFiles := map[string]bool{}
+	for _, artifact := range list {
+		if artifact.Verification() == artifacts.VerificationChecksums {
+			checksumFiles[artifact.Name] = true
+		}
+	}
+	for _, artifact := range list {
+		s.NotEmpty(artifact.Verification(), "%s %s has no verification", artifact.Kind, artifact.Name)
+		if artifact.Verification() == artifacts.VerificationChecksumFile {
+			s.True(checksumFiles[artifact.ChecksumFile], "the checksum file of %s is not fetched", artifact.Name)
+		}
+	}
+}
+
+func (s *ArtifactDownloaderTest) TestChecksumMismatch() {
+	s.source.corrupted = "rke2-images.linux-amd64.tar.zst"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "refusing to install RKE2")
+}
+
+func (s *ArtifactDownloaderTest) TestOrchestratorOnly() {
+	s.step.OrchestratorOnly = true
+	s.source.corrupted = "rke2-images.linux-amd64.tar.zst"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	s.FileExists(filepath.Join(s.workDir, "archives", "edge-manageability-framework"))
+	s.FileExists(filepath.Join(s.workDir, "charts", "gitea-10.6.0.tgz"))
+	s.NotContains(s.source.fetched, "onprem-ke-installer")
+	s.NotContains(s.source.fetched, "rke2-images.linux-amd64.tar.zst")
+	s.NoDirExists(filepath.Join(s.workDir, "installers"))
+}
@@ -0,0 +1,367 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/version"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+const (
+	ClusterCheckReportFile = "cluster-check-report.json"
+
+	// Oldest Kubernetes version the orchestrator runs on, and the newest one it was tested with
+	minKubernetesVersion    = "1.28"
+	testedKubernetesVersion = "1.30"
+
+	defaultLoadBalancerTimeout = 3 * time.Minute
+	loadBalancerPollInterval   = 2 * time.Second
+	// Service created to find out whether the cluster provisions load balancers
+	loadBalancerCheckService   = "orch-installer-lb-check"
+	loadBalancerCheckNamespace = "default"
+)
+
+type clusterCheck struct {
+	name string
+	run  func(ctx context.Context, cfg config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult
+}
+
+var clusterCheckStepLabels = []string{"cluster_check"}
+
+// ClusterCheckStep checks that an existing cluster can run the orchestrator, like the
+// PreflightStep checks the host of an on-prem install.
+type ClusterCheckStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	// Replaced in tests
+	CreateClient func(kubeConfig string) (kubernetes.Interface, error)
+	// How long the cluster has to assign an address to a LoadBalancer service, defaults to 3 minutes
+	LoadBalancerTimeout time.Duration
+}
+
+func CreateClusterCheckStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *ClusterCheckStep {
+	return &ClusterCheckStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             clusterCheckStepLabels,
+		CreateClient:           createClusterClient,
+	}
+}
+
+func createClusterClient(kubeConfig string) (kubernetes.Interface, error) {
+	restConfig, err := clientcmd.RESTConfigFromKubeConfig([]byte(kubeConfig))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+	return kubernetes.NewForConfig(restConfig)
+}
+
+// Checks run in this order, ClusterCheckNames lists them for existingCluster.ignoreChecks.
+func (s *ClusterCheckStep) checks() []clusterCheck {
+	return []clusterCheck{
+		{name: "kubernetes-version", run: checkKubernetesVersion},
+		{name: "storage-class", run: checkStorageClass},
+		{name: "load-balancer", run: s.checkLoadBalancer},
+		{name: "cpu", run: checkClusterCPU},
+		{name: "memory", run: checkClusterMemory},
+	}
+}
+
+func ClusterCheckNames() []string {
+	checks := (&ClusterCheckStep{}).checks()
+	names := make([]string, len(checks))
+	for i, check := range checks {
+		names[i] = check.name
+	}
+	return names
+}
+
+func (s *ClusterCheckStep) Name() string {
+	return "ClusterCheckStep"
+}
+
+func (s *ClusterCheckStep) Labels() []string {
+	return s.StepLabels
+}
+
+func (s *ClusterCheckStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	names := ClusterCheckNames()
+	for _, name := range cfg.ExistingCluster.IgnoreChecks {
+		if !slices.Contains(names, name) {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("unknown cluster check %s in existingCluster.ignoreChecks, must be one of %s", name, strings.Join(names, ", ")),
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			}
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *ClusterCheckStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+// RunStep checks the cluster before an install or upgrade and blocks it if any check fails.
+// The cluster is managed by its owner, it may have been upgraded or resized since the install.
+func (s *ClusterCheckStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action != "install" && runtimeState.Action != "upgrade" {
+		return runtimeState, nil
+	}
+	if runtimeState.ExistingCluster.KubeConfig == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot check the cluster",
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	client, err := s.CreateClient(runtimeState.ExistingCluster.KubeConfig)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to create the client of the cluster: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+		}
+	}
+	report := s.RunClusterChecks(ctx, cfg, client)
+
+	internal.Logger().Info("Cluster checks:")
+	for _, check := range report.Checks {
+		message := check.Message
+		if check.Ignored {
+			message += " (ignored)"
+		}
+		internal.Logger().Infof("  [%s] %-18s %s", strings.ToUpper(string(check.Status)), check.Name, message)
+	}
+	if runtimeState.LogDir != "" {
+		if err := writePreflightReport(filepath.Join(runtimeState.LogDir, ClusterCheckReportFile), report); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  err.Error(),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+	}
+	if !report.Passed {
+		failed := []string{}
+		for _, check := range report.Checks {
+			if check.Status == PreflightFail {
+				failed = append(failed, check.Name)
+			}
+		}
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg: fmt.Sprintf("cluster checks failed: %s. Fix the cluster or add the checks to existingCluster.ignoreChecks to proceed anyway",
+				strings.Join(failed, ", ")),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *ClusterCheckStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+// RunClusterChecks runs every check against the cluster. Failures of the checks listed in
+// existingCluster.ignoreChecks are reported as warnings.
+func (s *ClusterCheckStep) RunClusterChecks(ctx context.Context, cfg config.OrchInstallerConfig, client kubernetes.Interface) PreflightReport {
+	report := PreflightReport{
+		Scale:  cfg.Global.Scale,
+		Passed: true,
+	}
+	for _, check := range s.checks() {
+		result := check.run(ctx, cfg, client)
+		result.Name = check.name
+		if result.Status == PreflightFail && slices.Contains(cfg.ExistingCluster.IgnoreChecks, check.name) {
+			result.Status = PreflightWarn
+			result.Ignored = true
+		}
+		if result.Status == PreflightFail {
+			report.Passed = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func checkKubernetesVersion(_ context.Context, _ config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult {
+	info, err := client.Discovery().ServerVersion()
+	if err != nil {
+		return checkResult(PreflightFail, "failed to get the server version: %s", err)
+	}
+	serverVersion, err := version.ParseGeneric(info.GitVersion)
+	if err != nil {
+		return checkResult(PreflightFail, "unknown server version %s", info.GitVersion)
+	}
+	if serverVersion.LessThan(version.MustParseGeneric(minKubernetesVersion)) {
+		return checkResult(PreflightFail, "Kubernetes %s, %s or later required", info.GitVersion, minKubernetesVersion)
+	}
+	if version.MajorMinor(serverVersion.Major(), serverVersion.Minor()).GreaterThan(version.MustParseGeneric(testedKubernetesVersion)) {
+		return checkResult(PreflightWarn, "Kubernetes %s, newer than %s the orchestrator was tested with", info.GitVersion, testedKubernetesVersion)
+	}
+	return checkResult(PreflightPass, "Kubernetes %s", info.GitVersion)
+}
+
+func checkStorageClass(ctx context.Context, cfg config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult {
+	if name := cfg.ExistingCluster.StorageClass; name != "" {
+		if _, err := client.StorageV1().StorageClasses().Get(ctx, name, metav1.GetOptions{}); err != nil {
+			return checkResult(PreflightFail, "storage class %s: %s", name, err)
+		}
+		return checkResult(PreflightPass, "storage class %s", name)
+	}
+	classes, err := client.StorageV1().StorageClasses().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return checkResult(PreflightFail, "failed to list the storage classes: %s", err)
+	}
+	for _, class := range classes.Items {
+		if isDefaultStorageClass(class) {
+			return checkResult(PreflightPass, "default storage class %s", class.Name)
+		}
+	}
+	return checkResult(PreflightFail, "no default storage class, set existingCluster.storageClass")
+}
+
+func isDefaultStorageClass(class storagev1.StorageClass) bool {
+	for _, annotation := range []string{
+		"storageclass.kubernetes.io/is-default-class",
+		"storageclass.beta.kubernetes.io/is-default-class",
+	} {
+		if class.Annotations[annotation] == "true" {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLoadBalancer creates a LoadBalancer service and waits for the cluster to assign it an
+// address, that ArgoCD and the orchestrator ingress need.
+func (s *ClusterCheckStep) checkLoadBalancer(ctx context.Context, _ config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult {
+	timeout := s.LoadBalancerTimeout
+	if timeout <= 0 {
+		timeout = defaultLoadBalancerTimeout
+	}
+	services := client.CoreV1().Services(loadBalancerCheckNamespace)
+	service := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: loadBalancerCheckService},
+		Spec: corev1.ServiceSpec{
+			Type: corev1.ServiceTypeLoadBalancer,
+			// Selects no pod, only the address matters
+			Selector: map[string]string{"app.kubernetes.io/name": loadBalancerCheckService},
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 80, TargetPort: intstr.FromInt32(80)}},
+		},
+	}
+	if _, err := services.Create(ctx, service, metav1.CreateOptions{}); err != nil {
+		return checkResult(PreflightFail, "failed to create a LoadBalancer service: %s", err)
+	}
+	defer func() {
+		// The service must not outlive a cancelled install
+		if err := services.Delete(context.WithoutCancel(ctx), loadBalancerCheckService, metav1.DeleteOptions{}); err != nil {
+			internal.Logger().Warnf("failed to delete service %s/%s: %s", loadBalancerCheckNamespace, loadBalancerCheckService, err)
+		}
+	}()
+
+	address := ""
+	err := wait.PollUntilContextTimeout(ctx, loadBalancerPollInterval, timeout, true, func(ctx context.Context) (bool, error) {
+		service, err := services.Get(ctx, loadBalancerCheckService, metav1.GetOptions{})
+		if err != nil {
+			return false, err
+		}
+		for _, ingress := range service.Status.LoadBalancer.Ingress {
+			address = ingress.IP
+			if address == "" {
+				address = ingress.Hostname
+			}
+			if address != "" {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil {
+		if wait.Interrupted(err) {
+			return checkResult(PreflightFail, "no address assigned to a LoadBalancer service within %s", timeout)
+		}
+		return checkResult(PreflightFail, "%s", err)
+	}
+	return checkResult(PreflightPass, "LoadBalancer service got address %s", address)
+}
+
+// allocatable returns the resources the pods can use on the nodes that accept them.
+func allocatable(ctx context.Context, client kubernetes.Interface, name corev1.ResourceName) (resource.Quantity, int, error) {
+	total := resource.Quantity{}
+	nodes, err := client.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return total, 0, fmt.Errorf("failed to list the nodes: %w", err)
+	}
+	count := 0
+	for _, node := range nodes.Items {
+		if !nodeSchedulable(node) {
+			continue
+		}
+		total.Add(node.Status.Allocatable[name])
+		count++
+	}
+	return total, count, nil
+}
+
+func nodeSchedulable(node corev1.Node) bool {
+	if node.Spec.Unschedulable {
+		return false
+	}
+	for _, taint := range node.Spec.Taints {
+		if taint.Effect == corev1.TaintEffectNoSchedule || taint.Effect == corev1.TaintEffectNoExecute {
+			return false
+		}
+	}
+	for _, condition := range node.Status.Conditions {
+		if condition.Type == corev1.NodeReady {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func checkClusterCPU(ctx context.Context, cfg config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult {
+	required := requirementsFor(cfg.Global.Scale).CPUs
+	cpu, nodes, err := allocatable(ctx, client, corev1.ResourceCPU)
+	if err != nil {
+		return checkResult(PreflightFail, "%s", err)
+	}
+	cpus := float64(cpu.MilliValue()) / 1000
+	// The kubelet reserves part of the CPUs of each node, allow 5% below the requirement
+	if cpu.MilliValue() < int64(required)*1000/100*95 {
+		return checkResult(PreflightFail, "%.1f allocatable CPUs on %d nodes, %d required for scale %d", cpus, nodes, required, cfg.Global.Scale)
+	}
+	return checkResult(PreflightPass, "%.1f allocatable CPUs on %d nodes", cpus, nodes)
+}
+
+func checkClusterMemory(ctx context.Context, cfg config.OrchInstallerConfig, client kubernetes.Interface) PreflightCheckResult {
+	required := requirementsFor(cfg.Global.Scale).MemoryGiB
+	memory, nodes, err := allocatable(ctx, client, corev1.ResourceMemory)
+	if err != nil {
+		return checkResult(PreflightFail, "%s", err)
+	}
+	// The kubelet reserves part of the memory of each node, allow 5% below the requirement
+	if uint64(memory.Value()) < required*gib/100*95 {
+		return checkResult(PreflightFail, "%d GiB of allocatable memory on %d nodes, %d GiB required for scale %d",
+			uint64(memory.Value())/gib, nodes, required, cfg.Global.Scale)
+	}
+	return checkResult(PreflightPass, "%d GiB of allocatable memory on %d nodes", uint64(memory.Value())/gib, nodes)
+}
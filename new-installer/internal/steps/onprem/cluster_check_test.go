@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+type ClusterCheckStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.ClusterCheckStep
+	client       *fake.Clientset
+}
+
+func TestClusterCheckStep(t *testing.T) {
+	suite.Run(t, new(ClusterCheckStepTest))
+}
+
+func clusterNode(name, cpu, memory string, ready bool) *corev1.Node {
+	status := corev1.ConditionTrue
+	if !ready {
+		status = corev1.ConditionFalse
+	}
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Allocatable: corev1.ResourceList{
+				corev1.ResourceCPU:    resource.MustParse(cpu),
+				corev1.ResourceMemory: resource.MustParse(memory),
+			},
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: status}},
+		},
+	}
+}
+
+func (s *ClusterCheckStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{Provider: "existing-cluster"}
+	s.config.Global.Scale = config.Scale50
+	s.runtimeState = config.OrchInstallerRuntimeState{Action: "install", LogDir: s.T().TempDir()}
+	s.runtimeState.ExistingCluster.KubeConfig = "test-kubeconfig"
+
+	s.client = fake.NewSimpleClientset(
+		clusterNode("node-1", "7900m", "31Gi", true),
+		clusterNode("node-2", "7900m", "31Gi", true),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{
+			Name:        "gp3",
+			Annotations: map[string]string{"storageclass.kubernetes.io/is-default-class": "true"},
+		}},
+	)
+	s.client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.30.4-eks-a737599"}
+	// The cloud controller assigns an address to the LoadBalancer services
+	s.client.PrependReactor("get", "services", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		service := &corev1.Service{ObjectMeta: metav1.ObjectMeta{Name: action.(k8stesting.GetAction).GetName()}}
+		service.Status.LoadBalancer.Ingress = []corev1.LoadBalancerIngress{{Hostname: "lb.example.com"}}
+		return true, service, nil
+	})
+
+	s.step = onprem.CreateClusterCheckStep("", false, nil)
+	s.step.CreateClient = func(kubeConfig string) (kubernetes.Interface, error) {
+		s.Equal("test-kubeconfig", kubeConfig)
+		return s.client, nil
+	}
+	s.step.LoadBalancerTimeout = 100 * time.Millisecond
+}
+
+func (s *ClusterCheckStepTest) readReport() onprem.PreflightReport {
+	data, err := os.ReadFile(filepath.Join(s.runtimeState.LogDir, onprem.ClusterCheckReportFile))
+	s.Require().NoError(err)
+	report := onprem.PreflightReport{}
+	s.Require().NoError(json.Unmarshal(data, &report))
+	return report
+}
+
+func (s *ClusterCheckStepTest) statuses(report onprem.PreflightReport) map[string]onprem.PreflightStatus {
+	statuses := map[string]onprem.PreflightStatus{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func (s *ClusterCheckStepTest) TestPassingCluster() {
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	report := s.readReport()
+	s.True(report.Passed)
+	s.Equal(onprem.ClusterCheckNames(), func() []string {
+		names := []string{}
+		for _, check := range report.Checks {
+			names = append(names, check.Name)
+		}
+		return names
+	}())
+	for name, status := range s.statuses(report) {
+		s.Equal(onprem.PreflightPass, status, name)
+	}
+	// The probe service is removed
+	services, listErr := s.client.CoreV1().Services("default").List(s.T().Context(), metav1.ListOptions{})
+	s.Require().NoError(listErr)
+	s.Empty(services.Items)
+}
+
+func (s *ClusterCheckStepTest) TestUnfitCluster() {
+	s.client = fake.NewSimpleClientset(
+		clusterNode("node-1", "8", "32Gi", true),
+		// Not ready and tainted nodes do not count
+		clusterNode("node-2", "8", "32Gi", false),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "standard"}},
+	)
+	s.client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.27.3"}
+	tainted := clusterNode("node-3", "8", "32Gi", true)
+	tainted.Spec.Taints = []corev1.Taint{{Key: "node-role.kubernetes.io/control-plane", Effect: corev1.TaintEffectNoSchedule}}
+	_, createErr := s.client.CoreV1().Nodes().Create(s.T().Context(), tainted, metav1.CreateOptions{})
+	s.Require().NoError(createErr)
+
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.Contains(err.ErrorMsg, "kubernetes-version, storage-class, load-balancer, cpu, memory")
+
+	statuses := s.statuses(s.readReport())
+	for _, name := range onprem.ClusterCheckNames() {
+		s.Equal(onprem.PreflightFail, statuses[name], name)
+	}
+}
+
+func (s *ClusterCheckStepTest) TestNewerVersionWarns() {
+	s.client.Discovery().(*fakediscovery.FakeDiscovery).FakedServerVersion = &version.Info{GitVersion: "v1.32.1"}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal(onprem.PreflightWarn, s.statuses(s.readReport())["kubernetes-version"])
+}
+
+func (s *ClusterCheckStepTest) TestConfiguredStorageClass() {
+	s.config.ExistingCluster.StorageClass = "fast"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "storage-class")
+
+	_, createErr := s.client.StorageV1().StorageClasses().Create(s.T().Context(),
+		&storagev1.StorageClass{ObjectMeta: metav1.ObjectMeta{Name: "fast"}}, metav1.CreateOptions{})
+	s.Require().NoError(createErr)
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+}
+
+func (s *ClusterCheckStepTest) TestIgnoredFailure() {
+	s.config.Global.Scale = config.Scale1000
+	s.config.ExistingCluster.IgnoreChecks = []string{"cpu", "memory"}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	report := s.readReport()
+	s.True(report.Passed)
+	for _, check := range report.Checks {
+		if check.Name == "cpu" || check.Name == "memory" {
+			s.Equal(onprem.PreflightWarn, check.Status)
+			s.True(check.Ignored)
+		}
+	}
+}
+
+func (s *ClusterCheckStepTest) TestUnknownIgnoredCheck() {
+	s.config.ExistingCluster.IgnoreChecks = []string{"disk"}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+}
+
+func (s *ClusterCheckStepTest) TestSkippedOnUninstall() {
+	s.runtimeState.Action = "uninstall"
+	s.step.CreateClient = func(kubeConfig string) (kubernetes.Interface, error) {
+		s.Fail("the cluster is not checked on uninstall")
+		return nil, nil
+	}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+}
@@ -0,0 +1,205 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5"
+	gitconfig "github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+)
+
+const portForwardReadyTimeout = 30 * time.Second
+
+// RepoPushTarget is the Gitea repository a directory is pushed to.
+type RepoPushTarget struct {
+	URL      string
+	Username string
+	Password string
+	// Certificates trusted in addition to the system ones
+	CABundle []byte
+}
+
+// RepoPusher replaces the branch main of a repository with the content of a directory.
+type RepoPusher interface {
+	Push(ctx context.Context, dir string, target RepoPushTarget) (string, error)
+}
+
+type gitRepoPusher struct{}
+
+func CreateRepoPusher() RepoPusher {
+	return &gitRepoPusher{}
+}
+
+// Push commits the directory as a new repository and force pushes it, so that the Gitea
+// repository always matches the bundled content. It returns the hash of the commit.
+func (p *gitRepoPusher) Push(ctx context.Context, dir string, target RepoPushTarget) (string, error) {
+	repo, err := git.PlainInitWithOptions(dir, &git.PlainInitOptions{
+		InitOptions: git.InitOptions{DefaultBranch: plumbing.NewBranchReferenceName("main")},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to initialize repository: %w", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		return "", fmt.Errorf("failed to open worktree: %w", err)
+	}
+	if err := worktree.AddWithOptions(&git.AddOptions{All: true}); err != nil {
+		return "", fmt.Errorf("failed to add files: %w", err)
+	}
+	commit, err := worktree.Commit("Recreate repo from artifact", &git.CommitOptions{
+		AllowEmptyCommits: true,
+		Author: &object.Signature{
+			Name:  target.Username,
+			Email: target.Username + "@orch-installer.com",
+			When:  time.Now(),
+		},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to commit: %w", err)
+	}
+	if _, err := repo.CreateRemote(&gitconfig.RemoteConfig{Name: "gitea", URLs: []string{target.URL}}); err != nil {
+		return "", fmt.Errorf("failed to add remote: %w", err)
+	}
+	pushOptions := &git.PushOptions{
+		RemoteName: "gitea",
+		RefSpecs:   []gitconfig.RefSpec{"+refs/heads/main:refs/heads/main"},
+		Force:      true,
+		CABundle:   target.CABundle,
+	}
+	if target.Username != "" {
+		pushOptions.Auth = &githttp.BasicAuth{Username: target.Username, Password: target.Password}
+	}
+	if err := repo.PushContext(ctx, pushOptions); err != nil && err != git.NoErrAlreadyUpToDate {
+		return "", fmt.Errorf("failed to push to %s: %w", target.URL, err)
+	}
+	return commit.String(), nil
+}
+
+// ServiceForwarder makes a cluster service reachable from the installer.
+type ServiceForwarder interface {
+	// Forward returns the local address of the service and a function that stops forwarding.
+	Forward(ctx context.Context, kubeConfigFile, namespace, service string, port int) (string, func(), error)
+}
+
+type kubectlPortForwarder struct{}
+
+func CreateServiceForwarder() ServiceForwarder {
+	return &kubectlPortForwarder{}
+}
+
+func (f *kubectlPortForwarder) Forward(ctx context.Context, kubeConfigFile, namespace, service string, port int) (string, func(), error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to find a free local port: %w", err)
+	}
+	localPort := listener.Addr().(*net.TCPAddr).Port
+	listener.Close()
+
+	// The shell utility stops background commands when it returns, so run kubectl directly
+	cmd := exec.CommandContext(ctx, "kubectl", "--kubeconfig", kubeConfigFile, "port-forward",
+		"-n", namespace, "svc/"+service, fmt.Sprintf("%d:%d", localPort, port))
+	stderr := &strings.Builder{}
+	cmd.Stderr = stderr
+	if err := cmd.Start(); err != nil {
+		return "", nil, fmt.Errorf("failed to start port-forward to %s/%s: %w", namespace, service, err)
+	}
+	stop := func() {
+		_ = cmd.Process.Kill()
+		_ = cmd.Wait()
+	}
+
+	address := fmt.Sprintf("localhost:%d", localPort)
+	deadline := time.Now().Add(portForwardReadyTimeout)
+	for {
+		conn, err := net.DialTimeout("tcp", address, time.Second)
+		if err == nil {
+			conn.Close()
+			return address, stop, nil
+		}
+		if time.Now().After(deadline) {
+			stop()
+			return "", nil, fmt.Errorf("port-forward to %s/%s is not ready: %s", namespace, service, strings.TrimSpace(stderr.String()))
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+}
+
+// findArchive returns the path of the .tgz archive in dir whose name contains name.
+func findArchive(dir, name string) (string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.Contains(entry.Name(), name) && strings.HasSuffix(entry.Name(), ".tgz") {
+			return filepath.Join(dir, entry.Name()), nil
+		}
+	}
+	return "", fmt.Errorf("no %s archive found in %s", name, dir)
+}
+
+// extractTarGz extracts a gzipped tarball into dir, rejecting entries outside of it.
+func extractTarGz(archive, dir string) error {
+	file, err := os.Open(archive)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", archive, err)
+	}
+	defer file.Close()
+	gz, err := gzip.NewReader(file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", archive, err)
+	}
+	defer gz.Close()
+
+	reader := tar.NewReader(gz)
+	for {
+		header, err := reader.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", archive, err)
+		}
+		target := filepath.Join(dir, header.Name)
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return fmt.Errorf("archive entry %s is outside of the target directory", header.Name)
+		}
+		switch header.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0o755); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(header.Mode)&0o755|0o600)
+			if err != nil {
+				return err
+			}
+			if _, err := io.Copy(out, reader); err != nil {
+				out.Close()
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+			if err := out.Close(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,535 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	giteaNamespace     = "gitea"
+	giteaChartRepo     = "https://dl.gitea.com/charts/"
+	giteaChartVersion  = "10.6.0"
+	giteaChartArchive  = "gitea-" + giteaChartVersion + ".tgz"
+	giteaServiceDomain = "gitea-http.gitea.svc.cluster.local"
+	giteaTLSSecret     = "gitea-tls-certs"
+	giteaAdminSecret   = "gitea-cred"
+	// ArgoCD mounts the Gitea certificate from this path on the node
+	giteaCACertPath = "/usr/local/share/ca-certificates/gitea_cert.crt"
+	// ArgoCD trusts the certificates of this config map on an existing cluster
+	argocdTLSCertsConfigMap = "argocd-tls-certs-cm"
+
+	giteaInstallTimeout = 900 // seconds
+	giteaPasswordLength = 16
+)
+
+// Helm values carried over from onprem-gitea
+const giteaValues = `redis-cluster:
+  enabled: false
+postgresql:
+  enabled: true
+  primary:
+    persistence:
+      enabled: true
+      storageClass: "openebs-hostpath"
+    extendedConfiguration: |-
+      huge_pages = off
+    initdb:
+      args: "--set huge_pages=off"
+postgresql-ha:
+  enabled: false
+
+persistence:
+  enabled: true
+  storageClass: "openebs-hostpath"
+
+gitea:
+  config:
+    database:
+      DB_TYPE: postgres
+    session:
+      PROVIDER: db
+    cache:
+      ADAPTER: memory
+    queue:
+      TYPE: level
+    indexer:
+      ISSUE_INDEXER_TYPE: bleve
+      REPO_INDEXER_ENABLED: true
+    repository:
+      ENABLE_PUSH_CREATE_USER: true
+      DEFAULT_PUSH_CREATE_PRIVATE: true
+      FORCE_PRIVATE: true
+    service:
+      DISABLE_REGISTRATION: true
+    server:
+      APP_DATA_PATH: /data
+      DOMAIN: gitea-http.gitea.svc.cluster.local
+      PROTOCOL: https
+      CERT_FILE: /tmp/secret-volume/tls.crt
+      KEY_FILE: /tmp/secret-volume/tls.key
+
+service:
+  http:
+    port: 443
+
+extraVolumes:
+- name: secret-volume
+  secret:
+    secretName: gitea-tls-certs
+
+extraContainerVolumeMounts:
+- name: secret-volume
+  readOnly: true
+  mountPath: /tmp/secret-volume
+
+containerSecurityContext:
+  allowPrivilegeEscalation: false
+  capabilities:
+    drop:
+    - ALL
+  seccompProfile:
+    type: RuntimeDefault
+  runAsNonRoot: true
+`
+
+// giteaAccount is a Gitea user and the secret holding its credentials.
+type giteaAccount struct {
+	secretName string
+	namespace  string
+	username   string
+	// The admin account is created by the chart from its secret
+	admin bool
+}
+
+var (
+	giteaArgoCDAccount = giteaAccount{secretName: "argocd-gitea-credential", namespace: giteaNamespace, username: "argocd"}
+	giteaAccounts      = []giteaAccount{
+		{secretName: giteaAdminSecret, namespace: giteaNamespace, username: "gitea_admin", admin: true},
+		giteaArgoCDAccount,
+		{secretName: "app-gitea-credential", namespace: "orch-platform", username: "apporch"},
+		{secretName: "cluster-gitea-credential", namespace: "orch-platform", username: "clusterorch"},
+	}
+)
+
+var giteaStepLabels = []string{"gitea"}
+
+type GiteaStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	ShellUtility           steps.ShellUtility
+	// Chart archive, the one fetched by the ArtifactDownloader when empty. The chart
+	// repository is used when it is missing.
+	ChartPath string
+}
+
+func CreateGiteaStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *GiteaStep {
+	return &GiteaStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             giteaStepLabels,
+		ShellUtility:           steps.CreateShellUtility(),
+	}
+}
+
+func (s *GiteaStep) Name() string {
+	return "GiteaStep"
+}
+
+func (s *GiteaStep) Labels() []string {
+	return s.StepLabels
+}
+
+func (s *GiteaStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if s.ChartPath == "" && runtimeState.Onprem.Artifacts.ChartsDir != "" {
+		s.ChartPath = filepath.Join(runtimeState.Onprem.Artifacts.ChartsDir, giteaChartArchive)
+	}
+	return runtimeState, nil
+}
+
+func (s *GiteaStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if common.ClusterKubeConfig(cfg, runtimeState) == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot manage Gitea",
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *GiteaStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	kubeConfigFile, err := writeKubeConfig(common.ClusterKubeConfig(cfg, runtimeState))
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	defer os.Remove(kubeConfigFile)
+	cluster := &rke2Cluster{
+		shellUtility:   s.ShellUtility,
+		kubeConfigFile: kubeConfigFile,
+	}
+
+	if runtimeState.Action == "uninstall" {
+		if !runtimeState.Onprem.GiteaInstalled {
+			internal.Logger().Info("Gitea was not installed by the installer, skipping uninstall")
+			return runtimeState, nil
+		}
+		if err := s.uninstallGitea(ctx, cfg, cluster); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to uninstall Gitea: %s", err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+		runtimeState.Onprem.GiteaInstalled = false
+		return runtimeState, nil
+	}
+
+	// Install and upgrade converge to the same state, credentials are kept if they exist
+	if err := s.installGitea(ctx, cfg, cluster); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to install Gitea: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	runtimeState.Onprem.GiteaInstalled = true
+	return runtimeState, nil
+}
+
+func (s *GiteaStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+func (s *GiteaStep) installGitea(ctx context.Context, cfg config.OrchInstallerConfig, cluster *rke2Cluster) error {
+	for _, namespace := range []string{giteaNamespace, "orch-platform"} {
+		if err := cluster.apply(ctx, fmt.Sprintf("apiVersion: v1\nkind: Namespace\nmetadata:\n  name: %s\n", namespace)); err != nil {
+			return err
+		}
+	}
+	if err := s.ensureCertificate(ctx, cfg, cluster); err != nil {
+		return err
+	}
+	passwords := map[string]string{}
+	for _, account := range giteaAccounts {
+		password, err := ensureGiteaCredential(ctx, cluster, account)
+		if err != nil {
+			return err
+		}
+		passwords[account.username] = password
+	}
+
+	valuesFile, err := os.CreateTemp("", "gitea-values-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create values file: %w", err)
+	}
+	defer os.Remove(valuesFile.Name())
+	if _, err := valuesFile.WriteString(giteaValues); err != nil {
+		valuesFile.Close()
+		return fmt.Errorf("failed to write values file: %w", err)
+	}
+	if err := valuesFile.Close(); err != nil {
+		return fmt.Errorf("failed to write values file: %w", err)
+	}
+	internal.Logger().Infof("Installing Gitea chart %s...", giteaChartVersion)
+	chart := []string{"gitea", "--repo", giteaChartRepo, "--version", giteaChartVersion}
+	if _, err := os.Stat(s.ChartPath); s.ChartPath != "" && err == nil {
+		chart = []string{s.ChartPath}
+	}
+	args := append([]string{"upgrade", "--install", "gitea"}, chart...)
+	args = append(args, "--values", valuesFile.Name(), "--set", "gitea.admin.existingSecret="+giteaAdminSecret)
+	if cfg.Provider == common.ExistingClusterProvider {
+		// The volumes of an existing cluster use its default storage class unless one is set
+		storageClass := cfg.ExistingCluster.StorageClass
+		args = append(args, "--set", "persistence.storageClass="+storageClass,
+			"--set", "postgresql.primary.persistence.storageClass="+storageClass)
+	}
+	if err := cluster.helm(ctx, giteaInstallTimeout, append(args,
+		"-n", giteaNamespace, "--wait", "--timeout", "15m0s")...); err != nil {
+		return err
+	}
+
+	pod, err := cluster.kubectl(ctx, 0, "get", "pods", "-n", giteaNamespace, "-l", "app=gitea", "-o", "jsonpath={.items[0].metadata.name}")
+	if err != nil {
+		return err
+	}
+	pod = strings.TrimSpace(pod)
+	if pod == "" {
+		return fmt.Errorf("no Gitea pod found")
+	}
+	for _, account := range giteaAccounts {
+		if account.admin {
+			continue
+		}
+		if err := ensureGiteaUser(ctx, cluster, pod, account, passwords[account.username]); err != nil {
+			return err
+		}
+	}
+	internal.Logger().Info("Gitea installed")
+	return nil
+}
+
+func (s *GiteaStep) uninstallGitea(ctx context.Context, cfg config.OrchInstallerConfig, cluster *rke2Cluster) error {
+	if err := cluster.helm(ctx, giteaInstallTimeout, "uninstall", "gitea", "-n", giteaNamespace, "--wait"); err != nil {
+		return err
+	}
+	// The credential secrets outside of the Gitea namespace are not deleted with it
+	for _, account := range giteaAccounts {
+		if account.namespace == giteaNamespace {
+			continue
+		}
+		if _, err := cluster.kubectl(ctx, 0, "delete", "secret", account.secretName,
+			"-n", account.namespace, "--ignore-not-found"); err != nil {
+			return err
+		}
+	}
+	if _, err := cluster.kubectl(ctx, giteaInstallTimeout, "delete", "namespace", giteaNamespace, "--ignore-not-found"); err != nil {
+		return err
+	}
+	if cfg.Provider == common.ExistingClusterProvider {
+		// ArgoCD is uninstalled after Gitea, its config map goes with it
+		internal.Logger().Info("Gitea uninstalled")
+		return nil
+	}
+	if err := s.run(ctx, "sudo", "rm", "-f", giteaCACertPath); err != nil {
+		return err
+	}
+	if err := s.run(ctx, "sudo", "update-ca-certificates", "--fresh"); err != nil {
+		return err
+	}
+	internal.Logger().Info("Gitea uninstalled")
+	return nil
+}
+
+// ensureCertificate creates the self-signed Gitea certificate unless it exists and trusts it
+// on the node, where ArgoCD mounts it from. On an existing cluster it is added to the TLS
+// certificates of ArgoCD instead.
+func (s *GiteaStep) ensureCertificate(ctx context.Context, cfg config.OrchInstallerConfig, cluster *rke2Cluster) error {
+	cert, err := cluster.secretValue(ctx, giteaNamespace, giteaTLSSecret, "tls.crt")
+	if err != nil {
+		return err
+	}
+	if cert == "" {
+		var key string
+		cert, key, err = generateGiteaCertificate()
+		if err != nil {
+			return err
+		}
+		manifest, err := secretManifest(giteaNamespace, giteaTLSSecret, "kubernetes.io/tls", nil,
+			map[string]string{"tls.crt": cert, "tls.key": key})
+		if err != nil {
+			return err
+		}
+		if err := cluster.apply(ctx, manifest); err != nil {
+			return err
+		}
+	}
+
+	if cfg.Provider == common.ExistingClusterProvider {
+		patch, err := json.Marshal(map[string]any{"data": map[string]string{giteaServiceDomain: cert}})
+		if err != nil {
+			return fmt.Errorf("failed to serialize the ArgoCD TLS certificates: %w", err)
+		}
+		_, err = cluster.kubectl(ctx, 0, "patch", "configmap", argocdTLSCertsConfigMap, "-n", "argocd",
+			"--type", "merge", "-p", string(patch))
+		return err
+	}
+
+	certFile, err := os.CreateTemp("", "gitea-cert-*.crt")
+	if err != nil {
+		return fmt.Errorf("failed to create certificate file: %w", err)
+	}
+	defer os.Remove(certFile.Name())
+	if _, err := certFile.WriteString(cert); err != nil {
+		certFile.Close()
+		return fmt.Errorf("failed to write certificate file: %w", err)
+	}
+	if err := certFile.Close(); err != nil {
+		return fmt.Errorf("failed to write certificate file: %w", err)
+	}
+	if err := s.run(ctx, "sudo", "install", "-D", "-m", "644", certFile.Name(), giteaCACertPath); err != nil {
+		return err
+	}
+	return s.run(ctx, "sudo", "update-ca-certificates")
+}
+
+func (s *GiteaStep) run(ctx context.Context, command ...string) error {
+	output, err := s.ShellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: command,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return fmt.Errorf("%s: %s: %s", strings.Join(command, " "), err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return fmt.Errorf("%s: %s", strings.Join(command, " "), err.ErrorMsg)
+	}
+	return nil
+}
+
+// ensureGiteaCredential creates the credential secret of an account with a random password,
+// and returns the password already stored in the secret if it exists.
+func ensureGiteaCredential(ctx context.Context, cluster *rke2Cluster, account giteaAccount) (string, error) {
+	password, err := cluster.secretValue(ctx, account.namespace, account.secretName, "password")
+	if err != nil {
+		return "", err
+	}
+	if password != "" {
+		return password, nil
+	}
+	password, err = randomPassword(giteaPasswordLength)
+	if err != nil {
+		return "", err
+	}
+	manifest, err := secretManifest(account.namespace, account.secretName, "Opaque", nil,
+		map[string]string{"username": account.username, "password": password})
+	if err != nil {
+		return "", err
+	}
+	if err := cluster.apply(ctx, manifest); err != nil {
+		return "", err
+	}
+	return password, nil
+}
+
+// ensureGiteaUser creates the Gitea user of an account, or resets its password to the one in
+// the credential secret, and creates its access token secret.
+func ensureGiteaUser(ctx context.Context, cluster *rke2Cluster, pod string, account giteaAccount, password string) error {
+	gitea := []string{"exec", "-n", giteaNamespace, pod, "-c", "gitea", "--", "gitea", "admin", "user"}
+	users, err := cluster.kubectl(ctx, 0, append(gitea, "list")...)
+	if err != nil {
+		return err
+	}
+	exists := false
+	for _, line := range strings.Split(users, "\n") {
+		if fields := strings.Fields(line); len(fields) > 1 && fields[1] == account.username {
+			exists = true
+		}
+	}
+	// The password is passed on stdin, so that it is neither on the command line of kubectl nor
+	// in the log of the commands
+	withPassword := func(subcommand string, args ...string) []string {
+		return append([]string{"exec", "-i", "-n", giteaNamespace, pod, "-c", "gitea", "--", "sh", "-c",
+			`read -r password && exec gitea admin user ` + subcommand + ` --password "$password" "$@"`, "sh"}, args...)
+	}
+	if exists {
+		internal.Logger().Infof("Gitea account %s exists, updating its password", account.username)
+		_, err = cluster.kubectlWithStdin(ctx, 0, password+"\n", withPassword("change-password",
+			"--username", account.username, "--must-change-password=false")...)
+	} else {
+		internal.Logger().Infof("Creating Gitea account %s", account.username)
+		_, err = cluster.kubectlWithStdin(ctx, 0, password+"\n", withPassword("create",
+			"--username", account.username, "--email", account.username+"@orch-installer.com", "--must-change-password=false")...)
+	}
+	if err != nil {
+		return err
+	}
+
+	tokenSecret := "gitea-" + account.username + "-token"
+	token, err := cluster.secretValue(ctx, giteaNamespace, tokenSecret, "token")
+	if err != nil || token != "" {
+		return err
+	}
+	output, err := cluster.kubectl(ctx, 0, append(gitea, "generate-access-token", "--scopes", "write:repository,write:user",
+		"--username", account.username, "--token-name", fmt.Sprintf("%s-%d", account.username, time.Now().Unix()))...)
+	if err != nil {
+		return err
+	}
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return fmt.Errorf("no access token generated for Gitea account %s", account.username)
+	}
+	manifest, err := secretManifest(giteaNamespace, tokenSecret, "Opaque", nil, map[string]string{"token": fields[len(fields)-1]})
+	if err != nil {
+		return err
+	}
+	return cluster.apply(ctx, manifest)
+}
+
+func secretManifest(namespace, name, secretType string, labels, data map[string]string) (string, error) {
+	metadata := map[string]any{"name": name, "namespace": namespace}
+	if len(labels) > 0 {
+		metadata["labels"] = labels
+	}
+	manifest, err := yaml.Marshal(map[string]any{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata":   metadata,
+		"type":       secretType,
+		"stringData": data,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal secret %s/%s: %w", namespace, name, err)
+	}
+	return string(manifest), nil
+}
+
+func randomPassword(length int) (string, error) {
+	const letters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	password := make([]byte, length)
+	for i := range password {
+		n, err := rand.Int(rand.Reader, big.NewInt(int64(len(letters))))
+		if err != nil {
+			return "", fmt.Errorf("failed to generate password: %w", err)
+		}
+		password[i] = letters[n.Int64()]
+	}
+	return string(password), nil
+}
+
+// generateGiteaCertificate returns a self-signed certificate and key for the Gitea service,
+// valid for localhost as well so that the installer can reach it through a port-forward.
+func generateGiteaCertificate() (string, string, error) {
+	key, err := rsa.GenerateKey(rand.Reader, 4096)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate key: %w", err)
+	}
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", fmt.Errorf("failed to generate serial number: %w", err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Country:            []string{"US"},
+			Organization:       []string{"Orch Deploy"},
+			OrganizationalUnit: []string{"Orchestrator"},
+		},
+		DNSNames:              []string{"localhost", giteaServiceDomain},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().AddDate(1, 0, 0),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create certificate: %w", err)
+	}
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return string(cert), string(keyPEM), nil
+}
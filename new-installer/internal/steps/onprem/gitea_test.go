@@ -0,0 +1,213 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"context"
+	"encoding/base64"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+)
+
+var secretKeyRegex = regexp.MustCompile(`jsonpath=\{\.data\.(.+)\}`)
+
+// fakeKubeShellUtility keeps the secrets applied with kubectl and the Gitea users, and
+// records every command.
+type fakeKubeShellUtility struct {
+	commands   []string
+	stdins     []string
+	secrets    map[string]map[string]string
+	giteaUsers []string
+}
+
+func newFakeKubeShellUtility() *fakeKubeShellUtility {
+	return &fakeKubeShellUtility{secrets: map[string]map[string]string{}}
+}
+
+func (f *fakeKubeShellUtility) Run(ctx context.Context, input steps.ShellUtilityInput) (*steps.ShellUtilityOutput, *internal.OrchInstallerError) {
+	command := strings.Join(input.Command, " ")
+	f.commands = append(f.commands, command)
+	if input.Stdin != "" {
+		f.stdins = append(f.stdins, input.Stdin)
+	}
+	output := &steps.ShellUtilityOutput{}
+	args := input.Command
+	switch {
+	case strings.Contains(command, " apply -f "):
+		data, err := os.ReadFile(args[len(args)-1])
+		if err != nil {
+			return output, &internal.OrchInstallerError{ErrorCode: internal.OrchInstallerErrorCodeInternal, ErrorMsg: err.Error()}
+		}
+		manifest := struct {
+			Kind     string `yaml:"kind"`
+			Metadata struct {
+				Name      string `yaml:"name"`
+				Namespace string `yaml:"namespace"`
+			} `yaml:"metadata"`
+			StringData map[string]string `yaml:"stringData"`
+		}{}
+		if err := yaml.Unmarshal(data, &manifest); err != nil {
+			return output, &internal.OrchInstallerError{ErrorCode: internal.OrchInstallerErrorCodeInternal, ErrorMsg: err.Error()}
+		}
+		if manifest.Kind == "Secret" {
+			f.secrets[manifest.Metadata.Namespace+"/"+manifest.Metadata.Name] = manifest.StringData
+		}
+	case strings.Contains(command, " get secret "):
+		name := args[slices.Index(args, "secret")+1]
+		namespace := args[slices.Index(args, "-n")+1]
+		key := strings.ReplaceAll(secretKeyRegex.FindStringSubmatch(command)[1], `\.`, ".")
+		if value, ok := f.secrets[namespace+"/"+name][key]; ok {
+			output.Stdout.WriteString(base64.StdEncoding.EncodeToString([]byte(value)))
+		}
+	case strings.Contains(command, " get pods "):
+		output.Stdout.WriteString("gitea-0")
+	case strings.HasSuffix(command, "admin user list"):
+		output.Stdout.WriteString("ID   Username  Email  IsActive  IsAdmin  2FA\n")
+		for i, user := range f.giteaUsers {
+			output.Stdout.WriteString(strings.Join([]string{string(rune('1' + i)), user, user + "@orch-installer.com", "true", "false", "false"}, "  ") + "\n")
+		}
+	case strings.Contains(command, "admin user create"):
+		f.giteaUsers = append(f.giteaUsers, args[slices.Index(args, "--username")+1])
+	case strings.Contains(command, "generate-access-token"):
+		output.Stdout.WriteString("Access token was successfully created: 0123456789abcdef\n")
+	}
+	return output, nil
+}
+
+func (f *fakeKubeShellUtility) Process() *os.Process { return nil }
+func (f *fakeKubeShellUtility) Kill() error          { return nil }
+func (f *fakeKubeShellUtility) Wait() error          { return nil }
+
+func (f *fakeKubeShellUtility) commandsMatching(substr string) []string {
+	matching := []string{}
+	for _, command := range f.commands {
+		if strings.Contains(command, substr) {
+			matching = append(matching, command)
+		}
+	}
+	return matching
+}
+
+type GiteaStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.GiteaStep
+	shellUtility *fakeKubeShellUtility
+}
+
+func TestGiteaStep(t *testing.T) {
+	suite.Run(t, new(GiteaStepTest))
+}
+
+func (s *GiteaStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{}
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "install"
+	s.runtimeState.Onprem.KubeConfig = "test-kubeconfig"
+	s.shellUtility = newFakeKubeShellUtility()
+	s.step = onprem.CreateGiteaStep("", false, nil)
+	s.step.ShellUtility = s.shellUtility
+}
+
+func (s *GiteaStepTest) TestInstall() {
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.True(runtimeState.Onprem.GiteaInstalled)
+
+	s.Contains(s.shellUtility.secrets["gitea/gitea-tls-certs"]["tls.crt"], "BEGIN CERTIFICATE")
+	s.Equal("argocd", s.shellUtility.secrets["gitea/argocd-gitea-credential"]["username"])
+	s.Len(s.shellUtility.secrets["gitea/argocd-gitea-credential"]["password"], 16)
+	s.Equal("apporch", s.shellUtility.secrets["orch-platform/app-gitea-credential"]["username"])
+	s.Equal("0123456789abcdef", s.shellUtility.secrets["gitea/gitea-clusterorch-token"]["token"])
+	s.Equal([]string{"argocd", "apporch", "clusterorch"}, s.shellUtility.giteaUsers)
+
+	installs := s.shellUtility.commandsMatching("upgrade --install gitea")
+	s.Require().Len(installs, 1)
+	s.Contains(installs[0], "gitea.admin.existingSecret=gitea-cred")
+	s.Len(s.shellUtility.commandsMatching("sudo update-ca-certificates"), 1)
+}
+
+func (s *GiteaStepTest) TestReinstallKeepsCredentials() {
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	password := s.shellUtility.secrets["gitea/argocd-gitea-credential"]["password"]
+	cert := s.shellUtility.secrets["gitea/gitea-tls-certs"]["tls.crt"]
+
+	s.shellUtility.commands = nil
+	runtimeState.Action = "upgrade"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.Equal(password, s.shellUtility.secrets["gitea/argocd-gitea-credential"]["password"])
+	s.Equal(cert, s.shellUtility.secrets["gitea/gitea-tls-certs"]["tls.crt"])
+	s.Empty(s.shellUtility.commandsMatching("admin user create"))
+	s.Len(s.shellUtility.commandsMatching("change-password --password \"$password\" \"$@\" sh --username argocd"), 1)
+	s.Contains(s.shellUtility.stdins, password+"\n")
+	s.Empty(s.shellUtility.commandsMatching(password))
+	s.Empty(s.shellUtility.commandsMatching("generate-access-token"))
+}
+
+func (s *GiteaStepTest) TestUninstall() {
+	s.runtimeState.Action = "uninstall"
+	s.runtimeState.Onprem.GiteaInstalled = true
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.False(runtimeState.Onprem.GiteaInstalled)
+	s.Len(s.shellUtility.commandsMatching("uninstall gitea -n gitea"), 1)
+	s.Len(s.shellUtility.commandsMatching("delete namespace gitea"), 1)
+	s.Len(s.shellUtility.commandsMatching("delete secret app-gitea-credential -n orch-platform"), 1)
+	s.Empty(s.shellUtility.commandsMatching("-token -n orch-platform"))
+	s.Len(s.shellUtility.commandsMatching("sudo rm -f /usr/local/share/ca-certificates/gitea_cert.crt"), 1)
+}
+
+func (s *GiteaStepTest) TestUninstallNotInstalled() {
+	s.runtimeState.Action = "uninstall"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Empty(s.shellUtility.commands)
+}
+
+func (s *GiteaStepTest) TestMissingKubeConfig() {
+	s.runtimeState.Onprem.KubeConfig = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *GiteaStepTest) TestExistingCluster() {
+	s.config.Provider = "existing-cluster"
+	s.config.ExistingCluster.StorageClass = "gp3"
+	s.runtimeState.Onprem.KubeConfig = ""
+	s.runtimeState.ExistingCluster.KubeConfig = "test-kubeconfig"
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.True(runtimeState.Onprem.GiteaInstalled)
+
+	installs := s.shellUtility.commandsMatching("upgrade --install gitea")
+	s.Require().Len(installs, 1)
+	s.Contains(installs[0], "persistence.storageClass=gp3")
+	s.Contains(installs[0], "postgresql.primary.persistence.storageClass=gp3")
+	// The nodes are not touched, ArgoCD trusts the certificate from its config map
+	s.Empty(s.shellUtility.commandsMatching("sudo"))
+	patches := s.shellUtility.commandsMatching("patch configmap argocd-tls-certs-cm -n argocd --type merge")
+	s.Require().Len(patches, 1)
+	s.Contains(patches[0], `"gitea-http.gitea.svc.cluster.local":"-----BEGIN CERTIFICATE-----`)
+
+	s.shellUtility.commands = nil
+	runtimeState.Action = "uninstall"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.Len(s.shellUtility.commandsMatching("uninstall gitea -n gitea"), 1)
+	s.Empty(s.shellUtility.commandsMatching("sudo"))
+}
@@ -0,0 +1,118 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+// HostInfo reads the properties of the local host checked before an install.
+type HostInfo interface {
+	CPUCount() int
+	MemoryBytes() (uint64, error)
+	// FreeDiskBytes returns the space available on the file system holding path, or its
+	// closest existing parent if path does not exist yet.
+	FreeDiskBytes(path string) (uint64, error)
+	KernelModuleLoaded(name string) bool
+	Sysctl(name string) (string, error)
+	PortInUse(port int) bool
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	TimeSynchronized(ctx context.Context) (bool, error)
+}
+
+type hostInfoImpl struct{}
+
+func CreateHostInfo() HostInfo {
+	return &hostInfoImpl{}
+}
+
+func (h *hostInfoImpl) CPUCount() int {
+	return runtime.NumCPU()
+}
+
+func (h *hostInfoImpl) MemoryBytes() (uint64, error) {
+	data, err := os.ReadFile("/proc/meminfo")
+	if err != nil {
+		return 0, fmt.Errorf("failed to read /proc/meminfo: %w", err)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) >= 2 && fields[0] == "MemTotal:" {
+			kib, err := strconv.ParseUint(fields[1], 10, 64)
+			if err != nil {
+				return 0, fmt.Errorf("failed to parse MemTotal %s: %w", fields[1], err)
+			}
+			return kib * 1024, nil
+		}
+	}
+	return 0, fmt.Errorf("MemTotal not found in /proc/meminfo")
+}
+
+func (h *hostInfoImpl) FreeDiskBytes(path string) (uint64, error) {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			break
+		}
+		path = parent
+	}
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return 0, fmt.Errorf("failed to stat file system of %s: %w", path, err)
+	}
+	return stat.Bavail * uint64(stat.Bsize), nil
+}
+
+func (h *hostInfoImpl) KernelModuleLoaded(name string) bool {
+	// Built-in modules are listed in /sys/module as well, with underscores
+	_, err := os.Stat(filepath.Join("/sys/module", strings.ReplaceAll(name, "-", "_")))
+	return err == nil
+}
+
+func (h *hostInfoImpl) Sysctl(name string) (string, error) {
+	data, err := os.ReadFile(filepath.Join("/proc/sys", strings.ReplaceAll(name, ".", "/")))
+	if err != nil {
+		return "", fmt.Errorf("failed to read sysctl %s: %w", name, err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+func (h *hostInfoImpl) PortInUse(port int) bool {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", port))
+	if err != nil {
+		// Binding a privileged port fails for other reasons when not running as root
+		return errors.Is(err, syscall.EADDRINUSE)
+	}
+	listener.Close()
+	return false
+}
+
+func (h *hostInfoImpl) LookupHost(ctx context.Context, host string) ([]string, error) {
+	return net.DefaultResolver.LookupHost(ctx, host)
+}
+
+func (h *hostInfoImpl) TimeSynchronized(ctx context.Context) (bool, error) {
+	output, err := exec.CommandContext(ctx, "timedatectl", "show", "--property=NTPSynchronized", "--value").Output()
+	if err != nil {
+		return false, fmt.Errorf("failed to run timedatectl: %w", err)
+	}
+	return strings.TrimSpace(string(output)) == "yes", nil
+}
@@ -0,0 +1,365 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	// Kept from onprem-config-installer so that hosts it configured are left as they are
+	lvSnapshotModulesPath = "/etc/modules-load.d/lv-snapshots.conf"
+	inotifySysctlPath     = "/etc/sysctl.d/99-orch-inotify.conf"
+	openEBSHostpathDir    = "/var/openebs/local"
+	toolsBinDir           = "/usr/local/bin"
+	// Suffix of the copies of the drop-in files the installer replaced, not loaded as they do not end with .conf
+	backupSuffix = ".orch-installer-backup"
+
+	yqVersion   = "v4.44.3"
+	yqURL       = "https://github.com/mikefarah/yq/releases/download/" + yqVersion + "/yq_linux_amd64"
+	helmVersion = "v3.12.3"
+	helmURL     = "https://get.helm.sh/helm-" + helmVersion + "-linux-amd64.tar.gz"
+
+	osConfigCommandTimeout = 300 // seconds
+)
+
+var (
+	lvSnapshotModules = []string{"dm-snapshot", "dm-mirror"}
+	inotifySysctl     = fmt.Sprintf("fs.inotify.max_queued_events = %[1]d\nfs.inotify.max_user_instances = %[1]d\nfs.inotify.max_user_watches = %[1]d\n", minInotifyLimit)
+)
+
+// osConfigChange is a change to the host, made only if the host does not have it already.
+// The runtime state records the changes made by the installer so that uninstall reverts
+// those and nothing else.
+type osConfigChange struct {
+	name     string
+	recorded func(runtimeState *config.OrchInstallerRuntimeState) *bool
+	applied  func(ctx context.Context) (bool, error)
+	apply    func(ctx context.Context, runtimeState *config.OrchInstallerRuntimeState) error
+	revert   func(ctx context.Context, runtimeState *config.OrchInstallerRuntimeState) error
+	// Set for the changes only reverted when the config asks for it, tells how
+	keepUnless func(cfg config.OrchInstallerConfig) (bool, string)
+}
+
+var osConfigStepLabels = []string{"onprem", "os_config"}
+
+type OSConfigStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	ShellUtility           steps.ShellUtility
+	// Root of the host file system, changed in tests
+	HostRoot string
+}
+
+func CreateOSConfigStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *OSConfigStep {
+	return &OSConfigStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             osConfigStepLabels,
+		ShellUtility:           steps.CreateShellUtility(),
+		HostRoot:               "/",
+	}
+}
+
+func (s *OSConfigStep) Name() string {
+	return "OSConfigStep"
+}
+
+func (s *OSConfigStep) Labels() []string {
+	return s.StepLabels
+}
+
+// SupportsDryRun tells that the step only prints the changes of the host with --dry-run.
+func (s *OSConfigStep) SupportsDryRun() bool {
+	return true
+}
+
+func (s *OSConfigStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *OSConfigStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *OSConfigStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action == "uninstall" {
+		return s.revertChanges(ctx, cfg, runtimeState)
+	}
+	return s.applyChanges(ctx, runtimeState)
+}
+
+func (s *OSConfigStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+func (s *OSConfigStep) applyChanges(ctx context.Context, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	internal.Logger().Info("OS configuration:")
+	for _, change := range s.changes() {
+		applied, err := change.applied(ctx)
+		if err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to check %s: %s", change.name, err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+		switch {
+		case applied:
+			internal.Logger().Infof("  unchanged     %s", change.name)
+		case runtimeState.DryRun:
+			internal.Logger().Infof("  would change  %s", change.name)
+		default:
+			if err := change.apply(ctx, &runtimeState); err != nil {
+				return runtimeState, &internal.OrchInstallerError{
+					ErrorMsg:  fmt.Sprintf("failed to configure %s: %s", change.name, err),
+					ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				}
+			}
+			*change.recorded(&runtimeState) = true
+			internal.Logger().Infof("  changed       %s", change.name)
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *OSConfigStep) revertChanges(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	internal.Logger().Info("Reverting OS configuration:")
+	changes := s.changes()
+	for i := len(changes) - 1; i >= 0; i-- {
+		change := changes[i]
+		revert, hint := true, ""
+		if change.keepUnless != nil {
+			revert, hint = change.keepUnless(cfg)
+		}
+		switch {
+		case !*change.recorded(&runtimeState):
+			internal.Logger().Infof("  unchanged     %s, not made by the installer", change.name)
+		case !revert:
+			internal.Logger().Infof("  kept          %s, %s", change.name, hint)
+		case runtimeState.DryRun:
+			internal.Logger().Infof("  would revert  %s", change.name)
+		default:
+			if err := change.revert(ctx, &runtimeState); err != nil {
+				return runtimeState, &internal.OrchInstallerError{
+					ErrorMsg:  fmt.Sprintf("failed to revert %s: %s", change.name, err),
+					ErrorCode: internal.OrchInstallerErrorCodeInternal,
+				}
+			}
+			*change.recorded(&runtimeState) = false
+			internal.Logger().Infof("  reverted      %s", change.name)
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *OSConfigStep) changes() []osConfigChange {
+	return []osConfigChange{
+		s.dropInChange("sysctl", inotifySysctlPath, inotifySysctl,
+			func(rs *config.OrchInstallerRuntimeState) *bool {
+				return &rs.Onprem.OSConfig.SysctlConfigured
+			},
+			func(rs *config.OrchInstallerRuntimeState) *bool {
+				return &rs.Onprem.OSConfig.SysctlBackedUp
+			},
+			// The limits stay raised until the next reboot
+			func(ctx context.Context) error {
+				return s.run(ctx, "sudo", "sysctl", "--system")
+			}),
+		s.dropInChange("kernel modules", lvSnapshotModulesPath, strings.Join(lvSnapshotModules, "\n")+"\n",
+			func(rs *config.OrchInstallerRuntimeState) *bool {
+				return &rs.Onprem.OSConfig.ModulesConfigured
+			},
+			func(rs *config.OrchInstallerRuntimeState) *bool {
+				return &rs.Onprem.OSConfig.ModulesBackedUp
+			},
+			// The modules stay loaded until the next reboot
+			func(ctx context.Context) error {
+				for _, module := range lvSnapshotModules {
+					if err := s.run(ctx, "sudo", "modprobe", module); err != nil {
+						return err
+					}
+				}
+				return nil
+			}),
+		{
+			name: "hostpath directory " + openEBSHostpathDir,
+			recorded: func(rs *config.OrchInstallerRuntimeState) *bool {
+				return &rs.Onprem.OSConfig.HostpathDirCreated
+			},
+			applied: func(ctx context.Context) (bool, error) {
+				return s.exists(openEBSHostpathDir)
+			},
+			apply: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+				return s.run(ctx, "sudo", "mkdir", "-p", s.hostPath(openEBSHostpathDir))
+			},
+			revert: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+				return s.run(ctx, "sudo", "rm", "-rf", s.hostPath(openEBSHostpathDir))
+			},
+			keepUnless: func(cfg config.OrchInstallerConfig) (bool, string) {
+				return cfg.Onprem.RemoveHostpathData, "holds the data of the local volumes, set onprem.removeHostpathData to remove it"
+			},
+		},
+		s.toolChange("yq", func(rs *config.OrchInstallerRuntimeState) *bool {
+			return &rs.Onprem.OSConfig.YqInstalled
+		}, s.installYq),
+		s.toolChange("helm", func(rs *config.OrchInstallerRuntimeState) *bool {
+			return &rs.Onprem.OSConfig.HelmInstalled
+		}, s.installHelm),
+	}
+}
+
+// dropInChange writes a drop-in file and loads it. A file of the host found at the same path is
+// backed up and restored on uninstall, a file created by the installer is removed.
+func (s *OSConfigStep) dropInChange(kind, path, content string, recorded, backedUp func(rs *config.OrchInstallerRuntimeState) *bool, load func(ctx context.Context) error) osConfigChange {
+	return osConfigChange{
+		name:     kind + " " + path,
+		recorded: recorded,
+		applied: func(ctx context.Context) (bool, error) {
+			return s.fileHasContent(path, content)
+		},
+		apply: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+			exists, err := s.exists(path)
+			if err != nil {
+				return err
+			}
+			if exists {
+				if err := s.run(ctx, "sudo", "cp", "-p", s.hostPath(path), s.hostPath(path+backupSuffix)); err != nil {
+					return err
+				}
+				*backedUp(rs) = true
+			}
+			if err := s.writeFile(ctx, path, content); err != nil {
+				return err
+			}
+			return load(ctx)
+		},
+		revert: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+			if *backedUp(rs) {
+				if err := s.run(ctx, "sudo", "mv", "-f", s.hostPath(path+backupSuffix), s.hostPath(path)); err != nil {
+					return err
+				}
+				*backedUp(rs) = false
+				return nil
+			}
+			return s.run(ctx, "sudo", "rm", "-f", s.hostPath(path))
+		},
+	}
+}
+
+// toolChange installs a tool to /usr/local/bin unless it is already in the PATH.
+func (s *OSConfigStep) toolChange(tool string, recorded func(rs *config.OrchInstallerRuntimeState) *bool, install func(ctx context.Context, downloadDir string) error) osConfigChange {
+	return osConfigChange{
+		name:     "tool " + tool,
+		recorded: recorded,
+		applied: func(ctx context.Context) (bool, error) {
+			output, err := s.ShellUtility.Run(ctx, steps.ShellUtilityInput{
+				Command:   []string{"sh", "-c", "command -v " + tool},
+				SkipError: true,
+			})
+			if err != nil {
+				return false, fmt.Errorf("%s", err.ErrorMsg)
+			}
+			if strings.TrimSpace(output.Stdout.String()) != "" {
+				return true, nil
+			}
+			return s.exists(filepath.Join(toolsBinDir, tool))
+		},
+		apply: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+			downloadDir, err := os.MkdirTemp("", tool+"-*")
+			if err != nil {
+				return fmt.Errorf("failed to create download directory: %w", err)
+			}
+			defer os.RemoveAll(downloadDir)
+			return install(ctx, downloadDir)
+		},
+		revert: func(ctx context.Context, rs *config.OrchInstallerRuntimeState) error {
+			return s.run(ctx, "sudo", "rm", "-f", s.hostPath(filepath.Join(toolsBinDir, tool)))
+		},
+	}
+}
+
+func (s *OSConfigStep) installYq(ctx context.Context, downloadDir string) error {
+	binary := filepath.Join(downloadDir, "yq")
+	if err := s.run(ctx, "curl", "-fsSL", "-o", binary, yqURL); err != nil {
+		return err
+	}
+	return s.run(ctx, "sudo", "install", "-D", "-m", "755", binary, s.hostPath(filepath.Join(toolsBinDir, "yq")))
+}
+
+func (s *OSConfigStep) installHelm(ctx context.Context, downloadDir string) error {
+	archive := filepath.Join(downloadDir, "helm.tar.gz")
+	if err := s.run(ctx, "curl", "-fsSL", "-o", archive, helmURL); err != nil {
+		return err
+	}
+	if err := s.run(ctx, "tar", "-xzf", archive, "-C", downloadDir, "linux-amd64/helm"); err != nil {
+		return err
+	}
+	return s.run(ctx, "sudo", "install", "-D", "-m", "755", filepath.Join(downloadDir, "linux-amd64", "helm"), s.hostPath(filepath.Join(toolsBinDir, "helm")))
+}
+
+func (s *OSConfigStep) hostPath(path string) string {
+	return filepath.Join(s.HostRoot, path)
+}
+
+func (s *OSConfigStep) exists(path string) (bool, error) {
+	_, err := os.Stat(s.hostPath(path))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (s *OSConfigStep) fileHasContent(path, content string) (bool, error) {
+	data, err := os.ReadFile(s.hostPath(path))
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return string(data) == content, nil
+}
+
+// writeFile writes the content to a temporary file and installs it as root.
+func (s *OSConfigStep) writeFile(ctx context.Context, path, content string) error {
+	file, err := os.CreateTemp("", "os-config-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.WriteString(content); err != nil {
+		file.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	return s.run(ctx, "sudo", "install", "-D", "-m", "644", file.Name(), s.hostPath(path))
+}
+
+func (s *OSConfigStep) run(ctx context.Context, command ...string) error {
+	output, err := s.ShellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: command,
+		Timeout: osConfigCommandTimeout,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return fmt.Errorf("%s: %s: %s", strings.Join(command, " "), err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return fmt.Errorf("%s: %s", strings.Join(command, " "), err.ErrorMsg)
+	}
+	return nil
+}
@@ -0,0 +1,224 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+// hostShellUtility runs file commands against a temporary host root without sudo, stubs
+// the commands that change the running kernel and fakes the downloads.
+type hostShellUtility struct {
+	commands []string
+	tools    []string
+}
+
+func (h *hostShellUtility) Run(ctx context.Context, input steps.ShellUtilityInput) (*steps.ShellUtilityOutput, *internal.OrchInstallerError) {
+	command := input.Command
+	h.commands = append(h.commands, strings.Join(command, " "))
+	output := &steps.ShellUtilityOutput{}
+	if command[0] == "sudo" {
+		command = command[1:]
+	}
+	var err error
+	switch command[0] {
+	case "modprobe", "sysctl":
+	case "sh":
+		tool := strings.TrimPrefix(command[2], "command -v ")
+		if slices.Contains(h.tools, tool) {
+			output.Stdout.WriteString("/usr/bin/" + tool + "\n")
+		}
+	case "curl":
+		err = os.WriteFile(command[slices.Index(command, "-o")+1], []byte("binary"), 0o644)
+	case "tar":
+		dir := command[slices.Index(command, "-C")+1]
+		if err = os.MkdirAll(filepath.Join(dir, "linux-amd64"), 0o755); err == nil {
+			err = os.WriteFile(filepath.Join(dir, command[len(command)-1]), []byte("binary"), 0o755)
+		}
+	default:
+		err = exec.CommandContext(ctx, command[0], command[1:]...).Run()
+	}
+	if err != nil {
+		return output, &internal.OrchInstallerError{ErrorCode: internal.OrchInstallerErrorCodeInternal, ErrorMsg: err.Error()}
+	}
+	return output, nil
+}
+
+func (h *hostShellUtility) Process() *os.Process { return nil }
+func (h *hostShellUtility) Kill() error          { return nil }
+func (h *hostShellUtility) Wait() error          { return nil }
+
+type OSConfigStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.OSConfigStep
+	shellUtility *hostShellUtility
+	hostRoot     string
+}
+
+func TestOSConfigStep(t *testing.T) {
+	suite.Run(t, new(OSConfigStepTest))
+}
+
+func (s *OSConfigStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{}
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "install"
+	s.hostRoot = s.T().TempDir()
+	s.shellUtility = &hostShellUtility{}
+	s.step = onprem.CreateOSConfigStep("", false, nil)
+	s.step.ShellUtility = s.shellUtility
+	s.step.HostRoot = s.hostRoot
+}
+
+func (s *OSConfigStepTest) hostFileExists(path string) bool {
+	_, err := os.Stat(filepath.Join(s.hostRoot, path))
+	return err == nil
+}
+
+// changingCommands returns the recorded commands run with sudo.
+func (s *OSConfigStepTest) changingCommands() []string {
+	changing := []string{}
+	for _, command := range s.shellUtility.commands {
+		if strings.HasPrefix(command, "sudo ") {
+			changing = append(changing, command)
+		}
+	}
+	return changing
+}
+
+func (s *OSConfigStepTest) TestInstall() {
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	sysctl, readErr := os.ReadFile(filepath.Join(s.hostRoot, "/etc/sysctl.d/99-orch-inotify.conf"))
+	s.Require().NoError(readErr)
+	s.Contains(string(sysctl), "fs.inotify.max_user_watches = 1048576")
+	modules, readErr := os.ReadFile(filepath.Join(s.hostRoot, "/etc/modules-load.d/lv-snapshots.conf"))
+	s.Require().NoError(readErr)
+	s.Equal("dm-snapshot\ndm-mirror\n", string(modules))
+	s.True(s.hostFileExists("/var/openebs/local"))
+	s.True(s.hostFileExists("/usr/local/bin/yq"))
+	s.True(s.hostFileExists("/usr/local/bin/helm"))
+	s.Contains(s.shellUtility.commands, "sudo modprobe dm-snapshot")
+	s.Contains(s.shellUtility.commands, "sudo sysctl --system")
+
+	s.True(runtimeState.Onprem.OSConfig.SysctlConfigured)
+	s.True(runtimeState.Onprem.OSConfig.ModulesConfigured)
+	s.True(runtimeState.Onprem.OSConfig.HostpathDirCreated)
+	s.True(runtimeState.Onprem.OSConfig.YqInstalled)
+	s.True(runtimeState.Onprem.OSConfig.HelmInstalled)
+
+	// A second run finds everything in place and changes nothing
+	s.shellUtility.commands = nil
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.Empty(s.changingCommands())
+	s.True(runtimeState.Onprem.OSConfig.HelmInstalled)
+}
+
+func (s *OSConfigStepTest) TestExistingToolsNotRecorded() {
+	s.shellUtility.tools = []string{"helm"}
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.False(runtimeState.Onprem.OSConfig.HelmInstalled)
+	s.True(runtimeState.Onprem.OSConfig.YqInstalled)
+	s.False(s.hostFileExists("/usr/local/bin/helm"))
+}
+
+func (s *OSConfigStepTest) TestDryRun() {
+	s.runtimeState.DryRun = true
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Empty(s.changingCommands())
+	s.False(s.hostFileExists("/etc"))
+	s.False(s.hostFileExists("/var/openebs/local"))
+	s.Equal(config.OrchInstallerRuntimeState{}.Onprem.OSConfig, runtimeState.Onprem.OSConfig)
+}
+
+func (s *OSConfigStepTest) TestUninstallRevertsRecordedChanges() {
+	// The host had a hostpath directory before the install
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.hostRoot, "/var/openebs/local"), 0o755))
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.False(runtimeState.Onprem.OSConfig.HostpathDirCreated)
+
+	runtimeState.Action = "uninstall"
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.False(s.hostFileExists("/etc/sysctl.d/99-orch-inotify.conf"))
+	s.False(s.hostFileExists("/etc/modules-load.d/lv-snapshots.conf"))
+	s.False(s.hostFileExists("/usr/local/bin/yq"))
+	s.False(s.hostFileExists("/usr/local/bin/helm"))
+	s.True(s.hostFileExists("/var/openebs/local"))
+	s.Equal(config.OrchInstallerRuntimeState{}.Onprem.OSConfig, runtimeState.Onprem.OSConfig)
+}
+
+func (s *OSConfigStepTest) TestUninstallKeepsHostpathData() {
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.True(runtimeState.Onprem.OSConfig.HostpathDirCreated)
+
+	runtimeState.Action = "uninstall"
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.True(s.hostFileExists("/var/openebs/local"))
+	s.True(runtimeState.Onprem.OSConfig.HostpathDirCreated)
+
+	s.config.Onprem.RemoveHostpathData = true
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.False(s.hostFileExists("/var/openebs/local"))
+	s.False(runtimeState.Onprem.OSConfig.HostpathDirCreated)
+}
+
+func (s *OSConfigStepTest) TestUninstallRestoresReplacedFiles() {
+	sysctlPath := filepath.Join(s.hostRoot, "/etc/sysctl.d/99-orch-inotify.conf")
+	s.Require().NoError(os.MkdirAll(filepath.Dir(sysctlPath), 0o755))
+	s.Require().NoError(os.WriteFile(sysctlPath, []byte("fs.inotify.max_user_watches = 524288\n"), 0o644))
+
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.True(runtimeState.Onprem.OSConfig.SysctlConfigured)
+	s.True(runtimeState.Onprem.OSConfig.SysctlBackedUp)
+	s.False(runtimeState.Onprem.OSConfig.ModulesBackedUp)
+
+	runtimeState.Action = "uninstall"
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	sysctl, readErr := os.ReadFile(sysctlPath)
+	s.Require().NoError(readErr)
+	s.Equal("fs.inotify.max_user_watches = 524288\n", string(sysctl))
+	s.False(s.hostFileExists("/etc/sysctl.d/99-orch-inotify.conf.orch-installer-backup"))
+	s.False(s.hostFileExists("/etc/modules-load.d/lv-snapshots.conf"))
+	s.False(runtimeState.Onprem.OSConfig.SysctlConfigured)
+	s.False(runtimeState.Onprem.OSConfig.SysctlBackedUp)
+}
+
+func (s *OSConfigStepTest) TestUninstallDryRun() {
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+
+	s.shellUtility.commands = nil
+	runtimeState.Action = "uninstall"
+	runtimeState.DryRun = true
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, runtimeState)
+	s.Require().Nil(err)
+	s.Empty(s.changingCommands())
+	s.True(s.hostFileExists("/usr/local/bin/helm"))
+	s.True(runtimeState.Onprem.OSConfig.HelmInstalled)
+}
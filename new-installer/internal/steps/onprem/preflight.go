@@ -0,0 +1,342 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+)
+
+const (
+	PreflightReportFile = "preflight-report.json"
+
+	gib = uint64(1) << 30
+	// RKE2 stores container images, etcd and local volumes under this path
+	rke2DataDir = "/var/lib/rancher"
+	// Applied by the OS configuration, lower values break log collection on large sites
+	minInotifyLimit = 1048576
+)
+
+type PreflightStatus string
+
+const (
+	PreflightPass PreflightStatus = "pass"
+	PreflightWarn PreflightStatus = "warn"
+	PreflightFail PreflightStatus = "fail"
+)
+
+type PreflightCheckResult struct {
+	Name    string          `json:"name"`
+	Status  PreflightStatus `json:"status"`
+	Message string          `json:"message"`
+	// A failure that was downgraded to a warning by onprem.ignorePreflightChecks
+	Ignored bool `json:"ignored,omitempty"`
+}
+
+type PreflightReport struct {
+	Scale  config.Scale           `json:"scale"`
+	Passed bool                   `json:"passed"`
+	Checks []PreflightCheckResult `json:"checks"`
+}
+
+// Minimum host resources for each scale, in number of edge nodes.
+type hostRequirements struct {
+	CPUs      int
+	MemoryGiB uint64
+	DiskGiB   uint64
+}
+
+var scaleRequirements = map[config.Scale]hostRequirements{
+	config.Scale50:   {CPUs: 16, MemoryGiB: 64, DiskGiB: 256},
+	config.Scale100:  {CPUs: 24, MemoryGiB: 96, DiskGiB: 512},
+	config.Scale500:  {CPUs: 32, MemoryGiB: 128, DiskGiB: 1024},
+	config.Scale1000: {CPUs: 48, MemoryGiB: 192, DiskGiB: 1536},
+}
+
+var (
+	// Kernel modules needed by the CNI and the LVM local volumes
+	preflightKernelModules  = []string{"overlay", "br_netfilter", "dm_snapshot", "dm_mirror"}
+	preflightInotifySysctls = []string{
+		"fs.inotify.max_queued_events",
+		"fs.inotify.max_user_instances",
+		"fs.inotify.max_user_watches",
+	}
+	// RKE2 API server, supervisor, kubelet, etcd and the ingress ports
+	preflightPorts = []int{80, 443, 2379, 2380, 6443, 9345, 10250}
+)
+
+type preflightCheck struct {
+	name string
+	run  func(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult
+}
+
+// Checks run in this order, PreflightCheckNames lists them for onprem.ignorePreflightChecks.
+var preflightChecks = []preflightCheck{
+	{name: "cpu", run: checkCPU},
+	{name: "memory", run: checkMemory},
+	{name: "disk", run: checkDisk},
+	{name: "kernel-modules", run: checkKernelModules},
+	{name: "inotify", run: checkInotify},
+	{name: "ports", run: checkPorts},
+	{name: "dns", run: checkDNS},
+	{name: "time-sync", run: checkTimeSync},
+}
+
+func PreflightCheckNames() []string {
+	names := make([]string, len(preflightChecks))
+	for i, check := range preflightChecks {
+		names[i] = check.name
+	}
+	return names
+}
+
+var preflightStepLabels = []string{"onprem", "preflight"}
+
+type PreflightStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	HostInfo               HostInfo
+}
+
+func CreatePreflightStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *PreflightStep {
+	return &PreflightStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             preflightStepLabels,
+		HostInfo:               CreateHostInfo(),
+	}
+}
+
+func (s *PreflightStep) Name() string {
+	return "PreflightStep"
+}
+
+func (s *PreflightStep) Labels() []string {
+	return s.StepLabels
+}
+
+func (s *PreflightStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	names := PreflightCheckNames()
+	for _, name := range cfg.Onprem.IgnorePreflightChecks {
+		if !slices.Contains(names, name) {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("unknown pre-flight check %s in onprem.ignorePreflightChecks, must be one of %s", name, strings.Join(names, ", ")),
+				ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			}
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *PreflightStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+// RunStep checks the host before an install and blocks it if any check fails. The host is
+// already running the orchestrator during an upgrade or uninstall, e.g. the ports are in use.
+func (s *PreflightStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Action != "install" {
+		return runtimeState, nil
+	}
+	report := RunPreflightChecks(ctx, cfg, runtimeState, s.HostInfo)
+
+	internal.Logger().Info("Pre-flight checks:")
+	for _, check := range report.Checks {
+		message := check.Message
+		if check.Ignored {
+			message += " (ignored)"
+		}
+		internal.Logger().Infof("  [%s] %-15s %s", strings.ToUpper(string(check.Status)), check.Name, message)
+	}
+	if runtimeState.LogDir != "" {
+		if err := writePreflightReport(filepath.Join(runtimeState.LogDir, PreflightReportFile), report); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  err.Error(),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+	}
+	if !report.Passed {
+		failed := []string{}
+		for _, check := range report.Checks {
+			if check.Status == PreflightFail {
+				failed = append(failed, check.Name)
+			}
+		}
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg: fmt.Sprintf("pre-flight checks failed: %s. Fix the host or add the checks to onprem.ignorePreflightChecks to proceed anyway",
+				strings.Join(failed, ", ")),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *PreflightStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+// RunPreflightChecks runs every check against the host. Failures of the checks listed in
+// onprem.ignorePreflightChecks are reported as warnings.
+func RunPreflightChecks(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, host HostInfo) PreflightReport {
+	report := PreflightReport{
+		Scale:  cfg.Global.Scale,
+		Passed: true,
+	}
+	for _, check := range preflightChecks {
+		result := check.run(ctx, cfg, runtimeState, host)
+		result.Name = check.name
+		if result.Status == PreflightFail && slices.Contains(cfg.Onprem.IgnorePreflightChecks, check.name) {
+			result.Status = PreflightWarn
+			result.Ignored = true
+		}
+		if result.Status == PreflightFail {
+			report.Passed = false
+		}
+		report.Checks = append(report.Checks, result)
+	}
+	return report
+}
+
+func writePreflightReport(path string, report PreflightReport) error {
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to serialize pre-flight report: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write pre-flight report %s: %w", path, err)
+	}
+	return nil
+}
+
+func requirementsFor(scale config.Scale) hostRequirements {
+	if requirements, ok := scaleRequirements[scale]; ok {
+		return requirements
+	}
+	return scaleRequirements[config.Scale50]
+}
+
+func checkResult(status PreflightStatus, format string, args ...any) PreflightCheckResult {
+	return PreflightCheckResult{Status: status, Message: fmt.Sprintf(format, args...)}
+}
+
+func checkCPU(_ context.Context, cfg config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	required := requirementsFor(cfg.Global.Scale).CPUs
+	cpus := host.CPUCount()
+	if cpus < required {
+		return checkResult(PreflightFail, "%d CPUs, %d required for scale %d", cpus, required, cfg.Global.Scale)
+	}
+	return checkResult(PreflightPass, "%d CPUs", cpus)
+}
+
+func checkMemory(_ context.Context, cfg config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	required := requirementsFor(cfg.Global.Scale).MemoryGiB
+	memory, err := host.MemoryBytes()
+	if err != nil {
+		return checkResult(PreflightFail, "%s", err)
+	}
+	// The kernel reserves part of the installed memory, allow 5% below the requirement
+	if memory < required*gib/100*95 {
+		return checkResult(PreflightFail, "%d GiB of memory, %d GiB required for scale %d", memory/gib, required, cfg.Global.Scale)
+	}
+	return checkResult(PreflightPass, "%d GiB of memory", memory/gib)
+}
+
+func checkDisk(_ context.Context, cfg config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	required := requirementsFor(cfg.Global.Scale).DiskGiB
+	free, err := host.FreeDiskBytes(rke2DataDir)
+	if err != nil {
+		return checkResult(PreflightFail, "%s", err)
+	}
+	if free < required*gib {
+		return checkResult(PreflightFail, "%d GiB free for %s, %d GiB required for scale %d", free/gib, rke2DataDir, required, cfg.Global.Scale)
+	}
+	return checkResult(PreflightPass, "%d GiB free for %s", free/gib, rke2DataDir)
+}
+
+// The OS configuration loads the modules, a missing one is only a warning before install.
+func checkKernelModules(_ context.Context, _ config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	missing := []string{}
+	for _, module := range preflightKernelModules {
+		if !host.KernelModuleLoaded(module) {
+			missing = append(missing, module)
+		}
+	}
+	if len(missing) > 0 {
+		return checkResult(PreflightWarn, "not loaded: %s", strings.Join(missing, ", "))
+	}
+	return checkResult(PreflightPass, "%s loaded", strings.Join(preflightKernelModules, ", "))
+}
+
+// The OS configuration raises the limits, a low value is only a warning before install.
+func checkInotify(_ context.Context, _ config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	low := []string{}
+	for _, name := range preflightInotifySysctls {
+		value, err := host.Sysctl(name)
+		if err != nil {
+			return checkResult(PreflightWarn, "%s", err)
+		}
+		if n, err := strconv.Atoi(value); err != nil || n < minInotifyLimit {
+			low = append(low, fmt.Sprintf("%s=%s", name, value))
+		}
+	}
+	if len(low) > 0 {
+		return checkResult(PreflightWarn, "below %d: %s", minInotifyLimit, strings.Join(low, ", "))
+	}
+	return checkResult(PreflightPass, "inotify limits at least %d", minInotifyLimit)
+}
+
+func checkPorts(_ context.Context, _ config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	inUse := []string{}
+	for _, port := range preflightPorts {
+		if host.PortInUse(port) {
+			inUse = append(inUse, strconv.Itoa(port))
+		}
+	}
+	// A re-run of the install finds the ports taken by the RKE2 it installed
+	if len(inUse) > 0 && runtimeState.Onprem.RKE2Version != "" {
+		return checkResult(PreflightWarn, "ports in use: %s, expected with RKE2 %s installed", strings.Join(inUse, ", "), runtimeState.Onprem.RKE2Version)
+	}
+	if len(inUse) > 0 {
+		return checkResult(PreflightFail, "ports in use: %s", strings.Join(inUse, ", "))
+	}
+	return checkResult(PreflightPass, "ports available")
+}
+
+// Edge nodes reach the orchestrator through names under the parent domain, which may be
+// added to DNS after the install.
+func checkDNS(ctx context.Context, cfg config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	if cfg.Global.ParentDomain == "" {
+		return checkResult(PreflightWarn, "parent domain is not set")
+	}
+	addresses, err := host.LookupHost(ctx, cfg.Global.ParentDomain)
+	if err != nil || len(addresses) == 0 {
+		return checkResult(PreflightWarn, "%s does not resolve: %v", cfg.Global.ParentDomain, err)
+	}
+	return checkResult(PreflightPass, "%s resolves to %s", cfg.Global.ParentDomain, strings.Join(addresses, ", "))
+}
+
+func checkTimeSync(ctx context.Context, _ config.OrchInstallerConfig, _ config.OrchInstallerRuntimeState, host HostInfo) PreflightCheckResult {
+	synchronized, err := host.TimeSynchronized(ctx)
+	if err != nil {
+		return checkResult(PreflightWarn, "%s", err)
+	}
+	if !synchronized {
+		return checkResult(PreflightWarn, "system clock is not synchronized, certificates may be rejected by edge nodes")
+	}
+	return checkResult(PreflightPass, "system clock synchronized")
+}
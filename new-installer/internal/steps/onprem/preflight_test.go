@@ -0,0 +1,190 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+const gib = uint64(1) << 30
+
+type fakeHostInfo struct {
+	cpus           int
+	memory         uint64
+	freeDisk       uint64
+	missingModules []string
+	sysctls        map[string]string
+	portsInUse     []int
+	resolves       bool
+	timeSynced     bool
+}
+
+func (f *fakeHostInfo) CPUCount() int                             { return f.cpus }
+func (f *fakeHostInfo) MemoryBytes() (uint64, error)              { return f.memory, nil }
+func (f *fakeHostInfo) FreeDiskBytes(path string) (uint64, error) { return f.freeDisk, nil }
+func (f *fakeHostInfo) KernelModuleLoaded(name string) bool {
+	return !slices.Contains(f.missingModules, name)
+}
+
+func (f *fakeHostInfo) Sysctl(name string) (string, error) {
+	if value, ok := f.sysctls[name]; ok {
+		return value, nil
+	}
+	return "1048576", nil
+}
+func (f *fakeHostInfo) PortInUse(port int) bool { return slices.Contains(f.portsInUse, port) }
+func (f *fakeHostInfo) LookupHost(ctx context.Context, host string) ([]string, error) {
+	if !f.resolves {
+		return nil, fmt.Errorf("no such host")
+	}
+	return []string{"192.168.1.2"}, nil
+}
+
+func (f *fakeHostInfo) TimeSynchronized(ctx context.Context) (bool, error) {
+	return f.timeSynced, nil
+}
+
+type PreflightStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.PreflightStep
+	host         *fakeHostInfo
+}
+
+func TestPreflightStep(t *testing.T) {
+	suite.Run(t, new(PreflightStepTest))
+}
+
+func (s *PreflightStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{}
+	s.config.Global.Scale = config.Scale100
+	s.config.Global.ParentDomain = "example.com"
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "install"
+	s.runtimeState.LogDir = s.T().TempDir()
+	s.host = &fakeHostInfo{
+		cpus:       24,
+		memory:     96 * gib,
+		freeDisk:   1024 * gib,
+		resolves:   true,
+		timeSynced: true,
+	}
+	s.step = onprem.CreatePreflightStep("", false, nil)
+	s.step.HostInfo = s.host
+}
+
+func (s *PreflightStepTest) readReport() onprem.PreflightReport {
+	data, err := os.ReadFile(filepath.Join(s.runtimeState.LogDir, onprem.PreflightReportFile))
+	s.Require().NoError(err)
+	report := onprem.PreflightReport{}
+	s.Require().NoError(json.Unmarshal(data, &report))
+	return report
+}
+
+func (s *PreflightStepTest) statuses(report onprem.PreflightReport) map[string]onprem.PreflightStatus {
+	statuses := map[string]onprem.PreflightStatus{}
+	for _, check := range report.Checks {
+		statuses[check.Name] = check.Status
+	}
+	return statuses
+}
+
+func (s *PreflightStepTest) TestPassingHost() {
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Nil(err)
+	report := s.readReport()
+	s.True(report.Passed)
+	s.Equal(config.Scale100, report.Scale)
+	s.Len(report.Checks, len(onprem.PreflightCheckNames()))
+	for name, status := range s.statuses(report) {
+		s.Equal(onprem.PreflightPass, status, name)
+	}
+}
+
+func (s *PreflightStepTest) TestUndersizedHost() {
+	s.config.Global.Scale = config.Scale500
+	s.host.freeDisk = 600 * gib
+	s.host.portsInUse = []int{6443}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.Contains(err.ErrorMsg, "cpu, memory, disk, ports")
+
+	report := s.readReport()
+	s.False(report.Passed)
+	statuses := s.statuses(report)
+	s.Equal(onprem.PreflightFail, statuses["cpu"])
+	s.Equal(onprem.PreflightFail, statuses["memory"])
+	s.Equal(onprem.PreflightFail, statuses["disk"])
+	s.Equal(onprem.PreflightFail, statuses["ports"])
+}
+
+func (s *PreflightStepTest) TestWarningsDoNotBlock() {
+	s.host.missingModules = []string{"dm_snapshot"}
+	s.host.sysctls = map[string]string{"fs.inotify.max_user_instances": "128"}
+	s.host.resolves = false
+	s.host.timeSynced = false
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Nil(err)
+
+	statuses := s.statuses(s.readReport())
+	s.Equal(onprem.PreflightWarn, statuses["kernel-modules"])
+	s.Equal(onprem.PreflightWarn, statuses["inotify"])
+	s.Equal(onprem.PreflightWarn, statuses["dns"])
+	s.Equal(onprem.PreflightWarn, statuses["time-sync"])
+}
+
+func (s *PreflightStepTest) TestIgnoredFailure() {
+	s.host.cpus = 8
+	s.config.Onprem.IgnorePreflightChecks = []string{"cpu"}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Nil(err)
+
+	report := s.readReport()
+	s.True(report.Passed)
+	s.Equal(onprem.PreflightWarn, report.Checks[0].Status)
+	s.True(report.Checks[0].Ignored)
+}
+
+func (s *PreflightStepTest) TestUnknownIgnoredCheck() {
+	s.config.Onprem.IgnorePreflightChecks = []string{"gpu"}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+}
+
+func (s *PreflightStepTest) TestPortsOfInstalledRKE2() {
+	s.host.portsInUse = []int{443, 6443}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(onprem.PreflightFail, s.statuses(s.readReport())["ports"])
+
+	// A re-run of the install after RKE2 was installed
+	s.runtimeState.Onprem.RKE2Version = "v1.30.10+rke2r1"
+	_, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Nil(err)
+	s.Equal(onprem.PreflightWarn, s.statuses(s.readReport())["ports"])
+}
+
+func (s *PreflightStepTest) TestSkippedOnUpgrade() {
+	s.runtimeState.Action = "upgrade"
+	s.host.portsInUse = []int{443, 6443}
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Nil(err)
+	_, statErr := os.Stat(filepath.Join(s.runtimeState.LogDir, onprem.PreflightReportFile))
+	s.True(os.IsNotExist(statErr))
+}
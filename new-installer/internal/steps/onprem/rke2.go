@@ -11,21 +11,29 @@ import (
 	"os"
 	"os/exec"
 	"os/user"
+	"path/filepath"
+	"strings"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 )
 
 const (
 	rke2ImagesDir   = "/var/lib/rancher/rke2/agent/images"
 	useDebInstaller = true // Set to true if using deb package installation
+	// The RKE2 tarball is extracted here, its systemd units end up in lib/systemd/system
+	rke2InstallPrefix = "/usr/local"
 )
 
+var rke2StepLabels = []string{"onprem", "rke2"}
+
 type Rke2Step struct {
 	RootPath               string
 	KeepGeneratedFiles     bool
 	orchConfigReaderWriter config.OrchConfigReaderWriter
 	StepLabels             []string
+	ShellUtility           steps.ShellUtility
 }
 
 func CreateRke2Step(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *Rke2Step {
@@ -33,6 +41,8 @@ func CreateRke2Step(rootPath string, keepGeneratedFiles bool, orchConfigReaderWr
 		RootPath:               rootPath,
 		KeepGeneratedFiles:     keepGeneratedFiles,
 		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             rke2StepLabels,
+		ShellUtility:           steps.CreateShellUtility(),
 	}
 }
 
@@ -44,23 +54,35 @@ func (s *Rke2Step) Labels() []string {
 	return s.StepLabels
 }
 
-func (s *Rke2Step) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+// ConfigStep resolves the artifact locations from the config, the ones in the runtime state are
+// left from an earlier release when the ArtifactDownloader is not selected by --target.
+func (s *Rke2Step) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	locations, err := ResolveArtifactLocations(s.RootPath, cfg)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	runtimeState.Onprem.Artifacts = locations
 	return runtimeState, nil
 }
 
 func (s *Rke2Step) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	// no-op for now
-	fmt.Println("PreStep for Rke2Step is a no-op")
+	internal.Logger().Info("PreStep for Rke2Step is a no-op")
 	return runtimeState, nil
 }
 
 func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
 	if runtimeState.Action == "uninstall" {
-		fmt.Println("Running RKE2 uninstallation step")
+		internal.Logger().Info("Running RKE2 uninstallation step")
+		s.removeNodes(ctx, config)
+
 		// Stop RKE2 service
 		if err := exec.Command("sudo", "/usr/local/bin/rke2-killall.sh").Run(); err != nil {
 			// Upon failure, just log the error and continue
-			fmt.Printf("Failed to stop RKE2 service(may not be running), continuing with uninstall...: %s\n", err)
+			internal.Logger().Warnf("Failed to stop RKE2 service(may not be running), continuing with uninstall...: %s", err)
 		}
 
 		// Remove RKE2 service
@@ -70,10 +92,23 @@ func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfi
 				ErrorCode: internal.OrchInstallerErrorCodeInternal,
 			}
 		}
+		runtimeState.Onprem.RKE2Version = ""
+	}
+
+	if runtimeState.Action == "upgrade" {
+		return s.upgradeRKE2(ctx, runtimeState)
 	}
 
 	if runtimeState.Action == "install" {
-		fmt.Println("Running RKE2 installation step")
+		internal.Logger().Info("Running RKE2 installation step")
+		locations := runtimeState.Onprem.Artifacts
+
+		if err := s.installBundledImages(ctx, locations.ImagesDir); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to install the bundled images: %s", err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
 
 		if useDebInstaller {
 			var dockerUsername, dockerPassword string
@@ -89,7 +124,7 @@ func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfi
 			}
 
 			var kubeConfig string
-			if kubeConfig, err = installRKE2(INSTALLERS_DIR, dockerUsername, dockerPassword, currentUser.Username); err != nil {
+			if kubeConfig, err = installRKE2(locations.InstallersDir, locations.OrchVersion, dockerUsername, dockerPassword, currentUser.Username); err != nil {
 				return runtimeState, &internal.OrchInstallerError{
 					ErrorMsg:  fmt.Sprintf("failed to install RKE2: %s", err),
 					ErrorCode: internal.OrchInstallerErrorCodeInternal,
@@ -97,17 +132,18 @@ func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfi
 			}
 
 			runtimeState.Onprem.KubeConfig = kubeConfig
-			fmt.Println("RKE2 installation completed successfully")
+			runtimeState.Onprem.RKE2Version = locations.RKE2Version
+			internal.Logger().Info("RKE2 installation completed successfully")
 
 		} else {
 
-			if err := installRKE2New(ctx, INSTALLERS_DIR); err != nil {
+			if err := installRKE2New(ctx, locations.InstallersDir, locations.RKE2Version); err != nil {
 				return runtimeState, &internal.OrchInstallerError{
 					ErrorMsg:  fmt.Sprintf("failed to install RKE2: %s", err),
 					ErrorCode: internal.OrchInstallerErrorCodeInternal,
 				}
 			}
-			fmt.Println("RKE2 installation completed successfully")
+			internal.Logger().Info("RKE2 installation completed successfully")
 
 			if err := createRKE2ImagesDir(rke2ImagesDir); err != nil {
 				return runtimeState, &internal.OrchInstallerError{
@@ -115,16 +151,16 @@ func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfi
 					ErrorCode: internal.OrchInstallerErrorCodeInternal,
 				}
 			}
-			fmt.Println("RKE2 images directory created successfully")
+			internal.Logger().Info("RKE2 images directory created successfully")
 
-			if err := copyRKE2Images(INSTALLERS_DIR, rke2ImagesDir); err != nil {
+			if err := copyRKE2Images(locations.InstallersDir, rke2ImagesDir); err != nil {
 				return runtimeState, &internal.OrchInstallerError{
 					ErrorMsg:  fmt.Sprintf("failed to copy RKE2 images to %s: %s", rke2ImagesDir, err),
 					ErrorCode: internal.OrchInstallerErrorCodeInternal,
 				}
 			}
 
-			fmt.Println("RKE2 images copied successfully")
+			internal.Logger().Info("RKE2 images copied successfully")
 
 			if err := enableRKE2Service(ctx); err != nil {
 				return runtimeState, &internal.OrchInstallerError{
@@ -133,8 +169,11 @@ func (s *Rke2Step) RunStep(ctx context.Context, config config.OrchInstallerConfi
 				}
 			}
 
-			fmt.Println("RKE2 service enabled and started successfully")
+			internal.Logger().Info("RKE2 service enabled and started successfully")
+			runtimeState.Onprem.RKE2Version = locations.RKE2Version
 		}
+
+		return s.joinNodes(ctx, config, runtimeState)
 	}
 
 	return runtimeState, nil
@@ -144,24 +183,91 @@ func (s *Rke2Step) PostStep(ctx context.Context, config config.OrchInstallerConf
 	return runtimeState, prevStepError
 }
 
-func installRKE2New(ctx context.Context, artifactDir string) error {
-	fmt.Println("Installing RKE2...")
+// upgradeRKE2 upgrades the cluster to the RKE2 version of the release one minor version at a time. The runtime
+// state records the version reached after each hop so a failed upgrade resumes from there.
+func (s *Rke2Step) upgradeRKE2(ctx context.Context, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if runtimeState.Onprem.KubeConfig == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot upgrade RKE2",
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	kubeConfigFile, err := writeKubeConfig(runtimeState.Onprem.KubeConfig)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	defer os.Remove(kubeConfigFile)
+	upgrader := &rke2Cluster{
+		shellUtility:   s.ShellUtility,
+		kubeConfigFile: kubeConfigFile,
+	}
 
-	if err := os.Chmod(fmt.Sprintf("%s/install.sh", artifactDir), 0o755); err != nil {
-		return fmt.Errorf("modifying install script permissions: %w", err)
+	installedVersion, err := upgrader.installedVersion(ctx)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to detect the installed RKE2 version: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	runtimeState.Onprem.RKE2Version = installedVersion
+	targetVersion := runtimeState.Onprem.Artifacts.RKE2Version
+	hops, err := ComputeRKE2UpgradeHops(installedVersion, targetVersion)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	if len(hops) == 0 {
+		internal.Logger().Infof("RKE2 is already at version %s, skipping upgrade", targetVersion)
+		return runtimeState, nil
 	}
+	internal.Logger().Infof("Upgrading RKE2 from %s through %s", installedVersion, strings.Join(hops, ", "))
 
-	cmd := exec.CommandContext(ctx, "sudo", "env",
-		fmt.Sprintf("INSTALL_RKE2_ARTIFACT_PATH=%s", artifactDir),
-		fmt.Sprintf("INSTALL_RKE2_METHOD=%s", "tar"),
-		fmt.Sprintf("INSTALL_RKE2_VERSION=%s", rke2Version),
-		"sh", "-c", fmt.Sprintf("%s/install.sh", artifactDir),
-	)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	if err := upgrader.prepare(ctx); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to prepare RKE2 upgrade: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	for _, hop := range hops {
+		if err := upgrader.upgradeTo(ctx, hop); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to upgrade RKE2 to %s: %s", hop, err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+		runtimeState.Onprem.RKE2Version = hop
+		internal.Logger().Infof("RKE2 upgraded to version %s", hop)
+	}
+	if err := upgrader.cleanup(ctx); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to clean up after RKE2 upgrade: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	return runtimeState, nil
+}
 
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to run the install script: %w", err)
+// installRKE2New installs RKE2 from the tarball verified by the ArtifactDownloader, the way the
+// tarball method of the RKE2 install script does, without running a script that is not verified.
+func installRKE2New(ctx context.Context, artifactDir, version string) error {
+	internal.Logger().Infof("Installing RKE2 %s...", version)
+
+	for _, command := range [][]string{
+		{"sudo", "mkdir", "-p", rke2InstallPrefix},
+		{"sudo", "tar", "-xzf", filepath.Join(artifactDir, rke2Binary), "-C", rke2InstallPrefix},
+		{"sudo", "systemctl", "daemon-reload"},
+	} {
+		cmd := exec.CommandContext(ctx, command[0], command[1:]...)
+		cmd.Stdout = os.Stdout
+		cmd.Stderr = os.Stderr
+		if err := cmd.Run(); err != nil {
+			return fmt.Errorf("failed to run %s: %w", strings.Join(command, " "), err)
+		}
 	}
 
 	return nil
@@ -175,6 +281,25 @@ func createRKE2ImagesDir(imagesDir string) error {
 	return nil
 }
 
+// installBundledImages places the images exported from a bundle where RKE2 imports them when
+// it starts.
+func (s *Rke2Step) installBundledImages(ctx context.Context, imagesDir string) error {
+	archives, err := filepath.Glob(filepath.Join(imagesDir, "*.tar"))
+	if err != nil || len(archives) == 0 {
+		return err
+	}
+	for _, command := range [][]string{
+		{"sudo", "mkdir", "-p", rke2ImagesDir},
+		append([]string{"sudo", "cp", "-t", rke2ImagesDir}, archives...),
+	} {
+		if _, err := s.ShellUtility.Run(ctx, steps.ShellUtilityInput{Command: command, Timeout: 600}); err != nil {
+			return fmt.Errorf("%s", err.ErrorMsg)
+		}
+	}
+	internal.Logger().Infof("Copied %d bundled images to %s", len(archives), rke2ImagesDir)
+	return nil
+}
+
 func copyRKE2Images(source, destination string) error {
 	for _, image := range []string{
 		rke2ImagesPkg,
@@ -230,24 +355,24 @@ func enableRKE2Service(ctx context.Context) error {
 	return nil
 }
 
-func installRKE2(debDirName, dockerUsername, dockerPassword, currentUser string) (string, error) {
-	fmt.Println("Installing RKE2...")
+func installRKE2(debDirName, orchVersion, dockerUsername, dockerPassword, currentUser string) (string, error) {
+	internal.Logger().Info("Installing RKE2...")
 	var cmd *exec.Cmd
 	var kubeconfig string
 	if dockerUsername != "" && dockerPassword != "" {
-		fmt.Println("Docker credentials provided. Installing RKE2 with Docker credentials")
+		internal.Logger().Info("Docker credentials provided. Installing RKE2 with Docker credentials")
 		cmd = exec.Command("sudo", "env",
 			fmt.Sprintf("DOCKER_USERNAME=%s", dockerUsername),
 			fmt.Sprintf("DOCKER_PASSWORD=%s", dockerPassword),
 			"NEEDRESTART_MODE=a", "DEBIAN_FRONTEND=noninteractive",
 			"apt-get", "install", "-y",
-			fmt.Sprintf("%s/onprem-ke-installer_%s_amd64.deb", debDirName, ORCH_VERSION),
+			fmt.Sprintf("%s/onprem-ke-installer_%s_amd64.deb", debDirName, orchVersion),
 		)
 	} else {
 		cmd = exec.Command("sudo",
 			"NEEDRESTART_MODE=a", "DEBIAN_FRONTEND=noninteractive",
 			"apt-get", "install", "-y",
-			fmt.Sprintf("%s/onprem-ke-installer_%s_amd64.deb", debDirName, ORCH_VERSION),
+			fmt.Sprintf("%s/onprem-ke-installer_%s_amd64.deb", debDirName, orchVersion),
 		)
 	}
 	cmd.Stdout = os.Stdout
@@ -255,7 +380,7 @@ func installRKE2(debDirName, dockerUsername, dockerPassword, currentUser string)
 	if err := cmd.Run(); err != nil {
 		return "", fmt.Errorf("failed to install RKE2: %w", err)
 	}
-	fmt.Println("OS level configuration installed and RKE2 Installed")
+	internal.Logger().Info("OS level configuration installed and RKE2 Installed")
 
 	kubeDir := fmt.Sprintf("/home/%s/.kube", currentUser)
 	if err := os.MkdirAll(kubeDir, 0o700); err != nil {
@@ -0,0 +1,255 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	rke2NodeTokenPath      = "/var/lib/rancher/rke2/server/node-token"
+	rke2ConfigPath         = "/etc/rancher/rke2/config.yaml"
+	rke2SupervisorPort     = 9345
+	rke2NodeJoinTimeout    = 900 // seconds
+	rke2NodeCommandTimeout = 300 // seconds
+)
+
+// rke2NodeJoiner joins additional nodes to the cluster bootstrapped on this machine.
+type rke2NodeJoiner struct {
+	shellUtility  steps.ShellUtility
+	serverAddress string
+	// The verified RKE2 tarball and images the local server was installed from
+	artifacts config.ArtifactLocations
+}
+
+func sshTarget(node config.OnpremNode) string {
+	return fmt.Sprintf("%s@%s", node.SSHUser, node.Address)
+}
+
+func sshOptions(node config.OnpremNode) []string {
+	return []string{
+		"-i", os.ExpandEnv(node.SSHKeyPath),
+		"-o", "BatchMode=yes",
+		"-o", "StrictHostKeyChecking=accept-new",
+	}
+}
+
+func (j *rke2NodeJoiner) ssh(ctx context.Context, node config.OnpremNode, timeout int, command string) (string, error) {
+	return j.sshWithStdin(ctx, node, timeout, "", command)
+}
+
+// sshWithStdin runs command on the node with stdin written to its standard input.
+func (j *rke2NodeJoiner) sshWithStdin(ctx context.Context, node config.OnpremNode, timeout int, stdin, command string) (string, error) {
+	args := append([]string{"ssh"}, sshOptions(node)...)
+	output, err := j.shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: append(args, sshTarget(node), command),
+		Timeout: timeout,
+		Stdin:   stdin,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return "", fmt.Errorf("%s on %s: %s: %s", command, node.Address, err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return "", fmt.Errorf("%s on %s: %s", command, node.Address, err.ErrorMsg)
+	}
+	return output.Stdout.String(), nil
+}
+
+func (j *rke2NodeJoiner) scp(ctx context.Context, node config.OnpremNode, localPath, remotePath string) error {
+	args := append([]string{"scp"}, sshOptions(node)...)
+	output, err := j.shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: append(args, localPath, fmt.Sprintf("%s:%s", sshTarget(node), remotePath)),
+		Timeout: rke2NodeCommandTimeout,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return fmt.Errorf("failed to copy %s to %s: %s: %s", localPath, node.Address, err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return fmt.Errorf("failed to copy %s to %s: %s", localPath, node.Address, err.ErrorMsg)
+	}
+	return nil
+}
+
+// joinToken reads the token other nodes use to join the local server.
+func (j *rke2NodeJoiner) joinToken(ctx context.Context) (string, error) {
+	output, err := j.shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: []string{"sudo", "cat", rke2NodeTokenPath},
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to read the RKE2 join token: %s", err.ErrorMsg)
+	}
+	token := strings.TrimSpace(output.Stdout.String())
+	if token == "" {
+		return "", fmt.Errorf("RKE2 join token %s is empty", rke2NodeTokenPath)
+	}
+	return token, nil
+}
+
+// writeNodeConfig writes the RKE2 config pointing to the local server on the node. The config
+// is streamed over SSH rather than passed on the command line or staged in a file, to keep the
+// token out of the process list and off the disk of the node.
+func (j *rke2NodeJoiner) writeNodeConfig(ctx context.Context, node config.OnpremNode, token string) error {
+	_, err := j.sshWithStdin(ctx, node, rke2NodeCommandTimeout,
+		fmt.Sprintf("server: https://%s:%d\ntoken: %s\n", j.serverAddress, rke2SupervisorPort, token),
+		fmt.Sprintf("sudo install -D -m 600 /dev/stdin %s", rke2ConfigPath))
+	return err
+}
+
+// install copies the RKE2 tarball and images to a private directory of the node and installs
+// them the same way as on the local server, so that the node runs the verified release.
+func (j *rke2NodeJoiner) install(ctx context.Context, node config.OnpremNode) error {
+	images := []string{
+		filepath.Join(j.artifacts.InstallersDir, rke2ImagesPkg),
+		filepath.Join(j.artifacts.InstallersDir, rke2CalicoImagePkg),
+	}
+	if j.artifacts.ImagesDir != "" {
+		bundledImages, err := filepath.Glob(filepath.Join(j.artifacts.ImagesDir, "*.tar"))
+		if err != nil {
+			return fmt.Errorf("failed to list the bundled images: %w", err)
+		}
+		images = append(images, bundledImages...)
+	}
+
+	output, err := j.ssh(ctx, node, rke2NodeCommandTimeout, "mktemp -d")
+	if err != nil {
+		return err
+	}
+	dir := strings.TrimSpace(output)
+	if dir == "" {
+		return fmt.Errorf("mktemp on %s returned no directory", node.Address)
+	}
+	defer func() {
+		if _, err := j.ssh(ctx, node, rke2NodeCommandTimeout, "rm -rf "+dir); err != nil {
+			internal.Logger().Warnf("Failed to remove %s from %s: %s", dir, node.Address, err)
+		}
+	}()
+	if err := j.scp(ctx, node, filepath.Join(j.artifacts.InstallersDir, rke2Binary), dir+"/"); err != nil {
+		return err
+	}
+	remoteImages := make([]string, 0, len(images))
+	for _, image := range images {
+		if err := j.scp(ctx, node, image, dir+"/"); err != nil {
+			return err
+		}
+		remoteImages = append(remoteImages, dir+"/"+filepath.Base(image))
+	}
+	_, err = j.ssh(ctx, node, rke2NodeJoinTimeout, strings.Join([]string{
+		fmt.Sprintf("sudo mkdir -p %s %s", rke2InstallPrefix, rke2ImagesDir),
+		fmt.Sprintf("sudo tar -xzf %s/%s -C %s", dir, rke2Binary, rke2InstallPrefix),
+		fmt.Sprintf("sudo cp -t %s %s", rke2ImagesDir, strings.Join(remoteImages, " ")),
+		"sudo systemctl daemon-reload",
+	}, " && "))
+	return err
+}
+
+// join installs RKE2 on the node and starts it as a server or an agent of the local cluster.
+func (j *rke2NodeJoiner) join(ctx context.Context, node config.OnpremNode, token string) error {
+	internal.Logger().Infof("Joining %s %s to the RKE2 cluster with RKE2 %s", node.Role, node.Address, j.artifacts.RKE2Version)
+	if err := j.writeNodeConfig(ctx, node, token); err != nil {
+		return err
+	}
+	if err := j.install(ctx, node); err != nil {
+		return err
+	}
+	// Starting a server blocks until it has joined etcd
+	_, err := j.ssh(ctx, node, rke2NodeJoinTimeout, fmt.Sprintf("sudo systemctl enable --now rke2-%s.service", node.Role))
+	return err
+}
+
+// remove stops and uninstalls RKE2 from the node.
+func (j *rke2NodeJoiner) remove(ctx context.Context, node config.OnpremNode) error {
+	internal.Logger().Infof("Removing %s %s from the RKE2 cluster", node.Role, node.Address)
+	_, err := j.ssh(ctx, node, rke2NodeCommandTimeout, "sudo /usr/local/bin/rke2-uninstall.sh")
+	return err
+}
+
+// joinOrder returns the servers followed by the agents. Servers join one at a time so that
+// etcd keeps quorum, and agents need a server to register with.
+func joinOrder(nodes []config.OnpremNode) []config.OnpremNode {
+	ordered := make([]config.OnpremNode, 0, len(nodes))
+	for _, role := range []string{config.OnpremNodeRoleServer, config.OnpremNodeRoleAgent} {
+		for _, node := range nodes {
+			if node.Role == role {
+				ordered = append(ordered, node)
+			}
+		}
+	}
+	return ordered
+}
+
+// joinNodes joins the additional nodes to the cluster installed on this machine and waits for
+// all of them to be Ready.
+func (s *Rke2Step) joinNodes(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if len(cfg.Onprem.Nodes) == 0 {
+		return runtimeState, nil
+	}
+	joiner := &rke2NodeJoiner{
+		shellUtility:  s.ShellUtility,
+		serverAddress: cfg.Onprem.ServerAddress,
+		artifacts:     runtimeState.Onprem.Artifacts,
+	}
+	token, err := joiner.joinToken(ctx)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	for _, node := range joinOrder(cfg.Onprem.Nodes) {
+		if err := joiner.join(ctx, node, token); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to join %s %s: %s", node.Role, node.Address, err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+	}
+
+	kubeConfigFile, err := writeKubeConfig(runtimeState.Onprem.KubeConfig)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	defer os.Remove(kubeConfigFile)
+	cluster := &rke2Cluster{
+		shellUtility:   s.ShellUtility,
+		kubeConfigFile: kubeConfigFile,
+		expectedNodes:  len(cfg.Onprem.Nodes) + 1,
+	}
+	if err := cluster.waitFor(ctx, fmt.Sprintf("%d nodes to be Ready", cluster.expectedNodes), func() (bool, error) {
+		return cluster.nodesReady(ctx)
+	}); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	internal.Logger().Infof("All %d RKE2 nodes are Ready", cluster.expectedNodes)
+	return runtimeState, nil
+}
+
+// removeNodes uninstalls RKE2 from the additional nodes in the reverse order they joined.
+// Unreachable nodes are reported and skipped so that they do not block the uninstall.
+func (s *Rke2Step) removeNodes(ctx context.Context, cfg config.OrchInstallerConfig) {
+	joiner := &rke2NodeJoiner{
+		shellUtility:  s.ShellUtility,
+		serverAddress: cfg.Onprem.ServerAddress,
+	}
+	nodes := joinOrder(cfg.Onprem.Nodes)
+	for i := len(nodes) - 1; i >= 0; i-- {
+		if err := joiner.remove(ctx, nodes[i]); err != nil {
+			internal.Logger().Warnf("Failed to uninstall RKE2 from %s, continuing with uninstall...: %s", nodes[i].Address, err)
+		}
+	}
+}
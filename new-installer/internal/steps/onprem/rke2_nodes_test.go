@@ -0,0 +1,173 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/stretchr/testify/suite"
+)
+
+// recordingShellUtility records the commands and reports a Ready node for every node that
+// has been started over SSH.
+type recordingShellUtility struct {
+	commands     []string
+	stdins       map[string]string
+	startedNodes int
+	failAddress  string
+}
+
+func (r *recordingShellUtility) Run(ctx context.Context, input steps.ShellUtilityInput) (*steps.ShellUtilityOutput, *internal.OrchInstallerError) {
+	command := strings.Join(input.Command, " ")
+	r.commands = append(r.commands, command)
+	if input.Stdin != "" {
+		r.stdins[command] = input.Stdin
+	}
+	output := &steps.ShellUtilityOutput{}
+	switch {
+	case r.failAddress != "" && strings.Contains(command, "@"+r.failAddress):
+		output.Stderr.WriteString("connection refused")
+		return output, &internal.OrchInstallerError{ErrorCode: internal.OrchInstallerErrorCodeInternal, ErrorMsg: "exit status 255"}
+	case strings.HasSuffix(command, "mktemp -d"):
+		output.Stdout.WriteString("/tmp/tmp.rke2\n")
+	case strings.HasPrefix(command, "sudo cat "+rke2NodeTokenPath):
+		output.Stdout.WriteString("K10test::server:secret\n")
+	case strings.Contains(command, "systemctl enable --now"):
+		r.startedNodes++
+	case strings.Contains(command, `@.type=="Ready"`):
+		for range r.startedNodes + 1 {
+			output.Stdout.WriteString("True\n")
+		}
+	}
+	return output, nil
+}
+
+func (r *recordingShellUtility) Process() *os.Process { return nil }
+func (r *recordingShellUtility) Kill() error          { return nil }
+func (r *recordingShellUtility) Wait() error          { return nil }
+
+type Rke2NodesTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *Rke2Step
+	shellUtility *recordingShellUtility
+}
+
+func TestRke2Nodes(t *testing.T) {
+	suite.Run(t, new(Rke2NodesTest))
+}
+
+func (s *Rke2NodesTest) SetupTest() {
+	RKE2UpgradePollInterval = time.Millisecond
+	s.config = config.OrchInstallerConfig{}
+	s.config.Onprem.ServerAddress = "192.168.1.10"
+	s.config.Onprem.Nodes = []config.OnpremNode{
+		{Address: "192.168.1.13", Role: config.OnpremNodeRoleAgent, SSHUser: "ubuntu", SSHKeyPath: "/keys/id_ed25519"},
+		{Address: "192.168.1.11", Role: config.OnpremNodeRoleServer, SSHUser: "ubuntu", SSHKeyPath: "/keys/id_ed25519"},
+		{Address: "192.168.1.12", Role: config.OnpremNodeRoleServer, SSHUser: "ubuntu", SSHKeyPath: "/keys/id_ed25519"},
+	}
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Onprem.Artifacts.RKE2Version = rke2Version
+	s.runtimeState.Onprem.Artifacts.InstallersDir = "/work/installers"
+	s.runtimeState.Onprem.KubeConfig = "test-kubeconfig"
+	s.shellUtility = &recordingShellUtility{stdins: map[string]string{}}
+	s.step = CreateRke2Step("", false, nil)
+	s.step.ShellUtility = s.shellUtility
+}
+
+// commandsMatching returns the recorded commands containing substr, in order.
+func (s *Rke2NodesTest) commandsMatching(substr string) []string {
+	matching := []string{}
+	for _, command := range s.shellUtility.commands {
+		if strings.Contains(command, substr) {
+			matching = append(matching, command)
+		}
+	}
+	return matching
+}
+
+func (s *Rke2NodesTest) TestJoinNodes() {
+	_, err := s.step.joinNodes(context.Background(), s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	started := s.commandsMatching("systemctl enable --now")
+	s.Require().Len(started, 3)
+	s.Contains(started[0], "ubuntu@192.168.1.11")
+	s.Contains(started[0], "rke2-server.service")
+	s.Contains(started[1], "ubuntu@192.168.1.12")
+	s.Contains(started[2], "ubuntu@192.168.1.13")
+	s.Contains(started[2], "rke2-agent.service")
+
+	// The verified tarball and images are copied to a private directory of each node, nothing
+	// is fetched from the network
+	for _, file := range rke2VerifiedFiles {
+		copies := s.commandsMatching("/work/installers/" + file)
+		s.Require().Len(copies, 3, file)
+		s.Contains(copies[0], "ubuntu@192.168.1.11:/tmp/tmp.rke2/")
+	}
+	installs := s.commandsMatching("sudo tar -xzf /tmp/tmp.rke2/" + rke2Binary + " -C " + rke2InstallPrefix)
+	s.Require().Len(installs, 3)
+	s.Contains(installs[0], "sudo cp -t "+rke2ImagesDir+" /tmp/tmp.rke2/"+rke2ImagesPkg+" /tmp/tmp.rke2/"+rke2CalicoImagePkg)
+	s.Len(s.commandsMatching("rm -rf /tmp/tmp.rke2"), 3)
+	s.Empty(s.commandsMatching("curl"))
+
+	// The config is streamed to a file only root can read, the join token must never appear on
+	// a command line
+	configs := s.commandsMatching("sudo install -D -m 600 /dev/stdin " + rke2ConfigPath)
+	s.Require().Len(configs, 3)
+	s.Equal("server: https://192.168.1.10:9345\ntoken: K10test::server:secret\n", s.shellUtility.stdins[configs[0]])
+	for _, command := range s.shellUtility.commands {
+		s.NotContains(command, "K10test")
+	}
+}
+
+func (s *Rke2NodesTest) TestJoinNodesWithBundledImages() {
+	imagesDir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(imagesDir, "orch.tar"), []byte("images"), 0o600))
+	s.runtimeState.Onprem.Artifacts.ImagesDir = imagesDir
+	s.config.Onprem.Nodes = s.config.Onprem.Nodes[:1]
+	_, err := s.step.joinNodes(context.Background(), s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Len(s.commandsMatching(filepath.Join(imagesDir, "orch.tar")+" ubuntu@192.168.1.13:/tmp/tmp.rke2/"), 1)
+	installs := s.commandsMatching("sudo cp -t " + rke2ImagesDir)
+	s.Require().Len(installs, 1)
+	s.Contains(installs[0], "/tmp/tmp.rke2/orch.tar")
+}
+
+func (s *Rke2NodesTest) TestJoinNodeFailure() {
+	s.shellUtility.failAddress = "192.168.1.12"
+	_, err := s.step.joinNodes(context.Background(), s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "failed to join server 192.168.1.12")
+	s.Contains(err.ErrorMsg, "connection refused")
+	s.Empty(s.commandsMatching("ubuntu@192.168.1.13"))
+}
+
+func (s *Rke2NodesTest) TestJoinNoNodes() {
+	s.config.Onprem.Nodes = nil
+	_, err := s.step.joinNodes(context.Background(), s.config, s.runtimeState)
+	s.Nil(err)
+	s.Empty(s.shellUtility.commands)
+}
+
+func (s *Rke2NodesTest) TestRemoveNodes() {
+	s.shellUtility.failAddress = "192.168.1.13"
+	s.step.removeNodes(context.Background(), s.config)
+	removed := s.commandsMatching("rke2-uninstall.sh")
+	s.Require().Len(removed, 3)
+	s.Contains(removed[0], "ubuntu@192.168.1.13")
+	s.Contains(removed[1], "ubuntu@192.168.1.12")
+	s.Contains(removed[2], "ubuntu@192.168.1.11")
+}
@@ -0,0 +1,157 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"context"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+const testRKE2TargetVersion = "v1.30.10+rke2r1"
+
+var planVersionRegex = regexp.MustCompile(`(?m)^\s*version: (\S+)`)
+
+// fakeClusterShellUtility emulates kubectl against a cluster where the system-upgrade-controller
+// upgrades every node as soon as a plan is applied.
+type fakeClusterShellUtility struct {
+	nodeVersions []string
+	appliedPlans []string
+	commands     [][]string
+}
+
+func (f *fakeClusterShellUtility) Run(ctx context.Context, input steps.ShellUtilityInput) (*steps.ShellUtilityOutput, *internal.OrchInstallerError) {
+	f.commands = append(f.commands, input.Command)
+	output := &steps.ShellUtilityOutput{}
+	args := strings.Join(input.Command, " ")
+	switch {
+	case strings.Contains(args, "kubeletVersion"):
+		output.Stdout.WriteString(strings.Join(f.nodeVersions, "\n"))
+	case strings.Contains(args, `@.type=="Ready"`):
+		for range f.nodeVersions {
+			output.Stdout.WriteString("True\n")
+		}
+	case strings.Contains(args, "apply -f") && strings.Contains(args, "rke2-upgrade-plan-"):
+		plan, err := os.ReadFile(input.Command[len(input.Command)-1])
+		if err != nil {
+			return output, &internal.OrchInstallerError{ErrorCode: internal.OrchInstallerErrorCodeInternal, ErrorMsg: err.Error()}
+		}
+		version := planVersionRegex.FindStringSubmatch(string(plan))[1]
+		f.appliedPlans = append(f.appliedPlans, version)
+		for i := range f.nodeVersions {
+			f.nodeVersions[i] = version
+		}
+	}
+	return output, nil
+}
+
+func (f *fakeClusterShellUtility) Process() *os.Process { return nil }
+func (f *fakeClusterShellUtility) Kill() error          { return nil }
+func (f *fakeClusterShellUtility) Wait() error          { return nil }
+
+type Rke2StepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.Rke2Step
+	shellUtility *fakeClusterShellUtility
+}
+
+func TestRke2Step(t *testing.T) {
+	suite.Run(t, new(Rke2StepTest))
+}
+
+func (s *Rke2StepTest) SetupTest() {
+	onprem.RKE2UpgradePollInterval = time.Millisecond
+	s.config = config.OrchInstallerConfig{}
+	s.config.Artifacts.Version = "3.1.0"
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "upgrade"
+	s.runtimeState.Onprem.KubeConfig = "test-kubeconfig"
+	s.shellUtility = &fakeClusterShellUtility{}
+	s.step = onprem.CreateRke2Step("", false, nil)
+	s.step.ShellUtility = s.shellUtility
+}
+
+func (s *Rke2StepTest) TestComputeRKE2UpgradeHops() {
+	hops, err := onprem.ComputeRKE2UpgradeHops(testRKE2TargetVersion, testRKE2TargetVersion)
+	s.NoError(err)
+	s.Empty(hops)
+
+	hops, err = onprem.ComputeRKE2UpgradeHops("v1.30.5+rke2r1", testRKE2TargetVersion)
+	s.NoError(err)
+	s.Equal([]string{testRKE2TargetVersion}, hops)
+
+	hops, err = onprem.ComputeRKE2UpgradeHops("v1.27.3+rke2r1", testRKE2TargetVersion)
+	s.NoError(err)
+	s.Equal([]string{"v1.28.15+rke2r1", "v1.29.12+rke2r1", testRKE2TargetVersion}, hops)
+
+	_, err = onprem.ComputeRKE2UpgradeHops("v1.31.1+rke2r1", testRKE2TargetVersion)
+	s.ErrorContains(err, "cannot downgrade")
+
+	_, err = onprem.ComputeRKE2UpgradeHops("v1.23.17+rke2r1", testRKE2TargetVersion)
+	s.ErrorContains(err, "no known RKE2 release for Kubernetes 1.24")
+
+	_, err = onprem.ComputeRKE2UpgradeHops("1.28", testRKE2TargetVersion)
+	s.ErrorContains(err, "invalid RKE2 version")
+}
+
+func (s *Rke2StepTest) TestUpgradeMultipleHops() {
+	s.shellUtility.nodeVersions = []string{"v1.28.15+rke2r1", "v1.29.12+rke2r1"}
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(testRKE2TargetVersion, rs.Onprem.RKE2Version)
+	s.Equal([]string{"v1.29.12+rke2r1", testRKE2TargetVersion}, s.shellUtility.appliedPlans)
+	s.True(slices.ContainsFunc(s.shellUtility.commands, func(command []string) bool {
+		return slices.Contains(command, "rke2-upgrade=false")
+	}), "nodes should be unlabelled after the upgrade")
+}
+
+func (s *Rke2StepTest) TestUpgradeIgnoresStaleArtifacts() {
+	// Left by the install of an earlier release, the ArtifactDownloader is not selected
+	s.runtimeState.Onprem.Artifacts.OrchVersion = "3.0.0"
+	s.runtimeState.Onprem.Artifacts.RKE2Version = "v1.29.12+rke2r1"
+	s.shellUtility.nodeVersions = []string{"v1.29.12+rke2r1"}
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(testRKE2TargetVersion, rs.Onprem.RKE2Version)
+	s.Equal([]string{testRKE2TargetVersion}, s.shellUtility.appliedPlans)
+	s.Equal("3.1.0", rs.Onprem.Artifacts.OrchVersion)
+}
+
+func (s *Rke2StepTest) TestUpgradeUpToDate() {
+	s.shellUtility.nodeVersions = []string{testRKE2TargetVersion}
+	rs, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	if err != nil {
+		s.NoError(err)
+		return
+	}
+	s.Equal(testRKE2TargetVersion, rs.Onprem.RKE2Version)
+	s.Empty(s.shellUtility.appliedPlans)
+	s.Len(s.shellUtility.commands, 1)
+}
+
+func (s *Rke2StepTest) TestUpgradeWithoutKubeConfig() {
+	s.runtimeState.Onprem.KubeConfig = ""
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+	s.Empty(s.shellUtility.commands)
+}
@@ -0,0 +1,397 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+const (
+	systemUpgradeControllerURL = "https://github.com/rancher/system-upgrade-controller/releases/download/v0.13.2/system-upgrade-controller.yaml"
+	rke2UpgradeHopTimeout      = 30 * time.Minute
+)
+
+// Kubernetes cannot skip minor versions. These are the releases installed for each
+// minor version between the running version and rke2Version.
+var rke2MinorReleases = map[int]string{
+	26: "v1.26.15+rke2r1",
+	27: "v1.27.16+rke2r2",
+	28: "v1.28.15+rke2r1",
+	29: "v1.29.12+rke2r1",
+	30: rke2Version,
+}
+
+// Interval between two checks of the node versions and conditions during an upgrade.
+var RKE2UpgradePollInterval = 10 * time.Second
+
+var rke2VersionRegex = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)\+rke2r(\d+)$`)
+
+var rke2UpgradePlanTemplate = template.Must(template.New("upgrade-plan").Parse(`apiVersion: upgrade.cattle.io/v1
+kind: Plan
+metadata:
+  name: server-plan
+  namespace: system-upgrade
+  labels:
+    rke2-upgrade: server
+spec:
+  concurrency: 1
+  nodeSelector:
+    matchExpressions:
+      - { key: rke2-upgrade, operator: Exists }
+      - { key: rke2-upgrade, operator: NotIn, values: ["disabled", "false"] }
+      - { key: node-role.kubernetes.io/control-plane, operator: In, values: ["true"] }
+  tolerations:
+    - key: "CriticalAddonsOnly"
+      operator: "Equal"
+      value: "true"
+      effect: "NoExecute"
+  serviceAccountName: system-upgrade
+  cordon: true
+  upgrade:
+    image: rancher/rke2-upgrade
+  version: {{ .Version }}
+---
+apiVersion: upgrade.cattle.io/v1
+kind: Plan
+metadata:
+  name: agent-plan
+  namespace: system-upgrade
+  labels:
+    rke2-upgrade: agent
+spec:
+  concurrency: 1
+  nodeSelector:
+    matchExpressions:
+      - { key: rke2-upgrade, operator: Exists }
+      - { key: rke2-upgrade, operator: NotIn, values: ["disabled", "false"] }
+      - { key: node-role.kubernetes.io/control-plane, operator: NotIn, values: ["true"] }
+  prepare:
+    image: rancher/rke2-upgrade
+    args: ["prepare", "server-plan"]
+  serviceAccountName: system-upgrade
+  cordon: true
+  drain:
+    force: true
+  upgrade:
+    image: rancher/rke2-upgrade
+  version: {{ .Version }}
+`))
+
+type rke2Release struct {
+	major, minor, patch, release int
+}
+
+func parseRKE2Version(version string) (rke2Release, error) {
+	matches := rke2VersionRegex.FindStringSubmatch(strings.TrimSpace(version))
+	if matches == nil {
+		return rke2Release{}, fmt.Errorf("invalid RKE2 version %q", version)
+	}
+	var v rke2Release
+	for i, field := range []*int{&v.major, &v.minor, &v.patch, &v.release} {
+		n, err := strconv.Atoi(matches[i+1])
+		if err != nil {
+			return rke2Release{}, fmt.Errorf("invalid RKE2 version %q: %w", version, err)
+		}
+		*field = n
+	}
+	return v, nil
+}
+
+func (v rke2Release) compare(other rke2Release) int {
+	for _, d := range []int{v.major - other.major, v.minor - other.minor, v.patch - other.patch, v.release - other.release} {
+		if d != 0 {
+			return d
+		}
+	}
+	return 0
+}
+
+// ComputeRKE2UpgradeHops returns the versions to install, in order, to go from the current to
+// the target version without skipping a minor version. It returns no hop if the cluster is
+// already at the target version.
+func ComputeRKE2UpgradeHops(current, target string) ([]string, error) {
+	currentVersion, err := parseRKE2Version(current)
+	if err != nil {
+		return nil, err
+	}
+	targetVersion, err := parseRKE2Version(target)
+	if err != nil {
+		return nil, err
+	}
+	if currentVersion.major != targetVersion.major {
+		return nil, fmt.Errorf("cannot upgrade RKE2 across major versions, from %s to %s", current, target)
+	}
+	switch cmp := currentVersion.compare(targetVersion); {
+	case cmp == 0:
+		return nil, nil
+	case cmp > 0:
+		return nil, fmt.Errorf("cannot downgrade RKE2 from %s to %s", current, target)
+	}
+
+	hops := []string{}
+	for minor := currentVersion.minor + 1; minor < targetVersion.minor; minor++ {
+		release, ok := rke2MinorReleases[minor]
+		if !ok {
+			return nil, fmt.Errorf("no known RKE2 release for Kubernetes 1.%d, cannot upgrade from %s to %s", minor, current, target)
+		}
+		hops = append(hops, release)
+	}
+	return append(hops, target), nil
+}
+
+// rke2Cluster runs kubectl against the cluster described by a kubeconfig file.
+type rke2Cluster struct {
+	shellUtility   steps.ShellUtility
+	kubeConfigFile string
+	// Number of nodes expected to be Ready, any number if zero
+	expectedNodes int
+}
+
+// writeKubeConfig writes the kubeconfig from the runtime state to a temporary file, the
+// caller removes it.
+func writeKubeConfig(kubeConfig string) (string, error) {
+	if kubeConfig == "" {
+		return "", fmt.Errorf("kubeconfig is empty")
+	}
+	kubeConfigFile, err := os.CreateTemp("", "kubeconfig-*")
+	if err != nil {
+		return "", fmt.Errorf("failed to create kubeconfig file: %w", err)
+	}
+	if _, err := kubeConfigFile.WriteString(kubeConfig); err != nil {
+		kubeConfigFile.Close()
+		os.Remove(kubeConfigFile.Name())
+		return "", fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+	if err := kubeConfigFile.Close(); err != nil {
+		os.Remove(kubeConfigFile.Name())
+		return "", fmt.Errorf("failed to write kubeconfig file: %w", err)
+	}
+	return kubeConfigFile.Name(), nil
+}
+
+func (u *rke2Cluster) kubectl(ctx context.Context, timeout int, args ...string) (string, error) {
+	return u.kubectlWithStdin(ctx, timeout, "", args...)
+}
+
+// kubectlWithStdin runs kubectl with stdin written to its standard input.
+func (u *rke2Cluster) kubectlWithStdin(ctx context.Context, timeout int, stdin string, args ...string) (string, error) {
+	output, err := u.shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: append([]string{"kubectl", "--kubeconfig", u.kubeConfigFile}, args...),
+		Timeout: timeout,
+		Stdin:   stdin,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return "", fmt.Errorf("kubectl %s: %s: %s", strings.Join(args, " "), err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return "", fmt.Errorf("kubectl %s: %s", strings.Join(args, " "), err.ErrorMsg)
+	}
+	return output.Stdout.String(), nil
+}
+
+// apply applies a manifest from a temporary file, which keeps secrets off the command line.
+func (u *rke2Cluster) apply(ctx context.Context, manifest string) error {
+	manifestFile, err := os.CreateTemp("", "manifest-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create manifest file: %w", err)
+	}
+	defer os.Remove(manifestFile.Name())
+	if _, err := manifestFile.WriteString(manifest); err != nil {
+		manifestFile.Close()
+		return fmt.Errorf("failed to write manifest file: %w", err)
+	}
+	if err := manifestFile.Close(); err != nil {
+		return fmt.Errorf("failed to write manifest file: %w", err)
+	}
+	_, err = u.kubectl(ctx, 0, "apply", "-f", manifestFile.Name())
+	return err
+}
+
+// secretValue returns a decoded key of a secret, or an empty string if the secret does
+// not exist.
+func (u *rke2Cluster) secretValue(ctx context.Context, namespace, name, key string) (string, error) {
+	output, err := u.kubectl(ctx, 0, "get", "secret", name, "-n", namespace, "--ignore-not-found",
+		"-o", fmt.Sprintf("jsonpath={.data.%s}", strings.ReplaceAll(key, ".", `\.`)))
+	if err != nil {
+		return "", err
+	}
+	value, err := base64.StdEncoding.DecodeString(strings.TrimSpace(output))
+	if err != nil {
+		return "", fmt.Errorf("failed to decode %s of secret %s/%s: %w", key, namespace, name, err)
+	}
+	return string(value), nil
+}
+
+// helm runs helm against the cluster.
+func (u *rke2Cluster) helm(ctx context.Context, timeout int, args ...string) error {
+	output, err := u.shellUtility.Run(ctx, steps.ShellUtilityInput{
+		Command: append([]string{"helm", "--kubeconfig", u.kubeConfigFile}, args...),
+		Timeout: timeout,
+	})
+	if err != nil {
+		if output != nil && output.Stderr.Len() > 0 {
+			return fmt.Errorf("helm %s: %s: %s", strings.Join(args, " "), err.ErrorMsg, strings.TrimSpace(output.Stderr.String()))
+		}
+		return fmt.Errorf("helm %s: %s", strings.Join(args, " "), err.ErrorMsg)
+	}
+	return nil
+}
+
+// nodeVersions returns the kubelet version of every node in the cluster.
+func (u *rke2Cluster) nodeVersions(ctx context.Context) ([]string, error) {
+	output, err := u.kubectl(ctx, 0, "get", "nodes", "-o", `jsonpath={range .items[*]}{.status.nodeInfo.kubeletVersion}{"\n"}{end}`)
+	if err != nil {
+		return nil, err
+	}
+	versions := strings.Fields(output)
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("no node found in the cluster")
+	}
+	return versions, nil
+}
+
+// installedVersion returns the lowest RKE2 version running in the cluster, a node that has not
+// completed a previous upgrade must go through the same hops as the others.
+func (u *rke2Cluster) installedVersion(ctx context.Context) (string, error) {
+	versions, err := u.nodeVersions(ctx)
+	if err != nil {
+		return "", err
+	}
+	parsed := make([]rke2Release, len(versions))
+	for i, version := range versions {
+		if parsed[i], err = parseRKE2Version(version); err != nil {
+			return "", err
+		}
+	}
+	lowest := 0
+	for i := range parsed {
+		if parsed[i].compare(parsed[lowest]) < 0 {
+			lowest = i
+		}
+	}
+	return versions[lowest], nil
+}
+
+func (u *rke2Cluster) nodesReady(ctx context.Context) (bool, error) {
+	output, err := u.kubectl(ctx, 0, "get", "nodes", "-o", `jsonpath={range .items[*]}{.status.conditions[?(@.type=="Ready")].status}{"\n"}{end}`)
+	if err != nil {
+		return false, err
+	}
+	statuses := strings.Fields(output)
+	if len(statuses) == 0 || len(statuses) < u.expectedNodes {
+		return false, nil
+	}
+	for _, status := range statuses {
+		if status != "True" {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// waitFor calls check until it returns true or the hop timeout is reached. Errors are
+// reported and retried, the API server is unavailable while a node restarts.
+func (u *rke2Cluster) waitFor(ctx context.Context, description string, check func() (bool, error)) error {
+	ctx, cancel := context.WithTimeout(ctx, rke2UpgradeHopTimeout)
+	defer cancel()
+	for {
+		done, err := check()
+		if done {
+			return nil
+		}
+		if err != nil {
+			internal.Logger().Infof("Waiting for %s: %s", description, err)
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("timed out waiting for %s", description)
+		case <-time.After(RKE2UpgradePollInterval):
+		}
+	}
+}
+
+// prepare installs the system-upgrade-controller and marks the nodes for upgrade.
+func (u *rke2Cluster) prepare(ctx context.Context) error {
+	if _, err := u.kubectl(ctx, 120, "apply", "-f", systemUpgradeControllerURL); err != nil {
+		return err
+	}
+	if _, err := u.kubectl(ctx, 600, "rollout", "status", "deployment/system-upgrade-controller", "-n", "system-upgrade", "--timeout=10m"); err != nil {
+		return err
+	}
+	if _, err := u.kubectl(ctx, 120, "wait", "--for=condition=established", "crd/plans.upgrade.cattle.io", "--timeout=60s"); err != nil {
+		return err
+	}
+	if _, err := u.kubectl(ctx, 0, "delete", "-n", "system-upgrade", "plans.upgrade.cattle.io", "--all"); err != nil {
+		return err
+	}
+	_, err := u.kubectl(ctx, 0, "label", "nodes", "--all", "rke2-upgrade=true", "--overwrite")
+	return err
+}
+
+// upgradeTo applies an upgrade plan for the given version and waits for all nodes to run it.
+func (u *rke2Cluster) upgradeTo(ctx context.Context, version string) error {
+	var plan bytes.Buffer
+	if err := rke2UpgradePlanTemplate.Execute(&plan, struct{ Version string }{Version: version}); err != nil {
+		return fmt.Errorf("failed to render upgrade plan: %w", err)
+	}
+	planFile, err := os.CreateTemp("", "rke2-upgrade-plan-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create upgrade plan file: %w", err)
+	}
+	defer os.Remove(planFile.Name())
+	if _, err := planFile.Write(plan.Bytes()); err != nil {
+		return fmt.Errorf("failed to write upgrade plan file: %w", err)
+	}
+	if err := planFile.Close(); err != nil {
+		return fmt.Errorf("failed to write upgrade plan file: %w", err)
+	}
+	if _, err := u.kubectl(ctx, 0, "apply", "-f", planFile.Name()); err != nil {
+		return err
+	}
+
+	internal.Logger().Infof("RKE2 upgrade plan applied, waiting for upgrade to version %s to complete...", version)
+	if err := u.waitFor(ctx, fmt.Sprintf("nodes to run %s", version), func() (bool, error) {
+		versions, err := u.nodeVersions(ctx)
+		if err != nil {
+			return false, err
+		}
+		for _, v := range versions {
+			if v != version {
+				return false, nil
+			}
+		}
+		return true, nil
+	}); err != nil {
+		return err
+	}
+	return u.waitFor(ctx, "nodes to be Ready", func() (bool, error) {
+		return u.nodesReady(ctx)
+	})
+}
+
+// cleanup removes the upgrade labels and the system-upgrade-controller.
+func (u *rke2Cluster) cleanup(ctx context.Context) error {
+	if _, err := u.kubectl(ctx, 0, "label", "nodes", "--all", "rke2-upgrade=false", "--overwrite"); err != nil {
+		return err
+	}
+	// Finalizers sometimes block the deletion indefinitely
+	if _, err := u.kubectl(ctx, 0, "patch", "clusterrolebinding", "system-upgrade", "-p", `{"metadata":{"finalizers":null}}`); err != nil {
+		return err
+	}
+	_, err := u.kubectl(ctx, 120, "delete", "-f", systemUpgradeControllerURL)
+	return err
+}
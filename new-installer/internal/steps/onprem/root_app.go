@@ -0,0 +1,374 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package onprem
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"gopkg.in/yaml.v3"
+)
+
+const (
+	deploymentRepoName                      = "edge-manageability-framework"
+	defaultDeploymentProfile                = "onprem"
+	defaultExistingClusterDeploymentProfile = "existing-cluster"
+	rootAppNamespace                        = "onprem"
+	rootAppInstallTimeout                   = 900 // seconds
+)
+
+var rootAppStepLabels = []string{"root_app"}
+
+type RootAppStep struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	orchConfigReaderWriter config.OrchConfigReaderWriter
+	StepLabels             []string
+	ShellUtility           steps.ShellUtility
+	Forwarder              ServiceForwarder
+	Pusher                 RepoPusher
+	// Directory holding the deployment repo archive, the one the ArtifactDownloader fetched it
+	// to when empty
+	ArchiveDir string
+}
+
+func CreateRootAppStep(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) *RootAppStep {
+	return &RootAppStep{
+		RootPath:               rootPath,
+		KeepGeneratedFiles:     keepGeneratedFiles,
+		orchConfigReaderWriter: orchConfigReaderWriter,
+		StepLabels:             rootAppStepLabels,
+		ShellUtility:           steps.CreateShellUtility(),
+		Forwarder:              CreateServiceForwarder(),
+		Pusher:                 CreateRepoPusher(),
+	}
+}
+
+func (s *RootAppStep) Name() string {
+	return "RootAppStep"
+}
+
+func (s *RootAppStep) Labels() []string {
+	return s.StepLabels
+}
+
+func (s *RootAppStep) ConfigStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if s.ArchiveDir == "" && runtimeState.Action != "uninstall" {
+		locations, err := artifactLocations(s.RootPath, cfg, runtimeState)
+		if err != nil {
+			return runtimeState, err
+		}
+		s.ArchiveDir = locations.ArchivesDir
+	}
+	return runtimeState, nil
+}
+
+func (s *RootAppStep) PreStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	if common.ClusterKubeConfig(cfg, runtimeState) == "" {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  "kubeconfig is not set in the runtime state, cannot manage root-app",
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	if runtimeState.Action != "uninstall" && !runtimeState.Onprem.GiteaInstalled {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  "Gitea is not installed, cannot push the deployment repo",
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	return runtimeState, nil
+}
+
+func (s *RootAppStep) RunStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	kubeConfigFile, err := writeKubeConfig(common.ClusterKubeConfig(cfg, runtimeState))
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	defer os.Remove(kubeConfigFile)
+	cluster := &rke2Cluster{
+		shellUtility:   s.ShellUtility,
+		kubeConfigFile: kubeConfigFile,
+	}
+
+	installedProfile := rootAppProfile(cfg, &runtimeState)
+	if runtimeState.Action == "uninstall" {
+		if *installedProfile == "" {
+			internal.Logger().Info("root-app was not installed by the installer, skipping uninstall")
+			return runtimeState, nil
+		}
+		if err := cluster.helm(ctx, rootAppInstallTimeout, "uninstall", "root-app", "-n", rootAppNamespace); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to uninstall root-app: %s", err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+		if _, err := cluster.kubectl(ctx, 0, "delete", "secret", deploymentRepoName, "-n", "argocd", "--ignore-not-found"); err != nil {
+			return runtimeState, &internal.OrchInstallerError{
+				ErrorMsg:  fmt.Sprintf("failed to delete the deployment repo secret: %s", err),
+				ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			}
+		}
+		*installedProfile = ""
+		runtimeState.Onprem.DeploymentRepoCommit = ""
+		return runtimeState, nil
+	}
+
+	profile := deploymentProfile(cfg)
+	repoDir, err := os.MkdirTemp("", deploymentRepoName+"-*")
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to create temporary directory: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	defer os.RemoveAll(repoDir)
+	if orchErr := s.extractDeploymentRepo(repoDir, profile); orchErr != nil {
+		return runtimeState, orchErr
+	}
+	repoDir = filepath.Join(repoDir, deploymentRepoName)
+	profileFile := filepath.Join(repoDir, "orch-configs", "clusters", profile+".yaml")
+	// The child apps read the values of the profile from the repo, the overrides are pushed with it
+	if err := overrideValues(profileFile, rootAppValues(cfg)); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to override the values of profile %s: %s", profile, err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+
+	commit, err := s.pushDeploymentRepo(ctx, cluster, repoDir)
+	if err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to push the deployment repo to Gitea: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	runtimeState.Onprem.DeploymentRepoCommit = commit
+	internal.Logger().Infof("Pushed the deployment repo to Gitea at commit %s", commit)
+
+	internal.Logger().Infof("Installing root-app with the %s profile...", profile)
+	if err := cluster.helm(ctx, rootAppInstallTimeout, "upgrade", "--install", "root-app",
+		filepath.Join(repoDir, "argocd", "root-app"),
+		"-f", profileFile,
+		"-n", rootAppNamespace, "--create-namespace"); err != nil {
+		return runtimeState, &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("failed to install root-app: %s", err),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	*installedProfile = profile
+	return runtimeState, nil
+}
+
+func (s *RootAppStep) PostStep(ctx context.Context, cfg config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+// deploymentProfile returns the cluster profile root-app is installed with.
+func deploymentProfile(cfg config.OrchInstallerConfig) string {
+	if cfg.Provider == common.ExistingClusterProvider {
+		return valueOrDefault(cfg.ExistingCluster.DeploymentProfile, defaultExistingClusterDeploymentProfile)
+	}
+	return valueOrDefault(cfg.Onprem.DeploymentProfile, defaultDeploymentProfile)
+}
+
+// rootAppProfile returns the field of the runtime state recording the profile root-app was
+// installed with.
+func rootAppProfile(cfg config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) *string {
+	if cfg.Provider == common.ExistingClusterProvider {
+		return &runtimeState.ExistingCluster.RootAppProfile
+	}
+	return &runtimeState.Onprem.RootAppProfile
+}
+
+// rootAppValues returns the values of the deployment that override the ones of the profile,
+// keyed by their dotted path. Empty values are left as the profile sets them.
+func rootAppValues(cfg config.OrchInstallerConfig) map[string]string {
+	values := map[string]string{
+		"argo.proxy.httpProxy":    cfg.Proxy.HTTPProxy,
+		"argo.proxy.httpsProxy":   cfg.Proxy.HTTPSProxy,
+		"argo.proxy.noProxy":      cfg.Proxy.NoProxy,
+		"argo.proxy.enHttpProxy":  cfg.Proxy.ENHTTPProxy,
+		"argo.proxy.enHttpsProxy": cfg.Proxy.ENHTTPSProxy,
+		"argo.proxy.enFtpProxy":   cfg.Proxy.ENFTPProxy,
+		"argo.proxy.enSocksProxy": cfg.Proxy.ENSOCKSProxy,
+		"argo.proxy.enNoProxy":    cfg.Proxy.ENNoProxy,
+	}
+	// The services are published under the same zone as on AWS
+	if cfg.Global.OrchName != "" && cfg.Global.ParentDomain != "" {
+		values["argo.clusterDomain"] = cfg.Global.OrchName + "." + cfg.Global.ParentDomain
+	}
+	if cfg.Provider == common.ExistingClusterProvider {
+		// The volumes of the orchestrator use the storage class the Gitea volumes use
+		for _, path := range []string{
+			"argo.catalog.storageClass",
+			"argo.harbor.storageClass",
+			"argo.infra-onboarding.dkamStorageClass",
+			"argo.infra-onboarding.tinkerbellStorageClass",
+			"argo.infra-onboarding.onboardingManagerStorageClass",
+		} {
+			values[path] = cfg.ExistingCluster.StorageClass
+		}
+	} else {
+		values["postCustomTemplateOverwrite.metallb-config.ArgoIP"] = cfg.Onprem.ArgoIP
+		values["postCustomTemplateOverwrite.metallb-config.TraefikIP"] = cfg.Onprem.TraefikIP
+		values["postCustomTemplateOverwrite.metallb-config.NginxIP"] = cfg.Onprem.NginxIP
+	}
+	for path, value := range values {
+		if value == "" {
+			delete(values, path)
+		}
+	}
+	return values
+}
+
+// overrideValues sets the values in the values file, keeping its comments.
+func overrideValues(file string, values map[string]string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	doc := &yaml.Node{}
+	if err := yaml.Unmarshal(data, doc); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", file, err)
+	}
+	if len(doc.Content) == 0 {
+		doc = &yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode}}}
+	}
+	paths := make([]string, 0, len(values))
+	for path := range values {
+		paths = append(paths, path)
+	}
+	slices.Sort(paths)
+	for _, path := range paths {
+		if err := setValue(doc.Content[0], strings.Split(path, "."), values[path]); err != nil {
+			return fmt.Errorf("failed to set %s: %w", path, err)
+		}
+	}
+	var out bytes.Buffer
+	encoder := yaml.NewEncoder(&out)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	if err := encoder.Close(); err != nil {
+		return err
+	}
+	return os.WriteFile(file, out.Bytes(), 0o644)
+}
+
+// setValue sets the string value at the path of the mapping, creating the missing mappings.
+func setValue(node *yaml.Node, path []string, value string) error {
+	if node.Kind != yaml.MappingNode {
+		return fmt.Errorf("%s is not a mapping", path[0])
+	}
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			node.Content[i+1] = &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+			return nil
+		}
+		return setValue(node.Content[i+1], path[1:], value)
+	}
+	child := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if len(path) > 1 {
+		child = &yaml.Node{Kind: yaml.MappingNode}
+		if err := setValue(child, path[1:], value); err != nil {
+			return err
+		}
+	}
+	node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: path[0]}, child)
+	return nil
+}
+
+// extractDeploymentRepo extracts the deployment repo archive into dir and checks that it
+// has the values of the profile.
+func (s *RootAppStep) extractDeploymentRepo(dir, profile string) *internal.OrchInstallerError {
+	archive, err := findArchive(s.ArchiveDir, deploymentRepoName)
+	if err != nil {
+		return &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidRuntimeState,
+		}
+	}
+	if err := extractTarGz(archive, dir); err != nil {
+		return &internal.OrchInstallerError{
+			ErrorMsg:  err.Error(),
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+		}
+	}
+	profileFile := filepath.Join(dir, deploymentRepoName, "orch-configs", "clusters", profile+".yaml")
+	if _, err := os.Stat(profileFile); err != nil {
+		return &internal.OrchInstallerError{
+			ErrorMsg:  fmt.Sprintf("deployment profile %s not found in %s", profile, archive),
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+		}
+	}
+	return nil
+}
+
+// pushDeploymentRepo pushes the repo to Gitea as the ArgoCD user through a port-forward,
+// and registers the repository with ArgoCD.
+func (s *RootAppStep) pushDeploymentRepo(ctx context.Context, cluster *rke2Cluster, repoDir string) (string, error) {
+	username, err := cluster.secretValue(ctx, giteaArgoCDAccount.namespace, giteaArgoCDAccount.secretName, "username")
+	if err != nil {
+		return "", err
+	}
+	password, err := cluster.secretValue(ctx, giteaArgoCDAccount.namespace, giteaArgoCDAccount.secretName, "password")
+	if err != nil {
+		return "", err
+	}
+	if username == "" || password == "" {
+		return "", fmt.Errorf("secret %s/%s has no credentials", giteaArgoCDAccount.namespace, giteaArgoCDAccount.secretName)
+	}
+	caBundle, err := cluster.secretValue(ctx, giteaNamespace, giteaTLSSecret, "tls.crt")
+	if err != nil {
+		return "", err
+	}
+
+	address, stop, err := s.Forwarder.Forward(ctx, cluster.kubeConfigFile, giteaNamespace, "gitea-http", 443)
+	if err != nil {
+		return "", err
+	}
+	defer stop()
+	commit, err := s.Pusher.Push(ctx, repoDir, RepoPushTarget{
+		URL:      fmt.Sprintf("https://%s/%s/%s.git", address, username, deploymentRepoName),
+		Username: username,
+		Password: password,
+		CABundle: []byte(caBundle),
+	})
+	if err != nil {
+		return "", err
+	}
+
+	manifest, err := secretManifest("argocd", deploymentRepoName, "Opaque",
+		map[string]string{"argocd.argoproj.io/secret-type": "repository"},
+		map[string]string{
+			"type":     "git",
+			"url":      fmt.Sprintf("https://%s/%s/%s", giteaServiceDomain, username, deploymentRepoName),
+			"username": username,
+			"password": password,
+		})
+	if err != nil {
+		return "", err
+	}
+	if err := cluster.apply(ctx, manifest); err != nil {
+		return "", err
+	}
+	return commit, nil
+}
@@ -0,0 +1,252 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package onprem_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeForwarder struct {
+	stopped bool
+}
+
+func (f *fakeForwarder) Forward(ctx context.Context, kubeConfigFile, namespace, service string, port int) (string, func(), error) {
+	return "localhost:8443", func() { f.stopped = true }, nil
+}
+
+type fakePusher struct {
+	target   onprem.RepoPushTarget
+	files    []string
+	contents map[string]string
+}
+
+func (f *fakePusher) Push(ctx context.Context, dir string, target onprem.RepoPushTarget) (string, error) {
+	f.target = target
+	f.contents = map[string]string{}
+	err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() {
+			rel, _ := filepath.Rel(dir, path)
+			f.files = append(f.files, rel)
+			content, readErr := os.ReadFile(path)
+			f.contents[rel] = string(content)
+			return readErr
+		}
+		return err
+	})
+	return "4b825dc642cb6eb9a060e54bf8d69288fbee4904", err
+}
+
+// writeArchive writes a gzipped tarball of the files, keyed by path.
+func writeArchive(path string, files map[string]string) error {
+	file, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	gz := gzip.NewWriter(file)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			return err
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			return err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+type RootAppStepTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	step         *onprem.RootAppStep
+	shellUtility *fakeKubeShellUtility
+	forwarder    *fakeForwarder
+	pusher       *fakePusher
+}
+
+func TestRootAppStep(t *testing.T) {
+	suite.Run(t, new(RootAppStepTest))
+}
+
+func (s *RootAppStepTest) SetupTest() {
+	s.config = config.OrchInstallerConfig{}
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Action = "install"
+	s.runtimeState.Onprem.KubeConfig = "test-kubeconfig"
+	s.runtimeState.Onprem.GiteaInstalled = true
+	s.shellUtility = newFakeKubeShellUtility()
+	s.shellUtility.secrets["gitea/argocd-gitea-credential"] = map[string]string{"username": "argocd", "password": "secret"}
+	s.shellUtility.secrets["gitea/gitea-tls-certs"] = map[string]string{"tls.crt": "test-ca"}
+	s.forwarder = &fakeForwarder{}
+	s.pusher = &fakePusher{}
+
+	archiveDir := s.T().TempDir()
+	s.Require().NoError(writeArchive(filepath.Join(archiveDir, "edge-manageability-framework_3.1.0.tgz"), map[string]string{
+		"edge-manageability-framework/argocd/root-app/Chart.yaml":                  "name: root-app\n",
+		"edge-manageability-framework/orch-configs/clusters/onprem.yaml":           "# Values of the profile\nargo:\n  clusterName: onprem\n  # Overridden by the installer\n  clusterDomain: cluster.onprem\n",
+		"edge-manageability-framework/orch-configs/clusters/onprem-1k.yaml":        "clusterName: onprem-1k\n",
+		"edge-manageability-framework/orch-configs/clusters/existing-cluster.yaml": "argo:\n  clusterName: existing-cluster\n",
+	}))
+	s.step = onprem.CreateRootAppStep("", false, nil)
+	s.step.ShellUtility = s.shellUtility
+	s.step.Forwarder = s.forwarder
+	s.step.Pusher = s.pusher
+	s.step.ArchiveDir = archiveDir
+}
+
+func (s *RootAppStepTest) TestInstall() {
+	s.config.Onprem.DeploymentProfile = "onprem-1k"
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal("onprem-1k", runtimeState.Onprem.RootAppProfile)
+	s.Equal("4b825dc642cb6eb9a060e54bf8d69288fbee4904", runtimeState.Onprem.DeploymentRepoCommit)
+
+	s.Equal("https://localhost:8443/argocd/edge-manageability-framework.git", s.pusher.target.URL)
+	s.Equal("secret", s.pusher.target.Password)
+	s.Equal([]byte("test-ca"), s.pusher.target.CABundle)
+	s.Contains(s.pusher.files, filepath.Join("argocd", "root-app", "Chart.yaml"))
+	s.True(s.forwarder.stopped)
+
+	repoSecret := s.shellUtility.secrets["argocd/edge-manageability-framework"]
+	s.Equal("https://gitea-http.gitea.svc.cluster.local/argocd/edge-manageability-framework", repoSecret["url"])
+	installs := s.shellUtility.commandsMatching("upgrade --install root-app")
+	s.Require().Len(installs, 1)
+	s.Contains(installs[0], filepath.Join("orch-configs", "clusters", "onprem-1k.yaml"))
+	s.Contains(installs[0], "-n onprem")
+}
+
+func (s *RootAppStepTest) TestOverrideValues() {
+	s.config.Global.OrchName = "demo"
+	s.config.Global.ParentDomain = "example.com"
+	s.config.Proxy.HTTPSProxy = "http://proxy.example.com:912"
+	s.config.Onprem.ArgoIP = "10.0.0.10"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal(`# Values of the profile
+argo:
+  clusterName: onprem
+  # Overridden by the installer
+  clusterDomain: demo.example.com
+  proxy:
+    httpsProxy: http://proxy.example.com:912
+postCustomTemplateOverwrite:
+  metallb-config:
+    ArgoIP: 10.0.0.10
+`, s.pusher.contents[filepath.Join("orch-configs", "clusters", "onprem.yaml")])
+}
+
+func (s *RootAppStepTest) TestExistingCluster() {
+	s.config.Provider = "existing-cluster"
+	// The on-prem profile does not apply to an existing cluster
+	s.config.Onprem.DeploymentProfile = "onprem-1k"
+	s.config.Onprem.ArgoIP = "10.0.0.10"
+	s.config.ExistingCluster.StorageClass = "gp3"
+	s.runtimeState.Onprem.KubeConfig = ""
+	s.runtimeState.ExistingCluster.KubeConfig = "test-kubeconfig"
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal("existing-cluster", runtimeState.ExistingCluster.RootAppProfile)
+	s.Empty(runtimeState.Onprem.RootAppProfile)
+	installs := s.shellUtility.commandsMatching("upgrade --install root-app")
+	s.Require().Len(installs, 1)
+	s.Contains(installs[0], filepath.Join("orch-configs", "clusters", "existing-cluster.yaml"))
+
+	// There is no MetalLB, the volumes use the storage class of the Gitea volumes
+	profile := s.pusher.contents[filepath.Join("orch-configs", "clusters", "existing-cluster.yaml")]
+	s.NotContains(profile, "metallb-config")
+	s.Contains(profile, "  catalog:\n    storageClass: gp3\n")
+	s.Contains(profile, "    dkamStorageClass: gp3\n")
+
+	s.runtimeState = runtimeState
+	s.runtimeState.Action = "uninstall"
+	runtimeState, err = steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Empty(runtimeState.ExistingCluster.RootAppProfile)
+	s.Len(s.shellUtility.commandsMatching("uninstall root-app -n onprem"), 1)
+}
+
+func (s *RootAppStepTest) TestUnknownProfile() {
+	s.config.Onprem.DeploymentProfile = "bkc"
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+	s.Empty(s.pusher.target.URL)
+}
+
+func (s *RootAppStepTest) TestMissingArchive() {
+	s.step.ArchiveDir = s.T().TempDir()
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *RootAppStepTest) TestArchiveOutsideTarget() {
+	s.Require().NoError(writeArchive(filepath.Join(s.step.ArchiveDir, "edge-manageability-framework_3.1.0.tgz"), map[string]string{
+		"../escaped.yaml": "",
+	}))
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "outside of the target directory")
+}
+
+func (s *RootAppStepTest) TestGiteaNotInstalled() {
+	s.runtimeState.Onprem.GiteaInstalled = false
+	_, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidRuntimeState, err.ErrorCode)
+}
+
+func (s *RootAppStepTest) TestUninstall() {
+	s.runtimeState.Action = "uninstall"
+	s.runtimeState.Onprem.RootAppProfile = "onprem"
+	s.runtimeState.Onprem.DeploymentRepoCommit = "4b825dc642cb6eb9a060e54bf8d69288fbee4904"
+	runtimeState, err := steps.GoThroughStepFunctions(s.step, &s.config, s.runtimeState)
+	s.Require().Nil(err)
+	s.Empty(runtimeState.Onprem.RootAppProfile)
+	s.Empty(runtimeState.Onprem.DeploymentRepoCommit)
+	s.Len(s.shellUtility.commandsMatching("uninstall root-app -n onprem"), 1)
+	s.Len(s.shellUtility.commandsMatching("delete secret edge-manageability-framework -n argocd"), 1)
+}
+
+func (s *RootAppStepTest) TestPushRepo() {
+	remoteDir := s.T().TempDir()
+	_, err := git.PlainInit(remoteDir, true)
+	s.Require().NoError(err)
+	repoDir := s.T().TempDir()
+	s.Require().NoError(os.WriteFile(filepath.Join(repoDir, "README.md"), []byte("deployment repo\n"), 0o644))
+
+	for range 2 {
+		commit, err := onprem.CreateRepoPusher().Push(context.Background(), repoDir, onprem.RepoPushTarget{URL: remoteDir})
+		s.Require().NoError(err)
+		s.Require().NoError(os.RemoveAll(filepath.Join(repoDir, ".git")))
+
+		remote, err := git.PlainOpen(remoteDir)
+		s.Require().NoError(err)
+		ref, err := remote.Reference(plumbing.NewBranchReferenceName("main"), true)
+		s.Require().NoError(err)
+		s.Equal(commit, ref.Hash().String())
+		commitObject, err := remote.CommitObject(ref.Hash())
+		s.Require().NoError(err)
+		_, err = commitObject.File("README.md")
+		s.NoError(err)
+	}
+}
@@ -33,6 +33,8 @@ type ShellUtilityInput struct {
 	Timeout         int
 	SkipError       bool
 	RunInBackground bool
+	// Written to the standard input of the command, keeps secrets off the command line
+	Stdin string
 }
 
 type ShellUtilityOutput struct {
@@ -61,6 +63,9 @@ func (s *shellUtilityImpl) Run(ctx context.Context, input ShellUtilityInput) (*S
 
 	s.cmd.Stdout = &stdoutWriter
 	s.cmd.Stderr = &stderrWriter
+	if input.Stdin != "" {
+		s.cmd.Stdin = strings.NewReader(input.Stdin)
+	}
 	var err error
 	if input.RunInBackground {
 		err = s.cmd.Start()
@@ -56,6 +56,18 @@ func (s *ShellUtilityTest) TestBasicCmdStderr() {
 	s.Require().NoError(output.Error)
 }
 
+func (s *ShellUtilityTest) TestStdin() {
+	shellUtil := steps.CreateShellUtility()
+	ctx := context.Background()
+	output, err := shellUtil.Run(ctx, steps.ShellUtilityInput{
+		Command: []string{"sh", "-c", "read -r line && echo \"got $line\""},
+		Timeout: 5,
+		Stdin:   "secret\n",
+	})
+	s.Require().Nil(err)
+	s.Equal("got secret\n", output.Stdout.String())
+}
+
 func (s *ShellUtilityTest) TestBasicCmdError() {
 	shellUtil := steps.CreateShellUtility()
 	ctx := context.Background()
@@ -6,16 +6,19 @@ package steps
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
 )
 
 type OrchInstallerStep interface {
 	// The name of the step
 	Name() string
 
-	// Labels for the step. We can selectively run a subset of steps by specifying labels.
+	// Labels for the step. We can selectively run a subset of steps by specifying labels or the name of the step.
 	Labels() []string
 
 	// Configure the step, such as generating configuration files or setting up the environment.
@@ -35,30 +38,106 @@ type OrchInstallerStep interface {
 	PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError)
 }
 
-func matchAnyLabel(stepLabels []string, filterLabels []string) bool {
-	for _, label := range stepLabels {
-		for _, filterLabel := range filterLabels {
-			if label == filterLabel {
-				return true
+// stepPhase is ConfigStep, PreStep or RunStep of a step.
+type stepPhase func(context.Context, config.OrchInstallerConfig, config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError)
+
+// RunStep runs the phases of a step of the stage, with their entries in the log file of the step
+// and a span for each.
+func RunStep(ctx context.Context, stage string, step OrchInstallerStep, config *config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) (err *internal.OrchInstallerError) {
+	ctx, span := tracing.Start(ctx, "step "+step.Name(), tracing.StageKey.String(stage), tracing.StepKey.String(step.Name()))
+	defer func() { internal.EndSpan(span, err) }()
+
+	stepLog, logErr := internal.StartStepLog(stage, step.Name())
+	if logErr != nil {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInternal,
+			ErrorMsg:  fmt.Sprintf("failed to open the log of step %s: %s", step.Name(), logErr),
+		}
+	}
+	defer stepLog.Close()
+
+	stepErr := func() *internal.OrchInstallerError {
+		for _, phase := range []struct {
+			name string
+			run  stepPhase
+		}{
+			{"ConfigStep", step.ConfigStep},
+			{"PreStep", step.PreStep},
+			{"RunStep", step.RunStep},
+		} {
+			phaseCtx, phaseSpan := stepLog.StartPhase(ctx, phase.name)
+			internal.Logger().Debugf("%s %s", phase.name, step.Name())
+			newRuntimeState, err := phase.run(phaseCtx, *config, *runtimeState)
+			if err == nil {
+				err = internal.UpdateRuntimeState(runtimeState, newRuntimeState)
+			}
+			internal.EndSpan(phaseSpan, err)
+			if err != nil {
+				return err
 			}
 		}
+		return nil
+	}()
+
+	phaseCtx, phaseSpan := stepLog.StartPhase(ctx, "PostStep")
+	internal.Logger().Debugf("PostStep %s", step.Name())
+	newRuntimeState, err := step.PostStep(phaseCtx, *config, *runtimeState, stepErr)
+	if err == nil {
+		err = internal.UpdateRuntimeState(runtimeState, newRuntimeState)
+	}
+	internal.EndSpan(phaseSpan, err)
+	if err != nil {
+		internal.Logger().Errorf("Step %s failed: %s", step.Name(), err.ErrorMsg)
 	}
-	return false
+	return err
 }
 
-func FilterSteps(steps []OrchInstallerStep, labels []string) []OrchInstallerStep {
-	if len(labels) == 0 {
+// DryRunStep is implemented by the steps that honor --dry-run, reporting their changes without
+// making them.
+type DryRunStep interface {
+	SupportsDryRun() bool
+}
+
+// DryRunSteps returns the names of the steps that honor --dry-run.
+func DryRunSteps(steps []OrchInstallerStep) []string {
+	names := []string{}
+	for _, step := range steps {
+		if dryRunStep, ok := step.(DryRunStep); ok && dryRunStep.SupportsDryRun() {
+			names = append(names, step.Name())
+		}
+	}
+	return names
+}
+
+// FilterSteps returns the steps of the stage selected by the selector. The names and labels of a
+// step are matched together with the ones of its stage.
+func FilterSteps(steps []OrchInstallerStep, stage selector.Target, sel *selector.Selector) []OrchInstallerStep {
+	if sel.Empty() {
 		return steps
 	}
 	var filteredSteps []OrchInstallerStep
 	for _, step := range steps {
-		if matchAnyLabel(step.Labels(), labels) {
+		if sel.Match(stage, StepTarget(step)) {
 			filteredSteps = append(filteredSteps, step)
 		}
 	}
 	return filteredSteps
 }
 
+// StepTarget returns the name and labels of the step, to be matched by a selector.
+func StepTarget(step OrchInstallerStep) selector.Target {
+	return selector.Target{Name: step.Name(), Labels: step.Labels()}
+}
+
+// StepTargets returns the names and labels of the steps.
+func StepTargets(steps []OrchInstallerStep) []selector.Target {
+	targets := []selector.Target{}
+	for _, step := range steps {
+		targets = append(targets, StepTarget(step))
+	}
+	return targets
+}
+
 func ReverseSteps(steps []OrchInstallerStep) []OrchInstallerStep {
 	var reversedSteps []OrchInstallerStep
 	for i := len(steps) - 1; i >= 0; i-- {
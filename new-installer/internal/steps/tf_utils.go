@@ -10,16 +10,21 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/hashicorp/go-version"
 	"github.com/hashicorp/hc-install/product"
 	"github.com/hashicorp/hc-install/releases"
 	"github.com/hashicorp/terraform-exec/tfexec"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
 )
 
 const (
 	TerraformVersion = "1.9.5"
+	// The outputs of a module are written next to its log file, with this suffix instead of .log
+	TerraformOutputsSuffix = "-outputs.json"
 )
 
 type TerraformUtility interface {
@@ -181,7 +186,9 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 		}
 		logger.Debugf("Backend and variables files created successfully")
 		logger.Debugf("Initializing Terraform with backend config: %s", backendConfigPath)
-		err = tf.Init(ctx, tfexec.Upgrade(true), tfexec.BackendConfig(backendConfigPath), tfexec.Reconfigure(true))
+		err = traceCommand(ctx, "init", input.ModulePath, func(ctx context.Context) error {
+			return tf.Init(ctx, tfexec.Upgrade(true), tfexec.BackendConfig(backendConfigPath), tfexec.Reconfigure(true))
+		})
 		if err != nil {
 			return TerraformUtilityOutput{}, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
@@ -203,7 +210,9 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 			logger.Debug("Successfully deleted existing terraform state file")
 		}
 		logger.Debug("Initializing Terraform with no backend config")
-		err = tf.Init(ctx, tfexec.Upgrade(true), tfexec.Backend(false), tfexec.Reconfigure(true))
+		err = traceCommand(ctx, "init", input.ModulePath, func(ctx context.Context) error {
+			return tf.Init(ctx, tfexec.Upgrade(true), tfexec.Backend(false), tfexec.Reconfigure(true))
+		})
 		if err != nil {
 			return TerraformUtilityOutput{}, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
@@ -232,7 +241,9 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 	}
 	if input.Action == "install" || input.Action == "upgrade" {
 		logger.Debugf("Applying Terraform with variables file: %s", variableFilePath)
-		err = tf.ApplyJSON(ctx, fileLogWriter, tfexec.VarFile(variableFilePath))
+		err = traceCommand(ctx, "apply", input.ModulePath, func(ctx context.Context) error {
+			return tf.ApplyJSON(ctx, fileLogWriter, tfexec.VarFile(variableFilePath))
+		})
 		if err != nil {
 			return TerraformUtilityOutput{}, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
@@ -242,7 +253,9 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 		logger.Debugf("Terraform applied successfully")
 	} else if input.Action == "uninstall" {
 		logger.Debugf("Destroying Terraform with variables file: %s", variableFilePath)
-		err = tf.DestroyJSON(ctx, fileLogWriter, tfexec.VarFile(variableFilePath), tfexec.Refresh(false))
+		err = traceCommand(ctx, "destroy", input.ModulePath, func(ctx context.Context) error {
+			return tf.DestroyJSON(ctx, fileLogWriter, tfexec.VarFile(variableFilePath), tfexec.Refresh(false))
+		})
 		if err != nil {
 			return TerraformUtilityOutput{}, &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
@@ -257,13 +270,24 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 		}
 	}
 
-	output, err := tf.Output(ctx)
+	var output map[string]tfexec.OutputMeta
+	err = traceCommand(ctx, "output", input.ModulePath, func(ctx context.Context) error {
+		output, err = tf.Output(ctx)
+		return err
+	})
 	if err != nil {
 		return TerraformUtilityOutput{}, &internal.OrchInstallerError{
 			ErrorCode: internal.OrchInstallerErrorCodeTerraform,
 			ErrorMsg:  fmt.Sprintf("failed to retrieve terraform output: %v", err),
 		}
 	}
+	if input.LogFile != "" {
+		// Kept with the logs for support bundles
+		outputFile := strings.TrimSuffix(input.LogFile, filepath.Ext(input.LogFile)) + TerraformOutputsSuffix
+		if err := writeTerraformOutputs(outputFile, output); err != nil {
+			logger.Warnf("failed to write terraform outputs to %s: %v", outputFile, err)
+		}
+	}
 
 	// Preserve terraform state in the runtime state
 	var terraformState string
@@ -301,6 +325,35 @@ func (tfUtil *terraformUtilityImpl) Run(ctx context.Context, input TerraformUtil
 	}, nil
 }
 
+// traceCommand runs a Terraform command on a module in a span.
+func traceCommand(ctx context.Context, command, modulePath string, run func(context.Context) error) error {
+	ctx, span := tracing.Start(ctx, "terraform "+command,
+		tracing.TerraformCommandKey.String(command), tracing.TerraformModuleKey.String(filepath.Base(modulePath)))
+	err := run(ctx)
+	tracing.End(span, err)
+	return err
+}
+
+// writeTerraformOutputs writes the outputs of a module as JSON, with the sensitive values redacted.
+func writeTerraformOutputs(path string, output map[string]tfexec.OutputMeta) error {
+	redactedValue, err := json.Marshal(config.RedactedValue)
+	if err != nil {
+		return err
+	}
+	redacted := map[string]tfexec.OutputMeta{}
+	for name, meta := range output {
+		if meta.Sensitive {
+			meta.Value = redactedValue
+		}
+		redacted[name] = meta
+	}
+	data, err := json.MarshalIndent(redacted, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, data, 0o600)
+}
+
 func InstallTerraformAndGetExecPath() (string, error) {
 	installer := &releases.ExactVersion{
 		Product: product.Terraform,
@@ -318,7 +371,9 @@ func (tfUtil *terraformUtilityImpl) MoveStates(ctx context.Context, input Terraf
 		}
 	}
 	for oldStateName, newStateName := range input.States {
-		err = tf.StateMv(ctx, oldStateName, newStateName)
+		err = traceCommand(ctx, "state mv", input.ModulePath, func(ctx context.Context) error {
+			return tf.StateMv(ctx, oldStateName, newStateName)
+		})
 		if err != nil {
 			return &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
@@ -338,7 +393,9 @@ func (tfUtil *terraformUtilityImpl) RemoveStates(ctx context.Context, input Terr
 		}
 	}
 	for _, stateName := range input.States {
-		err = tf.StateRm(ctx, stateName)
+		err = traceCommand(ctx, "state rm", input.ModulePath, func(ctx context.Context) error {
+			return tf.StateRm(ctx, stateName)
+		})
 		if err != nil {
 			return &internal.OrchInstallerError{
 				ErrorCode: internal.OrchInstallerErrorCodeTerraform,
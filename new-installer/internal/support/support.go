@@ -0,0 +1,301 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package support collects what support engineers need to diagnose an install into a single
+// tarball, so that they do not need access to the machine.
+package support
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/readiness"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ManifestFile lists the content of the bundle and what could not be collected.
+	ManifestFile = "manifest.json"
+
+	DefaultPodLogLines = 1000
+)
+
+// DefaultPodLogNamespaces are the namespaces whose pod logs are always collected. Elsewhere,
+// only the logs of pods that are not running or have restarted are.
+var DefaultPodLogNamespaces = []string{"argocd", "gitea"}
+
+// Options tune Collect, the zero value collects the logs of the default namespaces.
+type Options struct {
+	// Installer version, recorded in the manifest
+	Version string
+	// Installer log dir, including the Terraform logs and outputs
+	LogDir string
+	// Written with the secrets redacted
+	Config       *config.OrchInstallerConfig
+	RuntimeState *config.OrchInstallerRuntimeState
+	// Clients of the cluster, the cluster resources are not collected when nil
+	Client        kubernetes.Interface
+	DynamicClient dynamic.Interface
+	// Namespaces all pod logs are collected from
+	PodLogNamespaces []string
+	// Lines at the end of each container log collected
+	PodLogLines int64
+	Now         func() time.Time
+}
+
+// Manifest describes a support bundle.
+type Manifest struct {
+	Version   string    `json:"version,omitempty"`
+	Provider  string    `json:"provider,omitempty"`
+	CreatedAt time.Time `json:"createdAt"`
+	Files     []File    `json:"files"`
+	// What could not be collected, the bundle holds the rest
+	Errors []string `json:"errors,omitempty"`
+}
+
+// File is a file of a support bundle.
+type File struct {
+	Path string `json:"path"`
+	Size int64  `json:"size"`
+}
+
+// bundle writes the files of a support bundle under a directory named after the bundle.
+type bundle struct {
+	writer   *tar.Writer
+	root     string
+	time     time.Time
+	manifest Manifest
+	// Replaced in every file
+	secrets []string
+}
+
+// Collect writes a support bundle to dir, as orch-support-<time>.tar.gz, and returns its path.
+// Anything that cannot be collected is recorded in the manifest instead of failing the bundle.
+func Collect(ctx context.Context, dir string, options Options) (string, error) {
+	if options.PodLogNamespaces == nil {
+		options.PodLogNamespaces = DefaultPodLogNamespaces
+	}
+	if options.PodLogLines <= 0 {
+		options.PodLogLines = DefaultPodLogLines
+	}
+	if options.Now == nil {
+		options.Now = time.Now
+	}
+	now := options.Now().UTC()
+	name := "orch-support-" + now.Format("20060102T150405Z")
+	output := filepath.Join(dir, name+".tar.gz")
+	file, err := os.Create(output)
+	if err != nil {
+		return "", fmt.Errorf("failed to create support bundle: %w", err)
+	}
+	gzipWriter := gzip.NewWriter(file)
+	b := &bundle{
+		writer:   tar.NewWriter(gzipWriter),
+		root:     name,
+		time:     now,
+		manifest: Manifest{Version: options.Version, CreatedAt: now, Files: []File{}},
+	}
+
+	if options.Config != nil && options.RuntimeState != nil {
+		b.secrets = config.Secrets(*options.Config, *options.RuntimeState)
+		redactedConfig, redactedRuntimeState := config.Redact(*options.Config, *options.RuntimeState)
+		b.manifest.Provider = redactedConfig.Provider
+		b.addConfig("config.yaml", redactedConfig)
+		b.addConfig("runtime-state.yaml", redactedRuntimeState)
+	}
+	if options.LogDir != "" {
+		b.addDir("logs", options.LogDir)
+	}
+	if options.Client != nil {
+		collectCluster(ctx, b, options)
+	}
+
+	data, err := json.MarshalIndent(b.manifest, "", "  ")
+	if err == nil {
+		err = b.add(ManifestFile, data)
+	}
+	err = errors.Join(err, b.writer.Close(), gzipWriter.Close(), file.Close())
+	if err != nil {
+		return "", fmt.Errorf("failed to write support bundle: %w", err)
+	}
+	return output, nil
+}
+
+func (b *bundle) failed(item string, err error) {
+	b.manifest.Errors = append(b.manifest.Errors, fmt.Sprintf("%s: %s", item, err))
+}
+
+func (b *bundle) add(name string, data []byte) error {
+	for _, secret := range b.secrets {
+		data = bytes.ReplaceAll(data, []byte(secret), []byte(config.RedactedValue))
+	}
+	if err := b.writer.WriteHeader(&tar.Header{
+		Name:    path.Join(b.root, name),
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: b.time,
+	}); err != nil {
+		return err
+	}
+	if _, err := b.writer.Write(data); err != nil {
+		return err
+	}
+	if name != ManifestFile {
+		b.manifest.Files = append(b.manifest.Files, File{Path: name, Size: int64(len(data))})
+	}
+	return nil
+}
+
+// addYAML adds Kubernetes resources.
+func (b *bundle) addYAML(name string, value any) {
+	data, err := yaml.Marshal(value)
+	if err == nil {
+		err = b.add(name, data)
+	}
+	if err != nil {
+		b.failed(name, err)
+	}
+}
+
+// addConfig adds the config or runtime state, with the field names of their files.
+func (b *bundle) addConfig(name string, value any) {
+	data, err := config.SerializeToYAML(value)
+	if err == nil {
+		err = b.add(name, data)
+	}
+	if err != nil {
+		b.failed(name, err)
+	}
+}
+
+// addDir adds the files of dir under name.
+func (b *bundle) addDir(name, dir string) {
+	err := filepath.WalkDir(dir, func(file string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		relative, err := filepath.Rel(dir, file)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(file)
+		if err != nil {
+			b.failed(file, err)
+			return nil
+		}
+		return b.add(path.Join(name, filepath.ToSlash(relative)), data)
+	})
+	if err != nil {
+		b.failed(dir, err)
+	}
+}
+
+// collectCluster adds the nodes, pods, events, volume claims and ArgoCD applications of the
+// cluster, and the logs of selected pods.
+func collectCluster(ctx context.Context, b *bundle, options Options) {
+	all := metav1.NamespaceAll
+	if nodes, err := options.Client.CoreV1().Nodes().List(ctx, metav1.ListOptions{}); err != nil {
+		b.failed("nodes", err)
+	} else {
+		b.addYAML("cluster/nodes.yaml", nodes)
+	}
+	if events, err := options.Client.CoreV1().Events(all).List(ctx, metav1.ListOptions{}); err != nil {
+		b.failed("events", err)
+	} else {
+		b.addYAML("cluster/events.yaml", events)
+	}
+	if claims, err := options.Client.CoreV1().PersistentVolumeClaims(all).List(ctx, metav1.ListOptions{}); err != nil {
+		b.failed("persistent volume claims", err)
+	} else {
+		b.addYAML("cluster/pvcs.yaml", claims)
+	}
+	if options.DynamicClient != nil {
+		if apps, err := options.DynamicClient.Resource(readiness.ApplicationResource).Namespace(all).List(ctx, metav1.ListOptions{}); err != nil {
+			b.failed("ArgoCD applications", err)
+		} else {
+			b.addYAML("cluster/argocd-applications.yaml", apps)
+		}
+	}
+
+	pods, err := options.Client.CoreV1().Pods(all).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		b.failed("pods", err)
+		return
+	}
+	b.addYAML("cluster/pods.yaml", pods)
+	for _, pod := range pods.Items {
+		if !selectPod(pod, options.PodLogNamespaces) {
+			continue
+		}
+		for _, status := range pod.Status.ContainerStatuses {
+			collectPodLog(ctx, b, options, pod, status.Name, false)
+			if status.RestartCount > 0 {
+				collectPodLog(ctx, b, options, pod, status.Name, true)
+			}
+		}
+	}
+}
+
+// selectPod tells if the logs of a pod are collected: all the pods of the namespaces, and
+// elsewhere the ones that are not running or restarted.
+func selectPod(pod corev1.Pod, namespaces []string) bool {
+	for _, namespace := range namespaces {
+		if pod.Namespace == namespace {
+			return true
+		}
+	}
+	if pod.Status.Phase != corev1.PodRunning && pod.Status.Phase != corev1.PodSucceeded {
+		return true
+	}
+	for _, status := range pod.Status.ContainerStatuses {
+		if status.RestartCount > 0 || !status.Ready {
+			return true
+		}
+	}
+	return false
+}
+
+func collectPodLog(ctx context.Context, b *bundle, options Options, pod corev1.Pod, container string, previous bool) {
+	name := path.Join("pod-logs", pod.Namespace, pod.Name, container+".log")
+	if previous {
+		name = strings.TrimSuffix(name, ".log") + ".previous.log"
+	}
+	stream, err := options.Client.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &options.PodLogLines,
+	}).Stream(ctx)
+	if err != nil {
+		b.failed(name, err)
+		return
+	}
+	defer stream.Close()
+	data, err := io.ReadAll(stream)
+	if err == nil {
+		err = b.add(name, data)
+	}
+	if err != nil {
+		b.failed(name, err)
+	}
+}
@@ -0,0 +1,245 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+package support_test
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/readiness"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/support"
+	"github.com/stretchr/testify/suite"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var now = time.Date(2025, 6, 1, 12, 30, 0, 0, time.UTC)
+
+type SupportTest struct {
+	suite.Suite
+	dir          string
+	logDir       string
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+}
+
+func TestSupport(t *testing.T) {
+	suite.Run(t, new(SupportTest))
+}
+
+func (s *SupportTest) SetupTest() {
+	s.dir = s.T().TempDir()
+	s.logDir = s.T().TempDir()
+	s.Require().NoError(os.MkdirAll(filepath.Join(s.logDir, "steps"), 0o755))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.logDir, "orch-installer.log"), []byte("Installing RKE2...\n"), 0o600))
+	s.Require().NoError(os.WriteFile(filepath.Join(s.logDir, "steps", "Infra-Rke2Step.log"), []byte("Installing RKE2...\n"), 0o600))
+	// Terraform logs do not go through the installer logger, and are redacted in the bundle
+	s.Require().NoError(os.WriteFile(filepath.Join(s.logDir, "aws_rds.log"), []byte("password = db-s3cr3t\n"), 0o600))
+
+	s.config = config.OrchInstallerConfig{Provider: "onprem"}
+	s.config.Global.AdminPassword = "admin-s3cr3t"
+	s.runtimeState = config.OrchInstallerRuntimeState{}
+	s.runtimeState.Database.Password = "db-s3cr3t"
+	s.runtimeState.Onprem.KubeConfig = "apiVersion: v1\nkind: Config\n"
+}
+
+func pod(namespace, name string, phase corev1.PodPhase, restarts int32) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name},
+		Status: corev1.PodStatus{
+			Phase: phase,
+			ContainerStatuses: []corev1.ContainerStatus{
+				{Name: "main", Ready: phase == corev1.PodRunning && restarts == 0, RestartCount: restarts},
+			},
+		},
+	}
+}
+
+// readBundle returns the files of a bundle by their path in it.
+func (s *SupportTest) readBundle(path string) map[string]string {
+	file, err := os.Open(path)
+	s.Require().NoError(err)
+	defer file.Close()
+	gzipReader, err := gzip.NewReader(file)
+	s.Require().NoError(err)
+	reader := tar.NewReader(gzipReader)
+	files := map[string]string{}
+	for {
+		header, err := reader.Next()
+		if errors.Is(err, io.EOF) {
+			return files
+		}
+		s.Require().NoError(err)
+		data, err := io.ReadAll(reader)
+		s.Require().NoError(err)
+		s.True(strings.HasPrefix(header.Name, "orch-support-20250601T123000Z/"), header.Name)
+		files[strings.TrimPrefix(header.Name, "orch-support-20250601T123000Z/")] = string(data)
+	}
+}
+
+func (s *SupportTest) manifest(files map[string]string) support.Manifest {
+	manifest := support.Manifest{}
+	s.Require().NoError(json.Unmarshal([]byte(files[support.ManifestFile]), &manifest))
+	return manifest
+}
+
+func (s *SupportTest) TestCollect() {
+	client := fake.NewSimpleClientset(
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node-1"}},
+		pod("argocd", "argocd-server", corev1.PodRunning, 0),
+		pod("orch-platform", "keycloak", corev1.PodRunning, 0),
+		pod("orch-platform", "vault", corev1.PodRunning, 3),
+		pod("orch-infra", "inventory", corev1.PodPending, 0),
+		&corev1.Event{ObjectMeta: metav1.ObjectMeta{Namespace: "orch-infra", Name: "inventory.1"}, Reason: "FailedScheduling"},
+		&corev1.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Namespace: "orch-platform", Name: "vault-data"}},
+	)
+	app := &unstructured.Unstructured{Object: map[string]any{
+		"apiVersion": "argoproj.io/v1alpha1",
+		"kind":       "Application",
+		"metadata":   map[string]any{"name": "root-app", "namespace": "onprem"},
+	}}
+	dynamicClient := dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(),
+		map[schema.GroupVersionResource]string{readiness.ApplicationResource: "ApplicationList"}, app)
+
+	path, err := support.Collect(context.Background(), s.dir, support.Options{
+		Version:       "3.1.0",
+		LogDir:        s.logDir,
+		Config:        &s.config,
+		RuntimeState:  &s.runtimeState,
+		Client:        client,
+		DynamicClient: dynamicClient,
+		Now:           func() time.Time { return now },
+	})
+	s.Require().NoError(err)
+	s.Equal(filepath.Join(s.dir, "orch-support-20250601T123000Z.tar.gz"), path)
+
+	files := s.readBundle(path)
+	for _, name := range []string{
+		"config.yaml", "runtime-state.yaml",
+		"logs/orch-installer.log", "logs/steps/Infra-Rke2Step.log", "logs/aws_rds.log",
+		"cluster/nodes.yaml", "cluster/pods.yaml", "cluster/events.yaml", "cluster/pvcs.yaml", "cluster/argocd-applications.yaml",
+		// All pods of argocd, and the ones that are not running or restarted elsewhere
+		"pod-logs/argocd/argocd-server/main.log",
+		"pod-logs/orch-platform/vault/main.log",
+		"pod-logs/orch-platform/vault/main.previous.log",
+		"pod-logs/orch-infra/inventory/main.log",
+	} {
+		s.Contains(files, name)
+	}
+	s.NotContains(files, "pod-logs/orch-platform/keycloak/main.log")
+	s.Contains(files["cluster/nodes.yaml"], "node-1")
+	s.Contains(files["cluster/events.yaml"], "FailedScheduling")
+	s.Contains(files["cluster/argocd-applications.yaml"], "root-app")
+
+	for name, data := range files {
+		s.NotContains(data, "admin-s3cr3t", name)
+		s.NotContains(data, "db-s3cr3t", name)
+		s.NotContains(data, "kind: Config", name)
+	}
+	s.Contains(files["config.yaml"], "adminPassword: '[REDACTED]'")
+	s.Contains(files["logs/aws_rds.log"], "password = [REDACTED]")
+
+	manifest := s.manifest(files)
+	s.Equal("3.1.0", manifest.Version)
+	s.Equal("onprem", manifest.Provider)
+	s.Equal(now, manifest.CreatedAt)
+	s.Len(manifest.Files, len(files)-1)
+	s.Empty(manifest.Errors)
+}
+
+func (s *SupportTest) TestCollectWithErrors() {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("connection refused")
+	})
+
+	path, err := support.Collect(context.Background(), s.dir, support.Options{
+		LogDir: s.logDir,
+		Client: client,
+		Now:    func() time.Time { return now },
+	})
+	s.Require().NoError(err)
+
+	files := s.readBundle(path)
+	s.Contains(files, "logs/orch-installer.log")
+	s.Contains(files, "cluster/nodes.yaml")
+	s.NotContains(files, "config.yaml")
+	manifest := s.manifest(files)
+	s.Equal([]string{"pods: connection refused"}, manifest.Errors)
+}
+
+// secretLike matches the names of the fields that hold passwords, tokens, private keys and kubeconfigs,
+// and not the paths, endpoints and URLs next to them.
+var secretLike = regexp.MustCompile(`(?i)(password|token|secret|privatekey|tlskey|kubeconfig)$`)
+
+// setSecretLike sets the secret-like string fields of value, including the ones of its slice and
+// map elements, to unique values and returns them by field name.
+func setSecretLike(value reflect.Value, name string, secrets map[string]string) {
+	switch value.Kind() {
+	case reflect.Struct:
+		for i := range value.NumField() {
+			field := value.Type().Field(i)
+			if field.Type.Kind() == reflect.String {
+				if secretLike.MatchString(field.Name) {
+					secrets[name+"."+field.Name] = "s3cr3t-" + strings.ToLower(name+"-"+field.Name)
+					value.Field(i).SetString(secrets[name+"."+field.Name])
+				}
+				continue
+			}
+			setSecretLike(value.Field(i), name+"."+field.Name, secrets)
+		}
+	case reflect.Slice:
+		element := reflect.New(value.Type().Elem()).Elem()
+		setSecretLike(element, name+"[]", secrets)
+		value.Set(reflect.Append(value, element))
+	case reflect.Map:
+		element := reflect.New(value.Type().Elem()).Elem()
+		setSecretLike(element, name+"[]", secrets)
+		if value.IsNil() {
+			value.Set(reflect.MakeMap(value.Type()))
+		}
+		value.SetMapIndex(reflect.New(value.Type().Key()).Elem(), element)
+	}
+}
+
+func (s *SupportTest) TestCollectRedactsSecretLikeFields() {
+	secrets := map[string]string{}
+	setSecretLike(reflect.ValueOf(&s.config).Elem(), "config", secrets)
+	setSecretLike(reflect.ValueOf(&s.runtimeState).Elem(), "runtimeState", secrets)
+	s.Contains(secrets, "runtimeState.AWS.JumpHostSSHKeyPrivateKey")
+
+	path, err := support.Collect(context.Background(), s.dir, support.Options{
+		Config:       &s.config,
+		RuntimeState: &s.runtimeState,
+		Now:          func() time.Time { return now },
+	})
+	s.Require().NoError(err)
+
+	files := s.readBundle(path)
+	s.Contains(files, "config.yaml")
+	s.Contains(files, "runtime-state.yaml")
+	for field, secret := range secrets {
+		for name, data := range files {
+			s.NotContains(data, secret, "%s in %s", field, name)
+		}
+	}
+}
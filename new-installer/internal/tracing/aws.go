@@ -0,0 +1,42 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing
+
+import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/request"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// InstrumentAWS adds a span for each call made by the AWS clients of a session, e.g.
+// session.Handlers. The spans are children of the span in the context of the call or, for the
+// calls without one, of the current span.
+func InstrumentAWS(handlers *request.Handlers) {
+	handlers.Build.PushFrontNamed(request.NamedHandler{Name: "orch.tracing.Start", Fn: startAWSSpan})
+	handlers.Complete.PushBackNamed(request.NamedHandler{Name: "orch.tracing.End", Fn: endAWSSpan})
+}
+
+func startAWSSpan(r *request.Request) {
+	ctx := r.Context()
+	if !trace.SpanContextFromContext(ctx).IsValid() {
+		ctx = Current()
+	}
+	ctx, _ = Start(ctx, r.ClientInfo.ServiceID+"."+r.Operation.Name,
+		semconv.RPCSystemKey.String("aws-api"),
+		semconv.RPCService(r.ClientInfo.ServiceID),
+		semconv.RPCMethod(r.Operation.Name),
+		semconv.CloudRegion(aws.StringValue(r.Config.Region)),
+	)
+	r.SetContext(ctx)
+}
+
+func endAWSSpan(r *request.Request) {
+	span := trace.SpanFromContext(r.Context())
+	if r.HTTPResponse != nil {
+		span.SetAttributes(semconv.HTTPResponseStatusCode(r.HTTPResponse.StatusCode))
+	}
+	End(span, r.Error)
+}
@@ -0,0 +1,143 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package tracing records OpenTelemetry spans of the installer: the run, its stages, the phases
+// of the steps and the Terraform and AWS calls they make.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	TracerName  = "github.com/open-edge-platform/edge-manageability-framework/installer"
+	ServiceName = "orch-installer"
+
+	// Resource attributes of the deployment the spans belong to
+	DeploymentIDKey = attribute.Key("orch.deployment.id")
+	ProviderKey     = attribute.Key("orch.provider")
+
+	// Span attributes
+	ActionKey    = attribute.Key("orch.action")
+	StageKey     = attribute.Key("orch.stage")
+	StepKey      = attribute.Key("orch.step")
+	PhaseKey     = attribute.Key("orch.phase")
+	ErrorCodeKey = attribute.Key("orch.error_code")
+
+	TerraformCommandKey = attribute.Key("terraform.command")
+	TerraformModuleKey  = attribute.Key("terraform.module")
+)
+
+// Options tune Init, nothing is exported with the zero value.
+type Options struct {
+	// OTLP/HTTP endpoint of a collector, e.g. localhost:4318
+	Endpoint string
+	// Send the spans to the collector over plain HTTP
+	Insecure bool
+	// Also write the spans to this file, as JSON
+	File string
+
+	Version      string
+	DeploymentID string
+	Provider     string
+}
+
+// Init installs the global tracer provider. The returned function flushes the remaining spans
+// and must be called before the installer exits.
+func Init(ctx context.Context, options Options) (func(context.Context) error, error) {
+	if options.Endpoint == "" && options.File == "" {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	providerOptions := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource.NewWithAttributes(semconv.SchemaURL,
+			semconv.ServiceName(ServiceName),
+			semconv.ServiceVersion(options.Version),
+			DeploymentIDKey.String(options.DeploymentID),
+			ProviderKey.String(options.Provider),
+		)),
+	}
+	var file *os.File
+	if options.File != "" {
+		var err error
+		file, err = os.OpenFile(options.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open the trace file: %w", err)
+		}
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(file))
+		if err != nil {
+			return nil, errors.Join(err, file.Close())
+		}
+		providerOptions = append(providerOptions, sdktrace.WithBatcher(exporter))
+	}
+	if options.Endpoint != "" {
+		clientOptions := []otlptracehttp.Option{otlptracehttp.WithEndpoint(options.Endpoint)}
+		if options.Insecure {
+			clientOptions = append(clientOptions, otlptracehttp.WithInsecure())
+		}
+		exporter, err := otlptracehttp.New(ctx, clientOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create the OTLP exporter: %w", err)
+		}
+		providerOptions = append(providerOptions, sdktrace.WithBatcher(exporter))
+	}
+
+	provider := sdktrace.NewTracerProvider(providerOptions...)
+	otel.SetTracerProvider(provider)
+	return func(ctx context.Context) error {
+		err := provider.Shutdown(ctx)
+		if file != nil {
+			err = errors.Join(err, file.Close())
+		}
+		return err
+	}, nil
+}
+
+// Start starts a span, a child of the span in ctx if any.
+func Start(ctx context.Context, name string, attributes ...attribute.KeyValue) (context.Context, trace.Span) {
+	return otel.Tracer(TracerName).Start(ctx, name, trace.WithAttributes(attributes...))
+}
+
+// End records the error, if any, and ends the span.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+var current = struct {
+	sync.Mutex
+	ctx context.Context
+}{ctx: context.Background()}
+
+// SetCurrent records the context of the step phase being run, for the calls that are not given
+// a context, such as most AWS calls. Steps run one at a time, like their logs.
+func SetCurrent(ctx context.Context) {
+	current.Lock()
+	defer current.Unlock()
+	current.ctx = ctx
+}
+
+// Current returns the context of the step phase being run.
+func Current() context.Context {
+	current.Lock()
+	defer current.Unlock()
+	return current.ctx
+}
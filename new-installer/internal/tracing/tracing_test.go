@@ -0,0 +1,145 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package tracing_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/tracing"
+	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+type TracingTest struct {
+	suite.Suite
+}
+
+func TestTracing(t *testing.T) {
+	suite.Run(t, new(TracingTest))
+}
+
+func (s *TracingTest) TearDownTest() {
+	otel.SetTracerProvider(noop.NewTracerProvider())
+	tracing.SetCurrent(context.Background())
+}
+
+func (s *TracingTest) TestDisabled() {
+	shutdown, err := tracing.Init(context.Background(), tracing.Options{})
+	s.Require().NoError(err)
+	_, span := tracing.Start(context.Background(), "OrchInstaller.Run")
+	s.False(span.IsRecording())
+	s.NoError(shutdown(context.Background()))
+}
+
+func (s *TracingTest) TestFile() {
+	file := filepath.Join(s.T().TempDir(), "traces.json")
+	shutdown, err := tracing.Init(context.Background(), tracing.Options{
+		File:         file,
+		Version:      "3.1.0",
+		DeploymentID: "deployment-1",
+		Provider:     "onprem",
+	})
+	s.Require().NoError(err)
+
+	ctx, root := tracing.Start(context.Background(), "OrchInstaller.Run", tracing.ActionKey.String("install"))
+	_, stage := tracing.Start(ctx, "stage Infra", tracing.StageKey.String("Infra"))
+	tracing.End(stage, errors.New("rke2 failed"))
+	tracing.End(root, nil)
+	s.Require().NoError(shutdown(context.Background()))
+
+	data, err := os.ReadFile(file)
+	s.Require().NoError(err)
+	type span struct {
+		Name   string
+		Parent struct{ SpanID string }
+		Status struct {
+			Code        string
+			Description string
+		}
+		Resource []struct {
+			Key   string
+			Value struct{ Value any }
+		}
+	}
+	spans := map[string]span{}
+	decoder := json.NewDecoder(strings.NewReader(string(data)))
+	for decoder.More() {
+		var record span
+		s.Require().NoError(decoder.Decode(&record))
+		spans[record.Name] = record
+	}
+	s.Len(spans, 2)
+	s.Equal("Error", spans["stage Infra"].Status.Code)
+	s.Equal("rke2 failed", spans["stage Infra"].Status.Description)
+	resource := map[string]any{}
+	for _, attribute := range spans["OrchInstaller.Run"].Resource {
+		resource[attribute.Key] = attribute.Value.Value
+	}
+	s.Equal("orch-installer", resource["service.name"])
+	s.Equal("3.1.0", resource["service.version"])
+	s.Equal("deployment-1", resource[string(tracing.DeploymentIDKey)])
+	s.Equal("onprem", resource[string(tracing.ProviderKey)])
+}
+
+func (s *TracingTest) TestAWS() {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.Contains(r.URL.Path, "missing") {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte("<ListAllMyBucketsResult></ListAllMyBucketsResult>"))
+	}))
+	defer server.Close()
+	awsSession, err := session.NewSession(&aws.Config{
+		Region:           aws.String("us-west-2"),
+		Endpoint:         aws.String(server.URL),
+		Credentials:      credentials.NewStaticCredentials("id", "secret", ""),
+		S3ForcePathStyle: aws.Bool(true),
+		MaxRetries:       aws.Int(0),
+	})
+	s.Require().NoError(err)
+	tracing.InstrumentAWS(&awsSession.Handlers)
+	client := s3.New(awsSession)
+
+	// The calls without a context are children of the current span
+	phaseCtx, phase := tracing.Start(context.Background(), "RunStep VPC")
+	tracing.SetCurrent(phaseCtx)
+	_, err = client.ListBuckets(&s3.ListBucketsInput{})
+	s.Require().NoError(err)
+	_, err = client.HeadObject(&s3.HeadObjectInput{Bucket: aws.String("missing"), Key: aws.String("key")})
+	s.Require().Error(err)
+	tracing.End(phase, nil)
+
+	spans := recorder.Ended()
+	s.Require().Len(spans, 3)
+	list, head := spans[0], spans[1]
+	s.Equal("S3.ListBuckets", list.Name())
+	s.Equal(phase.SpanContext().SpanID(), list.Parent().SpanID())
+	s.Contains(list.Attributes(), attribute.String("rpc.method", "ListBuckets"))
+	s.Contains(list.Attributes(), attribute.String("cloud.region", "us-west-2"))
+	s.Contains(list.Attributes(), attribute.Int("http.response.status_code", http.StatusOK))
+	s.Equal("S3.HeadObject", head.Name())
+	s.Equal(codes.Error, head.Status().Code)
+	s.Contains(head.Attributes(), attribute.Int("http.response.status_code", http.StatusNotFound))
+}
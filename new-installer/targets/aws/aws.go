@@ -9,9 +9,106 @@ import (
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 	steps_aws "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/aws"
+	commonSteps "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
 )
 
-func CreateAWSStages(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) ([]internal.OrchInstallerStage, error) {
+const ProviderName = "aws"
+
+func init() {
+	targets.Register(targets.Provider{
+		Name: ProviderName,
+		Stages: []config.StageLayout{
+			{
+				Name:   "PreInfra",
+				Labels: []string{"pre-infra"},
+				Steps:  []string{"AWSStateBucketStep", "VPCStep"},
+			},
+			{
+				Name:   "Infra",
+				Labels: []string{"infra"},
+				Steps: []string{
+					"EKSStep",
+					"ECRCacheStep",
+					"EFSStep",
+					"RDSStep",
+					"ObservabilityBucketsStep",
+					"KMSStep",
+					"DRStep",
+					"ImportCertificateToACMStep",
+					"LoadBalancerStep",
+					"Route53Step",
+				},
+			},
+		},
+		NewStage: func(name string, steps []steps.OrchInstallerStep, labels []string, orchConfigReaderWriter config.OrchConfigReaderWriter) internal.OrchInstallerStage {
+			return NewAWSStage(name, steps, labels, orchConfigReaderWriter)
+		},
+	})
+
+	// The Terraform steps of the infrastructure
+	for _, step := range []struct {
+		name      string
+		dependsOn []string
+		create    func(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility steps_aws.AWSUtility) steps.OrchInstallerStep
+	}{
+		{"VPCStep", []string{"AWSStateBucketStep"}, wrap(steps_aws.CreateVPCStep)},
+		{"EKSStep", []string{"VPCStep"}, wrap(steps_aws.CreateEKSStep)},
+		{"ECRCacheStep", []string{"EKSStep"}, wrap(steps_aws.CreateECRCacheStep)},
+		{"EFSStep", []string{"EKSStep"}, wrap(steps_aws.CreateEFSStep)},
+		{"RDSStep", []string{"VPCStep"}, wrap(steps_aws.CreateRDSStep)},
+		{"ObservabilityBucketsStep", []string{"EKSStep"}, wrap(steps_aws.CreateObservabilityBucketsStep)},
+		{"KMSStep", []string{"EKSStep"}, wrap(steps_aws.CreateKMSStep)},
+		{"DRStep", []string{"RDSStep", "KMSStep"}, wrap(steps_aws.CreateDRStep)},
+		{"ImportCertificateToACMStep", []string{"AWSStateBucketStep"}, wrap(steps_aws.CreateImportCertificateToACMStep)},
+		{"LoadBalancerStep", []string{"EKSStep", "ImportCertificateToACMStep"}, wrap(steps_aws.CreateLoadBalancerStep)},
+		{"Route53Step", []string{"LoadBalancerStep"}, wrap(steps_aws.CreateRoute53Step)},
+	} {
+		targets.RegisterStep(targets.Step{
+			Name:      step.name,
+			Provider:  ProviderName,
+			DependsOn: step.dependsOn,
+			Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+				tfUtil, err := createTerraformUtility(env.RootPath)
+				if err != nil {
+					return nil, err
+				}
+				return step.create(env.RootPath, env.KeepGeneratedFiles, tfUtil, steps_aws.CreateAWSUtility()), nil
+			},
+		})
+	}
+	targets.RegisterStep(targets.Step{
+		Name:     "AWSStateBucketStep",
+		Provider: ProviderName,
+		Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+			tfUtil, err := createTerraformUtility(env.RootPath)
+			if err != nil {
+				return nil, err
+			}
+			return steps_aws.CreateAWSStateBucketStep(env.RootPath, env.KeepGeneratedFiles, tfUtil), nil
+		},
+	})
+	// Nothing installs ArgoCD and root-app on AWS yet, the step is only run by a stage layout
+	// of the config, against an orchestrator deployed by other means
+	targets.RegisterStep(targets.Step{
+		Name:      "WaitForReadyStep",
+		Provider:  ProviderName,
+		Labels:    []string{ProviderName},
+		DependsOn: []string{"EKSStep"},
+		Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+			return commonSteps.CreateWaitForReadyStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter), nil
+		},
+	})
+}
+
+// wrap returns a step factory returning the interface rather than the step type.
+func wrap[T steps.OrchInstallerStep](create func(string, bool, steps.TerraformUtility, steps_aws.AWSUtility) T) func(string, bool, steps.TerraformUtility, steps_aws.AWSUtility) steps.OrchInstallerStep {
+	return func(rootPath string, keepGeneratedFiles bool, terraformUtility steps.TerraformUtility, awsUtility steps_aws.AWSUtility) steps.OrchInstallerStep {
+		return create(rootPath, keepGeneratedFiles, terraformUtility, awsUtility)
+	}
+}
+
+func createTerraformUtility(rootPath string) (steps.TerraformUtility, error) {
 	tfUtil, err := steps.CreateTerraformUtility(rootPath)
 	if err != nil {
 		return nil, &internal.OrchInstallerError{
@@ -19,17 +116,5 @@ func CreateAWSStages(rootPath string, keepGeneratedFiles bool, orchConfigReaderW
 			ErrorMsg:  "Failed to create Terraform utility: " + err.Error(),
 		}
 	}
-	aws_util := steps_aws.CreateAWSUtility()
-	return []internal.OrchInstallerStage{
-		NewAWSStage("PreInfra", []steps.OrchInstallerStep{
-			steps_aws.CreateAWSStateBucketStep(rootPath, keepGeneratedFiles, tfUtil),
-			steps_aws.CreateVPCStep(rootPath, keepGeneratedFiles, tfUtil, aws_util),
-		}, []string{"pre-infra"}, orchConfigReaderWriter),
-		NewAWSStage("Infra", []steps.OrchInstallerStep{
-			steps_aws.CreateEFSStep(rootPath, keepGeneratedFiles, tfUtil, aws_util),
-			steps_aws.CreateRDSStep(rootPath, keepGeneratedFiles, tfUtil, aws_util),
-			steps_aws.CreateObservabilityBucketsStep(rootPath, keepGeneratedFiles, tfUtil, aws_util),
-			steps_aws.CreateKMSStep(rootPath, keepGeneratedFiles, tfUtil, aws_util),
-		}, []string{"infra"}, orchConfigReaderWriter),
-	}, nil
+	return tfUtil, nil
 }
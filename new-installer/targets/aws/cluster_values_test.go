@@ -0,0 +1,116 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package aws_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+	"gopkg.in/yaml.v3"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+)
+
+const (
+	repoRoot            = "../../.."
+	vaultTemplate       = "templates/vault.yaml"
+	testVaultRoleARN    = "arn:aws:iam::123456789012:role/vault-test"
+	vaultRoleAnnotation = "eks.amazonaws.com/role-arn"
+)
+
+// ClusterValuesTest renders the Vault application of root-app with the cluster values that
+// installer/configure-cluster.sh generates for an AWS deployment.
+type ClusterValuesTest struct {
+	suite.Suite
+	env map[string]string
+}
+
+func TestClusterValues(t *testing.T) {
+	suite.Run(t, new(ClusterValuesTest))
+}
+
+func (s *ClusterValuesTest) SetupTest() {
+	s.env = map[string]string{
+		"CLUSTER_NAME":   "test",
+		"CLUSTER_FQDN":   "test.example.com",
+		"AWS_ACCOUNT":    "123456789012",
+		"AWS_REGION":     "us-west-2",
+		"VAULT_ROLE_ARN": testVaultRoleARN,
+	}
+}
+
+func (s *ClusterValuesTest) TestVaultRole() {
+	vault := s.renderVault()
+	serviceAccount, _ := vault["server"].(map[string]any)["serviceAccount"].(map[string]any)
+	s.Require().NotNil(serviceAccount, "the Vault service account should be annotated")
+	s.Equal(testVaultRoleARN, serviceAccount["annotations"].(map[string]any)[vaultRoleAnnotation])
+	s.NotContains(vault["server"], "extraSecretEnvironmentVars")
+}
+
+func (s *ClusterValuesTest) TestNoVaultRole() {
+	s.env["VAULT_ROLE_ARN"] = ""
+	vault := s.renderVault()
+	s.NotContains(vault["server"], "serviceAccount")
+	s.Contains(vault["server"], "extraSecretEnvironmentVars")
+}
+
+// renderVault renders the Vault application and returns the values it passes to the Vault chart.
+func (s *ClusterValuesTest) renderVault() map[string]any {
+	tpl, err := os.ReadFile(filepath.Join(repoRoot, "installer", "cluster.tpl"))
+	s.Require().NoError(err)
+	clusterValues := map[string]any{}
+	s.Require().NoError(yaml.Unmarshal([]byte(os.Expand(string(tpl), func(key string) string {
+		return s.env[key]
+	})), &clusterValues))
+
+	// Later profiles override earlier ones, the cluster values come last
+	values := map[string]any{}
+	for _, file := range clusterValues["root"].(map[string]any)["clusterValues"].([]any) {
+		if strings.HasPrefix(file.(string), "orch-configs/clusters/") {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(repoRoot, file.(string)))
+		s.Require().NoError(err)
+		profile := map[string]any{}
+		s.Require().NoError(yaml.Unmarshal(data, &profile))
+		values = chartutil.CoalesceTables(profile, values)
+	}
+	values = chartutil.CoalesceTables(clusterValues, values)
+
+	applications, err := loader.Load(filepath.Join(repoRoot, "argocd", "applications"))
+	s.Require().NoError(err)
+	templates := []*chart.File{}
+	for _, template := range applications.Templates {
+		if template.Name == vaultTemplate || strings.HasPrefix(filepath.Base(template.Name), "_") {
+			templates = append(templates, template)
+		}
+	}
+	applications.Templates = templates
+	renderValues, err := chartutil.ToRenderValues(applications, values, chartutil.ReleaseOptions{
+		Name:      "root-app",
+		Namespace: s.env["CLUSTER_NAME"],
+	}, nil)
+	s.Require().NoError(err)
+	manifests, err := engine.Render(applications, renderValues)
+	s.Require().NoError(err)
+
+	application := struct {
+		Spec struct {
+			Sources []struct {
+				Helm struct {
+					ValuesObject map[string]any `yaml:"valuesObject"`
+				} `yaml:"helm"`
+			} `yaml:"sources"`
+		} `yaml:"spec"`
+	}{}
+	s.Require().NoError(yaml.Unmarshal([]byte(manifests[filepath.Join(applications.Name(), vaultTemplate)]), &application))
+	s.Require().NotEmpty(application.Spec.Sources, "the Vault application should be rendered")
+	return application.Spec.Sources[0].Helm.ValuesObject
+}
@@ -22,6 +22,16 @@ resource "aws_security_group_rule" "infra_allow_https" {
   description = "Allow HTTPS traffic from IP allow list"
 }
 
+resource "aws_security_group_rule" "infra_allow_egress" {
+  type              = "egress"
+  from_port         = 0
+  to_port           = 0
+  protocol          = "-1"
+  cidr_blocks       = ["0.0.0.0/0"]
+  security_group_id = aws_security_group.infra.id
+  description = "Allow traffic from the load balancer to the targets"
+}
+
 #trivy:ignore:AVD-AWS-0053 Allow public access to the load balancer
 resource "aws_lb" "infra" {
   name                       = substr(sha256("${var.cluster_name}-argocd"), 0, 32)
@@ -74,7 +84,7 @@ resource "aws_lb_target_group" "infra_gitea" {
     matcher             = 200
   }
   tags = {
-    Name = "${var.cluster_name}-infra-argocd"
+    Name = "${var.cluster_name}-infra-gitea"
   }
 }
 
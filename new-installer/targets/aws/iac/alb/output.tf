@@ -25,3 +25,19 @@ output "infra_argocd_target_group_arn" {
 output "infra_gitea_target_group_arn" {
   value = aws_lb_target_group.infra_gitea.arn
 }
+
+output "traefik_lb_arn" {
+  value = aws_lb.traefik.arn
+}
+
+output "infra_lb_arn" {
+  value = aws_lb.infra.arn
+}
+
+output "traefik_sg_id" {
+  value = aws_security_group.traefik.id
+}
+
+output "infra_sg_id" {
+  value = aws_security_group.infra.id
+}
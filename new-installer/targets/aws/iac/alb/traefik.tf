@@ -22,6 +22,16 @@ resource "aws_security_group_rule" "traefik_allow_https" {
   description = "Allow HTTPS traffic from IP allow list"
 }
 
+resource "aws_security_group_rule" "traefik_allow_egress" {
+  type              = "egress"
+  from_port         = 0
+  to_port           = 0
+  protocol          = "-1"
+  cidr_blocks       = ["0.0.0.0/0"]
+  security_group_id = aws_security_group.traefik.id
+  description = "Allow traffic from the load balancer to the targets"
+}
+
 #trivy:ignore:AVD-AWS-0053 Allow public access to the load balancer
 resource "aws_lb" "traefik" {
   name                       = substr(sha256("${var.cluster_name}-traefik"), 0, 32)
@@ -58,10 +68,10 @@ resource "aws_lb_target_group" "traefik" {
 }
 
 resource "aws_lb_target_group" "traefik_grpc" {
-  name             = substr(sha256("${var.cluster_name}-traefik"), 0, 32)
+  name             = substr(sha256("${var.cluster_name}-traefik-grpc"), 0, 32)
   port             = 1
   protocol         = "HTTPS"
-  protocol_version = "HTTP1"
+  protocol_version = "GRPC"
   vpc_id           = var.vpc_id
   target_type      = "ip"
   health_check {
@@ -74,7 +84,7 @@ resource "aws_lb_target_group" "traefik_grpc" {
     matcher             = 0
   }
   tags = {
-    Name = "${var.cluster_name}-traefik"
+    Name = "${var.cluster_name}-traefik-grpc"
   }
 }
 
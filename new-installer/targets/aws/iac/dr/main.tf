@@ -0,0 +1,261 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+data "aws_rds_cluster" "primary" {
+  cluster_identifier = var.rds_cluster_identifier
+}
+
+data "aws_s3_bucket" "state" {
+  bucket = var.state_bucket
+}
+
+data "aws_availability_zones" "dr" {
+  provider = aws.dr
+  state    = "available"
+}
+
+locals {
+  multi_region_key   = can(regex(":key/mrk-", var.kms_key_arn))
+  dr_kms_key_arn     = local.multi_region_key ? aws_kms_replica_key.dr[0].arn : aws_kms_key.dr[0].arn
+  dr_azs             = slice(data.aws_availability_zones.dr.names, 0, 3)
+  postgres_ver_major = split(".", data.aws_rds_cluster.primary.engine_version)[0]
+}
+
+# KMS: multi-region keys are replicated, otherwise a new key is created in the DR region
+resource "aws_kms_replica_key" "dr" {
+  provider                = aws.dr
+  count                   = local.multi_region_key ? 1 : 0
+  description             = "Replica of ${var.cluster_name} key for disaster recovery"
+  primary_key_arn         = var.kms_key_arn
+  deletion_window_in_days = 10
+}
+
+resource "aws_kms_key" "dr" {
+  provider                = aws.dr
+  count                   = local.multi_region_key ? 0 : 1
+  description             = "Key of ${var.cluster_name} for disaster recovery"
+  deletion_window_in_days = 10
+  enable_key_rotation     = true
+}
+
+# State bucket replica
+resource "aws_s3_bucket" "state" {
+  provider      = aws.dr
+  bucket        = "${var.state_bucket}-dr"
+  force_destroy = true
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "state" {
+  provider = aws.dr
+  bucket   = aws_s3_bucket.state.id
+  rule {
+    apply_server_side_encryption_by_default {
+      kms_master_key_id = local.dr_kms_key_arn
+      sse_algorithm     = "aws:kms"
+    }
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "state" {
+  provider                = aws.dr
+  bucket                  = aws_s3_bucket.state.id
+  block_public_acls       = true
+  block_public_policy     = true
+  restrict_public_buckets = true
+  ignore_public_acls      = true
+}
+
+resource "aws_s3_bucket_versioning" "state" {
+  provider = aws.dr
+  bucket   = aws_s3_bucket.state.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+
+data "aws_iam_policy_document" "replication_assume_role" {
+  statement {
+    actions = ["sts:AssumeRole"]
+    effect  = "Allow"
+    principals {
+      identifiers = ["s3.amazonaws.com"]
+      type        = "Service"
+    }
+  }
+}
+
+resource "aws_iam_role" "state_replication" {
+  name               = "${var.cluster_name}-state-replication-role"
+  description        = "Role to replicate the state bucket of ${var.cluster_name} to ${var.dr_region}"
+  assume_role_policy = data.aws_iam_policy_document.replication_assume_role.json
+}
+
+resource "aws_iam_role_policy" "state_replication" {
+  name = "${var.cluster_name}-state-replication"
+  role = aws_iam_role.state_replication.id
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid      = "SourceBucket"
+        Effect   = "Allow"
+        Action   = ["s3:GetReplicationConfiguration", "s3:ListBucket"]
+        Resource = data.aws_s3_bucket.state.arn
+      },
+      {
+        Sid      = "SourceObjects"
+        Effect   = "Allow"
+        Action   = ["s3:GetObjectVersionForReplication", "s3:GetObjectVersionAcl", "s3:GetObjectVersionTagging"]
+        Resource = "${data.aws_s3_bucket.state.arn}/*"
+      },
+      {
+        Sid      = "ReplicaObjects"
+        Effect   = "Allow"
+        Action   = ["s3:ReplicateObject", "s3:ReplicateDelete", "s3:ReplicateTags"]
+        Resource = "${aws_s3_bucket.state.arn}/*"
+      },
+      {
+        Sid      = "SourceKey"
+        Effect   = "Allow"
+        Action   = ["kms:Decrypt"]
+        Resource = var.kms_key_arn
+      },
+      {
+        Sid      = "ReplicaKey"
+        Effect   = "Allow"
+        Action   = ["kms:Encrypt", "kms:GenerateDataKey"]
+        Resource = local.dr_kms_key_arn
+      },
+    ]
+  })
+}
+
+resource "aws_s3_bucket_replication_configuration" "state" {
+  role   = aws_iam_role.state_replication.arn
+  bucket = data.aws_s3_bucket.state.id
+
+  rule {
+    id     = "disaster-recovery"
+    status = "Enabled"
+    filter {
+      prefix = ""
+    }
+    delete_marker_replication {
+      status = "Enabled"
+    }
+    source_selection_criteria {
+      sse_kms_encrypted_objects {
+        status = "Enabled"
+      }
+    }
+    destination {
+      bucket = aws_s3_bucket.state.arn
+      encryption_configuration {
+        replica_kms_key_id = local.dr_kms_key_arn
+      }
+    }
+  }
+
+  depends_on = [aws_s3_bucket_versioning.state]
+}
+
+# Network for the standby RDS cluster, only reachable from inside the VPC
+resource "aws_vpc" "dr" {
+  provider             = aws.dr
+  cidr_block           = var.vpc_cidr
+  enable_dns_hostnames = true
+  enable_dns_support   = true
+  tags = {
+    Name = "${var.cluster_name}-dr"
+  }
+}
+
+resource "aws_subnet" "dr" {
+  provider          = aws.dr
+  for_each          = { for i, az in local.dr_azs : az => i }
+  vpc_id            = aws_vpc.dr.id
+  availability_zone = each.key
+  cidr_block        = cidrsubnet(var.vpc_cidr, 4, each.value)
+  tags = {
+    Name = "${var.cluster_name}-dr-${each.key}"
+  }
+}
+
+resource "aws_db_subnet_group" "dr" {
+  provider   = aws.dr
+  name       = "${var.cluster_name}-dr"
+  subnet_ids = [for s in aws_subnet.dr : s.id]
+}
+
+resource "aws_security_group" "dr" {
+  provider = aws.dr
+  vpc_id   = aws_vpc.dr.id
+  name     = "${var.cluster_name}-dr-rds-sg"
+  ingress {
+    from_port   = 5432
+    to_port     = 5432
+    protocol    = "tcp"
+    cidr_blocks = [var.vpc_cidr]
+  }
+  egress {
+    from_port   = 0
+    to_port     = 0
+    protocol    = "-1"
+    cidr_blocks = [var.vpc_cidr]
+  }
+}
+
+# RDS global database, the primary cluster is the writer and the standby a read-only secondary
+resource "aws_rds_global_cluster" "main" {
+  global_cluster_identifier    = "${var.cluster_name}-global"
+  source_db_cluster_identifier = data.aws_rds_cluster.primary.arn
+  force_destroy                = true
+}
+
+resource "aws_rds_cluster_parameter_group" "dr" {
+  provider    = aws.dr
+  name        = "${var.cluster_name}-dr-cluster-pg"
+  family      = "aurora-postgresql${local.postgres_ver_major}"
+  description = "${var.cluster_name} DR cluster parameter group"
+
+  parameter {
+    name  = "rds.force_ssl"
+    value = "1"
+  }
+}
+
+resource "aws_rds_cluster" "dr" {
+  provider                        = aws.dr
+  cluster_identifier              = "${var.cluster_name}-dr"
+  global_cluster_identifier       = aws_rds_global_cluster.main.id
+  engine                          = aws_rds_global_cluster.main.engine
+  engine_version                  = aws_rds_global_cluster.main.engine_version_actual
+  engine_mode                     = "provisioned"
+  db_subnet_group_name            = aws_db_subnet_group.dr.name
+  vpc_security_group_ids          = [aws_security_group.dr.id]
+  db_cluster_parameter_group_name = aws_rds_cluster_parameter_group.dr.name
+  storage_encrypted               = true
+  kms_key_id                      = local.dr_kms_key_arn
+  skip_final_snapshot             = true
+  enabled_cloudwatch_logs_exports = ["postgresql"]
+
+  serverlessv2_scaling_configuration {
+    min_capacity = var.min_acus
+    max_capacity = var.max_acus
+  }
+
+  # The standby becomes a standalone writer after a failover
+  lifecycle {
+    ignore_changes = [replication_source_identifier, global_cluster_identifier]
+  }
+}
+
+resource "aws_rds_cluster_instance" "dr" {
+  provider           = aws.dr
+  identifier         = "${var.cluster_name}-dr-instance"
+  cluster_identifier = aws_rds_cluster.dr.id
+  instance_class     = "db.serverless"
+  engine             = aws_rds_cluster.dr.engine
+  engine_version     = aws_rds_cluster.dr.engine_version
+}
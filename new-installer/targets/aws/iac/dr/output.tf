@@ -0,0 +1,27 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+output "state_bucket" {
+  value = aws_s3_bucket.state.id
+}
+
+output "kms_key_arn" {
+  value = local.dr_kms_key_arn
+}
+
+output "global_cluster_id" {
+  value = aws_rds_global_cluster.main.id
+}
+
+output "cluster_arn" {
+  value = aws_rds_cluster.dr.arn
+}
+
+output "database_host" {
+  value = aws_rds_cluster.dr.endpoint
+}
+
+output "database_reader_host" {
+  value = aws_rds_cluster.dr.reader_endpoint
+}
@@ -0,0 +1,52 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+variable "region" {
+  type = string
+}
+
+variable "dr_region" {
+  type        = string
+  description = "Region of the warm standby"
+}
+
+variable "cluster_name" {
+  type = string
+}
+
+variable "customer_tag" {
+  type    = string
+  default = ""
+}
+
+variable "state_bucket" {
+  type        = string
+  description = "The state bucket to replicate to the DR region"
+}
+
+variable "kms_key_arn" {
+  type        = string
+  description = "Customer-managed key of the primary region. A replica key is created if it is a multi-region key"
+}
+
+variable "rds_cluster_identifier" {
+  type        = string
+  description = "The primary RDS cluster, it becomes the writer of the global cluster"
+}
+
+variable "vpc_cidr" {
+  type        = string
+  default     = "10.252.0.0/16"
+  description = "CIDR of the VPC created for the standby RDS cluster"
+}
+
+variable "min_acus" {
+  type    = number
+  default = 0.5
+}
+
+variable "max_acus" {
+  type    = number
+  default = 16
+}
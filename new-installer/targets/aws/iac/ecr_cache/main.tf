@@ -0,0 +1,65 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+data "aws_caller_identity" "current" {}
+
+locals {
+  account_id               = data.aws_caller_identity.current.account_id
+  registry                 = "${local.account_id}.dkr.ecr.${var.region}.amazonaws.com"
+  enable_docker_credential = anytrue([for rule in values(var.cache_rules) : rule.use_docker_credential])
+}
+
+# ECR requires the secret name to start with "ecr-pullthroughcache/"
+resource "aws_secretsmanager_secret" "docker_credential" {
+  count                   = local.enable_docker_credential ? 1 : 0
+  name                    = "ecr-pullthroughcache/${var.cluster_name}-dockerhub"
+  description             = "Docker Hub credential for the ECR pull-through cache"
+  recovery_window_in_days = 0
+}
+
+resource "aws_secretsmanager_secret_version" "docker_credential" {
+  count     = local.enable_docker_credential ? 1 : 0
+  secret_id = aws_secretsmanager_secret.docker_credential[0].id
+  secret_string = jsonencode({
+    username    = var.docker_username
+    accessToken = var.docker_token
+  })
+}
+
+resource "aws_ecr_pull_through_cache_rule" "cache" {
+  for_each              = var.cache_rules
+  ecr_repository_prefix = each.key
+  upstream_registry_url = each.value.upstream_registry_url
+  credential_arn        = each.value.use_docker_credential ? aws_secretsmanager_secret.docker_credential[0].arn : null
+  depends_on            = [aws_secretsmanager_secret_version.docker_credential]
+}
+
+# AmazonEC2ContainerRegistryReadOnly allows nodes to pull existing images only,
+# creating the cached repository on first pull needs extra permissions.
+resource "aws_iam_policy" "pull_through_cache" {
+  name = "${var.cluster_name}-ecr-pull-through-cache"
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "ecr:BatchImportUpstreamImage",
+          "ecr:CreateRepository",
+          "ecr:BatchGetImage",
+          "ecr:GetDownloadUrlForLayer",
+          "ecr:BatchCheckLayerAvailability"
+        ]
+        Resource = [
+          for prefix in keys(var.cache_rules) : "arn:aws:ecr:${var.region}:${local.account_id}:repository/${prefix}/*"
+        ]
+      }
+    ]
+  })
+}
+
+resource "aws_iam_role_policy_attachment" "pull_through_cache" {
+  role       = var.node_role_name
+  policy_arn = aws_iam_policy.pull_through_cache.arn
+}
@@ -0,0 +1,24 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+terraform {
+  backend "s3" {}
+  required_version = ">= 1.9.5"
+  required_providers {
+    aws = {
+      source = "hashicorp/aws"
+      version = "5.93.0"
+    }
+  }
+}
+
+provider "aws" {
+  region = var.region
+  default_tags {
+    tags = {
+      environment = "${var.cluster_name}"
+      customer = var.customer_tag
+    }
+  }
+}
@@ -0,0 +1,45 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+variable "region" {
+  type        = string
+  description = "AWS region"
+}
+
+variable "cluster_name" {
+  type        = string
+  description = "The Orchestrator cluster name, used as the root of the ECR repository prefixes"
+}
+
+variable "customer_tag" {
+  description = "The customer tag to be used for the resources"
+  type        = string
+  default     = ""
+}
+
+variable "node_role_name" {
+  type        = string
+  description = "The IAM role of the EKS nodes which pull images through the cache"
+}
+
+variable "cache_rules" {
+  type = map(object({
+    upstream_registry_url = string
+    use_docker_credential = bool
+  }))
+  description = "Pull-through cache rules keyed by ECR repository prefix"
+}
+
+variable "docker_username" {
+  type        = string
+  description = "Docker Hub username, required by the Docker Hub pull-through cache rule"
+  default     = ""
+}
+
+variable "docker_token" {
+  type        = string
+  description = "Docker Hub access token, required by the Docker Hub pull-through cache rule"
+  default     = ""
+  sensitive   = true
+}
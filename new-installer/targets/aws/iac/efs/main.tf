@@ -85,6 +85,7 @@ resource "aws_security_group_rule" "allow_nfs" {
 # EFS
 resource "aws_efs_file_system" "efs" {
   encrypted       = local.efs_encryption
+  kms_key_id      = var.kms_key_arn == "" ? null : var.kms_key_arn
   throughput_mode = local.efs_throughput_mode
   tags = {
     Name = var.cluster_name
@@ -96,6 +97,10 @@ resource "aws_efs_file_system" "efs" {
   lifecycle_policy {
     transition_to_primary_storage_class = local.transition_to_primary_storage_class
   }
+  # Changing the key replaces the file system, existing deployments keep their key
+  lifecycle {
+    ignore_changes = [kms_key_id]
+  }
 }
 
 resource "aws_efs_mount_target" "target" {
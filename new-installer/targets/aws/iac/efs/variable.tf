@@ -30,3 +30,9 @@ variable "eks_oidc_issuer" {
   type        = string
   default     = ""
 }
+
+variable "kms_key_arn" {
+  description = "Customer-managed KMS key used for encryption at rest, the AWS-managed key is used if empty"
+  type        = string
+  default     = ""
+}
@@ -0,0 +1,33 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+locals {
+  aws_auth = templatefile("${path.module}/aws_auth.yaml.tpl", {
+    aws_account_id = local.aws_account_id
+    node_role_name = local.eks_nodegroup_role_name
+    name           = var.name
+    iam_roles      = var.iam_roles
+  })
+}
+
+# Maps the node role, the jump host role and any additional IAM roles to cluster users.
+resource "null_resource" "aws_auth" {
+  triggers = {
+    aws_auth = sha256(local.aws_auth)
+  }
+  provisioner "local-exec" {
+    command = <<EOT
+        set -eu
+        aws eks update-kubeconfig --name "${var.name}" --region "${var.region}" --kubeconfig "${local.kube_config_path}"
+        echo "$AWS_AUTH" | kubectl apply --kubeconfig "${local.kube_config_path}" --context "arn:aws:eks:${var.region}:${local.aws_account_id}:cluster/${var.name}" -f -
+EOT
+    environment = {
+      AWS_AUTH = local.aws_auth
+    }
+  }
+  depends_on = [
+    null_resource.create_kubecnofig,
+    aws_eks_node_group.nodegroup,
+  ]
+}
@@ -65,6 +65,63 @@ EOF
 EOF
 %{ endif }
 
+%{ if length(ecr_cache_mirrors) > 0 }
+# Mirror the upstream registries to the ECR pull-through cache. The kubelet only passes ECR
+# credentials for images referenced by their ECR name, so containerd gets them from a token,
+# valid for 12 hours, that a timer refreshes in the hosts.toml of each registry.
+cat <<'EOF' > /usr/local/bin/ecr-cache-mirrors
+#!/bin/bash
+set -euo pipefail
+set -a
+. /etc/environment
+set +a
+TOKEN=$(aws ecr get-login-password --region ${region})
+AUTH=$(echo -n "AWS:$TOKEN" | base64 -w0)
+%{ for registry, mirror in ecr_cache_mirrors ~}
+mkdir -p /etc/containerd/certs.d/${registry}
+cat <<HOSTS > /etc/containerd/certs.d/${registry}/hosts.toml.new
+server = "${mirror.server}"
+
+[host."https://${ecr_registry}/v2/${mirror.repository_prefix}"]
+  capabilities = ["pull", "resolve"]
+  override_path = true
+  [host."https://${ecr_registry}/v2/${mirror.repository_prefix}".header]
+    Authorization = "Basic $AUTH"
+HOSTS
+chmod 600 /etc/containerd/certs.d/${registry}/hosts.toml.new
+mv /etc/containerd/certs.d/${registry}/hosts.toml.new /etc/containerd/certs.d/${registry}/hosts.toml
+%{ endfor ~}
+EOF
+chmod 700 /usr/local/bin/ecr-cache-mirrors
+
+cat <<'EOF' > /etc/systemd/system/ecr-cache-mirrors.service
+[Unit]
+Description=Refresh the ECR pull-through cache credentials of containerd
+Wants=network-online.target
+After=network-online.target
+
+[Service]
+Type=oneshot
+ExecStart=/usr/local/bin/ecr-cache-mirrors
+EOF
+
+cat <<'EOF' > /etc/systemd/system/ecr-cache-mirrors.timer
+[Unit]
+Description=Refresh the ECR pull-through cache credentials of containerd
+
+[Timer]
+OnBootSec=0
+OnUnitActiveSec=6h
+
+[Install]
+WantedBy=timers.target
+EOF
+
+systemctl daemon-reload
+systemctl enable ecr-cache-mirrors.timer
+systemctl --no-block start ecr-cache-mirrors.timer
+%{ endif }
+
 cloud-init-per instance reload_daemon systemctl daemon-reload
 
 sudo systemctl set-environment HTTP_PROXY=${http_proxy}
@@ -79,6 +136,6 @@ API_SERVER_URL=${eks_endpoint}
 B64_CLUSTER_CA=${eks_cluster_ca}
 eks_CLUSTER_DNS_IP=172.20.0.10
 
-/etc/eks/bootstrap.sh ${name} --kubelet-extra-args '--node-labels=eks.amazonaws.com/nodegroup-image=${eks_node_ami_id},eks.amazonaws.com/capacityType=ON_DEMAND,eks.amazonaws.com/nodegroup=nodegroup-${name}-1 --max-pods=${max_pods}' --b64-cluster-ca $B64_CLUSTER_CA --apiserver-endpoint $API_SERVER_URL
+/etc/eks/bootstrap.sh ${name} --kubelet-extra-args '--node-labels=eks.amazonaws.com/nodegroup-image=${eks_node_ami_id},eks.amazonaws.com/capacityType=ON_DEMAND,eks.amazonaws.com/nodegroup=nodegroup-${name}-1 --max-pods=${max_pods}' --b64-cluster-ca $B64_CLUSTER_CA --apiserver-endpoint $API_SERVER_URL %{ if cluster_dns_ip != "" } --dns-cluster-ip ${cluster_dns_ip} %{ endif }
 
 --//--
\ No newline at end of file
@@ -239,11 +239,14 @@ resource "aws_launch_template" "eks_launch_template" {
     region = var.region
     enable_cache_registry = var.enable_cache_registry
     cache_registry = var.cache_registry
+    ecr_cache_mirrors = var.ecr_cache_mirrors
+    ecr_registry = "${local.aws_account_id}.dkr.ecr.${var.region}.amazonaws.com"
     eks_endpoint = data.aws_eks_cluster.eks_cluster_data.endpoint
     eks_cluster_ca = data.aws_eks_cluster.eks_cluster_data.certificate_authority[0].data
     name = var.name
     eks_node_ami_id = data.aws_ami.eks_node_ami.id
     max_pods = var.max_pods
+    cluster_dns_ip = var.cluster_dns_ip
   }))
   metadata_options {
     http_tokens = "required"
@@ -289,11 +292,14 @@ resource "aws_launch_template" "additional_node_group_launch_template" {
     region = var.region
     enable_cache_registry = var.enable_cache_registry
     cache_registry = var.cache_registry
+    ecr_cache_mirrors = var.ecr_cache_mirrors
+    ecr_registry = "${local.aws_account_id}.dkr.ecr.${var.region}.amazonaws.com"
     eks_endpoint = data.aws_eks_cluster.eks_cluster_data.endpoint
     eks_cluster_ca = data.aws_eks_cluster.eks_cluster_data.certificate_authority[0].data
     name = var.name
     eks_node_ami_id = data.aws_ami.eks_node_ami.id
     max_pods = var.max_pods
+    cluster_dns_ip = var.cluster_dns_ip
   }))
   metadata_options {
     http_tokens = "required"
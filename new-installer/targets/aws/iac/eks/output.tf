@@ -0,0 +1,67 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+output "eks_cluster_name" {
+  value = aws_eks_cluster.eks_cluster.name
+}
+
+output "eks_api_endpoint" {
+  value = aws_eks_cluster.eks_cluster.endpoint
+}
+
+output "eks_certificate_authority" {
+  description = "Base64 encoded certificate data required to communicate with the cluster."
+  value       = aws_eks_cluster.eks_cluster.certificate_authority[0].data
+}
+
+output "eks_oidc_issuer" {
+  value = aws_eks_cluster.eks_cluster.identity[0].oidc[0].issuer
+}
+
+output "eks_oidc_provider_arn" {
+  value = aws_iam_openid_connect_provider.cluster.arn
+}
+
+output "eks_nodegroup_role_name" {
+  value = local.eks_nodegroup_role_name
+}
+
+output "eks_security_group_id" {
+  value       = aws_security_group.eks_cluster.id
+  description = "The major security group ID for the EKS cluster"
+}
+
+output "kubeconfig" {
+  description = "Kubeconfig for the EKS cluster, using the AWS CLI to retrieve tokens."
+  sensitive   = true
+  value = yamlencode({
+    apiVersion      = "v1"
+    kind            = "Config"
+    current-context = var.name
+    clusters = [{
+      name = var.name
+      cluster = {
+        server                     = aws_eks_cluster.eks_cluster.endpoint
+        certificate-authority-data = aws_eks_cluster.eks_cluster.certificate_authority[0].data
+      }
+    }]
+    contexts = [{
+      name = var.name
+      context = {
+        cluster = var.name
+        user    = var.name
+      }
+    }]
+    users = [{
+      name = var.name
+      user = {
+        exec = {
+          apiVersion = "client.authentication.k8s.io/v1beta1"
+          command    = "aws"
+          args       = ["eks", "get-token", "--cluster-name", var.name, "--region", var.region]
+        }
+      }
+    }]
+  })
+}
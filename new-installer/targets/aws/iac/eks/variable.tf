@@ -137,6 +137,15 @@ variable "cache_registry" {
   default = ""
 }
 
+variable "ecr_cache_mirrors" {
+  type = map(object({
+    server            = string
+    repository_prefix = string
+  }))
+  default     = {}
+  description = "Upstream registries the nodes mirror to the ECR pull-through cache, keyed by registry host"
+}
+
 variable "user_script_pre_cloud_init" {
   type        = string
   default     = ""
@@ -165,3 +174,15 @@ variable "no_proxy" {
   default     = ""
   description = "No proxy to use for EKS nodes"
 }
+
+variable "cluster_dns_ip" {
+  type        = string
+  default     = ""
+  description = "IP address of the cluster DNS service, leave empty to use the EKS default"
+}
+
+variable "iam_roles" {
+  type        = list(string)
+  default     = []
+  description = "Additional IAM role names that will be mapped to system:masters in the aws-auth ConfigMap"
+}
@@ -24,7 +24,7 @@ import (
 func policyString(t *testing.T, clusterName string) string {
 	t.Helper()
 	parsedAccId := strings.ReplaceAll(policy, "${local.account_id}", aws.GetAccountId(t))
-	return strings.ReplaceAll(parsedAccId, "${aws_iam_user.vault.name}", "vault-"+clusterName)
+	return strings.ReplaceAll(parsedAccId, "${aws_iam_role.vault.name}", "vault-"+clusterName)
 }
 
 const policy string = `{
@@ -50,7 +50,7 @@ const policy string = `{
             ],
             "Effect": "Allow",
             "Principal": {
-                "AWS": "arn:aws:iam::${local.account_id}:user/${aws_iam_user.vault.name}"
+                "AWS": "arn:aws:iam::${local.account_id}:role/${aws_iam_role.vault.name}"
             },
             "Resource": "*"
         }
@@ -63,9 +63,10 @@ type KMSTestSuite struct {
 }
 
 type KMSVariables struct {
-	Region      string `json:"region" yaml:"region"`
-	CustomerTag string `json:"customer_tag" yaml:"customer_tag"`
-	ClusterName string `json:"cluster_name" yaml:"cluster_name"`
+	Region        string `json:"region" yaml:"region"`
+	CustomerTag   string `json:"customer_tag" yaml:"customer_tag"`
+	ClusterName   string `json:"cluster_name" yaml:"cluster_name"`
+	EKSOIDCIssuer string `json:"eks_oidc_issuer" yaml:"eks_oidc_issuer"`
 }
 
 func TestKMSTestSuite(t *testing.T) {
@@ -82,9 +83,10 @@ func (s *KMSTestSuite) TestApplyingModule() {
 	}()
 	clusterName := "kms-test-" + randomPostfix
 	variables := KMSVariables{
-		Region:      utils.DefaultTestRegion,
-		CustomerTag: utils.DefaultTestCustomerTag,
-		ClusterName: clusterName,
+		Region:        utils.DefaultTestRegion,
+		CustomerTag:   utils.DefaultTestCustomerTag,
+		ClusterName:   clusterName,
+		EKSOIDCIssuer: "https://oidc.eks.us-west-2.amazonaws.com/id/test-oidc-id",
 	}
 	jsonData, err := json.Marshal(variables)
 	if err != nil {
@@ -113,14 +115,14 @@ func (s *KMSTestSuite) TestApplyingModule() {
 	defer terraform.Destroy(s.T(), terraformOptions)
 	terraform.InitAndApply(s.T(), terraformOptions)
 
-	// Verify that the IAM User for Vault was created
+	// Verify that the IAM Role for Vault was created
 	iamClient, err := aws.NewIamClientE(s.T(), utils.DefaultTestRegion)
 	s.Require().NoError(err, "Failed to create IAM client")
-	iamUserOutput, err := iamClient.GetUser(s.T().Context(), &iam.GetUserInput{
-		UserName: aws_sdk.String("vault-" + clusterName),
+	iamRoleOutput, err := iamClient.GetRole(s.T().Context(), &iam.GetRoleInput{
+		RoleName: aws_sdk.String("vault-" + clusterName),
 	})
-	s.Require().NoError(err, "IAM User for Vault should be created")
-	s.NotNil(iamUserOutput, "IAM User for Vault should be created")
+	s.Require().NoError(err, "IAM Role for Vault should be created")
+	s.Equal(*iamRoleOutput.Role.Arn, terraform.Output(s.T(), terraformOptions, "vault_role_arn"))
 
 	// Verify that the KMS Key was created
 	kmsClient, err := aws.NewKmsClientE(s.T(), utils.DefaultTestRegion)
@@ -130,6 +132,12 @@ func (s *KMSTestSuite) TestApplyingModule() {
 	})
 	s.Require().NoError(err, "KMS Key should be created")
 	s.NotNil(kmsKeyOutput, "KMS Key should be created")
+	s.Equal(*kmsKeyOutput.KeyMetadata.Arn, terraform.Output(s.T(), terraformOptions, "kms_key_arn"))
+	rotationStatus, err := kmsClient.GetKeyRotationStatus(s.T().Context(), &kms.GetKeyRotationStatusInput{
+		KeyId: kmsKeyOutput.KeyMetadata.KeyId,
+	})
+	s.Require().NoError(err, "Failed to get key rotation status for KMS Key")
+	s.True(rotationStatus.KeyRotationEnabled, "Key rotation should be enabled for the KMS Key")
 	keyPolicies, err := kmsClient.ListKeyPolicies(s.T().Context(), &kms.ListKeyPoliciesInput{
 		KeyId: aws_sdk.String(*kmsKeyOutput.KeyMetadata.KeyId),
 	})
@@ -5,31 +5,81 @@
 data "aws_caller_identity" "current" {}
 
 locals {
-  account_id = data.aws_caller_identity.current.account_id
+  account_id  = data.aws_caller_identity.current.account_id
+  oidc_issuer = replace(var.eks_oidc_issuer, "https://", "")
+  kms_key_arn = var.kms_key_arn == "" ? aws_kms_key.vault[0].arn : var.kms_key_arn
 }
 
-# Set up IAM user for Vault to access KMS
-resource "aws_iam_user" "vault" {
-  name = "vault-${var.cluster_name}"
+# Set up IAM role for Vault to access KMS through IRSA
+data "aws_iam_policy_document" "vault_assume_role" {
+  statement {
+    actions = ["sts:AssumeRoleWithWebIdentity"]
+    effect  = "Allow"
+    condition {
+      test     = "StringEquals"
+      variable = "${local.oidc_issuer}:sub"
+      values   = ["system:serviceaccount:${var.vault_namespace}:${var.vault_service_account}"]
+    }
+    condition {
+      test     = "StringEquals"
+      variable = "${local.oidc_issuer}:aud"
+      values   = ["sts.amazonaws.com"]
+    }
+    principals {
+      identifiers = ["arn:aws:iam::${local.account_id}:oidc-provider/${local.oidc_issuer}"]
+      type        = "Federated"
+    }
+  }
 }
 
-resource "aws_iam_access_key" "vault" {
-  user = aws_iam_user.vault.name
+resource "aws_iam_role" "vault" {
+  name               = "vault-${var.cluster_name}"
+  description        = "Role for Vault in ${var.cluster_name} cluster to access the unseal key"
+  assume_role_policy = data.aws_iam_policy_document.vault_assume_role.json
 }
 
-# Set up KMS key with alias
+resource "aws_iam_role_policy" "vault" {
+  name = "vault-kms-unseal"
+  role = aws_iam_role.vault.id
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid = "VaultKMSUnseal"
+        Action = [
+          "kms:Encrypt",
+          "kms:Decrypt",
+          "kms:DescribeKey"
+        ]
+        Effect   = "Allow"
+        Resource = local.kms_key_arn
+      },
+    ]
+  })
+}
+
+# Set up KMS key with alias, an existing customer-managed key is used if given
 resource "aws_kms_key" "vault" {
+  count                   = var.kms_key_arn == "" ? 1 : 0
   description             = "Vault unseal key"
   deletion_window_in_days = 10
+  enable_key_rotation     = true
+}
+
+moved {
+  from = aws_kms_key.vault
+  to   = aws_kms_key.vault[0]
 }
 
 resource "aws_kms_alias" "vault" {
   name          = "alias/vault-kms-unseal-${var.cluster_name}"
-  target_key_id = aws_kms_key.vault.key_id
+  target_key_id = local.kms_key_arn
 }
 
+# The policy of an existing key is managed by its owner, it must allow IAM policies of this account
 resource "aws_kms_key_policy" "vault" {
-  key_id = aws_kms_key.vault.id
+  count  = var.kms_key_arn == "" ? 1 : 0
+  key_id = aws_kms_key.vault[0].id
   policy = jsonencode({
     Id = "vault"
     Statement = [
@@ -53,7 +103,7 @@ resource "aws_kms_key_policy" "vault" {
         ]
         "Effect": "Allow"
         "Principal": {
-          "AWS": "arn:aws:iam::${local.account_id}:user/${aws_iam_user.vault.name}"
+          "AWS": [aws_iam_role.vault.arn]
         }
         "Resource": "*"
       },
@@ -61,3 +111,16 @@ resource "aws_kms_key_policy" "vault" {
     Version = "2012-10-17"
   })
 }
+
+moved {
+  from = aws_kms_key_policy.vault
+  to   = aws_kms_key_policy.vault[0]
+}
+
+output "kms_key_arn" {
+  value = local.kms_key_arn
+}
+
+output "vault_role_arn" {
+  value = aws_iam_role.vault.arn
+}
@@ -13,4 +13,24 @@ variable "region" {
 variable "customer_tag" {
   type = string
   default = ""
-}
\ No newline at end of file
+}
+variable "kms_key_arn" {
+  description = "ARN of an existing customer-managed key for Vault auto-unseal, a new key is created if empty"
+  type        = string
+  default     = ""
+}
+
+variable "eks_oidc_issuer" {
+  description = "OIDC issuer URL of the EKS cluster, used for the Vault IRSA role"
+  type        = string
+}
+
+variable "vault_namespace" {
+  type    = string
+  default = "orch-platform"
+}
+
+variable "vault_service_account" {
+  type    = string
+  default = "vault"
+}
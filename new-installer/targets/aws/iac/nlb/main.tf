@@ -43,7 +43,15 @@ resource "aws_security_group_rule" "common" {
   protocol          = "TCP"
   cidr_blocks       = local.ip_allow_list
   security_group_id = aws_security_group.common.id
+}
 
+resource "aws_security_group_rule" "common_egress" {
+  type              = "egress"
+  from_port         = 0
+  to_port           = 0
+  protocol          = "-1"
+  cidr_blocks       = ["0.0.0.0/0"]
+  security_group_id = aws_security_group.common.id
 }
 
 # Create EIP(if not internal), NLB, Listener, TargetGroup
@@ -94,7 +102,6 @@ resource "aws_lb_listener" "main" {
   load_balancer_arn = aws_lb.main.arn
   port              = 443
   protocol          = "TCP"
-  certificate_arn   = var.certificate_arn
   default_action {
     type             = "forward"
     target_group_arn = aws_lb_target_group.main.arn
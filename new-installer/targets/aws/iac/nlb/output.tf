@@ -13,4 +13,10 @@ output "target_groups" {
 }
 output "lb_sg_id" {
   value = aws_security_group.common.id
-}
\ No newline at end of file
+}
+output "lb_arn" {
+  value = aws_lb.main.arn
+}
+output "target_group_arn" {
+  value = aws_lb_target_group.main.arn
+}
@@ -28,6 +28,19 @@ locals {
   ])
 }
 
+locals {
+  kms_key_arn = var.kms_key_arn == "" ? aws_kms_key.bucket_key[0].arn : var.kms_key_arn
+  default_lifecycle = {
+    expiration_days        = 0
+    infrequent_access_days = 0
+    glacier_days           = 0
+    object_lock_mode       = ""
+    object_lock_days       = 0
+  }
+  lifecycles = { for b in local.buckets : b => lookup(var.bucket_lifecycles, b, local.default_lifecycle) }
+  locked_buckets = { for b, l in local.lifecycles : b => l if l.object_lock_mode != "" }
+}
+
 resource "random_integer" "random_prefix" {
   min = 100000
   max = 999999
@@ -48,6 +61,12 @@ resource "aws_iam_policy" "s3_policy" {
         Effect   = "Allow"
         Resource = "arn:aws:s3:::${var.cluster_name}-*"
       },
+      {
+        Action   = ["kms:Decrypt", "kms:GenerateDataKey"],
+        Sid      = "BucketKey"
+        Effect   = "Allow"
+        Resource = local.kms_key_arn
+      },
     ]
   })
 }
@@ -83,12 +102,18 @@ resource "aws_iam_role_policy_attachment" "s3_role" {
   policy_arn = aws_iam_policy.s3_policy.arn
 }
 
-# Key to encrypt S3 buckets
+# Key to encrypt S3 buckets, only created if no customer-managed key is given
 resource "aws_kms_key" "bucket_key" {
+  count               = var.kms_key_arn == "" ? 1 : 0
   description         = "KMS key for S3 buckets in ${var.cluster_name} cluster"
   enable_key_rotation = true
 }
 
+moved {
+  from = aws_kms_key.bucket_key
+  to   = aws_kms_key.bucket_key[0]
+}
+
 # S3
 #trivy:ignore:AVD-AWS-0089 Logging disabled
 resource "aws_s3_bucket" "bucket" {
@@ -102,7 +127,7 @@ resource "aws_s3_bucket_server_side_encryption_configuration" "bucket" {
   bucket   = each.value.id
   rule {
     apply_server_side_encryption_by_default {
-      kms_master_key_id = aws_kms_key.bucket_key.arn
+      kms_master_key_id = local.kms_key_arn
       sse_algorithm     = "aws:kms"
     }
   }
@@ -133,9 +158,40 @@ resource "aws_s3_bucket_lifecycle_configuration" "bucket_config" {
   rule {
     id = "intelligent-tiering"
 
-    transition {
-      days          = 0
-      storage_class = "INTELLIGENT_TIERING"
+    # Intelligent-Tiering is used unless explicit tiers are configured
+    dynamic "transition" {
+      for_each = local.lifecycles[each.key].infrequent_access_days == 0 && local.lifecycles[each.key].glacier_days == 0 ? [0] : []
+      content {
+        days          = transition.value
+        storage_class = "INTELLIGENT_TIERING"
+      }
+    }
+    dynamic "transition" {
+      for_each = local.lifecycles[each.key].infrequent_access_days > 0 ? [local.lifecycles[each.key].infrequent_access_days] : []
+      content {
+        days          = transition.value
+        storage_class = "STANDARD_IA"
+      }
+    }
+    dynamic "transition" {
+      for_each = local.lifecycles[each.key].glacier_days > 0 ? [local.lifecycles[each.key].glacier_days] : []
+      content {
+        days          = transition.value
+        storage_class = "GLACIER"
+      }
+    }
+    dynamic "expiration" {
+      for_each = local.lifecycles[each.key].expiration_days > 0 ? [local.lifecycles[each.key].expiration_days] : []
+      content {
+        days = expiration.value
+      }
+    }
+    # Buckets are versioned, expired objects are kept as noncurrent versions
+    dynamic "noncurrent_version_expiration" {
+      for_each = local.lifecycles[each.key].expiration_days > 0 ? [1] : []
+      content {
+        noncurrent_days = 1
+      }
     }
     status = "Enabled"
     filter {
@@ -144,6 +200,21 @@ resource "aws_s3_bucket_lifecycle_configuration" "bucket_config" {
   }
 }
 
+# Object lock can be enabled on existing buckets since versioning is enabled
+resource "aws_s3_bucket_object_lock_configuration" "bucket" {
+  for_each = local.locked_buckets
+  bucket   = aws_s3_bucket.bucket[each.key].id
+
+  rule {
+    default_retention {
+      mode = each.value.object_lock_mode
+      days = each.value.object_lock_days
+    }
+  }
+
+  depends_on = [aws_s3_bucket_versioning.bucket]
+}
+
 data "aws_iam_policy_document" "bucket_policy_doc" {
   for_each = local.buckets
   statement {
@@ -0,0 +1,165 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+locals {
+  replicated_buckets = var.replication_region == "" ? toset([]) : local.buckets
+}
+
+# KMS keys are regional, replicas are encrypted with a key in the replica region
+resource "aws_kms_key" "replica" {
+  provider            = aws.replica
+  count               = var.replication_region == "" ? 0 : 1
+  description         = "KMS key for replicated S3 buckets of ${var.cluster_name} cluster"
+  enable_key_rotation = true
+}
+
+#trivy:ignore:AVD-AWS-0089 Logging disabled
+resource "aws_s3_bucket" "replica" {
+  provider      = aws.replica
+  for_each      = local.replicated_buckets
+  bucket        = "${aws_s3_bucket.bucket[each.key].id}-replica"
+  force_destroy = true
+}
+
+resource "aws_s3_bucket_server_side_encryption_configuration" "replica" {
+  provider = aws.replica
+  for_each = aws_s3_bucket.replica
+  bucket   = each.value.id
+  rule {
+    apply_server_side_encryption_by_default {
+      kms_master_key_id = aws_kms_key.replica[0].arn
+      sse_algorithm     = "aws:kms"
+    }
+  }
+}
+
+resource "aws_s3_bucket_public_access_block" "replica" {
+  provider                = aws.replica
+  for_each                = aws_s3_bucket.replica
+  bucket                  = each.value.id
+  block_public_acls       = true
+  block_public_policy     = true
+  restrict_public_buckets = true
+  ignore_public_acls      = true
+}
+
+resource "aws_s3_bucket_versioning" "replica" {
+  provider = aws.replica
+  for_each = aws_s3_bucket.replica
+  bucket   = each.value.id
+  versioning_configuration {
+    status = "Enabled"
+  }
+}
+
+# Replicas expire with the source objects, there is no need to tier them
+resource "aws_s3_bucket_lifecycle_configuration" "replica" {
+  provider = aws.replica
+  for_each = { for b in local.replicated_buckets : b => local.lifecycles[b] if local.lifecycles[b].expiration_days > 0 }
+  bucket   = aws_s3_bucket.replica[each.key].id
+
+  rule {
+    id = "expiration"
+    expiration {
+      days = each.value.expiration_days
+    }
+    noncurrent_version_expiration {
+      noncurrent_days = 1
+    }
+    status = "Enabled"
+    filter {
+      prefix = ""
+    }
+  }
+}
+
+data "aws_iam_policy_document" "replication_assume_role" {
+  statement {
+    actions = ["sts:AssumeRole"]
+    effect  = "Allow"
+    principals {
+      identifiers = ["s3.amazonaws.com"]
+      type        = "Service"
+    }
+  }
+}
+
+resource "aws_iam_role" "replication" {
+  count              = var.replication_region == "" ? 0 : 1
+  description        = "Role to replicate S3 buckets of ${var.cluster_name} cluster to ${var.replication_region}"
+  name               = "${var.cluster_name}-s3-replication-role"
+  assume_role_policy = data.aws_iam_policy_document.replication_assume_role.json
+}
+
+resource "aws_iam_role_policy" "replication" {
+  count = var.replication_region == "" ? 0 : 1
+  name  = "${var.cluster_name}-s3-replication"
+  role  = aws_iam_role.replication[0].id
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Sid      = "SourceBuckets"
+        Effect   = "Allow"
+        Action   = ["s3:GetReplicationConfiguration", "s3:ListBucket"]
+        Resource = [for b in aws_s3_bucket.bucket : b.arn]
+      },
+      {
+        Sid      = "SourceObjects"
+        Effect   = "Allow"
+        Action   = ["s3:GetObjectVersionForReplication", "s3:GetObjectVersionAcl", "s3:GetObjectVersionTagging"]
+        Resource = [for b in aws_s3_bucket.bucket : "${b.arn}/*"]
+      },
+      {
+        Sid      = "ReplicaObjects"
+        Effect   = "Allow"
+        Action   = ["s3:ReplicateObject", "s3:ReplicateDelete", "s3:ReplicateTags"]
+        Resource = [for b in aws_s3_bucket.replica : "${b.arn}/*"]
+      },
+      {
+        Sid      = "SourceKey"
+        Effect   = "Allow"
+        Action   = ["kms:Decrypt"]
+        Resource = local.kms_key_arn
+      },
+      {
+        Sid      = "ReplicaKey"
+        Effect   = "Allow"
+        Action   = ["kms:Encrypt", "kms:GenerateDataKey"]
+        Resource = aws_kms_key.replica[0].arn
+      },
+    ]
+  })
+}
+
+resource "aws_s3_bucket_replication_configuration" "bucket" {
+  for_each = local.replicated_buckets
+  role     = aws_iam_role.replication[0].arn
+  bucket   = aws_s3_bucket.bucket[each.key].id
+
+  rule {
+    id     = "disaster-recovery"
+    status = "Enabled"
+    filter {
+      prefix = ""
+    }
+    delete_marker_replication {
+      status = "Enabled"
+    }
+    source_selection_criteria {
+      sse_kms_encrypted_objects {
+        status = "Enabled"
+      }
+    }
+    destination {
+      bucket = aws_s3_bucket.replica[each.key].arn
+      encryption_configuration {
+        replica_kms_key_id = aws_kms_key.replica[0].arn
+      }
+    }
+  }
+
+  # Versioning must be enabled on both sides before replication is configured
+  depends_on = [aws_s3_bucket_versioning.bucket, aws_s3_bucket_versioning.replica]
+}
@@ -22,3 +22,15 @@ provider "aws" {
     }
   }
 }
+
+# Region of the replica buckets, only used if replication is enabled
+provider "aws" {
+  alias  = "replica"
+  region = var.replication_region == "" ? var.region : var.replication_region
+  default_tags {
+    tags = {
+      environment = "${var.cluster_name}"
+      customer = var.customer_tag
+    }
+  }
+}
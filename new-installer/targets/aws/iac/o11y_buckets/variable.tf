@@ -24,3 +24,27 @@ variable "oidc_issuer" {
   type        = string
   description = "OIDC issuer URL for the EKS cluster"
 }
+
+variable "kms_key_arn" {
+  type        = string
+  default     = ""
+  description = "Customer-managed KMS key to encrypt the buckets, a new key is created if empty"
+}
+
+variable "bucket_lifecycles" {
+  type = map(object({
+    expiration_days        = optional(number, 0)
+    infrequent_access_days = optional(number, 0)
+    glacier_days           = optional(number, 0)
+    object_lock_mode       = optional(string, "")
+    object_lock_days       = optional(number, 0)
+  }))
+  default     = {}
+  description = "Lifecycle of the buckets keyed by bucket name, e.g. orch-loki-chunks. Zero days disables the transition or expiration"
+}
+
+variable "replication_region" {
+  type        = string
+  default     = ""
+  description = "Replicate the buckets to this region for disaster recovery, replication is disabled if empty"
+}
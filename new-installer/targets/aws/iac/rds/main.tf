@@ -40,19 +40,28 @@ resource "aws_rds_cluster" "main" {
   preferred_backup_window         = "02:00-03:00"
   db_subnet_group_name            = aws_db_subnet_group.main.name
   vpc_security_group_ids          = [aws_security_group.rds.id]
-  final_snapshot_identifier       = "${var.cluster_name}-final-snapshot"
+  # Keep final snapshots unique, the cluster is replaced when restored from a snapshot
+  final_snapshot_identifier       = var.snapshot_identifier == "" ? "${var.cluster_name}-final-snapshot" : "${var.snapshot_identifier}-final"
   enabled_cloudwatch_logs_exports = ["postgresql"]
   db_cluster_parameter_group_name = aws_rds_cluster_parameter_group.default.name
   apply_immediately               = var.dev_mode ? true : false
   # What timezone?
   preferred_maintenance_window = "Sat:04:00-Sat:05:00"
   storage_encrypted            = true
+  kms_key_id                   = var.kms_key_arn == "" ? null : var.kms_key_arn
+  snapshot_identifier          = var.snapshot_identifier == "" ? null : var.snapshot_identifier
 
   serverlessv2_scaling_configuration {
     # 1 ACU ~= 2GB memory
     min_capacity = var.min_acus
     max_capacity = var.max_acus
   }
+
+  # Changing the key replaces the cluster, existing deployments keep their key.
+  # The cluster joins a global cluster when the DR region is set.
+  lifecycle {
+    ignore_changes = [kms_key_id, global_cluster_identifier]
+  }
 }
 
 resource "aws_rds_cluster_parameter_group" "default" {
@@ -93,3 +93,15 @@ variable "ca_cert_identifier" {
   description = "The Certificate authority of the database"
   default     = "rds-ca-rsa2048-g1"
 }
+
+variable "snapshot_identifier" {
+  description = "Restore the cluster from this snapshot. Changing it replaces the cluster"
+  type        = string
+  default     = ""
+}
+
+variable "kms_key_arn" {
+  description = "Customer-managed KMS key used for encryption at rest, the AWS-managed key is used if empty"
+  type        = string
+  default     = ""
+}
@@ -7,6 +7,7 @@ locals {
   traefik_lb_name  = substr(sha256("${var.orch_name}-traefik"), 0, 32)
   argocd_lb_name   = substr(sha256("${var.orch_name}-argocd"), 0, 32)
   traefik2_lb_name = substr(sha256("${var.orch_name}-traefik2"), 0, 32)
+  ns_ttl           = var.reduce_ns_ttl ? 60 : 900
 }
 
 data "aws_route53_zone" "parent_public" {
@@ -36,7 +37,7 @@ resource "aws_route53_record" "orch_public" {
   zone_id    = data.aws_route53_zone.parent_public.zone_id
   name       = local.orch_zone
   type       = "NS"
-  ttl        = 900
+  ttl        = local.ns_ttl
   records    = aws_route53_zone.orch_public.name_servers
 }
 
@@ -44,7 +45,7 @@ resource "aws_route53_record" "orch_private" {
   zone_id    = data.aws_route53_zone.parent_private.zone_id
   name       = local.orch_zone
   type       = "NS"
-  ttl        = 900
+  ttl        = local.ns_ttl
   records    = aws_route53_zone.orch_private.name_servers
 }
 
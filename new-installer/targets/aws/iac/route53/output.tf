@@ -0,0 +1,31 @@
+# SPDX-FileCopyrightText: 2025 Intel Corporation
+#
+# SPDX-License-Identifier: Apache-2.0
+
+output "orch_domain" {
+  value = local.orch_zone
+}
+
+output "public_zone_id" {
+  value = aws_route53_zone.orch_public.zone_id
+}
+
+output "private_zone_id" {
+  value = aws_route53_zone.orch_private.zone_id
+}
+
+output "traefik_hostname" {
+  value = aws_route53_record.traetik_public.fqdn
+}
+
+output "argocd_hostname" {
+  value = aws_route53_record.argocd_public.fqdn
+}
+
+output "gitea_hostname" {
+  value = aws_route53_record.gitea_public.fqdn
+}
+
+output "traefik2_hostname" {
+  value = aws_route53_record.traefik2_public.fqdn
+}
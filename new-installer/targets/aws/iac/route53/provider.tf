@@ -12,6 +12,7 @@ terraform {
   }
 }
 provider "aws" {
+  region = var.vpc_region
   default_tags {
     tags = {
       environment = var.orch_name
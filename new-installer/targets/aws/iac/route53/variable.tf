@@ -19,6 +19,11 @@ variable "vpc_id" {
 variable "vpc_region" {
   description = "The VPC region for the private route53 zone"
 }
+variable "reduce_ns_ttl" {
+  description = "Reduce the TTL of the NS records delegating the Orchestrator zone from 900 to 60 seconds"
+  type        = bool
+  default     = false
+}
 variable "hostname" {
   type    = list(string)
   default = [
@@ -2,10 +2,22 @@
 #
 # SPDX-License-Identifier: Apache-2.0
 
+# A customer-managed key is created unless an existing one is given. The key is shared by
+# the EFS, RDS and observability bucket modules.
 resource "aws_kms_key" "main" {
+  count               = var.kms_key_arn == "" ? 1 : 0
   enable_key_rotation = true
 }
 
+moved {
+  from = aws_kms_key.main
+  to   = aws_kms_key.main[0]
+}
+
+locals {
+  kms_key_arn = var.kms_key_arn == "" ? aws_kms_key.main[0].arn : var.kms_key_arn
+}
+
 resource "aws_s3_bucket" "main" {
   bucket = var.bucket
 }
@@ -15,7 +27,7 @@ resource "aws_s3_bucket_server_side_encryption_configuration" "main" {
 
   rule {
     apply_server_side_encryption_by_default {
-      kms_master_key_id = aws_kms_key.main.arn
+      kms_master_key_id = local.kms_key_arn
       sse_algorithm     = "aws:kms"
     }
   }
@@ -34,4 +46,8 @@ resource "aws_s3_bucket_versioning" "versioning_main" {
   versioning_configuration {
     status = "Enabled"
   }
-}
\ No newline at end of file
+}
+
+output "kms_key_arn" {
+  value = local.kms_key_arn
+}
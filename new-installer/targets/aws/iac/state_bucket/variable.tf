@@ -10,4 +10,8 @@ variable "bucket" {
 }
 variable "orch_name" {
   description = "The name of the orchestration environment"
-}
\ No newline at end of file
+}
+variable "kms_key_arn" {
+  description = "ARN of an existing customer-managed KMS key, a new key is created if empty"
+  default     = ""
+}
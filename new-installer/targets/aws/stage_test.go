@@ -10,10 +10,16 @@ import (
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/suite"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace/noop"
 )
 
 type OrchInstallerStepMock struct {
@@ -162,3 +168,73 @@ func (s *OrchInstallerStageTest) TestRunFilteredSteps() {
 		return
 	}
 }
+
+// Should run the steps of the stage but the excluded ones
+func (s *OrchInstallerStageTest) TestRunExcludedSteps() {
+	ctx := context.Background()
+	orchConfig := config.OrchInstallerConfig{}
+	runtimeState := config.OrchInstallerRuntimeState{
+		Action: "install",
+	}
+	// The steps are selected by the labels of their stage too
+	runtimeState.TargetLabels = []string{"stage1", "!label3"}
+	steps := []steps.OrchInstallerStep{
+		createMockStep("step1", true, []string{"label1", "label2"}),
+		createMockStep("step2", false, []string{"label2", "label3"}),
+	}
+	stage := aws.NewAWSStage("stage1", steps, []string{"stage1"}, &DummyOrchConfigReaderWriter{})
+	s.Equal([]selector.Target{
+		{Name: "step1", Labels: []string{"label1", "label2"}},
+		{Name: "step2", Labels: []string{"label2", "label3"}},
+	}, stage.StepTargets())
+
+	s.Nil(stage.PreStage(ctx, &orchConfig, &runtimeState))
+	s.Nil(stage.RunStage(ctx, &orchConfig, &runtimeState))
+	s.Nil(stage.PostStage(ctx, &orchConfig, &runtimeState, nil))
+}
+
+// Should record a span for the step and each of its phases, with the error of the failed phase
+func (s *OrchInstallerStageTest) TestStepSpans() {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(noop.NewTracerProvider())
+
+	orchConfig := config.OrchInstallerConfig{}
+	runtimeState := config.OrchInstallerRuntimeState{
+		Action: "install",
+	}
+	newRS := &config.OrchInstallerRuntimeState{}
+	step := &OrchInstallerStepMock{}
+	step.On("Name").Return("step1")
+	step.On("Labels").Return([]string{})
+	step.On("ConfigStep", mock.Anything, mock.Anything).Return(newRS, nil)
+	step.On("PreStep", mock.Anything, mock.Anything).Return(newRS, nil)
+	step.On("RunStep", mock.Anything, mock.Anything).Return(newRS, &internal.OrchInstallerError{
+		ErrorCode: internal.OrchInstallerErrorCodeTerraform,
+		ErrorMsg:  "apply failed",
+	})
+	step.On("PostStep", mock.Anything, mock.Anything).Return(newRS, nil)
+	stage := aws.NewAWSStage("stage1", []steps.OrchInstallerStep{step}, []string{"stage1"}, &DummyOrchConfigReaderWriter{})
+
+	err := stage.RunStage(context.Background(), &orchConfig, &runtimeState)
+	s.Nil(err)
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	s.Len(spans, 5)
+	stepSpan := spans["step step1"]
+	s.Require().NotNil(stepSpan)
+	for _, phase := range []string{"ConfigStep", "PreStep", "RunStep", "PostStep"} {
+		span := spans[phase+" step1"]
+		s.Require().NotNil(span, phase)
+		s.Equal(stepSpan.SpanContext().SpanID(), span.Parent().SpanID(), phase)
+	}
+	s.Equal(codes.Error, spans["RunStep step1"].Status().Code)
+	s.Equal("apply failed", spans["RunStep step1"].Status().Description)
+	// The post step handles the error
+	s.Equal(codes.Unset, spans["PostStep step1"].Status().Code)
+	s.Equal(codes.Unset, stepSpan.Status().Code)
+}
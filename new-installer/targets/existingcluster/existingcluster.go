@@ -0,0 +1,79 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package existingcluster installs the orchestrator on a conformant Kubernetes cluster the
+// installer does not create, such as a managed one. Only ArgoCD, Gitea and root-app are
+// installed, after checking that the cluster can run the orchestrator.
+package existingcluster
+
+import (
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	commonSteps "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
+	onpremSteps "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
+)
+
+const ProviderName = commonSteps.ExistingClusterProvider
+
+func init() {
+	targets.Register(targets.Provider{
+		Name: ProviderName,
+		Stages: []config.StageLayout{
+			{
+				Name:   "PreInfra",
+				Labels: []string{"pre-infra"},
+				Steps:  []string{"ClusterCheckStep", "ArtifactDownloader"},
+			},
+			{
+				Name:   "Infra",
+				Labels: []string{"infra"},
+				Steps:  []string{"ArgoStep"},
+			},
+			{
+				Name:   "Orchestrator",
+				Labels: []string{"orchestrator"},
+				Steps:  []string{"GiteaStep", "RootAppStep", "WaitForReadyStep"},
+			},
+		},
+		NewStage: NewExistingClusterStage,
+	})
+
+	for _, step := range []struct {
+		name      string
+		dependsOn []string
+		create    func(env targets.Environment) steps.OrchInstallerStep
+	}{
+		{"ClusterCheckStep", nil, func(env targets.Environment) steps.OrchInstallerStep {
+			return onpremSteps.CreateClusterCheckStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+		}},
+		{"ArtifactDownloader", nil, func(env targets.Environment) steps.OrchInstallerStep {
+			step := onpremSteps.CreateArtifactDownloader(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+			step.OrchestratorOnly = true
+			return step
+		}},
+		{"ArgoStep", []string{"ClusterCheckStep", "ArtifactDownloader"}, func(env targets.Environment) steps.OrchInstallerStep {
+			return commonSteps.CreateArgoStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+		}},
+		{"GiteaStep", []string{"ClusterCheckStep", "ArtifactDownloader"}, func(env targets.Environment) steps.OrchInstallerStep {
+			return onpremSteps.CreateGiteaStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+		}},
+		{"RootAppStep", []string{"ArtifactDownloader", "ArgoStep", "GiteaStep"}, func(env targets.Environment) steps.OrchInstallerStep {
+			return onpremSteps.CreateRootAppStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+		}},
+		{"WaitForReadyStep", []string{"RootAppStep"}, func(env targets.Environment) steps.OrchInstallerStep {
+			return commonSteps.CreateWaitForReadyStep(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter)
+		}},
+	} {
+		targets.RegisterStep(targets.Step{
+			Name:      step.name,
+			Provider:  ProviderName,
+			Labels:    []string{ProviderName},
+			DependsOn: step.dependsOn,
+			Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+				return step.create(env), nil
+			},
+		})
+	}
+}
@@ -0,0 +1,65 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package existingcluster
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/onprem"
+)
+
+// ExistingClusterStage runs its steps like an on-prem stage, with the kubeconfig of the
+// cluster read into the runtime state first.
+type ExistingClusterStage struct {
+	internal.OrchInstallerStage
+}
+
+func NewExistingClusterStage(name string, steps []steps.OrchInstallerStep, labels []string, orchConfigReaderWriter config.OrchConfigReaderWriter) internal.OrchInstallerStage {
+	return &ExistingClusterStage{
+		OrchInstallerStage: onprem.NewOnPremStage(name, steps, labels, orchConfigReaderWriter),
+	}
+}
+
+// StepTargets returns the names and labels of the steps of the stage.
+func (a *ExistingClusterStage) StepTargets() []selector.Target {
+	return a.OrchInstallerStage.(internal.StageWithSteps).StepTargets()
+}
+
+// DryRunSteps returns the names of the steps of the stage that honor --dry-run.
+func (a *ExistingClusterStage) DryRunSteps() []string {
+	return a.OrchInstallerStage.(internal.StageWithDryRun).DryRunSteps()
+}
+
+// PreStage reads the kubeconfig on every run, whichever stages are targeted, so that the steps
+// use the current credentials of the cluster.
+func (a *ExistingClusterStage) PreStage(ctx context.Context, config *config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) *internal.OrchInstallerError {
+	if config == nil {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "OrchInstallerConfig is nil",
+		}
+	}
+	if config.ExistingCluster.KubeConfigPath == "" {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  "existingCluster.kubeConfigPath is not set, cannot access the cluster",
+		}
+	}
+	kubeConfig, err := os.ReadFile(os.ExpandEnv(config.ExistingCluster.KubeConfigPath))
+	if err != nil {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  fmt.Sprintf("failed to read the kubeconfig of the cluster: %s", err),
+		}
+	}
+	runtimeState.ExistingCluster.KubeConfig = string(kubeConfig)
+	return a.OrchInstallerStage.PreStage(ctx, config, runtimeState)
+}
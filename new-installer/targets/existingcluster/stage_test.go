@@ -0,0 +1,78 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package existingcluster_test
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets/existingcluster"
+	"github.com/stretchr/testify/suite"
+)
+
+type ExistingClusterStageTest struct {
+	suite.Suite
+	config       config.OrchInstallerConfig
+	runtimeState config.OrchInstallerRuntimeState
+	stage        internal.OrchInstallerStage
+}
+
+func TestExistingClusterStage(t *testing.T) {
+	suite.Run(t, new(ExistingClusterStageTest))
+}
+
+func (s *ExistingClusterStageTest) SetupTest() {
+	kubeConfig := filepath.Join(s.T().TempDir(), "kubeconfig")
+	s.Require().NoError(os.WriteFile(kubeConfig, []byte("test-kubeconfig"), 0o600))
+	s.config = config.OrchInstallerConfig{Provider: existingcluster.ProviderName}
+	s.config.ExistingCluster.KubeConfigPath = kubeConfig
+	s.runtimeState = config.OrchInstallerRuntimeState{Action: "install"}
+	s.stage = existingcluster.NewExistingClusterStage("Infra", []steps.OrchInstallerStep{}, []string{"infra"}, nil)
+}
+
+func (s *ExistingClusterStageTest) TestReadsKubeConfig() {
+	err := s.stage.PreStage(context.Background(), &s.config, &s.runtimeState)
+	s.Require().Nil(err)
+	s.Equal("test-kubeconfig", s.runtimeState.ExistingCluster.KubeConfig)
+	s.Equal("Infra", s.stage.Name())
+	s.Equal([]string{"infra"}, s.stage.Labels())
+}
+
+func (s *ExistingClusterStageTest) TestMissingKubeConfig() {
+	s.config.ExistingCluster.KubeConfigPath = ""
+	err := s.stage.PreStage(context.Background(), &s.config, &s.runtimeState)
+	s.Require().NotNil(err)
+	s.Equal(internal.OrchInstallerErrorCodeInvalidArgument, err.ErrorCode)
+
+	s.config.ExistingCluster.KubeConfigPath = filepath.Join(s.T().TempDir(), "missing")
+	err = s.stage.PreStage(context.Background(), &s.config, &s.runtimeState)
+	s.Require().NotNil(err)
+	s.Contains(err.ErrorMsg, "failed to read the kubeconfig")
+}
+
+func (s *ExistingClusterStageTest) TestSteps() {
+	// Only ArgoCD, Gitea and root-app are installed, the cluster is not created
+	layout, err := targets.Layout(s.config)
+	s.Require().NoError(err)
+	names := []string{}
+	for _, stage := range layout {
+		names = append(names, stage.Steps...)
+	}
+	s.Equal([]string{"ClusterCheckStep", "ArtifactDownloader", "ArgoStep", "GiteaStep", "RootAppStep", "WaitForReadyStep"}, names)
+
+	stages, err := targets.CreateStages(s.config, targets.Environment{})
+	s.Require().NoError(err)
+	s.Len(stages, 3)
+	s.Equal([]string{"orchestrator"}, stages[2].Labels())
+	s.Equal([]selector.Target{{Name: "ArgoStep", Labels: []string{"argocd", "existing-cluster"}}},
+		stages[1].(internal.StageWithSteps).StepTargets())
+}
@@ -5,48 +5,67 @@
 package onprem
 
 import (
-	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 	commonSteps "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/common"
 	onpremSteps "github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps/onprem"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
 )
 
-func CreateOnPremStages(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) ([]internal.OrchInstallerStage, error) {
-	var preInfraStage, infraStage, orchStage internal.OrchInstallerStage
+const ProviderName = "onprem"
 
-	preInfraStage = NewOnPremStage(
-		"PreInfra",
-		[]steps.OrchInstallerStep{
-			onpremSteps.CreateGenericStep(rootPath, keepGeneratedFiles, orchConfigReaderWriter),
+func init() {
+	targets.Register(targets.Provider{
+		Name: ProviderName,
+		Stages: []config.StageLayout{
+			{
+				Name:   "PreInfra",
+				Labels: []string{"pre-infra"},
+				Steps:  []string{"PreflightStep", "OSConfigStep"},
+			},
+			{
+				Name:   "Infra",
+				Labels: []string{"infra"},
+				Steps:  []string{"ArtifactDownloader", "Rke2InfraStep", "ArgoStep"},
+			},
+			{
+				Name:   "Orchestrator",
+				Labels: []string{"orchestrator"},
+				Steps:  []string{"GiteaStep", "RootAppStep", "WaitForReadyStep"},
+			},
 		},
-		[]string{"pre-infra"},
-		orchConfigReaderWriter,
-	)
+		NewStage: NewOnPremStage,
+	})
 
-	infraStage = NewOnPremStage(
-		"Infra",
-		[]steps.OrchInstallerStep{
-			onpremSteps.CreateArtifactDownloader(rootPath, keepGeneratedFiles, orchConfigReaderWriter),
-			onpremSteps.CreateRke2Step(rootPath, keepGeneratedFiles, orchConfigReaderWriter),
-			commonSteps.CreateArgoStep(rootPath, keepGeneratedFiles, orchConfigReaderWriter),
-		},
-		[]string{"infra"},
-		orchConfigReaderWriter,
-	)
-
-	orchStage = NewOnPremStage(
-		"Orchestrator",
-		[]steps.OrchInstallerStep{
-			onpremSteps.CreateGenericStep(rootPath, keepGeneratedFiles, orchConfigReaderWriter),
-		},
-		[]string{"orchestator"},
-		orchConfigReaderWriter,
-	)
+	for _, step := range []struct {
+		name      string
+		dependsOn []string
+		create    func(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) steps.OrchInstallerStep
+	}{
+		{"PreflightStep", nil, wrap(onpremSteps.CreatePreflightStep)},
+		{"OSConfigStep", []string{"PreflightStep"}, wrap(onpremSteps.CreateOSConfigStep)},
+		{"ArtifactDownloader", nil, wrap(onpremSteps.CreateArtifactDownloader)},
+		{"Rke2InfraStep", []string{"ArtifactDownloader"}, wrap(onpremSteps.CreateRke2Step)},
+		{"ArgoStep", []string{"Rke2InfraStep"}, wrap(commonSteps.CreateArgoStep)},
+		{"GiteaStep", []string{"Rke2InfraStep"}, wrap(onpremSteps.CreateGiteaStep)},
+		{"RootAppStep", []string{"ArtifactDownloader", "ArgoStep", "GiteaStep"}, wrap(onpremSteps.CreateRootAppStep)},
+		{"WaitForReadyStep", []string{"RootAppStep"}, wrap(commonSteps.CreateWaitForReadyStep)},
+	} {
+		targets.RegisterStep(targets.Step{
+			Name:      step.name,
+			Provider:  ProviderName,
+			Labels:    []string{ProviderName},
+			DependsOn: step.dependsOn,
+			Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+				return step.create(env.RootPath, env.KeepGeneratedFiles, env.OrchConfigReaderWriter), nil
+			},
+		})
+	}
+}
 
-	return []internal.OrchInstallerStage{
-		preInfraStage,
-		infraStage,
-		orchStage,
-	}, nil
+// wrap returns a step factory returning the interface rather than the step type.
+func wrap[T steps.OrchInstallerStep](create func(string, bool, config.OrchConfigReaderWriter) T) func(string, bool, config.OrchConfigReaderWriter) steps.OrchInstallerStep {
+	return func(rootPath string, keepGeneratedFiles bool, orchConfigReaderWriter config.OrchConfigReaderWriter) steps.OrchInstallerStep {
+		return create(rootPath, keepGeneratedFiles, orchConfigReaderWriter)
+	}
 }
@@ -9,6 +9,7 @@ import (
 
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/selector"
 	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
 )
 
@@ -36,12 +37,21 @@ func (a *OnPremStage) Labels() []string {
 	return a.labels
 }
 
+// StepTargets returns the names and labels of the steps of the stage.
+func (a *OnPremStage) StepTargets() []selector.Target {
+	return steps.StepTargets(a.steps)
+}
+
+// DryRunSteps returns the names of the steps of the stage that honor --dry-run.
+func (a *OnPremStage) DryRunSteps() []string {
+	return steps.DryRunSteps(a.steps)
+}
+
 func (a *OnPremStage) PreStage(ctx context.Context, config *config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) *internal.OrchInstallerError {
 	return nil
 }
 
 func (a *OnPremStage) RunStage(ctx context.Context, config *config.OrchInstallerConfig, runtimeState *config.OrchInstallerRuntimeState) *internal.OrchInstallerError {
-	logger := internal.Logger()
 	if config == nil {
 		return &internal.OrchInstallerError{
 			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
@@ -51,38 +61,20 @@ func (a *OnPremStage) RunStage(ctx context.Context, config *config.OrchInstaller
 	if runtimeState.Action == "uninstall" {
 		a.steps = steps.ReverseSteps(a.steps)
 	}
-	a.steps = steps.FilterSteps(a.steps, runtimeState.TargetLabels)
+	sel, err := selector.Parse(runtimeState.TargetLabels)
+	if err != nil {
+		return &internal.OrchInstallerError{
+			ErrorCode: internal.OrchInstallerErrorCodeInvalidArgument,
+			ErrorMsg:  err.Error(),
+		}
+	}
+	a.steps = steps.FilterSteps(a.steps, internal.StageTarget(a), sel)
 	if len(a.steps) == 0 {
 		return nil
 	}
 
 	for _, step := range a.steps {
-		logger.Debugf("ConfigStep %s", step.Name())
-		stepErr := func() *internal.OrchInstallerError {
-			if newRuntimeState, err := step.ConfigStep(ctx, *config, *runtimeState); err != nil {
-				return err
-			} else if err = internal.UpdateRuntimeState(runtimeState, newRuntimeState); err != nil {
-				return err
-			}
-			logger.Debugf("PreStep %s", step.Name())
-			if newRuntimeState, err := step.PreStep(ctx, *config, *runtimeState); err != nil {
-				return err
-			} else if err = internal.UpdateRuntimeState(runtimeState, newRuntimeState); err != nil {
-				return err
-			}
-			logger.Debugf("RunStep %s", step.Name())
-			if newRuntimeState, err := step.RunStep(ctx, *config, *runtimeState); err != nil {
-				return err
-			} else if err = internal.UpdateRuntimeState(runtimeState, newRuntimeState); err != nil {
-				return err
-			}
-			return nil
-		}()
-
-		logger.Debugf("PostStep %s", step.Name())
-		if newRuntimeState, err := step.PostStep(ctx, *config, *runtimeState, stepErr); err != nil {
-			return err
-		} else if err = internal.UpdateRuntimeState(runtimeState, newRuntimeState); err != nil {
+		if err := steps.RunStep(ctx, a.name, step, config, runtimeState); err != nil {
 			return err
 		}
 	}
@@ -0,0 +1,214 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+// Package targets is the registry of the providers the installer deploys to and of their steps.
+// A provider registers itself and its steps from the init function of its package, so adding a
+// provider only takes importing its package in the installer:
+//
+//	import _ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/onprem"
+package targets
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+)
+
+// Environment is what the stages and steps of a provider are created with.
+type Environment struct {
+	RootPath               string
+	KeepGeneratedFiles     bool
+	OrchConfigReaderWriter config.OrchConfigReaderWriter
+}
+
+// StageFactory creates a stage of a provider from its steps.
+type StageFactory func(name string, steps []steps.OrchInstallerStep, labels []string, orchConfigReaderWriter config.OrchConfigReaderWriter) internal.OrchInstallerStage
+
+// StepFactory creates a step.
+type StepFactory func(env Environment) (steps.OrchInstallerStep, error)
+
+// Provider is an environment the installer deploys to, selected by the provider of the config.
+type Provider struct {
+	Name string
+	// Default stages of the provider, replaced by the stages of the config when it sets them
+	Stages   []config.StageLayout
+	NewStage StageFactory
+}
+
+// Step is a step a provider can run.
+type Step struct {
+	Name     string
+	Provider string
+	// Added to the labels of the step, e.g. to select the steps shared by several providers
+	// by provider
+	Labels []string
+	// Steps that must run before this one on install, in the same stage or an earlier one
+	DependsOn []string
+	Create    StepFactory
+}
+
+var registry = struct {
+	sync.Mutex
+	providers map[string]Provider
+	// Steps of each provider, in the order they were registered
+	steps map[string][]Step
+}{
+	providers: map[string]Provider{},
+	steps:     map[string][]Step{},
+}
+
+// Register registers a provider. It panics if the provider is registered twice, like the steps.
+func Register(provider Provider) {
+	registry.Lock()
+	defer registry.Unlock()
+	if provider.Name == "" || provider.NewStage == nil {
+		panic("targets: a provider needs a name and a stage factory")
+	}
+	if _, ok := registry.providers[provider.Name]; ok {
+		panic("targets: provider " + provider.Name + " registered twice")
+	}
+	registry.providers[provider.Name] = provider
+}
+
+// RegisterStep registers a step of a provider. The provider can be registered before or after
+// its steps.
+func RegisterStep(step Step) {
+	registry.Lock()
+	defer registry.Unlock()
+	if step.Name == "" || step.Provider == "" || step.Create == nil {
+		panic("targets: a step needs a name, a provider and a factory")
+	}
+	for _, registered := range registry.steps[step.Provider] {
+		if registered.Name == step.Name {
+			panic("targets: step " + step.Name + " of provider " + step.Provider + " registered twice")
+		}
+	}
+	registry.steps[step.Provider] = append(registry.steps[step.Provider], step)
+}
+
+// Providers returns the names of the registered providers, sorted.
+func Providers() []string {
+	registry.Lock()
+	defer registry.Unlock()
+	names := make([]string, 0, len(registry.providers))
+	for name := range registry.providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// LookupProvider returns a registered provider.
+func LookupProvider(name string) (Provider, bool) {
+	registry.Lock()
+	defer registry.Unlock()
+	provider, ok := registry.providers[name]
+	return provider, ok
+}
+
+// Steps returns the registered steps of a provider, in the order they were registered.
+func Steps(provider string) []Step {
+	registry.Lock()
+	defer registry.Unlock()
+	return slices.Clone(registry.steps[provider])
+}
+
+// Layout returns the stages of the provider of the config: the stages of the config if it sets
+// them, the default stages of the provider otherwise. Every step of the layout must be a step of
+// the provider, and run after the steps it depends on.
+func Layout(cfg config.OrchInstallerConfig) ([]config.StageLayout, error) {
+	provider, ok := LookupProvider(cfg.Provider)
+	if !ok {
+		return nil, fmt.Errorf("target environment %s not supported, expected one of %s", cfg.Provider, strings.Join(Providers(), ", "))
+	}
+	layout := provider.Stages
+	if len(cfg.Stages) > 0 {
+		layout = cfg.Stages
+	}
+
+	registered := map[string]Step{}
+	for _, step := range Steps(provider.Name) {
+		registered[step.Name] = step
+	}
+	stageNames := map[string]bool{}
+	// Steps of the layout, in the order they run
+	done := map[string]bool{}
+	for _, stage := range layout {
+		if stage.Name == "" {
+			return nil, fmt.Errorf("a stage of provider %s has no name", provider.Name)
+		}
+		if stageNames[stage.Name] {
+			return nil, fmt.Errorf("stage %s of provider %s is listed twice", stage.Name, provider.Name)
+		}
+		stageNames[stage.Name] = true
+		for _, name := range stage.Steps {
+			step, ok := registered[name]
+			if !ok {
+				return nil, fmt.Errorf("step %s of stage %s is not a step of provider %s", name, stage.Name, provider.Name)
+			}
+			if done[name] {
+				return nil, fmt.Errorf("step %s is listed twice", name)
+			}
+			for _, dependency := range step.DependsOn {
+				if !done[dependency] {
+					return nil, fmt.Errorf("step %s depends on %s, which must run before it", name, dependency)
+				}
+			}
+			done[name] = true
+		}
+	}
+	return layout, nil
+}
+
+// CreateStages creates the stages of the provider of the config, laid out as returned by Layout.
+func CreateStages(cfg config.OrchInstallerConfig, env Environment) ([]internal.OrchInstallerStage, error) {
+	layout, err := Layout(cfg)
+	if err != nil {
+		return nil, err
+	}
+	provider, _ := LookupProvider(cfg.Provider)
+	registered := map[string]Step{}
+	for _, step := range Steps(provider.Name) {
+		registered[step.Name] = step
+	}
+
+	stages := []internal.OrchInstallerStage{}
+	for _, stage := range layout {
+		stageSteps := []steps.OrchInstallerStep{}
+		for _, name := range stage.Steps {
+			step, err := registered[name].Create(env)
+			if err != nil {
+				return nil, fmt.Errorf("failed to create step %s: %w", name, err)
+			}
+			if labels := registered[name].Labels; len(labels) > 0 {
+				step = &labeledStep{OrchInstallerStep: step, labels: labels}
+			}
+			stageSteps = append(stageSteps, step)
+		}
+		stages = append(stages, provider.NewStage(stage.Name, stageSteps, stage.Labels, env.OrchConfigReaderWriter))
+	}
+	return stages, nil
+}
+
+// labeledStep adds the labels of its registration to a step.
+type labeledStep struct {
+	steps.OrchInstallerStep
+	labels []string
+}
+
+func (s *labeledStep) Labels() []string {
+	labels := slices.Clone(s.OrchInstallerStep.Labels())
+	for _, label := range s.labels {
+		if !slices.Contains(labels, label) {
+			labels = append(labels, label)
+		}
+	}
+	return labels
+}
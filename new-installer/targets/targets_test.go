@@ -0,0 +1,202 @@
+// SPDX-FileCopyrightText: 2025 Intel Corporation
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package targets_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/config"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/internal/steps"
+	"github.com/open-edge-platform/edge-manageability-framework/installer/targets"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/aws"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/existingcluster"
+	_ "github.com/open-edge-platform/edge-manageability-framework/installer/targets/onprem"
+	"github.com/stretchr/testify/suite"
+)
+
+type fakeStep struct {
+	name   string
+	labels []string
+}
+
+func (s *fakeStep) Name() string {
+	return s.name
+}
+
+func (s *fakeStep) Labels() []string {
+	return s.labels
+}
+
+func (s *fakeStep) ConfigStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *fakeStep) PreStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *fakeStep) RunStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, nil
+}
+
+func (s *fakeStep) PostStep(ctx context.Context, config config.OrchInstallerConfig, runtimeState config.OrchInstallerRuntimeState, prevStepError *internal.OrchInstallerError) (config.OrchInstallerRuntimeState, *internal.OrchInstallerError) {
+	return runtimeState, prevStepError
+}
+
+// fakeStage records what the stage was created with.
+type fakeStage struct {
+	internal.OrchInstallerStage
+	name   string
+	labels []string
+	steps  []steps.OrchInstallerStep
+}
+
+func (s *fakeStage) Name() string {
+	return s.name
+}
+
+func (s *fakeStage) Labels() []string {
+	return s.labels
+}
+
+const testProvider = "test"
+
+func init() {
+	targets.Register(targets.Provider{
+		Name: testProvider,
+		Stages: []config.StageLayout{
+			{Name: "Infra", Labels: []string{"infra"}, Steps: []string{"ClusterStep"}},
+			{Name: "Orchestrator", Labels: []string{"orchestrator"}, Steps: []string{"AppStep"}},
+		},
+		NewStage: func(name string, steps []steps.OrchInstallerStep, labels []string, orchConfigReaderWriter config.OrchConfigReaderWriter) internal.OrchInstallerStage {
+			return &fakeStage{name: name, labels: labels, steps: steps}
+		},
+	})
+	targets.RegisterStep(targets.Step{
+		Name:     "ClusterStep",
+		Provider: testProvider,
+		Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+			return &fakeStep{name: "ClusterStep", labels: []string{"cluster"}}, nil
+		},
+	})
+	targets.RegisterStep(targets.Step{
+		Name:      "AppStep",
+		Provider:  testProvider,
+		Labels:    []string{"test", "app"},
+		DependsOn: []string{"ClusterStep"},
+		Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+			return &fakeStep{name: "AppStep", labels: []string{"app"}}, nil
+		},
+	})
+}
+
+type TargetsTest struct {
+	suite.Suite
+}
+
+func TestTargets(t *testing.T) {
+	suite.Run(t, new(TargetsTest))
+}
+
+func (s *TargetsTest) TestProviders() {
+	s.Equal([]string{"aws", "existing-cluster", "onprem", testProvider}, targets.Providers())
+	// The default layouts of the providers are valid
+	for _, provider := range targets.Providers() {
+		_, err := targets.Layout(config.OrchInstallerConfig{Provider: provider})
+		s.NoError(err, provider)
+	}
+}
+
+func (s *TargetsTest) TestCreateStages() {
+	stages, err := targets.CreateStages(config.OrchInstallerConfig{Provider: testProvider}, targets.Environment{})
+	s.Require().NoError(err)
+	s.Require().Len(stages, 2)
+	infra, orch := stages[0].(*fakeStage), stages[1].(*fakeStage)
+	s.Equal("Infra", infra.name)
+	s.Equal([]string{"infra"}, infra.labels)
+	s.Require().Len(infra.steps, 1)
+	s.Equal("ClusterStep", infra.steps[0].Name())
+	s.Equal([]string{"cluster"}, infra.steps[0].Labels())
+	s.Equal("Orchestrator", orch.name)
+	s.Require().Len(orch.steps, 1)
+	// The labels of the registration are added to the ones of the step
+	s.Equal([]string{"app", "test"}, orch.steps[0].Labels())
+}
+
+func (s *TargetsTest) TestConfigLayout() {
+	cfg := config.OrchInstallerConfig{Provider: testProvider}
+	cfg.Stages = []config.StageLayout{
+		{Name: "All", Labels: []string{"all"}, Steps: []string{"ClusterStep", "AppStep"}},
+	}
+	stages, err := targets.CreateStages(cfg, targets.Environment{})
+	s.Require().NoError(err)
+	s.Require().Len(stages, 1)
+	s.Equal("All", stages[0].Name())
+	s.Len(stages[0].(*fakeStage).steps, 2)
+}
+
+func (s *TargetsTest) TestInvalidLayout() {
+	for _, test := range []struct {
+		name   string
+		stages []config.StageLayout
+		err    string
+	}{
+		{
+			name:   "unknown step",
+			stages: []config.StageLayout{{Name: "Infra", Steps: []string{"ClusterStep", "RDSStep"}}},
+			err:    "step RDSStep of stage Infra is not a step of provider test",
+		},
+		{
+			name: "dependency after the step",
+			stages: []config.StageLayout{
+				{Name: "Orchestrator", Steps: []string{"AppStep"}},
+				{Name: "Infra", Steps: []string{"ClusterStep"}},
+			},
+			err: "step AppStep depends on ClusterStep, which must run before it",
+		},
+		{
+			name:   "missing dependency",
+			stages: []config.StageLayout{{Name: "Orchestrator", Steps: []string{"AppStep"}}},
+			err:    "step AppStep depends on ClusterStep, which must run before it",
+		},
+		{
+			name:   "step listed twice",
+			stages: []config.StageLayout{{Name: "Infra", Steps: []string{"ClusterStep", "ClusterStep"}}},
+			err:    "step ClusterStep is listed twice",
+		},
+		{
+			name: "stage listed twice",
+			stages: []config.StageLayout{
+				{Name: "Infra", Steps: []string{"ClusterStep"}},
+				{Name: "Infra", Steps: []string{"AppStep"}},
+			},
+			err: "stage Infra of provider test is listed twice",
+		},
+	} {
+		cfg := config.OrchInstallerConfig{Provider: testProvider}
+		cfg.Stages = test.stages
+		_, err := targets.CreateStages(cfg, targets.Environment{})
+		s.EqualError(err, test.err, test.name)
+	}
+}
+
+func (s *TargetsTest) TestUnknownProvider() {
+	_, err := targets.CreateStages(config.OrchInstallerConfig{Provider: "gcp"}, targets.Environment{})
+	s.EqualError(err, "target environment gcp not supported, expected one of aws, existing-cluster, onprem, test")
+}
+
+func (s *TargetsTest) TestRegisterTwice() {
+	s.Panics(func() {
+		targets.RegisterStep(targets.Step{
+			Name:     "ClusterStep",
+			Provider: testProvider,
+			Create: func(env targets.Environment) (steps.OrchInstallerStep, error) {
+				return &fakeStep{name: "ClusterStep"}, nil
+			},
+		})
+	})
+}